@@ -0,0 +1,144 @@
+// Package analysis provides deck analysis over card data returned by the SDK,
+// such as manabase validation. Functions are pure and never query the database.
+package analysis
+
+import (
+	"math"
+	"slices"
+
+	"github.com/mtgjson/mtgjson-sdk-go/manacost"
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+// manaColors are the colors a manabase is checked for, in WUBRG order with
+// colorless last.
+var manaColors = []string{"W", "U", "B", "R", "G", "C"}
+
+// karstenTables holds the number of sources of a color needed to cast a spell
+// on curve about 90% of the time, from Frank Karsten's 2022 analysis
+// ("How Many Sources Do You Need to Consistently Cast Your Spells?"). Tables
+// are keyed by deck size, then indexed [pips-1][manaValue-1] for mana values
+// 1-6; a zero marks a cost that cannot exist (more pips than mana value).
+var karstenTables = map[int][4][6]int{
+	40: {
+		{9, 8, 7, 6, 5, 5},
+		{0, 14, 12, 10, 9, 8},
+		{0, 0, 16, 14, 12, 11},
+		{0, 0, 0, 17, 15, 14},
+	},
+	60: {
+		{14, 13, 12, 10, 9, 8},
+		{0, 21, 18, 16, 15, 13},
+		{0, 0, 23, 20, 18, 16},
+		{0, 0, 0, 24, 22, 20},
+	},
+	99: {
+		{19, 19, 18, 16, 15, 14},
+		{0, 30, 28, 26, 24, 23},
+		{0, 0, 36, 33, 30, 28},
+		{0, 0, 0, 39, 36, 33},
+	},
+}
+
+// ParsePips counts the colored pips in a mana cost such as "{2}{W}{W}",
+// keyed by W, U, B, R, G and C, reading it with manacost.Parse. Generic, X,
+// hybrid and Phyrexian symbols place no hard requirement on a single color
+// and are not counted. A cost manacost.Parse rejects has no pips.
+func ParsePips(manaCost string) map[string]int {
+	pips := make(map[string]int)
+	cost, err := manacost.Parse(manaCost)
+	if err != nil {
+		return pips
+	}
+	for _, s := range cost {
+		switch s.Kind {
+		case manacost.Colored:
+			pips[s.Colors[0]]++
+		case manacost.Colorless:
+			pips["C"]++
+		}
+	}
+	return pips
+}
+
+// RequiredSources returns the number of sources of a color a deck of the
+// given size needs to cast a spell with the given colored pips and mana value
+// on curve. Costs outside the tables are clamped to the nearest entry and
+// deck sizes between tables are scaled from the nearest one.
+func RequiredSources(deckSize, pips, manaValue int) int {
+	if pips <= 0 || deckSize <= 0 {
+		return 0
+	}
+	size := 60
+	switch {
+	case deckSize < 50:
+		size = 40
+	case deckSize >= 80:
+		size = 99
+	}
+	pips = min(pips, 4)
+	manaValue = min(max(manaValue, pips), 6)
+	n := karstenTables[size][pips-1][manaValue-1]
+	if deckSize == size {
+		return n
+	}
+	return int(math.Ceil(float64(n) * float64(deckSize) / float64(size)))
+}
+
+// Manabase analyzes the colored mana requirements of a deck's cards against
+// the colors its lands produce. For each color it reports the strictest
+// requirement among the deck's spells (per RequiredSources), the card that
+// drives it, and how many lands produce that color. The report is Valid when
+// no color falls short.
+func Manabase(cards []models.CardDeck) *models.ManabaseReport {
+	report := &models.ManabaseReport{}
+	for _, c := range cards {
+		report.DeckSize += c.Count
+	}
+
+	reqs := make(map[string]*models.ColorRequirement, len(manaColors))
+	for _, color := range manaColors {
+		reqs[color] = &models.ColorRequirement{Color: color}
+	}
+	for _, c := range cards {
+		if slices.Contains(c.Types, "Land") {
+			report.LandCount += c.Count
+			for _, color := range c.ProducedMana {
+				if r := reqs[color]; r != nil {
+					r.Sources += c.Count
+				}
+			}
+			continue
+		}
+		if c.ManaCost == nil {
+			continue
+		}
+		manaValue := c.ManaValue
+		if c.FaceManaValue != nil {
+			manaValue = *c.FaceManaValue
+		}
+		for color, n := range ParsePips(*c.ManaCost) {
+			r := reqs[color]
+			r.Pips += n * c.Count
+			if need := RequiredSources(report.DeckSize, n, int(manaValue)); need > r.Required {
+				r.Required = need
+				r.DrivingCard = c.Name
+				r.DrivingCost = *c.ManaCost
+			}
+		}
+	}
+
+	report.Valid = true
+	for _, color := range manaColors {
+		r := reqs[color]
+		if r.Pips == 0 && r.Sources == 0 {
+			continue
+		}
+		r.Shortfall = max(r.Required-r.Sources, 0)
+		if r.Shortfall > 0 {
+			report.Valid = false
+		}
+		report.Requirements = append(report.Requirements, *r)
+	}
+	return report
+}
@@ -0,0 +1,71 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestParsePips(t *testing.T) {
+	pips := ParsePips("{2}{W}{W}{U/B}{R/P}{X}{C}")
+	if pips["W"] != 2 || pips["C"] != 1 || len(pips) != 2 {
+		t.Fatalf("unexpected pips: %v", pips)
+	}
+	// Symbols manacost reads as hybrid or Phyrexian, however they are
+	// written, are not pips.
+	if pips := ParsePips("{G/W}{2/R}{C/U}{W/U/P}{HW} // {B}"); pips["B"] != 1 || len(pips) != 1 {
+		t.Fatalf("unexpected pips: %v", pips)
+	}
+	if pips := ParsePips("{W}{Q}"); len(pips) != 0 {
+		t.Fatalf("expected no pips for a malformed cost, got %v", pips)
+	}
+}
+
+func TestRequiredSources(t *testing.T) {
+	tests := []struct {
+		deckSize, pips, manaValue, want int
+	}{
+		{60, 1, 1, 14},
+		{60, 2, 2, 21},
+		{60, 1, 9, 8},  // mana value clamped to 6
+		{60, 2, 1, 21}, // mana value raised to pip count
+		{40, 1, 3, 7},
+		{100, 1, 1, 20}, // scaled from the 99-card table
+		{60, 0, 3, 0},
+	}
+	for _, tt := range tests {
+		if got := RequiredSources(tt.deckSize, tt.pips, tt.manaValue); got != tt.want {
+			t.Errorf("RequiredSources(%d, %d, %d) = %d, want %d", tt.deckSize, tt.pips, tt.manaValue, got, tt.want)
+		}
+	}
+}
+
+func TestManabase(t *testing.T) {
+	cards := []models.CardDeck{
+		{CardSet: models.CardSet{Name: "Lightning Bolt", ManaCost: strPtr("{R}"), ManaValue: 1, Types: []string{"Instant"}}, Count: 4},
+		{CardSet: models.CardSet{Name: "Counterspell", ManaCost: strPtr("{U}{U}"), ManaValue: 2, Types: []string{"Instant"}}, Count: 4},
+		{CardSet: models.CardSet{Name: "Filler", ManaCost: strPtr("{3}"), ManaValue: 3, Types: []string{"Artifact"}}, Count: 28},
+		{CardSet: models.CardSet{Name: "Mountain", Types: []string{"Land"}, ProducedMana: []string{"R"}}, Count: 10},
+		{CardSet: models.CardSet{Name: "Island", Types: []string{"Land"}, ProducedMana: []string{"U"}}, Count: 10},
+		{CardSet: models.CardSet{Name: "Steam Vents", Types: []string{"Land"}, ProducedMana: []string{"U", "R"}}, Count: 4},
+	}
+	report := Manabase(cards)
+	if report.DeckSize != 60 || report.LandCount != 24 {
+		t.Fatalf("unexpected totals: %+v", report)
+	}
+	if len(report.Requirements) != 2 {
+		t.Fatalf("expected 2 requirements, got %+v", report.Requirements)
+	}
+	blue, red := report.Requirements[0], report.Requirements[1]
+	if blue.Color != "U" || blue.Required != 21 || blue.Sources != 14 || blue.Shortfall != 7 || blue.DrivingCard != "Counterspell" {
+		t.Fatalf("unexpected blue requirement: %+v", blue)
+	}
+	if red.Color != "R" || red.Required != 14 || red.Sources != 14 || red.Shortfall != 0 || red.Pips != 4 {
+		t.Fatalf("unexpected red requirement: %+v", red)
+	}
+	if report.Valid {
+		t.Fatal("expected manabase to be invalid")
+	}
+}
@@ -0,0 +1,75 @@
+package analysis
+
+import (
+	"context"
+	"math"
+
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+const openingHandSize = 7
+
+// Hypergeometric returns the probability of drawing at least atLeast of the
+// given copies when drawing draws cards from a deck of deckSize.
+func Hypergeometric(deckSize, copies, draws, atLeast int) float64 {
+	if atLeast <= 0 {
+		return 1
+	}
+	if deckSize <= 0 || copies <= 0 || draws <= 0 {
+		return 0
+	}
+	copies = min(copies, deckSize)
+	draws = min(draws, deckSize)
+	p := 0.0
+	for k := atLeast; k <= min(copies, draws); k++ {
+		p += math.Exp(lnChoose(copies, k) + lnChoose(deckSize-copies, draws-k) - lnChoose(deckSize, draws))
+	}
+	return min(p, 1)
+}
+
+// ChanceToDrawBy returns the probability of having drawn at least one of the
+// given copies by the given turn, on the play (seven cards in the opening
+// hand plus one draw per turn after the first), without mulligans.
+func ChanceToDrawBy(turn, copies, deckSize int) float64 {
+	if turn < 1 {
+		return 0
+	}
+	return Hypergeometric(deckSize, copies, openingHandSize+turn-1, 1)
+}
+
+// MulliganAdjustedOdds returns the probability of seeing at least one of the
+// given copies in an opening seven when willing to mulligan up to mulligans
+// times to find it. Under the London mulligan every hand draws seven cards,
+// so each attempt is independent.
+func MulliganAdjustedOdds(copies, deckSize, mulligans int) float64 {
+	p := Hypergeometric(deckSize, copies, openingHandSize, 1)
+	return 1 - math.Pow(1-p, float64(max(mulligans, 0)+1))
+}
+
+// OpeningHandContains returns the probability that a seven-card opening hand
+// from the deck holds at least one card matching predicate. predicate may
+// query the SDK; ctx is checked before each call and its error returned if
+// it is canceled.
+func OpeningHandContains(ctx context.Context, deck []models.CardDeck, predicate func(models.CardSet) bool) (float64, error) {
+	deckSize, copies := 0, 0
+	for _, c := range deck {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		deckSize += c.Count
+		if predicate(c.CardSet) {
+			copies += c.Count
+		}
+	}
+	return Hypergeometric(deckSize, copies, openingHandSize, 1), nil
+}
+
+func lnChoose(n, k int) float64 {
+	if k < 0 || k > n {
+		return math.Inf(-1)
+	}
+	a, _ := math.Lgamma(float64(n + 1))
+	b, _ := math.Lgamma(float64(k + 1))
+	c, _ := math.Lgamma(float64(n - k + 1))
+	return a - b - c
+}
@@ -0,0 +1,72 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+func approx(a, b float64) bool { return math.Abs(a-b) < 1e-4 }
+
+func TestHypergeometric(t *testing.T) {
+	// 4 copies in 60 cards, 7 drawn: P(>=1) = 1 - C(56,7)/C(60,7).
+	if got := Hypergeometric(60, 4, 7, 1); !approx(got, 0.39949) {
+		t.Fatalf("P(>=1) = %f", got)
+	}
+	if got := Hypergeometric(60, 4, 7, 0); got != 1 {
+		t.Fatalf("P(>=0) = %f", got)
+	}
+	if got := Hypergeometric(60, 0, 7, 1); got != 0 {
+		t.Fatalf("P with no copies = %f", got)
+	}
+	if got := Hypergeometric(10, 10, 3, 3); !approx(got, 1) {
+		t.Fatalf("P with all copies = %f", got)
+	}
+}
+
+func TestChanceToDrawBy(t *testing.T) {
+	if got := ChanceToDrawBy(1, 4, 60); !approx(got, Hypergeometric(60, 4, 7, 1)) {
+		t.Fatalf("turn 1 = %f", got)
+	}
+	if ChanceToDrawBy(3, 4, 60) <= ChanceToDrawBy(2, 4, 60) {
+		t.Fatal("odds should increase each turn")
+	}
+	if ChanceToDrawBy(0, 4, 60) != 0 {
+		t.Fatal("turn 0 should be 0")
+	}
+}
+
+func TestMulliganAdjustedOdds(t *testing.T) {
+	p := Hypergeometric(60, 4, 7, 1)
+	if got := MulliganAdjustedOdds(4, 60, 0); !approx(got, p) {
+		t.Fatalf("no mulligans = %f", got)
+	}
+	if got := MulliganAdjustedOdds(4, 60, 1); !approx(got, 1-(1-p)*(1-p)) {
+		t.Fatalf("one mulligan = %f", got)
+	}
+}
+
+func TestOpeningHandContains(t *testing.T) {
+	deck := []models.CardDeck{
+		{CardSet: models.CardSet{Name: "Lightning Bolt", Types: []string{"Instant"}}, Count: 4},
+		{CardSet: models.CardSet{Name: "Mountain", Types: []string{"Land"}}, Count: 56},
+	}
+	nonLand := func(c models.CardSet) bool { return !slices.Contains(c.Types, "Land") }
+	got, err := OpeningHandContains(context.Background(), deck, nonLand)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !approx(got, Hypergeometric(60, 4, 7, 1)) {
+		t.Fatalf("unexpected probability: %f", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := OpeningHandContains(ctx, deck, nonLand); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package benchmarks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	mtgjson "github.com/mtgjson/mtgjson-sdk-go"
+	"github.com/mtgjson/mtgjson-sdk-go/db"
+	"github.com/mtgjson/mtgjson-sdk-go/queries"
+)
+
+// fixtureCards is the number of printings in the generated fixture.
+const fixtureCards = 20000
+
+// fixtureQueries generate MTGJSON-shaped parquet files: list columns are
+// comma-separated strings and nested objects JSON strings, as on the CDN.
+// {n} is replaced with fixtureCards.
+var fixtureQueries = map[string]string{
+	"cards.parquet": `SELECT 'uuid-' || i AS uuid, 'Card ' || (i % 5000) AS name,
+		'S' || (i % 50) AS setCode, CAST(i AS VARCHAR) AS number,
+		['W', 'U', 'B', 'R', 'G', 'W, U', 'B, R', ''][i % 8 + 1] AS colors,
+		['W', 'U', 'B', 'R', 'G', 'W, U', 'B, R', ''][i % 8 + 1] AS colorIdentity,
+		['Creature — Elf', 'Instant', 'Sorcery', 'Artifact', 'Land'][i % 5 + 1] AS type,
+		['Creature', 'Instant', 'Sorcery', 'Artifact', 'Land'][i % 5 + 1] AS types,
+		CAST(i % 8 AS DOUBLE) AS manaValue, '{' || (i % 8) || '}' AS manaCost,
+		'Deal ' || (i % 7) || ' damage to any target. Draw a card.' AS text,
+		['common', 'uncommon', 'rare', 'mythic'][i % 4 + 1] AS rarity,
+		'normal' AS layout, 'English' AS language, 'black' AS borderColor, '2015' AS frameVersion,
+		'paper, mtgo' AS availability, 'nonfoil, foil' AS finishes, 'default' AS boosterTypes,
+		CASE WHEN i % 3 = 0 THEN 'Flying' ELSE '' END AS keywords,
+		'{"scryfallId": "scryfall-' || i || '"}' AS identifiers, '{}' AS purchaseUrls
+		FROM range({n}) t(i)`,
+	"AllPricesToday.parquet": `SELECT 'uuid-' || (i // 4) AS uuid, 'paper' AS source,
+		['tcgplayer', 'cardkingdom'][i % 2 + 1] AS provider, 'USD' AS currency,
+		['retail', 'buylist'][(i // 2) % 2 + 1] AS price_type, 'normal' AS finish,
+		'2024-01-01' AS date, CAST((i % 1000) / 10.0 AS DOUBLE) AS price
+		FROM range({n} * 4) t(i)`,
+}
+
+var benchSDK *mtgjson.SDK
+
+func TestMain(m *testing.M) {
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
+	dir := os.Getenv("MTGJSON_BENCH_CACHE_DIR")
+	offline := false
+	if dir == "" {
+		tmp, err := os.MkdirTemp("", "mtgjson-bench-")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer os.RemoveAll(tmp)
+		if err := writeFixture(tmp); err != nil {
+			fmt.Fprintln(os.Stderr, "write fixture:", err)
+			return 1
+		}
+		dir, offline = tmp, true
+	}
+	sdk, err := mtgjson.New(mtgjson.WithCacheDir(dir), mtgjson.WithOffline(offline))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer sdk.Close()
+	benchSDK = sdk
+	return m.Run()
+}
+
+func writeFixture(dir string) error {
+	cache, err := db.NewCacheManager(&db.Config{CacheDir: dir, Offline: true})
+	if err != nil {
+		return err
+	}
+	conn, err := db.NewConnection(cache)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := os.MkdirAll(filepath.Join(dir, "parquet"), 0o755); err != nil {
+		return err
+	}
+	for file, query := range fixtureQueries {
+		path := db.SQLPathLiteral(filepath.Join(dir, "parquet", file))
+		query = strings.ReplaceAll(query, "{n}", strconv.Itoa(fixtureCards))
+		copySQL := "COPY (" + query + ") TO " + path + " (FORMAT PARQUET)"
+		if _, err := conn.Raw().ExecContext(context.Background(), copySQL); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// sampleUUIDs returns n card UUIDs spread across the cards view.
+func sampleUUIDs(b *testing.B, n int) []string {
+	b.Helper()
+	ctx := context.Background()
+	if err := benchSDK.EnsureViews(ctx, "cards"); err != nil {
+		b.Fatal(err)
+	}
+	rows, err := benchSDK.SQL(ctx, fmt.Sprintf(
+		"SELECT uuid FROM cards USING SAMPLE reservoir(%d ROWS) REPEATABLE (42)", n))
+	if err != nil {
+		b.Fatal(err)
+	}
+	uuids := make([]string, len(rows))
+	for i, r := range rows {
+		uuids[i], _ = r["uuid"].(string)
+	}
+	return uuids
+}
+
+func floatPtr(f float64) *float64 { return &f }
+
+func BenchmarkEnsureViews(b *testing.B) {
+	ctx := context.Background()
+	conn := benchSDK.Connection()
+	for _, view := range []string{"cards", "all_prices_today"} {
+		b.Run(view, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				conn.ResetViews(view)
+				if err := conn.EnsureViews(ctx, view); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSearch(b *testing.B) {
+	ctx := context.Background()
+	cases := []struct {
+		name string
+		p    queries.SearchCardsParams
+	}{
+		{"Name", queries.SearchCardsParams{Name: "Card 42"}},
+		{"NameLike", queries.SearchCardsParams{Name: "Card 42%"}},
+		{"FuzzyName", queries.SearchCardsParams{FuzzyName: "Crad 4242"}},
+		{"Colors", queries.SearchCardsParams{Colors: []string{"W", "U"}}},
+		{"Text", queries.SearchCardsParams{Text: "damage to any target"}},
+		{"ManaValueRange", queries.SearchCardsParams{ManaValueGTE: floatPtr(2), ManaValueLTE: floatPtr(4)}},
+		{"Combined", queries.SearchCardsParams{Types: "Creature", Rarity: "rare", Keyword: "Flying", Limit: 50}},
+	}
+	for _, tc := range cases {
+		b.Run(tc.name, func(b *testing.B) {
+			if _, err := benchSDK.Cards().Search(ctx, tc.p); err != nil {
+				b.Fatal(err)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := benchSDK.Cards().Search(ctx, tc.p); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGetByUUIDs(b *testing.B) {
+	ctx := context.Background()
+	for _, n := range []int{1, 10, 100, 1000} {
+		uuids := sampleUUIDs(b, n)
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := benchSDK.Cards().GetByUUIDs(ctx, uuids); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkPrices(b *testing.B) {
+	ctx := context.Background()
+	uuid := sampleUUIDs(b, 1)[0]
+	b.Run("Get", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := benchSDK.Prices().Get(ctx, uuid); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Today", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := benchSDK.Prices().Today(ctx, uuid); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("ByVendor", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := benchSDK.Prices().ByVendor(ctx, uuid); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,10 @@
+// Package benchmarks holds go test benchmarks for the SDK's hot paths: card
+// search, batch lookups, price flattening and view registration.
+//
+//	go test -bench . -benchmem ./benchmarks
+//
+// By default they run against a generated fixture of 20,000 printings
+// written as MTGJSON-shaped parquet files. Set MTGJSON_BENCH_CACHE_DIR to a
+// cache directory to benchmark real MTGJSON data instead; missing files are
+// downloaded into it on first use. Compare runs with benchstat.
+package benchmarks
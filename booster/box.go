@@ -0,0 +1,339 @@
+package booster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/mtgjson/mtgjson-sdk-go/db"
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+const defaultBoxPacks = 36
+
+type boxConfig struct {
+	provider string
+	workers  int
+}
+
+// BoxOption configures OpenBoxSummary, SimulateBoxes and OpenPacksParallel.
+type BoxOption func(*boxConfig)
+
+// WithBoxProvider sets the price provider used to value pulls (default "tcgplayer").
+func WithBoxProvider(provider string) BoxOption {
+	return func(c *boxConfig) { c.provider = provider }
+}
+
+// WithWorkers sets how many boxes SimulateBoxes, or packs OpenPacksParallel,
+// opens concurrently (default 1).
+func WithWorkers(n int) BoxOption {
+	return func(c *boxConfig) { c.workers = n }
+}
+
+func newBoxConfig(opts []BoxOption) *boxConfig {
+	cfg := &boxConfig{provider: "tcgplayer", workers: 1}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.workers < 1 {
+		cfg.workers = 1
+	}
+	return cfg
+}
+
+// boxData is everything needed to open and summarize boxes without further
+// queries: the booster config, card colors for balanced sheets, card rarities
+// and prices for every card on the config's sheets.
+type boxData struct {
+	config   *models.BoosterConfig
+	colors   map[string][]string
+	rarities map[string]string
+	prices   map[string]float64
+}
+
+func (bs *BoosterSimulator) loadBoxData(ctx context.Context, setCode, boosterType, provider string) (*boxData, error) {
+	config, err := bs.Config(ctx, setCode, boosterType)
+	if err != nil {
+		return nil, err
+	}
+	colors, err := bs.sheetColors(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	var uuids []any
+	seen := make(map[string]bool)
+	for _, sheet := range config.Sheets {
+		for uuid := range sheet.Cards {
+			if !seen[uuid] {
+				seen[uuid] = true
+				uuids = append(uuids, uuid)
+			}
+		}
+	}
+	data := &boxData{config: config, colors: colors, rarities: make(map[string]string)}
+	if len(uuids) == 0 {
+		return data, nil
+	}
+	sql, params := db.NewSQLBuilder("cards").Select("uuid", "rarity").WhereIn("uuid", uuids).Build()
+	rows, err := bs.conn.Execute(ctx, sql, params...)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		uuid, _ := r["uuid"].(string)
+		data.rarities[uuid], _ = r["rarity"].(string)
+	}
+	if err := bs.conn.EnsureOptionalViews(ctx, "all_prices_today"); err != nil {
+		return nil, err
+	}
+	if bs.conn.HasView("all_prices_today") {
+		if data.prices, err = bs.retailPrices(ctx, uuids, provider); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// openBox draws packs and summarizes them.
+func (d *boxData) openBox(packs int, rng randSource) ([][]pull, models.BoxSummary) {
+	box := make([][]pull, packs)
+	for i := range box {
+		box[i] = openPackPulls(d.config, d.colors, rng)
+	}
+	return box, d.summarize(box)
+}
+
+func (d *boxData) summarize(box [][]pull) models.BoxSummary {
+	s := models.BoxSummary{Packs: len(box)}
+	counts := make(map[string]int)
+	for _, pack := range box {
+		for _, p := range pack {
+			s.Cards++
+			counts[p.uuid]++
+			switch d.rarities[p.uuid] {
+			case "rare":
+				s.Rares++
+			case "mythic":
+				s.Mythics++
+			}
+			finish := "normal"
+			if p.foil {
+				s.Foils++
+				finish = "foil"
+			}
+			s.TotalValue += d.prices[p.uuid+"|"+finish]
+		}
+	}
+	s.UniqueCards = len(counts)
+	s.Duplicates = s.Cards - s.UniqueCards
+	s.TotalValue = math.Round(s.TotalValue*100) / 100
+	return s
+}
+
+// OpenBoxSummary opens a booster box like OpenBox and also returns its summary
+// (rare, mythic and foil counts, duplicates and total retail value).
+// packs defaults to 36.
+func (bs *BoosterSimulator) OpenBoxSummary(ctx context.Context, setCode, boosterType string, packs int, opts ...BoxOption) (*models.BoosterBox, error) {
+	if packs <= 0 {
+		packs = defaultBoxPacks
+	}
+	cfg := newBoxConfig(opts)
+	data, err := bs.loadBoxData(ctx, setCode, boosterType, cfg.provider)
+	if err != nil {
+		return nil, err
+	}
+	pulls, summary := data.openBox(packs, bs.rng)
+	cards, err := bs.resolvePulls(ctx, pulls)
+	if err != nil {
+		return nil, err
+	}
+	return &models.BoosterBox{Packs: cards, Summary: summary}, nil
+}
+
+// resolvePulls looks up the cards of opened packs in one query. Pulls whose
+// card is missing from the cards view are left out.
+func (bs *BoosterSimulator) resolvePulls(ctx context.Context, pulls [][]pull) ([][]models.CardSet, error) {
+	var uuids []any
+	seen := make(map[string]bool)
+	for _, pack := range pulls {
+		for _, p := range pack {
+			if !seen[p.uuid] {
+				seen[p.uuid] = true
+				uuids = append(uuids, p.uuid)
+			}
+		}
+	}
+	cards := make(map[string]models.CardSet)
+	if len(uuids) > 0 {
+		sql, params := db.NewSQLBuilder("cards").WhereIn("uuid", uuids).Build()
+		var rows []models.CardSet
+		if err := bs.conn.ExecuteInto(ctx, &rows, sql, params...); err != nil {
+			return nil, err
+		}
+		for _, c := range rows {
+			cards[c.UUID] = c
+		}
+	}
+	packs := make([][]models.CardSet, len(pulls))
+	for i, pack := range pulls {
+		for _, p := range pack {
+			if c, ok := cards[p.uuid]; ok {
+				packs[i] = append(packs[i], c)
+			}
+		}
+	}
+	return packs, nil
+}
+
+// OpenPacksParallel opens n packs on WithWorkers goroutines and returns them
+// with their combined summary (valued at retail prices of WithBoxProvider's
+// provider) and pull counts. Packs that draw no cards, e.g. because the
+// config names a missing sheet, are reported together as one joined error.
+// Like SimulateBoxes, each pack uses its own random source seeded from the
+// simulator's, so results are reproducible with WithRand regardless of the
+// number of workers.
+func (bs *BoosterSimulator) OpenPacksParallel(ctx context.Context, setCode, boosterType string, n int, opts ...BoxOption) (*models.PackBatch, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("mtgjson: packs must be positive, got %d", n)
+	}
+	cfg := newBoxConfig(opts)
+	data, err := bs.loadBoxData(ctx, setCode, boosterType, cfg.provider)
+	if err != nil {
+		return nil, err
+	}
+	if len(data.config.Boosters) == 0 {
+		return nil, fmt.Errorf("mtgjson: %s %s has no pack templates", setCode, boosterType)
+	}
+	seeds := bs.seeds(n)
+	pulls := make([][]pull, n)
+	err = runParallel(ctx, n, cfg.workers, func(i int) error {
+		pulls[i] = openPackPulls(data.config, data.colors, rand.New(rand.NewSource(seeds[i])))
+		if len(pulls[i]) == 0 {
+			return fmt.Errorf("mtgjson: pack %d of %s %s drew no cards", i+1, setCode, boosterType)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	batch := &models.PackBatch{Summary: data.summarize(pulls), Pulls: make(map[string]int)}
+	for _, pack := range pulls {
+		for _, p := range pack {
+			batch.Pulls[p.uuid]++
+		}
+	}
+	if batch.Packs, err = bs.resolvePulls(ctx, pulls); err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// SimulateBoxes opens boxes booster boxes of packs packs each and aggregates
+// their summaries into a value and content distribution. Boxes are opened on
+// WithWorkers goroutines; each box uses its own random source seeded from the
+// simulator's, so results are reproducible with WithRand regardless of the
+// number of workers.
+func (bs *BoosterSimulator) SimulateBoxes(ctx context.Context, setCode, boosterType string, packs, boxes int, opts ...BoxOption) (*models.BoxDistribution, error) {
+	if boxes <= 0 {
+		return nil, fmt.Errorf("mtgjson: boxes must be positive, got %d", boxes)
+	}
+	if packs <= 0 {
+		packs = defaultBoxPacks
+	}
+	cfg := newBoxConfig(opts)
+	data, err := bs.loadBoxData(ctx, setCode, boosterType, cfg.provider)
+	if err != nil {
+		return nil, err
+	}
+
+	seeds := bs.seeds(boxes)
+	summaries := make([]models.BoxSummary, boxes)
+	err = runParallel(ctx, boxes, cfg.workers, func(i int) error {
+		_, summaries[i] = data.openBox(packs, rand.New(rand.NewSource(seeds[i])))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return distribution(summaries, packs), nil
+}
+
+// seeds draws n seeds from the simulator's random source, one per
+// independently opened pack or box.
+func (bs *BoosterSimulator) seeds(n int) []int64 {
+	seeds := make([]int64, n)
+	for i := range seeds {
+		seeds[i] = bs.rng.Int63()
+	}
+	return seeds
+}
+
+// runParallel calls fn for 0 through n-1 on up to workers goroutines,
+// stopping early if ctx is canceled. The errors of every call, in index
+// order, and ctx's error are joined with errors.Join.
+func runParallel(ctx context.Context, n, workers int, fn func(i int) error) error {
+	next := make(chan int)
+	errs := make([]error, n+1)
+	var wg sync.WaitGroup
+	for w := 0; w < min(max(workers, 1), n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				errs[i] = fn(i)
+			}
+		}()
+	}
+dispatch:
+	for i := 0; i < n; i++ {
+		select {
+		case next <- i:
+		case <-ctx.Done():
+			errs[n] = ctx.Err()
+			break dispatch
+		}
+	}
+	close(next)
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// distribution aggregates box summaries.
+func distribution(summaries []models.BoxSummary, packs int) *models.BoxDistribution {
+	d := &models.BoxDistribution{Boxes: len(summaries), PacksPerBox: packs, Summaries: summaries}
+	values := make([]float64, len(summaries))
+	for i, s := range summaries {
+		values[i] = s.TotalValue
+		d.MeanValue += s.TotalValue
+		d.MeanRares += float64(s.Rares)
+		d.MeanMythics += float64(s.Mythics)
+		d.MeanFoils += float64(s.Foils)
+		d.MeanUniqueCards += float64(s.UniqueCards)
+		d.MeanDuplicates += float64(s.Duplicates)
+	}
+	n := float64(len(summaries))
+	d.MeanValue = math.Round(d.MeanValue/n*100) / 100
+	d.MeanRares /= n
+	d.MeanMythics /= n
+	d.MeanFoils /= n
+	d.MeanUniqueCards /= n
+	d.MeanDuplicates /= n
+
+	sort.Float64s(values)
+	d.MinValue = values[0]
+	d.MaxValue = values[len(values)-1]
+	d.P10Value = percentile(values, 0.10)
+	d.P50Value = percentile(values, 0.50)
+	d.P90Value = percentile(values, 0.90)
+	return d
+}
+
+// percentile returns the nearest-rank percentile of sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	idx := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(idx, 0)]
+}
@@ -0,0 +1,275 @@
+package booster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/mtgjson/mtgjson-sdk-go/db"
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+// setupBoxDB registers the fixture's TST set, its cards and a few prices.
+func setupBoxDB(t *testing.T) *db.Connection {
+	t.Helper()
+	cfg := db.DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.Offline = true
+	cache, err := db.NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := db.NewConnection(cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	raw, err := os.ReadFile(filepath.Join("testdata", "allprintings_sample.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Data map[string]struct {
+			Booster json.RawMessage `json:"booster"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	rarities := map[string]string{"tst-r-1": "rare", "tst-r-2": "rare", "tst-r-3": "rare", "tst-m-1": "mythic"}
+	colors := loadSampleColors(t)
+	var cards []map[string]any
+	configs := loadSampleConfigs(t)
+	seen := make(map[string]bool)
+	for _, config := range configs["TST"] {
+		for _, sheet := range config.Sheets {
+			for uuid := range sheet.Cards {
+				if seen[uuid] {
+					continue
+				}
+				seen[uuid] = true
+				rarity := rarities[uuid]
+				if rarity == "" {
+					rarity = "common"
+				}
+				c := colors[uuid]
+				if c == nil {
+					c = []string{}
+				}
+				cards = append(cards, map[string]any{"uuid": uuid, "name": uuid, "rarity": rarity, "colors": c})
+			}
+		}
+	}
+	prices := []map[string]any{
+		{"uuid": "tst-m-1", "source": "paper", "provider": "tcgplayer", "price_type": "retail", "finish": "normal", "price": 20.0, "date": "2024-01-01"},
+		{"uuid": "tst-r-1", "source": "paper", "provider": "tcgplayer", "price_type": "retail", "finish": "normal", "price": 2.0, "date": "2024-01-01"},
+		{"uuid": "tst-r-1", "source": "paper", "provider": "tcgplayer", "price_type": "retail", "finish": "foil", "price": 5.0, "date": "2024-01-01"},
+	}
+	for _, td := range []struct {
+		name string
+		data []map[string]any
+	}{
+		{"sets", []map[string]any{{"code": "TST", "booster": string(doc.Data["TST"].Booster)}}},
+		{"cards", cards},
+		{"all_prices_today", prices},
+	} {
+		if err := conn.RegisterTableFromData(ctx, td.name, td.data); err != nil {
+			t.Fatalf("register %s: %v", td.name, err)
+		}
+	}
+	return conn
+}
+
+func TestSummarize(t *testing.T) {
+	d := &boxData{
+		rarities: map[string]string{"r": "rare", "m": "mythic"},
+		prices:   map[string]float64{"r|normal": 1.5, "r|foil": 4, "m|normal": 10},
+	}
+	box := [][]pull{
+		{{uuid: "c"}, {uuid: "r"}, {uuid: "m"}},
+		{{uuid: "c"}, {uuid: "r", foil: true}},
+	}
+	got := d.summarize(box)
+	want := models.BoxSummary{Packs: 2, Cards: 5, Rares: 2, Mythics: 1, Foils: 1, UniqueCards: 3, Duplicates: 2, TotalValue: 15.5}
+	if got != want {
+		t.Fatalf("summarize = %+v, want %+v", got, want)
+	}
+}
+
+func TestDistribution(t *testing.T) {
+	var summaries []models.BoxSummary
+	for i := 1; i <= 10; i++ {
+		summaries = append(summaries, models.BoxSummary{TotalValue: float64(i), Rares: i})
+	}
+	d := distribution(summaries, 36)
+	if d.Boxes != 10 || d.MinValue != 1 || d.MaxValue != 10 || d.P50Value != 5 || d.P90Value != 9 || d.P10Value != 1 {
+		t.Fatalf("unexpected distribution: %+v", d)
+	}
+	if d.MeanValue != 5.5 || d.MeanRares != 5.5 {
+		t.Fatalf("unexpected means: %+v", d)
+	}
+}
+
+func TestOpenBoxSummary(t *testing.T) {
+	bs := NewBoosterSimulator(setupBoxDB(t), WithRand(rand.New(rand.NewSource(3))))
+	box, err := bs.OpenBoxSummary(context.Background(), "TST", "draft", 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(box.Packs) != 12 || box.Summary.Packs != 12 {
+		t.Fatalf("expected 12 packs, got %d", len(box.Packs))
+	}
+	cards := 0
+	for _, pack := range box.Packs {
+		cards += len(pack)
+	}
+	s := box.Summary
+	if s.Cards != cards || s.Rares+s.Mythics != 12 || s.UniqueCards+s.Duplicates != s.Cards {
+		t.Fatalf("summary inconsistent with packs (%d cards): %+v", cards, s)
+	}
+	if s.TotalValue < float64(s.Mythics)*20 {
+		t.Fatalf("total value %v should include mythic prices: %+v", s.TotalValue, s)
+	}
+}
+
+func TestSimulateBoxesReproducible(t *testing.T) {
+	conn := setupBoxDB(t)
+	ctx := context.Background()
+
+	serial, err := NewBoosterSimulator(conn, WithRand(rand.New(rand.NewSource(9)))).
+		SimulateBoxes(ctx, "TST", "draft", 6, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parallel, err := NewBoosterSimulator(conn, WithRand(rand.New(rand.NewSource(9)))).
+		SimulateBoxes(ctx, "TST", "draft", 6, 20, WithWorkers(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if serial.Boxes != 20 || serial.PacksPerBox != 6 || len(serial.Summaries) != 20 {
+		t.Fatalf("unexpected distribution: %+v", serial)
+	}
+	for i := range serial.Summaries {
+		if serial.Summaries[i] != parallel.Summaries[i] {
+			t.Fatalf("box %d differs between 1 and 4 workers: %+v vs %+v", i, serial.Summaries[i], parallel.Summaries[i])
+		}
+	}
+	if serial.MinValue > serial.P50Value || serial.P50Value > serial.MaxValue {
+		t.Fatalf("percentiles out of order: %+v", serial)
+	}
+
+	if _, err := NewBoosterSimulator(conn).SimulateBoxes(ctx, "TST", "draft", 6, 0); err == nil {
+		t.Fatal("expected error for zero boxes")
+	}
+}
+
+func TestOpenPacksParallel(t *testing.T) {
+	conn := setupBoxDB(t)
+	ctx := context.Background()
+
+	serial, err := NewBoosterSimulator(conn, WithRand(rand.New(rand.NewSource(11)))).
+		OpenPacksParallel(ctx, "TST", "draft", 50)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parallel, err := NewBoosterSimulator(conn, WithRand(rand.New(rand.NewSource(11)))).
+		OpenPacksParallel(ctx, "TST", "draft", 50, WithWorkers(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(serial.Packs) != 50 || serial.Summary != parallel.Summary {
+		t.Fatalf("expected 50 identical packs from 1 and 8 workers: %+v vs %+v", serial.Summary, parallel.Summary)
+	}
+	pulled := 0
+	for i := range serial.Packs {
+		for j, c := range serial.Packs[i] {
+			if c.UUID != parallel.Packs[i][j].UUID {
+				t.Fatalf("pack %d differs between 1 and 8 workers", i)
+			}
+			pulled++
+		}
+	}
+	total := 0
+	for _, n := range serial.Pulls {
+		total += n
+	}
+	if total != pulled || serial.Summary.Cards != pulled {
+		t.Errorf("expected pull counts to cover all %d cards, got %d (summary %+v)", pulled, total, serial.Summary)
+	}
+
+	other, err := NewBoosterSimulator(conn, WithRand(rand.New(rand.NewSource(11)))).
+		OpenPacksParallel(ctx, "TST", "draft", 50, WithWorkers(2), WithBoxProvider("cardkingdom"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if serial.Summary.TotalValue == 0 || other.Summary.TotalValue != 0 {
+		t.Errorf("expected packs valued at the given provider's prices, got %v and %v", serial.Summary.TotalValue, other.Summary.TotalValue)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := NewBoosterSimulator(conn).OpenPacksParallel(canceled, "TST", "draft", 1000, WithWorkers(2)); err == nil {
+		t.Error("expected an error for a canceled context")
+	}
+	if _, err := NewBoosterSimulator(conn).OpenPacksParallel(ctx, "TST", "draft", 0); err == nil {
+		t.Error("expected an error for zero packs")
+	}
+}
+
+func TestOpenPackConcurrentSharedRand(t *testing.T) {
+	bs := NewBoosterSimulator(setupBoxDB(t), WithRand(rand.New(rand.NewSource(5))))
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pack, err := bs.OpenPack(ctx, "TST", "draft")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if len(pack) == 0 {
+				t.Error("expected a non-empty pack")
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestRunParallelJoinsErrors(t *testing.T) {
+	errOdd := errors.New("odd")
+	var mu sync.Mutex
+	ran := 0
+	err := runParallel(context.Background(), 10, 3, func(i int) error {
+		mu.Lock()
+		ran++
+		mu.Unlock()
+		if i%2 == 1 {
+			return fmt.Errorf("call %d: %w", i, errOdd)
+		}
+		return nil
+	})
+	if ran != 10 {
+		t.Fatalf("expected every call to run despite errors, got %d", ran)
+	}
+	if !errors.Is(err, errOdd) {
+		t.Fatalf("expected the calls' errors, got %v", err)
+	}
+	if want := "call 1: odd\ncall 3: odd\ncall 5: odd\ncall 7: odd\ncall 9: odd"; err.Error() != want {
+		t.Errorf("expected the errors in call order, got %q", err.Error())
+	}
+	if err := runParallel(context.Background(), 5, 2, func(int) error { return nil }); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
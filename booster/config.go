@@ -0,0 +1,102 @@
+package booster
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+// ParseConfigs decodes a sets.booster column value into booster
+// configurations keyed by booster type (e.g. "draft", "collector"). The value
+// may be a JSON string, raw JSON bytes, or a decoded map/DuckDB struct.
+// Returns nil for a nil value.
+func ParseConfigs(v any) (map[string]models.BoosterConfig, error) {
+	var configs map[string]models.BoosterConfig
+	if err := decodeBooster(v, &configs); err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
+
+// parseTypes returns the booster types of a sets.booster column value in
+// ascending order, without decoding their configurations.
+func parseTypes(v any) ([]string, error) {
+	var configs map[string]json.RawMessage
+	if err := decodeBooster(v, &configs); err != nil {
+		return nil, err
+	}
+	return sortedKeys(configs), nil
+}
+
+// decodeBooster JSON-decodes a sets.booster column value into dst, leaving
+// dst untouched for a nil value.
+func decodeBooster(v any, dst any) error {
+	var data []byte
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case string:
+		data = []byte(t)
+	case []byte:
+		data = t
+	default:
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return fmt.Errorf("mtgjson: encode booster config: %w", err)
+		}
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return fmt.Errorf("mtgjson: decode booster config: %w", err)
+	}
+	return nil
+}
+
+// ParseAllPrintings reads the booster configurations from an AllPrintings.json
+// (or single-set JSON) document, keyed by set code then booster type. Sets
+// without booster data are omitted. All other set fields are skipped.
+func ParseAllPrintings(r io.Reader) (map[string]map[string]models.BoosterConfig, error) {
+	var doc struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("mtgjson: decode AllPrintings: %w", err)
+	}
+
+	type setBooster struct {
+		Code    string                          `json:"code"`
+		Booster map[string]models.BoosterConfig `json:"booster"`
+	}
+	result := make(map[string]map[string]models.BoosterConfig)
+	// A single-set file has the set object directly under "data".
+	var single setBooster
+	if err := json.Unmarshal(doc.Data, &single); err == nil && single.Code != "" {
+		if len(single.Booster) > 0 {
+			result[single.Code] = single.Booster
+		}
+		return result, nil
+	}
+	var sets map[string]setBooster
+	if err := json.Unmarshal(doc.Data, &sets); err != nil {
+		return nil, fmt.Errorf("mtgjson: decode AllPrintings: %w", err)
+	}
+	for code, s := range sets {
+		if len(s.Booster) > 0 {
+			result[code] = s.Booster
+		}
+	}
+	return result, nil
+}
+
+// sortedKeys returns the keys of m in ascending order, so simulations are
+// reproducible for a given random source.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,152 @@
+package booster
+
+import (
+	"encoding/json"
+	"flag"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// loadSampleConfigs parses the AllPrintings fixture.
+func loadSampleConfigs(t *testing.T) map[string]map[string]models.BoosterConfig {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", "allprintings_sample.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	configs, err := ParseAllPrintings(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return configs
+}
+
+// checkGolden compares got with testdata/<name>, rewriting it with -update.
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (run with -update to create): %v", err)
+	}
+	if string(got) != string(want) {
+		t.Fatalf("%s mismatch (run with -update to refresh)\ngot:\n%s\nwant:\n%s", name, got, want)
+	}
+}
+
+func TestParseAllPrintings(t *testing.T) {
+	configs := loadSampleConfigs(t)
+	if len(configs) != 1 {
+		t.Fatalf("expected only TST to have booster data, got %d sets", len(configs))
+	}
+	draft := configs["TST"]["draft"]
+	if len(draft.Boosters) != 3 || draft.BoostersTotalWeight != 10 {
+		t.Fatalf("unexpected draft config: %+v", draft)
+	}
+	if bc := draft.Sheets["common"].BalanceColors; bc == nil || !*bc {
+		t.Fatal("expected balanceColors on the common sheet")
+	}
+	if !draft.Sheets["foil"].Foil || draft.Sheets["common"].Foil {
+		t.Fatal("expected foil flag only on the foil sheet")
+	}
+	if fixed := configs["TST"]["collector"].Sheets["promo"].Fixed; fixed == nil || !*fixed {
+		t.Fatal("expected fixed flag on the collector promo sheet")
+	}
+
+	got, err := json.MarshalIndent(configs, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "allprintings_sample.golden.json", append(got, '\n'))
+}
+
+func TestParseAllPrintingsSingleSet(t *testing.T) {
+	doc := `{"data": {"code": "ONE", "booster": {"draft": {"boosters": [], "sheets": {}}}}}`
+	configs, err := ParseAllPrintings(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := configs["ONE"]["draft"]; !ok {
+		t.Fatalf("expected ONE/draft, got %v", configs)
+	}
+}
+
+func TestParseConfigs(t *testing.T) {
+	raw := `{"draft": {"boosters": [{"contents": {"common": 1}, "weight": 1}], "sheets": {"common": {"cards": {"a": 1}, "foil": false, "totalWeight": 1}}}}`
+	fromString, err := ParseConfigs(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(raw), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	fromMap, err := ParseConfigs(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fromString["draft"].Sheets["common"].Cards["a"] != 1 || fromMap["draft"].Boosters[0].Contents["common"] != 1 {
+		t.Fatalf("unexpected configs: %+v / %+v", fromString, fromMap)
+	}
+	if configs, err := ParseConfigs(nil); err != nil || configs != nil {
+		t.Fatalf("expected nil for nil value, got %v, %v", configs, err)
+	}
+	if _, err := ParseConfigs("not json"); err == nil {
+		t.Fatal("expected error for invalid JSON")
+	}
+}
+
+// loadSampleColors returns card colors from the AllPrintings fixture.
+func loadSampleColors(t *testing.T) map[string][]string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "allprintings_sample.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Data map[string]struct {
+			Cards []struct {
+				UUID   string   `json:"uuid"`
+				Colors []string `json:"colors"`
+			} `json:"cards"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	colors := make(map[string][]string)
+	for _, set := range doc.Data {
+		for _, c := range set.Cards {
+			colors[c.UUID] = c.Colors
+		}
+	}
+	return colors
+}
+
+func TestOpenPackGolden(t *testing.T) {
+	configs := loadSampleConfigs(t)
+	colors := loadSampleColors(t)
+	rng := rand.New(rand.NewSource(1))
+	var b strings.Builder
+	for _, boosterType := range []string{"draft", "collector"} {
+		config := configs["TST"][boosterType]
+		for i := 0; i < 10; i++ {
+			b.WriteString(boosterType + ": " + strings.Join(openPackUUIDs(&config, colors, rng), " ") + "\n")
+		}
+	}
+	checkGolden(t, "packs_seed1.golden", []byte(b.String()))
+}
@@ -0,0 +1,99 @@
+package booster
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+// jumpstartType is the booster type preferred by OpenJumpstartPack.
+const jumpstartType = "jumpstart"
+
+// OpenJumpstartPack opens a Jumpstart-style pack for a set. These boosters
+// contain a single themed half-deck rather than cards drawn from sheets: the
+// config's sheets list deck names and weights, and the chosen deck is read
+// from set_decks. The returned cards are the deck's main board, with each
+// card repeated by its count (20 cards for Jumpstart).
+func (bs *BoosterSimulator) OpenJumpstartPack(ctx context.Context, setCode string) (*models.JumpstartPack, error) {
+	configs, err := bs.Configs(ctx, setCode)
+	if err != nil {
+		return nil, err
+	}
+	config := deckConfig(configs)
+	if config == nil {
+		return nil, fmt.Errorf("mtgjson: no deck-based booster config for set %q", setCode)
+	}
+	theme := pickDeck(config, bs.rng)
+	if theme == "" {
+		return nil, fmt.Errorf("mtgjson: booster config for set %q has no decks", setCode)
+	}
+
+	if err := bs.conn.EnsureViews(ctx, "set_decks"); err != nil {
+		return nil, err
+	}
+	var decks []models.DeckSet
+	if err := bs.conn.ExecuteInto(ctx, &decks,
+		"SELECT code, name, type, mainBoard FROM set_decks WHERE setCode = $1 AND name = $2 ORDER BY code LIMIT 1",
+		setCode, theme); err != nil {
+		return nil, err
+	}
+	if len(decks) == 0 {
+		return nil, fmt.Errorf("mtgjson: deck %q not found in set %q", theme, setCode)
+	}
+	deck := decks[0]
+
+	var uuids []string
+	for _, c := range deck.MainBoard {
+		for i := 0; i < c.Count; i++ {
+			uuids = append(uuids, c.UUID)
+		}
+	}
+	cards, err := bs.cardsInOrder(ctx, uuids)
+	if err != nil {
+		return nil, err
+	}
+	return &models.JumpstartPack{SetCode: setCode, DeckCode: deck.Code, Theme: deck.Name, Cards: cards}, nil
+}
+
+// deckConfig returns the booster config whose sheets reference decks,
+// preferring the "jumpstart" type, or nil if there is none.
+func deckConfig(configs map[string]models.BoosterConfig) *models.BoosterConfig {
+	if config, ok := configs[jumpstartType]; ok && hasDeckSheets(config) {
+		return &config
+	}
+	for _, name := range sortedKeys(configs) {
+		if config := configs[name]; hasDeckSheets(config) {
+			return &config
+		}
+	}
+	return nil
+}
+
+func hasDeckSheets(config models.BoosterConfig) bool {
+	for _, sheet := range config.Sheets {
+		if len(sheet.Decks) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// pickDeck picks a pack template and returns a weighted pick from its first
+// deck sheet, in sheet-name order. Returns "" if the pack has no deck sheet.
+func pickDeck(config *models.BoosterConfig, rng randSource) string {
+	pack := pickPack(config.Boosters, rng)
+	if pack == nil {
+		return ""
+	}
+	for _, sheetName := range sortedKeys(pack.Contents) {
+		sheet, ok := config.Sheets[sheetName]
+		if !ok || pack.Contents[sheetName] <= 0 || len(sheet.Decks) == 0 {
+			continue
+		}
+		if picked := pickFromSheet(models.BoosterSheet{Cards: sheet.Decks}, 1, rng); len(picked) > 0 {
+			return picked[0]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,127 @@
+package booster
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/mtgjson/mtgjson-sdk-go/db"
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+const jumpstartBooster = `{
+	"draft": {
+		"boosters": [{"contents": {"common": 1}, "weight": 1}],
+		"boostersTotalWeight": 1,
+		"sheets": {"common": {"cards": {"jmp-c-1": 1}, "foil": false, "totalWeight": 1}},
+		"sourceSetCodes": ["JMP"]
+	},
+	"jumpstart": {
+		"boosters": [{"contents": {"theme": 1}, "weight": 1}],
+		"boostersTotalWeight": 1,
+		"sheets": {"theme": {"cards": {}, "decks": {"Unicorns": 1, "Dinosaurs": 3}, "foil": false, "totalWeight": 4}},
+		"sourceSetCodes": ["JMP"]
+	}
+}`
+
+// setupJumpstartDB registers a JMP set with two 20-card half-decks.
+func setupJumpstartDB(t *testing.T) *db.Connection {
+	t.Helper()
+	cfg := db.DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.Offline = true
+	cache, err := db.NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := db.NewConnection(cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	ctx := context.Background()
+	var cards []map[string]any
+	for _, uuid := range []string{"jmp-c-1", "uni-1", "uni-2", "uni-land", "dino-1", "dino-land"} {
+		cards = append(cards, map[string]any{"uuid": uuid, "name": uuid, "rarity": "common"})
+	}
+	decks := []map[string]any{
+		{"setCode": "JMP", "code": "JMP_UNICORNS", "name": "Unicorns", "type": "Jumpstart",
+			"mainBoard": []map[string]any{{"uuid": "uni-1", "count": 6}, {"uuid": "uni-2", "count": 6}, {"uuid": "uni-land", "count": 8}}},
+		{"setCode": "JMP", "code": "JMP_DINOSAURS", "name": "Dinosaurs", "type": "Jumpstart",
+			"mainBoard": []map[string]any{{"uuid": "dino-1", "count": 12}, {"uuid": "dino-land", "count": 8}}},
+	}
+	for _, td := range []struct {
+		name string
+		data []map[string]any
+	}{
+		{"sets", []map[string]any{{"code": "JMP", "booster": jumpstartBooster}}},
+		{"cards", cards},
+		{"set_decks", decks},
+	} {
+		if err := conn.RegisterTableFromData(ctx, td.name, td.data); err != nil {
+			t.Fatalf("register %s: %v", td.name, err)
+		}
+	}
+	return conn
+}
+
+func TestOpenJumpstartPack(t *testing.T) {
+	bs := NewBoosterSimulator(setupJumpstartDB(t), WithRand(rand.New(rand.NewSource(1))))
+	themes := make(map[string]int)
+	for i := 0; i < 20; i++ {
+		pack, err := bs.OpenJumpstartPack(context.Background(), "JMP")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pack.Cards) != 20 {
+			t.Fatalf("expected a 20-card half-deck, got %d cards", len(pack.Cards))
+		}
+		prefix := map[string]string{"Unicorns": "uni-", "Dinosaurs": "dino-"}[pack.Theme]
+		if prefix == "" {
+			t.Fatalf("unexpected theme %q", pack.Theme)
+		}
+		for _, c := range pack.Cards {
+			if c.UUID[:len(prefix)] != prefix {
+				t.Fatalf("card %s does not belong to theme %s", c.UUID, pack.Theme)
+			}
+		}
+		themes[pack.Theme]++
+	}
+	if len(themes) != 2 {
+		t.Fatalf("expected both themes over 20 packs, got %v", themes)
+	}
+}
+
+func TestOpenJumpstartPackNoDeckConfig(t *testing.T) {
+	bs := NewBoosterSimulator(setupJumpstartDB(t))
+	if _, err := bs.OpenJumpstartPack(context.Background(), "NOPE"); err == nil {
+		t.Fatal("expected error for a set without booster data")
+	}
+}
+
+func TestDeckConfig(t *testing.T) {
+	configs := map[string]models.BoosterConfig{
+		"draft": {Sheets: map[string]models.BoosterSheet{"common": {Cards: map[string]int{"a": 1}}}},
+	}
+	if deckConfig(configs) != nil {
+		t.Fatal("expected no deck config for card-only sheets")
+	}
+	configs["arena"] = models.BoosterConfig{Sheets: map[string]models.BoosterSheet{"theme": {Decks: map[string]int{"Elves": 1}}}}
+	if got := deckConfig(configs); got == nil || got.Sheets["theme"].Decks["Elves"] != 1 {
+		t.Fatalf("expected the deck-based config, got %+v", got)
+	}
+}
+
+func TestPickDeckSkipsCardSheets(t *testing.T) {
+	config := &models.BoosterConfig{
+		Boosters: []models.BoosterPack{{Contents: map[string]int{"common": 1, "theme": 1}, Weight: 1}},
+		Sheets: map[string]models.BoosterSheet{
+			"common": {Cards: map[string]int{"a": 1}},
+			"theme":  {Decks: map[string]int{"Elves": 1}},
+		},
+	}
+	if got := pickDeck(config, globalRand{}); got != "Elves" {
+		t.Fatalf("expected Elves, got %q", got)
+	}
+}
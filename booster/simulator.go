@@ -2,9 +2,9 @@ package booster
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"math/rand"
+	"sync"
 
 	"github.com/mtgjson/mtgjson-sdk-go/db"
 	"github.com/mtgjson/mtgjson-sdk-go/models"
@@ -13,20 +13,77 @@ import (
 // BoosterSimulator simulates opening booster packs using set booster configuration data.
 // Uses weighted random selection based on the booster field in set data.
 // Requires the booster column (present in AllPrintings, but NOT in the flat sets.parquet from CDN).
+// A BoosterSimulator is safe for concurrent use by multiple goroutines.
 type BoosterSimulator struct {
-	conn *db.Connection
+	conn db.Backend
+	rng  randSource
 }
 
-func NewBoosterSimulator(conn *db.Connection) *BoosterSimulator {
-	return &BoosterSimulator{conn: conn}
+// randSource is the subset of *rand.Rand used by the simulator.
+type randSource interface {
+	Float64() float64
+	Int63() int64
+	Shuffle(n int, swap func(i, j int))
+}
+
+// globalRand uses the math/rand package-level source.
+type globalRand struct{}
+
+func (globalRand) Float64() float64                   { return rand.Float64() }
+func (globalRand) Int63() int64                       { return rand.Int63() }
+func (globalRand) Shuffle(n int, swap func(i, j int)) { rand.Shuffle(n, swap) }
+
+// Option configures a BoosterSimulator.
+type Option func(*BoosterSimulator)
+
+// lockedRand serializes access to a *rand.Rand, which is not safe for
+// concurrent use on its own.
+type lockedRand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+func (l *lockedRand) Float64() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Float64()
+}
+
+func (l *lockedRand) Int63() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Int63()
+}
+
+func (l *lockedRand) Shuffle(n int, swap func(i, j int)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.r.Shuffle(n, swap)
+}
+
+// WithRand sets the random source used to pick packs and cards. Pass a seeded
+// source for reproducible packs. Access to r is serialized, so the simulator
+// stays safe for concurrent use; packs are only reproducible when opened from
+// a single goroutine.
+func WithRand(r *rand.Rand) Option {
+	return func(bs *BoosterSimulator) { bs.rng = &lockedRand{r: r} }
+}
+
+func NewBoosterSimulator(conn db.Backend, opts ...Option) *BoosterSimulator {
+	bs := &BoosterSimulator{conn: conn, rng: globalRand{}}
+	for _, opt := range opts {
+		opt(bs)
+	}
+	return bs
 }
 
 func (bs *BoosterSimulator) ensure(ctx context.Context) error {
 	return bs.conn.EnsureViews(ctx, "sets", "cards")
 }
 
-// getBoosterConfig returns the booster configuration for a set.
-func (bs *BoosterSimulator) getBoosterConfig(ctx context.Context, setCode string) (map[string]any, error) {
+// Configs returns the booster configurations for a set keyed by booster type,
+// or nil if the set has no booster data.
+func (bs *BoosterSimulator) Configs(ctx context.Context, setCode string) (map[string]models.BoosterConfig, error) {
 	if err := bs.ensure(ctx); err != nil {
 		return nil, err
 	}
@@ -37,80 +94,88 @@ func (bs *BoosterSimulator) getBoosterConfig(ctx context.Context, setCode string
 	if len(rows) == 0 {
 		return nil, nil
 	}
-	boosterRaw := rows[0]["booster"]
-	if boosterRaw == nil {
-		return nil, nil
-	}
-	// May be a string (JSON), map, or DuckDB struct
-	return extractBoosterConfig(boosterRaw), nil
+	return ParseConfigs(rows[0]["booster"])
 }
 
 // AvailableTypes lists available booster types for a set.
 func (bs *BoosterSimulator) AvailableTypes(ctx context.Context, setCode string) ([]string, error) {
-	config, err := bs.getBoosterConfig(ctx, setCode)
+	configs, err := bs.Configs(ctx, setCode)
 	if err != nil {
 		return nil, err
 	}
-	if config == nil {
+	if configs == nil {
 		return nil, nil
 	}
-	types := make([]string, 0, len(config))
-	for k := range config {
-		types = append(types, k)
+	return sortedKeys(configs), nil
+}
+
+// Catalog lists the booster types of every set that has booster data, keyed
+// by set code, in one query. Returns nil if the sets data has no booster
+// column.
+func (bs *BoosterSimulator) Catalog(ctx context.Context) (map[string][]string, error) {
+	if err := bs.conn.EnsureViews(ctx, "sets"); err != nil {
+		return nil, err
+	}
+	hasBooster, err := bs.conn.ExecuteScalar(ctx,
+		"SELECT COUNT(*) FROM information_schema.columns WHERE table_name = 'sets' AND column_name = 'booster'")
+	if err != nil {
+		return nil, err
+	}
+	if db.ScalarToInt(hasBooster) == 0 {
+		return nil, nil
+	}
+	rows, err := bs.conn.Execute(ctx, "SELECT code, booster FROM sets WHERE booster IS NOT NULL")
+	if err != nil {
+		return nil, err
+	}
+	catalog := make(map[string][]string, len(rows))
+	for _, row := range rows {
+		code, _ := row["code"].(string)
+		types, err := parseTypes(row["booster"])
+		if err != nil {
+			return nil, err
+		}
+		if len(types) > 0 {
+			catalog[code] = types
+		}
 	}
-	return types, nil
+	return catalog, nil
 }
 
-// OpenPack simulates opening a single booster pack.
-func (bs *BoosterSimulator) OpenPack(ctx context.Context, setCode, boosterType string) ([]models.CardSet, error) {
-	configs, err := bs.getBoosterConfig(ctx, setCode)
+// Config returns the configuration for one booster type of a set.
+func (bs *BoosterSimulator) Config(ctx context.Context, setCode, boosterType string) (*models.BoosterConfig, error) {
+	configs, err := bs.Configs(ctx, setCode)
 	if err != nil {
 		return nil, err
 	}
 	if configs == nil {
 		return nil, fmt.Errorf("mtgjson: no booster config for set %q", setCode)
 	}
-	configRaw, ok := configs[boosterType]
+	config, ok := configs[boosterType]
 	if !ok {
-		types := make([]string, 0, len(configs))
-		for k := range configs {
-			types = append(types, k)
-		}
-		return nil, fmt.Errorf("mtgjson: no booster type %q for set %q; available: %v", boosterType, setCode, types)
-	}
-	config, ok := configRaw.(map[string]any)
-	if !ok {
-		return nil, fmt.Errorf("mtgjson: invalid booster config type for %q/%q", setCode, boosterType)
+		return nil, fmt.Errorf("mtgjson: no booster type %q for set %q; available: %v", boosterType, setCode, sortedKeys(configs))
 	}
+	return &config, nil
+}
 
-	boostersRaw, _ := config["boosters"].([]any)
-	sheetsRaw, _ := config["sheets"].(map[string]any)
-
-	// Pick a pack template
-	packTemplate := pickPack(boostersRaw)
-	if packTemplate == nil {
-		return nil, nil
+// OpenPack simulates opening a single booster pack. Fixed sheets always
+// contribute all of their cards, and color-balanced sheets guarantee one
+// monocolored card of each color when five or more cards are drawn from them.
+func (bs *BoosterSimulator) OpenPack(ctx context.Context, setCode, boosterType string) ([]models.CardSet, error) {
+	config, err := bs.Config(ctx, setCode, boosterType)
+	if err != nil {
+		return nil, err
 	}
-
-	contents, _ := packTemplate["contents"].(map[string]any)
-	var cardUUIDs []string
-	for sheetName, countRaw := range contents {
-		count := db.ToInt(countRaw)
-		if count <= 0 {
-			continue
-		}
-		sheetRaw, ok := sheetsRaw[sheetName]
-		if !ok {
-			continue
-		}
-		sheet, ok := sheetRaw.(map[string]any)
-		if !ok {
-			continue
-		}
-		picked := pickFromSheet(sheet, count)
-		cardUUIDs = append(cardUUIDs, picked...)
+	colors, err := bs.sheetColors(ctx, config)
+	if err != nil {
+		return nil, err
 	}
+	return bs.cardsInOrder(ctx, openPackUUIDs(config, colors, bs.rng))
+}
 
+// cardsInOrder fetches the cards for uuids, preserving their order and
+// repeating cards whose UUID appears more than once.
+func (bs *BoosterSimulator) cardsInOrder(ctx context.Context, cardUUIDs []string) ([]models.CardSet, error) {
 	if len(cardUUIDs) == 0 {
 		return nil, nil
 	}
@@ -146,6 +211,85 @@ func (bs *BoosterSimulator) OpenPack(ctx context.Context, setCode, boosterType s
 	return ordered, nil
 }
 
+// sheetColors loads card colors for the cards on color-balanced sheets.
+// Returns nil if no sheet is balanced.
+func (bs *BoosterSimulator) sheetColors(ctx context.Context, config *models.BoosterConfig) (map[string][]string, error) {
+	var uuids []any
+	for _, sheet := range config.Sheets {
+		if sheet.BalanceColors == nil || !*sheet.BalanceColors {
+			continue
+		}
+		for uuid := range sheet.Cards {
+			uuids = append(uuids, uuid)
+		}
+	}
+	if len(uuids) == 0 {
+		return nil, nil
+	}
+	sql, params := db.NewSQLBuilder("cards").Select("uuid", "colors").WhereIn("uuid", uuids).Build()
+	var rows []struct {
+		UUID   string   `json:"uuid"`
+		Colors []string `json:"colors"`
+	}
+	if err := bs.conn.ExecuteInto(ctx, &rows, sql, params...); err != nil {
+		return nil, err
+	}
+	colors := make(map[string][]string, len(rows))
+	for _, r := range rows {
+		colors[r.UUID] = r.Colors
+	}
+	return colors, nil
+}
+
+// pull is one card drawn into a pack; foil is set for cards from foil sheets.
+type pull struct {
+	uuid string
+	foil bool
+}
+
+// openPackUUIDs picks a pack template and draws its cards, in sheet-name order.
+// colors maps card UUIDs to their colors for color-balanced sheets.
+func openPackUUIDs(config *models.BoosterConfig, colors map[string][]string, rng randSource) []string {
+	pulls := openPackPulls(config, colors, rng)
+	uuids := make([]string, len(pulls))
+	for i, p := range pulls {
+		uuids[i] = p.uuid
+	}
+	return uuids
+}
+
+// openPackPulls is openPackUUIDs with each card's foil treatment.
+func openPackPulls(config *models.BoosterConfig, colors map[string][]string, rng randSource) []pull {
+	pack := pickPack(config.Boosters, rng)
+	if pack == nil {
+		return nil
+	}
+	var pulls []pull
+	for _, sheetName := range sortedKeys(pack.Contents) {
+		count := pack.Contents[sheetName]
+		if count <= 0 {
+			continue
+		}
+		sheet, ok := config.Sheets[sheetName]
+		if !ok {
+			continue
+		}
+		var uuids []string
+		switch {
+		case sheet.Fixed != nil && *sheet.Fixed:
+			uuids = fixedSheetCards(sheet)
+		case sheet.BalanceColors != nil && *sheet.BalanceColors:
+			uuids = pickBalanced(sheet, count, colors, rng)
+		default:
+			uuids = pickFromSheet(sheet, count, rng)
+		}
+		for _, uuid := range uuids {
+			pulls = append(pulls, pull{uuid: uuid, foil: sheet.Foil})
+		}
+	}
+	return pulls
+}
+
 // OpenBox simulates opening a booster box (multiple packs).
 func (bs *BoosterSimulator) OpenBox(ctx context.Context, setCode, boosterType string, packs int) ([][]models.CardSet, error) {
 	if packs <= 0 {
@@ -164,115 +308,231 @@ func (bs *BoosterSimulator) OpenBox(ctx context.Context, setCode, boosterType st
 
 // SheetContents returns the card UUIDs and weights for a specific booster sheet.
 func (bs *BoosterSimulator) SheetContents(ctx context.Context, setCode, boosterType, sheetName string) (map[string]int, error) {
-	configs, err := bs.getBoosterConfig(ctx, setCode)
+	configs, err := bs.Configs(ctx, setCode)
 	if err != nil {
 		return nil, err
 	}
-	if configs == nil {
+	sheet, ok := configs[boosterType].Sheets[sheetName]
+	if !ok || sheet.Cards == nil {
 		return nil, nil
 	}
-	configRaw, ok := configs[boosterType]
-	if !ok {
-		return nil, nil
+	result := make(map[string]int, len(sheet.Cards))
+	for uuid, weight := range sheet.Cards {
+		result[uuid] = weight
 	}
-	config, ok := configRaw.(map[string]any)
-	if !ok {
-		return nil, nil
+	return result, nil
+}
+
+// ExpectedValue returns the expected retail value of a single pack, computed
+// exactly from the pack template and sheet weights rather than by sampling.
+// Cards on foil sheets are priced with foil prices, all others with normal
+// prices, using the latest retail prices from the given provider. Cards
+// without a price contribute zero.
+func (bs *BoosterSimulator) ExpectedValue(ctx context.Context, setCode, boosterType, provider string) (float64, error) {
+	config, err := bs.Config(ctx, setCode, boosterType)
+	if err != nil {
+		return 0, err
 	}
-	sheetsRaw, _ := config["sheets"].(map[string]any)
-	sheetRaw, ok := sheetsRaw[sheetName]
-	if !ok {
-		return nil, nil
+	if err := bs.conn.EnsureViews(ctx, "all_prices_today"); err != nil {
+		return 0, err
 	}
-	sheet, ok := sheetRaw.(map[string]any)
-	if !ok {
-		return nil, nil
+
+	var uuids []any
+	seen := make(map[string]bool)
+	for _, sheet := range config.Sheets {
+		for uuid := range sheet.Cards {
+			if !seen[uuid] {
+				seen[uuid] = true
+				uuids = append(uuids, uuid)
+			}
+		}
 	}
-	cardsRaw, _ := sheet["cards"].(map[string]any)
-	if cardsRaw == nil {
-		return nil, nil
+	if len(uuids) == 0 {
+		return 0, nil
 	}
-	result := make(map[string]int, len(cardsRaw))
-	for uuid, weightRaw := range cardsRaw {
-		result[uuid] = db.ToInt(weightRaw)
+
+	prices, err := bs.retailPrices(ctx, uuids, provider)
+	if err != nil {
+		return 0, err
 	}
-	return result, nil
+	return expectedPackValue(config, prices), nil
+}
+
+// retailPrices returns the latest retail prices from provider for the given
+// card UUIDs, keyed by "uuid|finish".
+func (bs *BoosterSimulator) retailPrices(ctx context.Context, uuids []any, provider string) (map[string]float64, error) {
+	b := db.NewSQLBuilder(db.LatestPricesTable).
+		Select("uuid", "finish", "price").
+		WhereEq("provider", provider).
+		WhereEq("price_type", "retail").
+		WhereIn("uuid", uuids)
+	sql, params := b.Build()
+	rows, err := bs.conn.Execute(ctx, sql, params...)
+	if err != nil {
+		return nil, err
+	}
+	prices := make(map[string]float64, len(rows))
+	for _, r := range rows {
+		uuid, _ := r["uuid"].(string)
+		finish, _ := r["finish"].(string)
+		prices[uuid+"|"+finish] = db.ToFloat64(r["price"])
+	}
+	return prices, nil
+}
+
+// expectedPackValue computes the expected value of a pack from its booster
+// configuration. prices is keyed by "uuid|finish".
+func expectedPackValue(config *models.BoosterConfig, prices map[string]float64) float64 {
+	sheetValues := make(map[string]float64, len(config.Sheets))
+	for name, sheet := range config.Sheets {
+		finish := "normal"
+		if sheet.Foil {
+			finish = "foil"
+		}
+		totalWeight, weighted := 0.0, 0.0
+		for uuid, weight := range sheet.Cards {
+			w := float64(weight)
+			totalWeight += w
+			weighted += w * prices[uuid+"|"+finish]
+		}
+		if totalWeight > 0 {
+			sheetValues[name] = weighted / totalWeight
+		}
+	}
+
+	totalPackWeight := 0.0
+	value := 0.0
+	for _, pack := range config.Boosters {
+		w := packWeight(pack)
+		totalPackWeight += w
+		packValue := 0.0
+		for sheetName, count := range pack.Contents {
+			packValue += float64(count) * sheetValues[sheetName]
+		}
+		value += w * packValue
+	}
+	if totalPackWeight == 0 {
+		return 0
+	}
+	return value / totalPackWeight
+}
+
+// packWeight returns a pack template's selection weight, treating a missing
+// weight as 1.
+func packWeight(pack models.BoosterPack) float64 {
+	if pack.Weight <= 0 {
+		return 1
+	}
+	return float64(pack.Weight)
 }
 
 // pickPack does a weighted random selection of a pack template.
-func pickPack(boosters []any) map[string]any {
+func pickPack(boosters []models.BoosterPack, rng randSource) *models.BoosterPack {
 	if len(boosters) == 0 {
 		return nil
 	}
-	type entry struct {
-		pack   map[string]any
-		weight float64
-	}
-	var entries []entry
 	totalWeight := 0.0
 	for _, b := range boosters {
-		m, ok := b.(map[string]any)
-		if !ok {
-			continue
+		totalWeight += packWeight(b)
+	}
+	r := rng.Float64() * totalWeight
+	cumulative := 0.0
+	for i := range boosters {
+		cumulative += packWeight(boosters[i])
+		if r < cumulative {
+			return &boosters[i]
 		}
-		w := db.ToFloat64(m["weight"])
-		if w <= 0 {
-			w = 1
+	}
+	return &boosters[len(boosters)-1]
+}
+
+// fixedSheetCards returns the contents of a fixed sheet: every card, repeated
+// as many times as its value in the cards map, regardless of the pack count.
+func fixedSheetCards(sheet models.BoosterSheet) []string {
+	var uuids []string
+	for _, uuid := range sortedKeys(sheet.Cards) {
+		for n := 0; n < sheet.Cards[uuid]; n++ {
+			uuids = append(uuids, uuid)
 		}
-		entries = append(entries, entry{pack: m, weight: w})
-		totalWeight += w
 	}
-	if len(entries) == 0 {
-		return nil
+	return uuids
+}
+
+// balancedColors are the colors guaranteed by a color-balanced sheet.
+var balancedColors = []string{"W", "U", "B", "R", "G"}
+
+// pickBalanced draws count cards from a color-balanced sheet: when count is at
+// least five, it first draws one monocolored card of each color (skipping
+// colors the sheet lacks), then fills the remaining slots from the whole
+// sheet without repeating cards unless the sheet allows duplicates, and
+// shuffles the result.
+func pickBalanced(sheet models.BoosterSheet, count int, colors map[string][]string, rng randSource) []string {
+	if count < len(balancedColors) || colors == nil {
+		return pickFromSheet(sheet, count, rng)
+	}
+	allowDuplicates := sheet.AllowDuplicates != nil && *sheet.AllowDuplicates
+	var picked []string
+	for _, color := range balancedColors {
+		var items []string
+		var weights []float64
+		for _, uuid := range sortedKeys(sheet.Cards) {
+			c := colors[uuid]
+			if len(c) == 1 && c[0] == color {
+				items = append(items, uuid)
+				weights = append(weights, float64(sheet.Cards[uuid]))
+			}
+		}
+		if len(items) > 0 {
+			picked = append(picked, weightedChoicesWithReplacement(items, weights, 1, rng)...)
+		}
 	}
-	r := rand.Float64() * totalWeight
-	cumulative := 0.0
-	for _, e := range entries {
-		cumulative += e.weight
-		if r < cumulative {
-			return e.pack
+
+	rest := sheet
+	if !allowDuplicates {
+		rest.Cards = make(map[string]int, len(sheet.Cards))
+		for uuid, w := range sheet.Cards {
+			rest.Cards[uuid] = w
+		}
+		for _, uuid := range picked {
+			delete(rest.Cards, uuid)
 		}
 	}
-	return entries[len(entries)-1].pack
+	picked = append(picked, pickFromSheet(rest, count-len(picked), rng)...)
+	rng.Shuffle(len(picked), func(i, j int) { picked[i], picked[j] = picked[j], picked[i] })
+	return picked
 }
 
 // pickFromSheet does weighted random selection of cards from a sheet.
-func pickFromSheet(sheet map[string]any, count int) []string {
-	cardsRaw, _ := sheet["cards"].(map[string]any)
-	if cardsRaw == nil {
+func pickFromSheet(sheet models.BoosterSheet, count int, rng randSource) []string {
+	if len(sheet.Cards) == 0 {
 		return nil
 	}
-	allowDuplicates, _ := sheet["allowDuplicates"].(bool)
-
-	uuids := make([]string, 0, len(cardsRaw))
-	weights := make([]float64, 0, len(cardsRaw))
-	for uuid, weightRaw := range cardsRaw {
-		uuids = append(uuids, uuid)
-		weights = append(weights, db.ToFloat64(weightRaw))
+	uuids := sortedKeys(sheet.Cards)
+	weights := make([]float64, len(uuids))
+	for i, uuid := range uuids {
+		weights[i] = float64(sheet.Cards[uuid])
 	}
 
-	if allowDuplicates {
-		return weightedChoicesWithReplacement(uuids, weights, count)
+	if sheet.AllowDuplicates != nil && *sheet.AllowDuplicates {
+		return weightedChoicesWithReplacement(uuids, weights, count, rng)
 	}
 
 	if count >= len(uuids) {
-		result := make([]string, len(uuids))
-		copy(result, uuids)
-		rand.Shuffle(len(result), func(i, j int) { result[i], result[j] = result[j], result[i] })
-		return result
+		rng.Shuffle(len(uuids), func(i, j int) { uuids[i], uuids[j] = uuids[j], uuids[i] })
+		return uuids
 	}
 
-	return weightedChoicesWithoutReplacement(uuids, weights, count)
+	return weightedChoicesWithoutReplacement(uuids, weights, count, rng)
 }
 
-func weightedChoicesWithReplacement(items []string, weights []float64, count int) []string {
+func weightedChoicesWithReplacement(items []string, weights []float64, count int, rng randSource) []string {
 	totalWeight := 0.0
 	for _, w := range weights {
 		totalWeight += w
 	}
 	result := make([]string, count)
 	for i := 0; i < count; i++ {
-		r := rand.Float64() * totalWeight
+		r := rng.Float64() * totalWeight
 		cumulative := 0.0
 		for j, w := range weights {
 			cumulative += w
@@ -288,7 +548,7 @@ func weightedChoicesWithReplacement(items []string, weights []float64, count int
 	return result
 }
 
-func weightedChoicesWithoutReplacement(items []string, weights []float64, count int) []string {
+func weightedChoicesWithoutReplacement(items []string, weights []float64, count int, rng randSource) []string {
 	remaining := make([]string, len(items))
 	copy(remaining, items)
 	remainingWeights := make([]float64, len(weights))
@@ -300,7 +560,7 @@ func weightedChoicesWithoutReplacement(items []string, weights []float64, count
 		for _, w := range remainingWeights {
 			totalWeight += w
 		}
-		r := rand.Float64() * totalWeight
+		r := rng.Float64() * totalWeight
 		cumulative := 0.0
 		idx := len(remaining) - 1
 		for j, w := range remainingWeights {
@@ -316,27 +576,3 @@ func weightedChoicesWithoutReplacement(items []string, weights []float64, count
 	}
 	return picked
 }
-
-func extractBoosterConfig(v any) map[string]any {
-	if v == nil {
-		return nil
-	}
-	if m, ok := v.(map[string]any); ok {
-		return m
-	}
-	if s, ok := v.(string); ok {
-		var m map[string]any
-		if err := json.Unmarshal([]byte(s), &m); err == nil {
-			return m
-		}
-	}
-	b, err := json.Marshal(v)
-	if err != nil {
-		return nil
-	}
-	var m map[string]any
-	if err := json.Unmarshal(b, &m); err != nil {
-		return nil
-	}
-	return m
-}
@@ -1,35 +1,37 @@
 package booster
 
 import (
+	"context"
 	"testing"
+
+	"github.com/mtgjson/mtgjson-sdk-go/models"
 )
 
+func boolPtr(b bool) *bool { return &b }
+
 func TestPickPackWeighted(t *testing.T) {
-	boosters := []any{
-		map[string]any{"contents": map[string]any{"rare": 1.0, "common": 10.0}, "weight": 7.0},
-		map[string]any{"contents": map[string]any{"mythic": 1.0, "common": 10.0}, "weight": 1.0},
+	boosters := []models.BoosterPack{
+		{Contents: map[string]int{"rare": 1, "common": 10}, Weight: 7},
+		{Contents: map[string]int{"mythic": 1, "common": 10}, Weight: 1},
 	}
-	pack := pickPack(boosters)
+	pack := pickPack(boosters, globalRand{})
 	if pack == nil {
 		t.Fatal("expected non-nil pack")
 	}
-	if _, ok := pack["contents"]; !ok {
-		t.Fatal("expected contents key")
+	if pack.Contents == nil {
+		t.Fatal("expected contents")
 	}
-	if _, ok := pack["weight"]; !ok {
-		t.Fatal("expected weight key")
+	if pack.Weight == 0 {
+		t.Fatal("expected weight")
 	}
 }
 
 func TestPickFromSheetBasic(t *testing.T) {
-	sheet := map[string]any{
-		"cards": map[string]any{
-			"uuid-a": 10.0, "uuid-b": 5.0, "uuid-c": 1.0,
-		},
-		"foil":        false,
-		"totalWeight": 16.0,
+	sheet := models.BoosterSheet{
+		Cards:       map[string]int{"uuid-a": 10, "uuid-b": 5, "uuid-c": 1},
+		TotalWeight: 16,
 	}
-	picked := pickFromSheet(sheet, 2)
+	picked := pickFromSheet(sheet, 2, globalRand{})
 	if len(picked) != 2 {
 		t.Fatalf("expected 2 picks, got %d", len(picked))
 	}
@@ -42,14 +44,11 @@ func TestPickFromSheetBasic(t *testing.T) {
 }
 
 func TestPickFromSheetNoDuplicates(t *testing.T) {
-	sheet := map[string]any{
-		"cards": map[string]any{
-			"uuid-a": 1.0, "uuid-b": 1.0, "uuid-c": 1.0,
-		},
-		"foil":        false,
-		"totalWeight": 3.0,
+	sheet := models.BoosterSheet{
+		Cards:       map[string]int{"uuid-a": 1, "uuid-b": 1, "uuid-c": 1},
+		TotalWeight: 3,
 	}
-	picked := pickFromSheet(sheet, 3)
+	picked := pickFromSheet(sheet, 3, globalRand{})
 	if len(picked) != 3 {
 		t.Fatalf("expected 3 picks, got %d", len(picked))
 	}
@@ -63,15 +62,12 @@ func TestPickFromSheetNoDuplicates(t *testing.T) {
 }
 
 func TestPickFromSheetWithDuplicates(t *testing.T) {
-	sheet := map[string]any{
-		"cards": map[string]any{
-			"uuid-a": 1.0,
-		},
-		"foil":            false,
-		"totalWeight":     1.0,
-		"allowDuplicates": true,
+	sheet := models.BoosterSheet{
+		Cards:           map[string]int{"uuid-a": 1},
+		TotalWeight:     1,
+		AllowDuplicates: boolPtr(true),
 	}
-	picked := pickFromSheet(sheet, 3)
+	picked := pickFromSheet(sheet, 3, globalRand{})
 	if len(picked) != 3 {
 		t.Fatalf("expected 3 picks, got %d", len(picked))
 	}
@@ -83,20 +79,135 @@ func TestPickFromSheetWithDuplicates(t *testing.T) {
 }
 
 func TestPickPackEmpty(t *testing.T) {
-	pack := pickPack(nil)
+	pack := pickPack(nil, globalRand{})
 	if pack != nil {
 		t.Fatalf("expected nil, got %v", pack)
 	}
 }
 
 func TestPickFromSheetMoreThanAvailable(t *testing.T) {
-	sheet := map[string]any{
-		"cards": map[string]any{
-			"uuid-a": 1.0, "uuid-b": 1.0,
-		},
+	sheet := models.BoosterSheet{
+		Cards: map[string]int{"uuid-a": 1, "uuid-b": 1},
 	}
-	picked := pickFromSheet(sheet, 5)
+	picked := pickFromSheet(sheet, 5, globalRand{})
 	if len(picked) != 2 {
 		t.Fatalf("expected 2 picks (all available), got %d", len(picked))
 	}
 }
+
+func TestExpectedPackValue(t *testing.T) {
+	config := &models.BoosterConfig{
+		Boosters: []models.BoosterPack{
+			{Contents: map[string]int{"common": 2, "rare": 1}, Weight: 3},
+			{Contents: map[string]int{"common": 2, "foil": 1}, Weight: 1},
+		},
+		Sheets: map[string]models.BoosterSheet{
+			"common": {Cards: map[string]int{"uuid-a": 1, "uuid-b": 1}},
+			"rare":   {Cards: map[string]int{"uuid-c": 1}},
+			"foil":   {Cards: map[string]int{"uuid-c": 1}, Foil: true},
+		},
+	}
+	prices := map[string]float64{
+		"uuid-a|normal": 1.0,
+		"uuid-b|normal": 3.0,
+		"uuid-c|normal": 10.0,
+		"uuid-c|foil":   30.0,
+	}
+	// common sheet EV = 2.0; pack 1 = 2*2 + 10 = 14; pack 2 = 2*2 + 30 = 34
+	// weighted: (3*14 + 1*34) / 4 = 19
+	got := expectedPackValue(config, prices)
+	if got != 19.0 {
+		t.Fatalf("expected 19.0, got %v", got)
+	}
+}
+
+func TestExpectedPackValueMissingPrices(t *testing.T) {
+	config := &models.BoosterConfig{
+		Boosters: []models.BoosterPack{
+			{Contents: map[string]int{"common": 1}, Weight: 1},
+		},
+		Sheets: map[string]models.BoosterSheet{
+			"common": {Cards: map[string]int{"uuid-a": 1}},
+		},
+	}
+	if got := expectedPackValue(config, nil); got != 0 {
+		t.Fatalf("expected 0, got %v", got)
+	}
+}
+
+func TestFixedSheetCards(t *testing.T) {
+	sheet := models.BoosterSheet{Cards: map[string]int{"uuid-b": 1, "uuid-a": 2}, Fixed: boolPtr(true)}
+	got := fixedSheetCards(sheet)
+	if len(got) != 3 || got[0] != "uuid-a" || got[1] != "uuid-a" || got[2] != "uuid-b" {
+		t.Fatalf("unexpected fixed sheet contents: %v", got)
+	}
+	config := &models.BoosterConfig{
+		Boosters: []models.BoosterPack{{Contents: map[string]int{"fixed": 1}, Weight: 1}},
+		Sheets:   map[string]models.BoosterSheet{"fixed": sheet},
+	}
+	if pack := openPackUUIDs(config, nil, globalRand{}); len(pack) != 3 {
+		t.Fatalf("expected all fixed cards regardless of pack count, got %v", pack)
+	}
+}
+
+func TestPickBalancedCoversEveryColor(t *testing.T) {
+	// One card of each color against many heavily weighted colorless cards:
+	// without balancing a five-card draw would rarely contain every color.
+	sheet := models.BoosterSheet{Cards: map[string]int{"w": 1, "u": 1, "b": 1, "r": 1, "g": 1}, BalanceColors: boolPtr(true)}
+	colors := map[string][]string{"w": {"W"}, "u": {"U"}, "b": {"B"}, "r": {"R"}, "g": {"G"}}
+	for i := 0; i < 20; i++ {
+		id := "a" + string(rune('0'+i%10)) + string(rune('0'+i/10))
+		sheet.Cards[id] = 100
+		colors[id] = nil
+	}
+	for i := 0; i < 50; i++ {
+		picked := pickBalanced(sheet, 6, colors, globalRand{})
+		if len(picked) != 6 {
+			t.Fatalf("expected 6 cards, got %v", picked)
+		}
+		seen := make(map[string]bool)
+		for _, uuid := range picked {
+			if seen[uuid] {
+				t.Fatalf("duplicate card on a no-duplicates sheet: %v", picked)
+			}
+			seen[uuid] = true
+		}
+		for _, c := range []string{"w", "u", "b", "r", "g"} {
+			if !seen[c] {
+				t.Fatalf("balanced pack missing %s: %v", c, picked)
+			}
+		}
+	}
+}
+
+func TestPickBalancedSmallCount(t *testing.T) {
+	sheet := models.BoosterSheet{Cards: map[string]int{"w": 1, "u": 1, "b": 1}, BalanceColors: boolPtr(true)}
+	colors := map[string][]string{"w": {"W"}, "u": {"U"}, "b": {"B"}}
+	if picked := pickBalanced(sheet, 2, colors, globalRand{}); len(picked) != 2 {
+		t.Fatalf("expected 2 cards, got %v", picked)
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	bs := NewBoosterSimulator(setupJumpstartDB(t))
+	catalog, err := bs.Catalog(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := catalog["JMP"]
+	if len(catalog) != 1 || len(types) != 2 || types[0] != "draft" || types[1] != "jumpstart" {
+		t.Errorf("expected JMP with draft and jumpstart, got %v", catalog)
+	}
+}
+
+func TestCatalogWithoutBoosterColumn(t *testing.T) {
+	conn := setupBoxDB(t)
+	ctx := context.Background()
+	if err := conn.RegisterTableFromData(ctx, "sets", []map[string]any{{"code": "TST", "name": "Test Set"}}); err != nil {
+		t.Fatal(err)
+	}
+	catalog, err := NewBoosterSimulator(conn).Catalog(ctx)
+	if err != nil || catalog != nil {
+		t.Errorf("expected nil for sets without booster data, got %v, %v", catalog, err)
+	}
+}
@@ -0,0 +1,46 @@
+// Command mtgjson-warm downloads MTGJSON datasets into a cache directory and
+// verifies them, for init containers and image builds:
+//
+//	mtgjson-warm -dir /cache cards sets all_prices_today
+//
+// It prints the MTGJSON version on success and exits with status 1 on
+// failure. With no datasets it warms cards and sets.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	mtgjson "github.com/mtgjson/mtgjson-sdk-go"
+)
+
+func main() {
+	dir := flag.String("dir", os.Getenv("MTGJSON_CACHE_DIR"), "cache directory (default $MTGJSON_CACHE_DIR)")
+	timeout := flag.Duration("timeout", 30*time.Minute, "give up after this long")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s -dir DIR [dataset ...]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *dir == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+
+	version, err := mtgjson.WarmCache(ctx, *dir, flag.Args()...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Println(version)
+}
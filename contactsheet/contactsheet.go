@@ -0,0 +1,208 @@
+// Package contactsheet renders a list of cards, such as an opened booster
+// pack, as one composite image or an HTML page of Scryfall card images, for
+// sharing simulated pack openings.
+package contactsheet
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/jpeg" // Scryfall serves card images as JPEG
+	_ "image/png"
+	"io"
+	"os"
+
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+// Scryfall image versions accepted by WithImageSize.
+const (
+	Small  = "small"  // 146x204
+	Normal = "normal" // 488x680
+	Large  = "large"  // 672x936
+)
+
+const scryfallImageURL = "https://cards.scryfall.io/%s/front/%s/%s/%s.jpg"
+
+// tileSizes are the pixel sizes of the Scryfall image versions.
+var tileSizes = map[string]image.Point{
+	Small:  {146, 204},
+	Normal: {488, 680},
+	Large:  {672, 936},
+}
+
+const (
+	defaultColumns = 5 // a 15-card pack is three rows
+	gap            = 8
+)
+
+var (
+	background  = color.RGBA{0x20, 0x20, 0x20, 0xff}
+	placeholder = color.RGBA{0x60, 0x60, 0x60, 0xff}
+)
+
+// Fetcher downloads an image URL to a local file, reusing earlier
+// downloads. *db.CacheManager implements it with EnsureImage.
+type Fetcher interface {
+	EnsureImage(ctx context.Context, url string) (string, error)
+}
+
+type config struct {
+	columns int
+	size    string
+	title   string
+}
+
+// Option configures Render and WriteHTML.
+type Option func(*config)
+
+// WithColumns sets how many cards each row holds (default 5).
+func WithColumns(n int) Option {
+	return func(c *config) { c.columns = n }
+}
+
+// WithImageSize sets the Scryfall image version: Small, Normal (the
+// default) or Large.
+func WithImageSize(size string) Option {
+	return func(c *config) { c.size = size }
+}
+
+// WithTitle sets the page title of WriteHTML.
+func WithTitle(title string) Option {
+	return func(c *config) { c.title = title }
+}
+
+func newConfig(opts []Option) (*config, error) {
+	cfg := &config{columns: defaultColumns, size: Normal, title: "Contact sheet"}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.columns < 1 {
+		cfg.columns = 1
+	}
+	if _, ok := tileSizes[cfg.size]; !ok {
+		return nil, fmt.Errorf("mtgjson: unknown image size %q", cfg.size)
+	}
+	return cfg, nil
+}
+
+// ImageURL returns the Scryfall image URL of a card's front face in the
+// given version (Small, Normal or Large), or "" if the card has no Scryfall
+// ID.
+func ImageURL(card models.CardSet, size string) string {
+	id := card.IdentifiersData.ScryfallId
+	if id == nil || len(*id) < 2 {
+		return ""
+	}
+	return fmt.Sprintf(scryfallImageURL, size, (*id)[:1], (*id)[1:2], *id)
+}
+
+// Render fetches the cards' images through f and lays them out in a grid,
+// in order, left to right. Cards without a Scryfall ID get a blank tile.
+func Render(ctx context.Context, f Fetcher, cards []models.CardSet, opts ...Option) (image.Image, error) {
+	cfg, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	tile := tileSizes[cfg.size]
+	cols := min(cfg.columns, max(len(cards), 1))
+	rows := (len(cards) + cols - 1) / cols
+	sheet := image.NewRGBA(image.Rect(0, 0, cols*(tile.X+gap)+gap, max(rows, 1)*(tile.Y+gap)+gap))
+	draw.Draw(sheet, sheet.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+
+	for i, card := range cards {
+		at := image.Pt(gap+(i%cols)*(tile.X+gap), gap+(i/cols)*(tile.Y+gap))
+		dst := image.Rectangle{Min: at, Max: at.Add(tile)}
+		url := ImageURL(card, cfg.size)
+		if url == "" {
+			draw.Draw(sheet, dst, image.NewUniform(placeholder), image.Point{}, draw.Src)
+			continue
+		}
+		img, err := loadImage(ctx, f, url)
+		if err != nil {
+			return nil, fmt.Errorf("mtgjson: image for %s: %w", card.Name, err)
+		}
+		scaleInto(sheet, dst, img)
+	}
+	return sheet, nil
+}
+
+func loadImage(ctx context.Context, f Fetcher, url string) (image.Image, error) {
+	path, err := f.EnsureImage(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	img, _, err := image.Decode(file)
+	return img, err
+}
+
+// scaleInto draws src over dst with nearest-neighbor scaling. Scryfall
+// images already have their version's size, so this rarely resamples.
+func scaleInto(sheet *image.RGBA, dst image.Rectangle, src image.Image) {
+	sb := src.Bounds()
+	if sb.Size() == dst.Size() {
+		draw.Draw(sheet, dst, src, sb.Min, draw.Src)
+		return
+	}
+	w, h := dst.Dx(), dst.Dy()
+	for y := 0; y < h; y++ {
+		sy := sb.Min.Y + y*sb.Dy()/h
+		for x := 0; x < w; x++ {
+			sheet.Set(dst.Min.X+x, dst.Min.Y+y, src.At(sb.Min.X+x*sb.Dx()/w, sy))
+		}
+	}
+}
+
+var htmlTemplate = template.Must(template.New("sheet").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { background: #202020; color: #ddd; font-family: sans-serif; }
+.sheet { display: grid; grid-template-columns: repeat({{.Columns}}, {{.Width}}px); gap: 8px; }
+.card { width: {{.Width}}px; height: {{.Height}}px; border-radius: 4.75%; background: #606060; overflow: hidden; }
+.card img { width: 100%; height: 100%; }
+.card span { display: block; padding: 8px; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="sheet">
+{{- range .Cards}}
+<div class="card">{{if .URL}}<img src="{{.URL}}" alt="{{.Name}}" title="{{.Name}} ({{.SetCode}} #{{.Number}})" loading="lazy">{{else}}<span>{{.Name}}</span>{{end}}</div>
+{{- end}}
+</div>
+</body>
+</html>
+`))
+
+// WriteHTML writes a standalone HTML page that shows the cards in a grid,
+// loading their images from Scryfall.
+func WriteHTML(w io.Writer, cards []models.CardSet, opts ...Option) error {
+	cfg, err := newConfig(opts)
+	if err != nil {
+		return err
+	}
+	type htmlCard struct {
+		Name, SetCode, Number, URL string
+	}
+	tile := tileSizes[cfg.size]
+	page := struct {
+		Title                  string
+		Columns, Width, Height int
+		Cards                  []htmlCard
+	}{Title: cfg.title, Columns: cfg.columns, Width: tile.X, Height: tile.Y}
+	for _, c := range cards {
+		page.Cards = append(page.Cards, htmlCard{c.Name, c.SetCode, c.Number, ImageURL(c, cfg.size)})
+	}
+	return htmlTemplate.Execute(w, page)
+}
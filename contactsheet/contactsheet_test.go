@@ -0,0 +1,108 @@
+package contactsheet
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+func strPtr(s string) *string { return &s }
+
+func card(name, scryfallID string) models.CardSet {
+	c := models.CardSet{Name: name, SetCode: "TST", Number: "1"}
+	if scryfallID != "" {
+		c.IdentifiersData.ScryfallId = strPtr(scryfallID)
+	}
+	return c
+}
+
+// fakeFetcher serves solid-color PNGs of the given size and records the
+// URLs it was asked for.
+type fakeFetcher struct {
+	dir  string
+	size image.Point
+	urls []string
+}
+
+func (f *fakeFetcher) EnsureImage(_ context.Context, url string) (string, error) {
+	f.urls = append(f.urls, url)
+	img := image.NewRGBA(image.Rectangle{Max: f.size})
+	for y := 0; y < f.size.Y; y++ {
+		for x := 0; x < f.size.X; x++ {
+			img.Set(x, y, color.RGBA{0xff, 0, 0, 0xff})
+		}
+	}
+	path := filepath.Join(f.dir, "img.png")
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	return path, png.Encode(file, img)
+}
+
+func TestImageURL(t *testing.T) {
+	got := ImageURL(card("Lightning Bolt", "e3285e6b-3e79-4d7c-bf96-d920f973b122"), Normal)
+	want := "https://cards.scryfall.io/normal/front/e/3/e3285e6b-3e79-4d7c-bf96-d920f973b122.jpg"
+	if got != want {
+		t.Errorf("ImageURL = %s, want %s", got, want)
+	}
+	if got := ImageURL(card("No ID", ""), Normal); got != "" {
+		t.Errorf("expected no URL without a Scryfall ID, got %s", got)
+	}
+}
+
+func TestRender(t *testing.T) {
+	// Images of the wrong size are scaled to the tile.
+	f := &fakeFetcher{dir: t.TempDir(), size: image.Pt(73, 102)}
+	cards := []models.CardSet{card("A", "aa11"), card("B", ""), card("C", "cc33")}
+	img, err := Render(context.Background(), f, cards, WithImageSize(Small), WithColumns(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tile := tileSizes[Small]
+	if want := image.Pt(2*(tile.X+gap)+gap, 2*(tile.Y+gap)+gap); img.Bounds().Size() != want {
+		t.Fatalf("expected a 2x2 grid of %v, got %v", want, img.Bounds().Size())
+	}
+	if len(f.urls) != 2 || !strings.Contains(f.urls[0], "/small/front/a/a/aa11.jpg") {
+		t.Fatalf("expected images for the two cards with IDs, got %v", f.urls)
+	}
+	red := color.RGBAModel.Convert(color.RGBA{0xff, 0, 0, 0xff})
+	if got := img.At(gap+tile.X-1, gap+tile.Y-1); got != red {
+		t.Errorf("expected the first tile filled by its image, got %v", got)
+	}
+	if got := img.At(2*gap+tile.X+1, gap+1); got != color.Color(placeholder) {
+		t.Errorf("expected a placeholder for the card without an image, got %v", got)
+	}
+
+	if _, err := Render(context.Background(), f, cards, WithImageSize("huge")); err == nil {
+		t.Error("expected an error for an unknown image size")
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	var buf bytes.Buffer
+	cards := []models.CardSet{card("Fire // Ice", "ab12"), card("<Unknown>", "")}
+	if err := WriteHTML(&buf, cards, WithTitle("MH3 pack #1"), WithColumns(3)); err != nil {
+		t.Fatal(err)
+	}
+	html := buf.String()
+	for _, want := range []string{
+		"<title>MH3 pack #1</title>",
+		`src="https://cards.scryfall.io/normal/front/a/b/ab12.jpg"`,
+		"repeat(3, 488px)",
+		"&lt;Unknown&gt;",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("expected %q in the page:\n%s", want, html)
+		}
+	}
+}
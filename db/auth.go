@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// AuthProvider authorizes a request to the CDN or a private mirror before it
+// is sent: it may set headers, such as a bearer token fetched per request,
+// or replace req.URL with a signed URL. The request's context is the
+// caller's. An error aborts the request.
+type AuthProvider func(req *http.Request) error
+
+// BearerToken returns an AuthProvider that sends a fixed bearer token.
+func BearerToken(token string) AuthProvider {
+	return func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	}
+}
+
+// cdnRequest builds a request for a file under a CDN or mirror base URL,
+// with the configured headers and authorization applied. With a rate limit
+// set, it first waits for the limiter.
+func (m *CacheManager) cdnRequest(ctx context.Context, method, base, filename string) (*http.Request, error) {
+	if m.limiter != nil {
+		if err := m.limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, base+"/"+filename, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range m.headers {
+		req.Header[k] = append([]string(nil), v...)
+	}
+	if m.auth != nil {
+		if err := m.auth(req); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+
+// mirrorBase returns the base URL for downloads: the mirror if set, else
+// the MTGJSON CDN.
+func mirrorBase(mirror string) string {
+	if mirror == "" {
+		return CDNBase
+	}
+	return strings.TrimSuffix(mirror, "/")
+}
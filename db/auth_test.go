@@ -0,0 +1,123 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+)
+
+func TestMirrorHeadersAndAuth(t *testing.T) {
+	cdn := &fakeCDN{
+		version: "v1",
+		etags:   map[string]string{"parquet/cards.parquet": `"c1"`},
+		gets:    make(map[string]int),
+	}
+	var mu sync.Mutex
+	var seen []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("X-Org") != "acme" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		cdn.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.MirrorURL = srv.URL + "/"
+	cfg.Headers = http.Header{"X-Org": {"acme"}}
+	cfg.Auth = BearerToken("secret")
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	path, err := cache.EnsureParquet(ctx, "cards")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != `parquet/cards.parquet@"c1"` {
+		t.Fatalf("unexpected download %q", data)
+	}
+	cache.ResetRemoteVersion()
+	if _, err := cache.RefreshDatasets(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"GET /Meta.json", "GET /parquet/cards.parquet", "GET /Meta.json", "HEAD /parquet/cards.parquet"}
+	if len(seen) != len(want) {
+		t.Fatalf("expected requests %v, got %v", want, seen)
+	}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Fatalf("expected requests %v, got %v", want, seen)
+		}
+	}
+}
+
+func TestAuthProviderSignedURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("sig") != "ok" {
+			http.Error(w, "bad signature", http.StatusForbidden)
+			return
+		}
+		if r.URL.Path == "/Meta.json" {
+			w.Write([]byte(`{"data":{"version":"v1"}}`))
+			return
+		}
+		w.Write([]byte("signed"))
+	}))
+	defer srv.Close()
+
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.MirrorURL = srv.URL
+	cfg.Auth = func(req *http.Request) error {
+		q := req.URL.Query()
+		q.Set("sig", "ok")
+		req.URL.RawQuery = q.Encode()
+		return nil
+	}
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, err := cache.EnsureParquet(context.Background(), "sets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "signed" {
+		t.Errorf("unexpected download %q", data)
+	}
+	if v := cache.LocalVersion(); v != "v1" {
+		t.Errorf("expected the signed Meta.json version, got %q", v)
+	}
+}
+
+func TestAuthProviderError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+	errNoToken := errors.New("no token")
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.MirrorURL = srv.URL
+	cfg.Auth = func(*http.Request) error { return errNoToken }
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.EnsureParquet(context.Background(), "sets"); !errors.Is(err, errNoToken) {
+		t.Errorf("expected the provider's error, got %v", err)
+	}
+}
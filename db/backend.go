@@ -0,0 +1,37 @@
+package db
+
+import "context"
+
+// Backend is the storage the query modules read from. *Connection is the
+// DuckDB implementation; others must accept the DuckDB SQL dialect the
+// queries use (list functions, QUALIFY, $N parameters) and expose the same
+// view names, e.g. a proxy to a remote DuckDB server or a test double.
+type Backend interface {
+	// EnsureViews makes the named views queryable, returning an error if
+	// any cannot be loaded.
+	EnsureViews(ctx context.Context, names ...string) error
+	// EnsureOptionalViews is EnsureViews for data that may be missing; a
+	// view that cannot be loaded is reported by HasView instead.
+	EnsureOptionalViews(ctx context.Context, names ...string) error
+	// HasView reports whether a view is loaded.
+	HasView(name string) bool
+	// Execute runs a query and returns its rows keyed by column name.
+	Execute(ctx context.Context, query string, params ...any) ([]map[string]any, error)
+	// ExecuteInto runs a query and JSON-decodes its rows into dst, a
+	// pointer to a slice.
+	ExecuteInto(ctx context.Context, dst any, query string, params ...any) error
+	// ExecuteScalar runs a query and returns the first column of its first
+	// row, or nil if there are no rows.
+	ExecuteScalar(ctx context.Context, query string, params ...any) (any, error)
+	// ExecuteEach runs a query and calls fn with each row, keyed by column
+	// name, without holding the whole result in memory. It stops at the
+	// first error fn returns.
+	ExecuteEach(ctx context.Context, fn func(row map[string]any) error, query string, params ...any) error
+	// Exec runs a statement that returns no rows, such as ATTACH, INSERT or
+	// CREATE TABLE.
+	Exec(ctx context.Context, query string, params ...any) error
+	// Capabilities reports the optional SQL features available.
+	Capabilities() Capabilities
+}
+
+var _ Backend = (*Connection)(nil)
@@ -12,36 +12,97 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 )
 
 // CacheManager downloads and caches MTGJSON data files from the CDN.
 // It checks Meta.json for version changes and re-downloads when stale.
 type CacheManager struct {
 	CacheDir   string
+	BaseDir    string // read-only fallback for CacheDir; "" if unused
 	Offline    bool
 	Timeout    int64 // seconds
-	onProgress ProgressFunc
-
-	client        *http.Client
-	clientOnce    sync.Once
-	remoteVer     string
-	remoteVerOnce sync.Once
-	mu            sync.Mutex
-	inFlight      map[string]chan struct{}
+	Strict     bool  // surface optional-data load failures instead of logging them
+	TempDir    string
+	onProgress ProgressEventFunc
+	onDownload DownloadNoticeFunc
+	store      Store
+
+	noImplicitDownloads bool // see Config.NoImplicitDownloads
+
+	// KeepVersions is how many past releases RefreshDatasets archives for
+	// VersionDir; 0 keeps none.
+	KeepVersions int
+
+	metrics Metrics
+	logger  *slog.Logger // nil means slog.Default
+
+	fallbacks []string // base URLs tried after baseURL fails
+	cooldown  time.Duration
+	health    mirrorHealth
+
+	remoteParquet bool
+	remoteViews   []string     // views read remotely; nil means all
+	limiter       *rateLimiter // nil without a rate limit
+
+	baseURL    string // CDNBase or the mirror; overridden in tests
+	headers    http.Header
+	auth       AuthProvider
+	client     *http.Client
+	clientOnce sync.Once
+	remoteVer  string
+	verMu      sync.Mutex // guards remoteVer; mu may be held while it is read
+	mu         sync.Mutex
+	inFlight   map[string]chan struct{}
+	manifestMu sync.Mutex // guards datasets.json
 }
 
 // NewCacheManager creates a CacheManager from the given Config.
 func NewCacheManager(cfg *Config) (*CacheManager, error) {
 	cm := &CacheManager{
 		CacheDir:   cfg.CacheDir,
+		BaseDir:    cfg.BaseCacheDir,
 		Offline:    cfg.Offline,
 		Timeout:    int64(cfg.Timeout.Seconds()),
-		onProgress: cfg.OnProgress,
+		Strict:     cfg.Strict,
+		TempDir:    cfg.TempDir,
+		onProgress: progressEvents(cfg.OnProgress, cfg.OnProgressEvent),
+		onDownload: cfg.OnDownload,
+		store:      cfg.Store,
 		inFlight:   make(map[string]chan struct{}),
+		baseURL:    mirrorBase(cfg.MirrorURL),
+		headers:    cfg.Headers,
+		auth:       cfg.Auth,
+	}
+	cm.KeepVersions = cfg.KeepVersions
+	cm.noImplicitDownloads = cfg.NoImplicitDownloads
+	cm.metrics = cfg.Metrics
+	cm.logger = newLogger(cfg.Logger, cfg.LogLevel)
+	for _, fallback := range cfg.FallbackMirrors {
+		cm.fallbacks = append(cm.fallbacks, mirrorBase(fallback))
+	}
+	cm.cooldown = cfg.MirrorCooldown
+	cm.remoteParquet, cm.remoteViews = cfg.RemoteParquet, cfg.RemoteParquetViews
+	if cm.cooldown <= 0 {
+		cm.cooldown = DefaultMirrorCooldown
+	}
+	if cm.metrics == nil {
+		cm.metrics = NopMetrics{}
+	}
+	if cfg.RateLimit > 0 {
+		cm.limiter = sharedLimiter(cm.baseURL, cfg.RateLimit, cfg.RateBurst)
 	}
 	if err := os.MkdirAll(cm.CacheDir, 0o755); err != nil {
 		return nil, fmt.Errorf("mtgjson: create cache dir: %w", err)
 	}
+	if cm.BaseDir == cm.CacheDir {
+		cm.BaseDir = ""
+	}
+	if cm.TempDir != "" {
+		if err := os.MkdirAll(cm.TempDir, 0o755); err != nil {
+			return nil, fmt.Errorf("mtgjson: create temp dir: %w", err)
+		}
+	}
 	return cm, nil
 }
 
@@ -61,39 +122,78 @@ func (m *CacheManager) Close() {
 	}
 }
 
-func (m *CacheManager) localVersion() string {
-	data, err := os.ReadFile(filepath.Join(m.CacheDir, "version.txt"))
+// LocalVersion returns the MTGJSON version of the cached files, or "" if
+// nothing has been downloaded yet. Without a version of its own, CacheDir
+// takes the version of the base cache.
+func (m *CacheManager) LocalVersion() string {
+	if v := readVersion(m.CacheDir); v != "" || m.BaseDir == "" {
+		return v
+	}
+	return readVersion(m.BaseDir)
+}
+
+func readVersion(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "version.txt"))
 	if err != nil {
 		return ""
 	}
 	return strings.TrimSpace(string(data))
 }
 
+// Path returns where a CDN file is read from: CacheDir if it holds the file,
+// otherwise the base cache if that does, otherwise CacheDir, where a
+// download would go.
+func (m *CacheManager) Path(filename string) string {
+	path := filepath.Join(m.CacheDir, filename)
+	if m.BaseDir != "" && !fileExists(path) {
+		if base := filepath.Join(m.BaseDir, filename); fileExists(base) {
+			return base
+		}
+	}
+	return path
+}
+
+// inBase reports whether Path resolves filename to the base cache.
+func (m *CacheManager) inBase(filename string) bool {
+	return m.BaseDir != "" && m.Path(filename) != filepath.Join(m.CacheDir, filename)
+}
+
 func (m *CacheManager) saveVersion(version string) {
-	_ = os.WriteFile(filepath.Join(m.CacheDir, "version.txt"), []byte(version), 0o644)
+	_ = writeFileAtomic(filepath.Join(m.CacheDir, "version.txt"), []byte(version))
 }
 
 // RemoteVersion fetches the current MTGJSON version from Meta.json on the CDN.
 // Returns empty string if offline or unreachable.
 func (m *CacheManager) RemoteVersion(ctx context.Context) string {
-	if m.remoteVer != "" {
-		return m.remoteVer
+	if v := m.cachedRemoteVersion(); v != "" {
+		return v
 	}
 	if m.Offline {
 		return ""
 	}
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, MetaURL, nil)
-	if err != nil {
-		return ""
+	var v string
+	if m.limiter != nil {
+		// Instances sharing a limiter share one Meta.json request.
+		v = singleFlight(ctx, m.baseURL+"/Meta.json", func() string { return m.fetchRemoteVersion(ctx) })
+	} else {
+		v = m.fetchRemoteVersion(ctx)
 	}
-	resp, err := m.httpClient().Do(req)
+	if v != "" {
+		m.setRemoteVersion(v)
+	}
+	return v
+}
+
+// fetchRemoteVersion reads the version from Meta.json, or "" on failure.
+func (m *CacheManager) fetchRemoteVersion(ctx context.Context) string {
+	resp, err := m.cdnDo(ctx, http.MethodGet, "Meta.json")
 	if err != nil {
-		slog.Warn("Failed to fetch MTGJSON version from CDN", "error", err)
+		m.Logger().Warn("Failed to fetch MTGJSON version from CDN", "error", err)
 		return ""
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		slog.Warn("MTGJSON CDN returned non-200", "status", resp.StatusCode)
+		m.Logger().Warn("MTGJSON CDN returned non-200", "status", resp.StatusCode)
 		return ""
 	}
 	var data map[string]any
@@ -103,22 +203,64 @@ func (m *CacheManager) RemoteVersion(ctx context.Context) string {
 	// Try data.version, then meta.version
 	if d, ok := data["data"].(map[string]any); ok {
 		if v, ok := d["version"].(string); ok && v != "" {
-			m.remoteVer = v
 			return v
 		}
 	}
 	if d, ok := data["meta"].(map[string]any); ok {
 		if v, ok := d["version"].(string); ok && v != "" {
-			m.remoteVer = v
 			return v
 		}
 	}
 	return ""
 }
 
+// ResolveRedirect issues a GET for an MTGJSON purchase link and returns the
+// vendor URL it redirects to, without following the redirect. With a rate
+// limit set, it first waits for the limiter.
+func (m *CacheManager) ResolveRedirect(ctx context.Context, link string) (string, error) {
+	if m.Offline {
+		return "", fmt.Errorf("mtgjson: cannot resolve %s in offline mode", link)
+	}
+	if m.limiter != nil {
+		if err := m.limiter.wait(ctx); err != nil {
+			return "", err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return "", err
+	}
+	client := *m.httpClient()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("mtgjson: resolve %s: %w", link, err)
+	}
+	resp.Body.Close()
+	loc, err := resp.Location()
+	if err != nil {
+		return "", fmt.Errorf("mtgjson: resolve %s: HTTP %d without redirect", link, resp.StatusCode)
+	}
+	return loc.String(), nil
+}
+
+func (m *CacheManager) cachedRemoteVersion() string {
+	m.verMu.Lock()
+	defer m.verMu.Unlock()
+	return m.remoteVer
+}
+
+func (m *CacheManager) setRemoteVersion(v string) {
+	m.verMu.Lock()
+	defer m.verMu.Unlock()
+	m.remoteVer = v
+}
+
 // IsStale checks if local cache is out of date compared to the CDN.
 func (m *CacheManager) IsStale(ctx context.Context) bool {
-	local := m.localVersion()
+	local := m.LocalVersion()
 	remote := m.RemoteVersion(ctx)
 	if remote == "" {
 		return false // can't check, assume fresh
@@ -129,9 +271,20 @@ func (m *CacheManager) IsStale(ctx context.Context) bool {
 	return local != remote
 }
 
-func (m *CacheManager) downloadFile(ctx context.Context, filename string, dest string) error {
-	url := CDNBase + "/" + filename
-	slog.Info("Downloading", "url", url)
+func (m *CacheManager) downloadFile(ctx context.Context, filename string, dest string) (err error) {
+	var downloaded, total int64
+	start := time.Now()
+	defer func() {
+		m.metrics.Download(filename, downloaded, time.Since(start), err)
+		if err == nil {
+			m.Logger().Info("Downloaded", "file", filename, "bytes", downloaded, "duration", time.Since(start))
+		}
+	}()
+	var progress *progressReporter
+	if m.onProgress != nil {
+		progress = newProgressReporter(m.onProgress)
+		defer func() { progress.finish(filename, downloaded, total, err) }()
+	}
 
 	dir := filepath.Dir(dest)
 	if err := os.MkdirAll(dir, 0o755); err != nil {
@@ -139,11 +292,7 @@ func (m *CacheManager) downloadFile(ctx context.Context, filename string, dest s
 	}
 
 	tmpDest := dest + ".tmp"
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return err
-	}
-	resp, err := m.httpClient().Do(req)
+	resp, err := m.cdnDo(ctx, http.MethodGet, filename)
 	if err != nil {
 		return fmt.Errorf("download %s: %w", filename, err)
 	}
@@ -152,14 +301,16 @@ func (m *CacheManager) downloadFile(ctx context.Context, filename string, dest s
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("download %s: HTTP %d", filename, resp.StatusCode)
 	}
+	from := *resp.Request.URL
+	from.RawQuery = "" // may hold a signature
+	m.Logger().Info("Downloading", "file", filename, "url", from.String())
 
-	total := resp.ContentLength
+	total = max(resp.ContentLength, 0)
 	f, err := os.Create(tmpDest)
 	if err != nil {
 		return err
 	}
 
-	var downloaded int64
 	buf := make([]byte, 65536)
 	for {
 		n, readErr := resp.Body.Read(buf)
@@ -170,8 +321,8 @@ func (m *CacheManager) downloadFile(ctx context.Context, filename string, dest s
 				return wErr
 			}
 			downloaded += int64(n)
-			if m.onProgress != nil {
-				m.onProgress(filename, downloaded, total)
+			if progress != nil {
+				progress.report(filename, downloaded, total)
 			}
 		}
 		if readErr == io.EOF {
@@ -189,12 +340,17 @@ func (m *CacheManager) downloadFile(ctx context.Context, filename string, dest s
 		os.Remove(tmpDest)
 		return err
 	}
+	m.recordDataset(filename, datasetEntry{
+		Version:      m.RemoteVersion(ctx),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	})
 	return nil
 }
 
-// ensureFile handles deduplicated downloading: if another goroutine is already
-// downloading the same file, block until it finishes instead of starting a
-// second download.
+// ensureFile handles deduplicated downloading: if another goroutine or
+// process is already downloading the same file, block until it finishes
+// instead of starting a second download.
 func (m *CacheManager) ensureFile(ctx context.Context, filename, localPath string) error {
 	m.mu.Lock()
 	if ch, ok := m.inFlight[filename]; ok {
@@ -218,9 +374,30 @@ func (m *CacheManager) ensureFile(ctx context.Context, filename, localPath strin
 		m.mu.Unlock()
 	}()
 
-	if err := m.downloadFile(ctx, filename, localPath); err != nil {
+	// Another process sharing the cache dir may be downloading the same file:
+	// wait for its lock, then skip the download if it left a fresh copy.
+	unlock, err := lockFile(ctx, localPath+".lock")
+	if err != nil {
 		return err
 	}
+	defer unlock()
+	if fileExists(localPath) && !m.datasetStale(ctx, filename) {
+		m.metrics.CacheHit(filename)
+		return nil
+	}
+
+	if m.fetchFromStore(ctx, filename, localPath) {
+		m.metrics.CacheHit(filename)
+	} else {
+		if !m.downloadsAllowed(ctx) {
+			return fmt.Errorf("%w: %s", ErrWouldDownload, filename)
+		}
+		m.noticeDownload(ctx, filename, fileExists(localPath) || fileExists(m.Path(filename)))
+		if err := m.downloadFile(ctx, filename, localPath); err != nil {
+			return err
+		}
+		m.pushToStore(ctx, filename, localPath)
+	}
 	if v := m.RemoteVersion(ctx); v != "" {
 		m.saveVersion(v)
 	}
@@ -233,23 +410,32 @@ func (m *CacheManager) EnsureParquet(ctx context.Context, viewName string) (stri
 	if !ok {
 		return "", fmt.Errorf("mtgjson: unknown parquet view %q", viewName)
 	}
-	localPath := filepath.Join(m.CacheDir, filename)
+	localPath := m.Path(filename)
 
 	m.mu.Lock()
 	exists := fileExists(localPath)
-	stale := m.IsStale(ctx)
+	stale := m.datasetStale(ctx, filename)
 	m.mu.Unlock()
 
 	if !exists || stale {
 		if m.Offline {
 			if exists {
+				m.metrics.CacheHit(filename)
 				return localPath, nil
 			}
 			return "", fmt.Errorf("mtgjson: parquet file %s not cached and offline mode is enabled", filename)
 		}
+		if exists && !m.downloadsAllowed(ctx) {
+			// Keep serving the stale copy rather than fail.
+			m.metrics.CacheHit(filename)
+			return localPath, nil
+		}
+		localPath = filepath.Join(m.CacheDir, filename)
 		if err := m.ensureFile(ctx, filename, localPath); err != nil {
 			return "", err
 		}
+	} else {
+		m.metrics.CacheHit(filename)
 	}
 	return localPath, nil
 }
@@ -260,23 +446,32 @@ func (m *CacheManager) EnsureJSON(ctx context.Context, name string) (string, err
 	if !ok {
 		return "", fmt.Errorf("mtgjson: unknown JSON file %q", name)
 	}
-	localPath := filepath.Join(m.CacheDir, filename)
+	localPath := m.Path(filename)
 
 	m.mu.Lock()
 	exists := fileExists(localPath)
-	stale := m.IsStale(ctx)
+	stale := m.datasetStale(ctx, filename)
 	m.mu.Unlock()
 
 	if !exists || stale {
 		if m.Offline {
 			if exists {
+				m.metrics.CacheHit(filename)
 				return localPath, nil
 			}
 			return "", fmt.Errorf("mtgjson: JSON file %s not cached and offline mode is enabled", filename)
 		}
+		if exists && !m.downloadsAllowed(ctx) {
+			// Keep serving the stale copy rather than fail.
+			m.metrics.CacheHit(filename)
+			return localPath, nil
+		}
+		localPath = filepath.Join(m.CacheDir, filename)
 		if err := m.ensureFile(ctx, filename, localPath); err != nil {
 			return "", err
 		}
+	} else {
+		m.metrics.CacheHit(filename)
 	}
 	return localPath, nil
 }
@@ -300,8 +495,7 @@ func (m *CacheManager) Clear() error {
 
 // ResetRemoteVersion clears the cached remote version so it's re-fetched.
 func (m *CacheManager) ResetRemoteVersion() {
-	m.remoteVer = ""
-	m.remoteVerOnce = sync.Once{}
+	m.setRemoteVersion("")
 }
 
 func fileExists(path string) bool {
@@ -0,0 +1,124 @@
+package db
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeBaseCache lays out a cache dir as a previous SDK run would leave it.
+func writeBaseCache(t *testing.T, version string, etags map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	entries := make(map[string]datasetEntry)
+	for filename, etag := range etags {
+		path := filepath.Join(dir, filename)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(filename+"@"+etag), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		entries[filename] = datasetEntry{Version: version, ETag: etag}
+	}
+	base := &CacheManager{CacheDir: dir}
+	base.writeManifest(entries)
+	base.saveVersion(version)
+	return dir
+}
+
+func TestBaseCacheDirOverlay(t *testing.T) {
+	cdn := &fakeCDN{
+		version: "v1",
+		etags:   map[string]string{"parquet/cards.parquet": `"c1"`, "parquet/AllPricesToday.parquet": `"p1"`},
+		gets:    make(map[string]int),
+	}
+	srv := httptest.NewServer(cdn)
+	defer srv.Close()
+	baseDir := writeBaseCache(t, "v1", map[string]string{
+		"parquet/cards.parquet":          `"c1"`,
+		"parquet/AllPricesToday.parquet": `"p1"`,
+	})
+
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.BaseCacheDir = baseDir
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.baseURL = srv.URL
+	ctx := context.Background()
+
+	path, err := cache.EnsureParquet(ctx, "cards")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(baseDir, "parquet", "cards.parquet") {
+		t.Fatalf("expected the base copy, got %s", path)
+	}
+	if v := cache.LocalVersion(); v != "v1" {
+		t.Fatalf("expected the base version, got %q", v)
+	}
+
+	// A release changing only cards: the base stays untouched, cards are
+	// downloaded into the overlay and prices keep coming from the base.
+	cdn.mu.Lock()
+	cdn.version = "v2"
+	cdn.etags["parquet/cards.parquet"] = `"c2"`
+	cdn.mu.Unlock()
+	cache.ResetRemoteVersion()
+	changed, err := cache.RefreshDatasets(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 1 || changed[0] != "parquet/cards.parquet" {
+		t.Fatalf("expected only cards to change, got %v", changed)
+	}
+	path, err = cache.EnsureParquet(ctx, "cards")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(cfg.CacheDir, "parquet", "cards.parquet") {
+		t.Fatalf("expected cards in the overlay, got %s", path)
+	}
+	if data, _ := os.ReadFile(path); string(data) != `parquet/cards.parquet@"c2"` {
+		t.Errorf("expected the new cards file, got %q", data)
+	}
+	if path, err := cache.EnsureParquet(ctx, "all_prices_today"); err != nil || path != filepath.Join(baseDir, "parquet", "AllPricesToday.parquet") {
+		t.Errorf("expected unchanged prices from the base, got %s (%v)", path, err)
+	}
+
+	cdn.mu.Lock()
+	cardGets, priceGets := cdn.gets["parquet/cards.parquet"], cdn.gets["parquet/AllPricesToday.parquet"]
+	cdn.mu.Unlock()
+	if cardGets != 1 || priceGets != 0 {
+		t.Errorf("expected one cards download and none for prices, got %d and %d", cardGets, priceGets)
+	}
+	if data, _ := os.ReadFile(filepath.Join(baseDir, "parquet", "cards.parquet")); string(data) != `parquet/cards.parquet@"c1"` {
+		t.Errorf("expected the base cache to be untouched, got %q", data)
+	}
+	if v := readVersion(baseDir); v != "v1" {
+		t.Errorf("expected the base version.txt to be untouched, got %q", v)
+	}
+}
+
+func TestBaseCacheDirOffline(t *testing.T) {
+	baseDir := writeBaseCache(t, "v1", map[string]string{"parquet/cards.parquet": `"c1"`})
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.BaseCacheDir = baseDir
+	cfg.Offline = true
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path, err := cache.EnsureParquet(context.Background(), "cards"); err != nil || path != filepath.Join(baseDir, "parquet", "cards.parquet") {
+		t.Errorf("expected the base copy offline, got %s (%v)", path, err)
+	}
+	if _, err := cache.EnsureParquet(context.Background(), "sets"); err == nil {
+		t.Error("expected an error for a file in neither cache offline")
+	}
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"time"
+)
+
+// Capabilities reports which optional DuckDB features a connection can use.
+// Missing features are worked around: without JSON, nested columns are left
+// as strings and results are encoded in Go; without JaroWinkler, fuzzy name
+// search ranks candidates by Levenshtein distance in Go.
+type Capabilities struct {
+	JSON        bool `json:"json"`         // json extension: JSON casts and to_json
+	JaroWinkler bool `json:"jaro_winkler"` // jaro_winkler_similarity for fuzzy search
+}
+
+// detectCapabilities probes the database for optional features, loading the
+// json extension if it is installed but not loaded.
+func detectCapabilities(ctx context.Context, logger *slog.Logger, db *sql.DB) Capabilities {
+	var caps Capabilities
+	caps.JSON = probe(ctx, db, "SELECT CAST('{}' AS JSON)")
+	if !caps.JSON {
+		if _, err := db.ExecContext(ctx, "LOAD json"); err == nil {
+			caps.JSON = probe(ctx, db, "SELECT CAST('{}' AS JSON)")
+		}
+	}
+	caps.JaroWinkler = probe(ctx, db, "SELECT jaro_winkler_similarity('a', 'a')")
+	if !caps.JSON {
+		logger.Warn("DuckDB json extension unavailable; decoding results in Go")
+	}
+	if !caps.JaroWinkler {
+		logger.Warn("DuckDB jaro_winkler_similarity unavailable; fuzzy search falls back to Levenshtein")
+	}
+	return caps
+}
+
+func probe(ctx context.Context, db *sql.DB, query string) bool {
+	var v any
+	return db.QueryRowContext(ctx, query).Scan(&v) == nil
+}
+
+// Capabilities returns the optional DuckDB features detected at startup.
+func (c *Connection) Capabilities() Capabilities {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.caps
+}
+
+// SetCapabilities overrides the detected capabilities, e.g. to force the Go
+// fallbacks for reproducible results across platforms. Enabling a feature
+// DuckDB lacks makes the queries that need it fail.
+func (c *Connection) SetCapabilities(caps Capabilities) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.caps = caps
+}
+
+// encodeRowsJSON is the ExecuteJSON fallback for when the json extension is
+// unavailable: rows are read with Execute and encoded with encoding/json.
+// Dates are written as YYYY-MM-DD, as to_json does.
+func (c *Connection) encodeRowsJSON(ctx context.Context, query string, params ...any) (string, error) {
+	rows, err := c.Execute(ctx, query, params...)
+	if err != nil {
+		return "[]", err
+	}
+	if rows == nil {
+		return "[]", nil
+	}
+	for _, row := range rows {
+		for col, v := range row {
+			if t, ok := v.(time.Time); ok && t.Equal(t.Truncate(24*time.Hour)) {
+				row[col] = t.Format(time.DateOnly)
+			}
+		}
+	}
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return "[]", err
+	}
+	return string(data), nil
+}
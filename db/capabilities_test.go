@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectCapabilities(t *testing.T) {
+	conn := testConnection(t)
+	caps := conn.Capabilities()
+	if !caps.JSON || !caps.JaroWinkler {
+		t.Fatalf("expected the bundled DuckDB to support both features, got %+v", caps)
+	}
+}
+
+func TestWithoutJSONExtension(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.Offline = true
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := NewConnection(cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetCapabilities(Capabilities{JSON: false, JaroWinkler: true})
+	ctx := context.Background()
+
+	path := filepath.Join(cfg.CacheDir, "parquet", "cards.parquet")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	_, err = conn.Raw().ExecContext(ctx, fmt.Sprintf(
+		"COPY (SELECT 'a' AS uuid, '{\"scryfallId\": \"s-1\"}' AS identifiers, DATE '2024-01-02' AS released) "+
+			"TO %s (FORMAT PARQUET)", SQLPathLiteral(path)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.EnsureViews(ctx, "cards"); err != nil {
+		t.Fatal(err)
+	}
+	val, err := conn.ExecuteScalar(ctx, "SELECT typeof(identifiers) FROM cards")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val != "VARCHAR" {
+		t.Fatalf("expected identifiers left as VARCHAR, got %v", val)
+	}
+
+	var rows []struct {
+		UUID        string            `json:"uuid"`
+		Identifiers map[string]string `json:"identifiers"`
+		Released    string            `json:"released"`
+	}
+	if err := conn.ExecuteInto(ctx, &rows, "SELECT * FROM cards"); err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || rows[0].Identifiers["scryfallId"] != "s-1" || rows[0].Released != "2024-01-02" {
+		t.Fatalf("unexpected rows decoded in Go: %+v", rows)
+	}
+	if got, err := conn.ExecuteJSON(ctx, "SELECT * FROM cards WHERE uuid = 'none'"); err != nil || got != "[]" {
+		t.Fatalf("expected [] for no rows, got %q (%v)", got, err)
+	}
+}
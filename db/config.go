@@ -1,6 +1,9 @@
 package db
 
 import (
+	"log/slog"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -9,7 +12,8 @@ import (
 
 // ProgressFunc is called during file downloads to report progress.
 // filename is the CDN file name, downloaded is bytes received so far,
-// total is the total expected bytes (0 if unknown).
+// total is the total expected bytes (0 if unknown). It is delivered like a
+// ProgressEventFunc.
 type ProgressFunc func(filename string, downloaded, total int64)
 
 // Config holds SDK configuration.
@@ -18,6 +22,94 @@ type Config struct {
 	Offline    bool
 	Timeout    time.Duration
 	OnProgress ProgressFunc
+	// OnProgressEvent receives the same downloads as OnProgress with their
+	// rate, ETA, completion and error.
+	OnProgressEvent ProgressEventFunc
+	Strict     bool
+	TempDir    string
+	// BaseCacheDir is a read-only cache, such as one baked into a container
+	// image, consulted for files missing from CacheDir. It is never written;
+	// newer files are downloaded into CacheDir.
+	BaseCacheDir string
+	// MirrorURL replaces CDNBase as the base URL for Meta.json and data
+	// files, for organizations mirroring MTGJSON; "" uses the CDN.
+	MirrorURL string
+	// FallbackMirrors are base URLs tried in order when the CDN (or
+	// MirrorURL) fails with a network error, 5xx or 429. A failed URL is
+	// tried last for MirrorCooldown (DefaultMirrorCooldown if 0).
+	FallbackMirrors []string
+	MirrorCooldown  time.Duration
+	// RemoteParquet registers views over the CDN URLs of their parquet
+	// files with DuckDB's httpfs extension, which fetches only the byte
+	// ranges a query needs, instead of downloading the files; see
+	// CacheManager.RemoteParquetURL. RemoteParquetViews limits it to the
+	// named views; nil means all.
+	RemoteParquet      bool
+	RemoteParquetViews []string
+	// Headers are added to every request to the CDN or mirror.
+	Headers http.Header
+	// Auth, if set, authorizes every request to the CDN or mirror after
+	// Headers are applied.
+	Auth AuthProvider
+	// Store, if set, keeps downloaded files between processes that have no
+	// persistent CacheDir, such as serverless invocations.
+	Store Store
+	// OnDownload, if set, is told before a call blocks on downloading a
+	// file, such as the first use of cards.
+	OnDownload DownloadNoticeFunc
+	// NoImplicitDownloads makes calls needing an uncached file fail with
+	// ErrWouldDownload instead of downloading it, unless their context
+	// comes from AllowDownloads. Stale cached files are used as they are;
+	// uncached optional data such as prices fails the same way.
+	NoImplicitDownloads bool
+	// DSN is the DuckDB data source name to open; "" is in-memory.
+	DSN string
+	// Backend, if set, is what the SDK's query modules read from instead
+	// of the DuckDB connection opened from DSN. Raw SQL, Refresh and
+	// exports still use that connection.
+	Backend Backend
+	// DuckDBOptions are DuckDB settings, such as memory_limit or threads,
+	// applied when the database is opened.
+	DuckDBOptions map[string]string
+	// ExcludeCasualLayouts makes card searches skip planes, schemes and
+	// other casual-only layouts unless a layout is requested explicitly.
+	ExcludeCasualLayouts bool
+	// ExcludeOnlineOnly and ExcludeMemorabilia leave Arena/MTGO-only sets
+	// and memorabilia, funny and token sets out of set listings and card
+	// searches; see queries.SetExclusions.
+	ExcludeOnlineOnly  bool
+	ExcludeMemorabilia bool
+	// KeepVersions is how many past MTGJSON releases Refresh keeps in the
+	// cache for SDK.AsOf; 0 keeps none.
+	KeepVersions int
+	// Metrics, if set, receives download, cache-hit and query counts and
+	// timings; nil discards them.
+	Metrics Metrics
+	// RateLimit caps requests to the CDN or mirror at this many per second,
+	// with bursts of up to RateBurst, shared by every SDK in the process
+	// using the same base URL and limit; 0 means no limit.
+	RateLimit float64
+	RateBurst int
+	// Logger receives the SDK's log records instead of slog.Default, and
+	// LogLevel, if set, drops records below it; see CacheManager.Logger.
+	Logger   *slog.Logger
+	LogLevel slog.Leveler
+	// Transliterator, if set, spells localized card names in ASCII so that
+	// romanized LocalizedName searches match them; see
+	// queries.WithTransliterator.
+	Transliterator func(name, language string) string
+	// MaterializeLegalities stores card_legalities as an indexed table; see
+	// Connection.MaterializeLegalities.
+	MaterializeLegalities bool
+	// LegalityHistory records legality changes on every refresh; see
+	// Connection.RecordLegalities.
+	LegalityHistory bool
+	// ViewColumns limits views to the listed parquet columns; see
+	// Connection.SetViewColumns.
+	ViewColumns map[string][]string
+	// AffiliateCodes holds query parameters appended to resolved purchase
+	// URLs, keyed by vendor ("tcgplayer", "cardKingdom", "cardmarket").
+	AffiliateCodes map[string]url.Values
 }
 
 // DefaultConfig returns the default SDK configuration.
@@ -71,23 +163,36 @@ var JSONFiles = map[string]string{
 	"deck_list":        "DeckList.json",
 	"enum_values":      "EnumValues.json",
 	"meta":             "Meta.json",
+	"all_printings":    "AllPrintings.json.gz",
 }
 
 func defaultCacheDir() string {
-	switch runtime.GOOS {
+	return cacheDirFor(runtime.GOOS, os.Getenv)
+}
+
+// cacheDirFor resolves the default cache directory for an OS: %LOCALAPPDATA%
+// on Windows, ~/Library/Caches on macOS and $XDG_CACHE_HOME (or ~/.cache)
+// elsewhere. Falls back to the temp dir when no home directory is set.
+func cacheDirFor(goos string, getenv func(string) string) string {
+	var base string
+	switch goos {
 	case "windows":
-		base := os.Getenv("LOCALAPPDATA")
-		if base == "" {
-			base = filepath.Join(os.Getenv("USERPROFILE"), "AppData", "Local")
+		base = getenv("LOCALAPPDATA")
+		if base == "" && getenv("USERPROFILE") != "" {
+			base = filepath.Join(getenv("USERPROFILE"), "AppData", "Local")
 		}
-		return filepath.Join(base, "mtgjson-sdk")
 	case "darwin":
-		return filepath.Join(os.Getenv("HOME"), "Library", "Caches", "mtgjson-sdk")
+		if home := getenv("HOME"); home != "" {
+			base = filepath.Join(home, "Library", "Caches")
+		}
 	default:
-		base := os.Getenv("XDG_CACHE_HOME")
-		if base == "" {
-			base = filepath.Join(os.Getenv("HOME"), ".cache")
+		base = getenv("XDG_CACHE_HOME")
+		if base == "" && getenv("HOME") != "" {
+			base = filepath.Join(getenv("HOME"), ".cache")
 		}
-		return filepath.Join(base, "mtgjson-sdk")
 	}
+	if base == "" {
+		base = os.TempDir()
+	}
+	return filepath.Join(base, "mtgjson-sdk")
 }
@@ -0,0 +1,33 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCacheDirFor(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(k string) string { return vars[k] }
+	}
+	tests := []struct {
+		name string
+		goos string
+		vars map[string]string
+		want string
+	}{
+		{"windows local app data", "windows", map[string]string{"LOCALAPPDATA": `C:\Users\me\AppData\Local`}, filepath.Join(`C:\Users\me\AppData\Local`, "mtgjson-sdk")},
+		{"windows user profile", "windows", map[string]string{"USERPROFILE": `C:\Users\me`}, filepath.Join(`C:\Users\me`, "AppData", "Local", "mtgjson-sdk")},
+		{"darwin", "darwin", map[string]string{"HOME": "/Users/me", "XDG_CACHE_HOME": "/ignored"}, "/Users/me/Library/Caches/mtgjson-sdk"},
+		{"linux xdg", "linux", map[string]string{"HOME": "/home/me", "XDG_CACHE_HOME": "/var/cache/me"}, "/var/cache/me/mtgjson-sdk"},
+		{"linux home", "linux", map[string]string{"HOME": "/home/me"}, "/home/me/.cache/mtgjson-sdk"},
+		{"no home", "linux", map[string]string{}, filepath.Join(os.TempDir(), "mtgjson-sdk")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cacheDirFor(tt.goos, env(tt.vars)); got != filepath.FromSlash(tt.want) && got != tt.want {
+				t.Fatalf("cacheDirFor(%s) = %q, want %q", tt.goos, got, tt.want)
+			}
+		})
+	}
+}
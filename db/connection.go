@@ -4,18 +4,20 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/url"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 	"sync"
-
-	_ "github.com/marcboeker/go-duckdb" // DuckDB driver registration
+	"time"
 )
 
-// staticListColumns are known list columns that don't follow the plural naming convention.
+// staticListColumns are known list columns. They are converted even when
+// sampling cannot tell, such as when every sampled value has one item.
 var staticListColumns = map[string]map[string]bool{
 	"cards": {
 		"artistIds": true, "attractionLights": true, "availability": true,
@@ -36,16 +38,10 @@ var staticListColumns = map[string]map[string]bool{
 	},
 }
 
-// ignoredColumns are VARCHAR columns that are NOT lists, even if they match the plural heuristic.
-var ignoredColumns = map[string]bool{
-	"text": true, "originalText": true, "flavorText": true, "printedText": true,
-	"identifiers": true, "legalities": true, "leadershipSkills": true,
-	"purchaseUrls": true, "relatedCards": true, "rulings": true,
-	"sourceProducts": true, "foreignData": true, "translations": true,
-	"toughness": true, "status": true, "format": true, "uris": true,
-	"scryfallUri": true,
-	"contents": true, "tokens": true, "planes": true, "schemes": true,
-	"sealedProductUuids": true, "sourceSetCodes": true,
+// intListColumns are comma-separated list columns holding integers, such as
+// the lit numbers of Unfinity Attractions.
+var intListColumns = map[string]bool{
+	"attractionLights": true,
 }
 
 // jsonCastColumns are VARCHAR columns containing JSON strings to cast to DuckDB JSON type.
@@ -59,28 +55,83 @@ var jsonCastColumns = map[string]bool{
 }
 
 // Connection wraps a DuckDB database/sql connection and registers parquet files as views.
+// It is safe for concurrent use by multiple goroutines.
 type Connection struct {
 	db              *sql.DB
 	cache           *CacheManager
 	registeredViews map[string]bool
-	mu              sync.RWMutex
+	overrides       map[string]viewOverride
+	columns         map[string]map[string]bool // per-view allow-lists
+	// materializeLegalities stores card_legalities as an indexed table
+	// instead of an UNPIVOT view.
+	materializeLegalities bool
+	caps                  Capabilities
+	mu                    sync.RWMutex
+
+	httpfs int // 1 loaded, -1 unavailable, 0 not tried yet
+}
+
+// viewOverride registers a view from another view's file, optionally
+// filtered by a SQL condition.
+type viewOverride struct {
+	source string
+	where  string
 }
 
 // NewConnection creates a new in-memory DuckDB connection backed by the given cache.
 func NewConnection(cache *CacheManager) (*Connection, error) {
-	db, err := sql.Open("duckdb", "")
+	return OpenConnection(cache, "", nil)
+}
+
+// OpenConnection is NewConnection for a DuckDB data source name: a database
+// file path, a MotherDuck "md:" string, or "" for an in-memory database.
+// options are DuckDB settings applied when the database is opened, such as
+// memory_limit, threads or temp_directory.
+func OpenConnection(cache *CacheManager, dsn string, options map[string]string) (*Connection, error) {
+	if !DuckDBAvailable {
+		return nil, fmt.Errorf("mtgjson: DuckDB requires cgo; in CGO_ENABLED=0 builds use the lite package")
+	}
+	dsn, err := withDuckDBOptions(dsn, options)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("duckdb", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("mtgjson: open DuckDB: %w", err)
 	}
 	// Prevent connection caching issues with temp objects
 	db.SetMaxIdleConns(0)
+	removeOrphanedTempFiles(cache.Logger(), tempDir(cache), orphanedTempAge)
 	return &Connection{
 		db:              db,
 		cache:           cache,
 		registeredViews: make(map[string]bool),
+		overrides:       make(map[string]viewOverride),
+		columns:         make(map[string]map[string]bool),
+		caps:            detectCapabilities(context.Background(), cache.Logger(), db),
 	}, nil
 }
 
+// withDuckDBOptions appends settings to a DSN as query parameters, which the
+// driver applies as the database is opened.
+func withDuckDBOptions(dsn string, options map[string]string) (string, error) {
+	if len(options) == 0 {
+		return dsn, nil
+	}
+	params := url.Values{}
+	for key, value := range options {
+		if key == "" || strings.Trim(key, "abcdefghijklmnopqrstuvwxyz_") != "" {
+			return "", fmt.Errorf("mtgjson: invalid DuckDB option %q", key)
+		}
+		params.Set(key, value)
+	}
+	sep := "?"
+	if strings.Contains(dsn, "?") {
+		sep = "&"
+	}
+	return dsn + sep + params.Encode(), nil
+}
+
 // Close closes the underlying DuckDB connection.
 func (c *Connection) Close() error {
 	if c.db != nil {
@@ -99,6 +150,23 @@ func (c *Connection) EnsureViews(ctx context.Context, names ...string) error {
 	return nil
 }
 
+// EnsureOptionalViews is EnsureViews for data the SDK can work without, such
+// as prices. A failed load is logged and nil is returned, so callers see empty
+// results; in strict mode, when ctx is done, or when the data would need an
+// implicit download (ErrWouldDownload), the error is returned instead.
+// Failed views are not marked as registered, so the next call retries.
+func (c *Connection) EnsureOptionalViews(ctx context.Context, names ...string) error {
+	err := c.EnsureViews(ctx, names...)
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil || c.cache.Strict || errors.Is(err, ErrWouldDownload) {
+		return err
+	}
+	c.Logger().Warn("Optional MTGJSON data unavailable", "view", names, "error", err)
+	return nil
+}
+
 func (c *Connection) ensureView(ctx context.Context, name string) error {
 	c.mu.RLock()
 	if c.registeredViews[name] {
@@ -113,36 +181,64 @@ func (c *Connection) ensureView(ctx context.Context, name string) error {
 		return nil
 	}
 
-	path, err := c.cache.EnsureParquet(ctx, name)
-	if err != nil {
-		return err
+	source, where := name, ""
+	if name == "card_legalities_wide" {
+		// The legalities file as published: a column per format.
+		source = "card_legalities"
 	}
-	pathStr := filepath.ToSlash(path)
+	if ov, ok := c.overrides[name]; ok {
+		source = ov.source
+		if ov.where != "" {
+			where = " WHERE " + ov.where
+		}
+	}
+	var path string
+	if file, ok := localParquetFiles[source]; ok {
+		if path = c.cache.Path(file); !fileExists(path) {
+			return fmt.Errorf("mtgjson: %s has not been recorded yet", file)
+		}
+	} else {
+		var err error
+		if path, err = c.parquetPath(ctx, source); err != nil {
+			return err
+		}
+	}
+	pathStr := SQLPathLiteral(path)
 
+	keep := c.columns[name]
 	if name == "card_legalities" {
-		return c.registerLegalitiesView(ctx, pathStr)
+		return c.registerLegalitiesView(ctx, path, pathStr, keep)
 	}
 
-	replaceClause, err := c.buildCSVReplace(ctx, pathStr, name)
+	replaceClause, err := c.buildCSVReplace(ctx, pathStr, name, keep, !isRemotePath(path))
 	if err != nil {
 		return err
 	}
 
 	_, err = c.db.ExecContext(ctx, fmt.Sprintf(
-		"CREATE OR REPLACE VIEW %s AS SELECT *%s FROM read_parquet('%s')",
-		name, replaceClause, pathStr,
+		"CREATE OR REPLACE VIEW %s AS SELECT *%s FROM read_parquet(%s)%s",
+		name, replaceClause, pathStr, where,
 	))
 	if err != nil {
 		return fmt.Errorf("mtgjson: register view %s: %w", name, err)
 	}
+	if err := c.buildDerived(ctx, name, c.caps); err != nil {
+		return err
+	}
 	c.registeredViews[name] = true
-	slog.Debug("Registered view", "name", name, "path", pathStr)
+	c.Logger().Debug("Registered view", "view", name, "file", path)
 	return nil
 }
 
-func (c *Connection) buildCSVReplace(ctx context.Context, pathStr, viewName string) (string, error) {
+// buildCSVReplace returns the REPLACE clause that splits comma-separated
+// list columns and casts JSON columns. When keep is non-nil, columns outside
+// it are replaced with typed NULLs, so DuckDB does not read them from the
+// file but queries referencing them still run. Unless sample is set, only
+// staticListColumns are split: sampling reads most of the file, which for
+// a remote file means downloading it.
+func (c *Connection) buildCSVReplace(ctx context.Context, pathStr, viewName string, keep map[string]bool, sample bool) (string, error) {
 	rows, err := c.db.QueryContext(ctx, fmt.Sprintf(
-		"SELECT column_name, column_type FROM (DESCRIBE SELECT * FROM read_parquet('%s'))", pathStr,
+		"SELECT column_name, column_type FROM (DESCRIBE SELECT * FROM read_parquet(%s))", pathStr,
 	))
 	if err != nil {
 		return "", err
@@ -168,16 +264,28 @@ func (c *Connection) buildCSVReplace(ctx context.Context, pathStr, viewName stri
 		}
 	}
 
-	// Layer 2: Dynamic heuristic
+	// Layer 2: Sampling. Any other VARCHAR column whose values look like
+	// lists is converted too, so new MTGJSON list columns need no code.
+	var sampled []string
 	for col, dtype := range schema {
-		if dtype != "VARCHAR" {
-			continue
-		}
-		if ignoredColumns[col] {
-			continue
+		if sample && dtype == "VARCHAR" && !candidates[col] && !jsonCastColumns[col] && (keep == nil || keep[col]) {
+			sampled = append(sampled, col)
 		}
-		if strings.HasSuffix(col, "s") {
+	}
+	sort.Strings(sampled)
+	kinds, err := c.detectListColumns(ctx, pathStr, sampled)
+	if err != nil {
+		return "", err
+	}
+	var jsonLists []string
+	for col, kind := range kinds {
+		switch kind {
+		case csvList:
 			candidates[col] = true
+		case jsonArray:
+			if c.caps.JSON { // c.mu is held by ensureView
+				jsonLists = append(jsonLists, col)
+			}
 		}
 	}
 
@@ -190,38 +298,98 @@ func (c *Connection) buildCSVReplace(ctx context.Context, pathStr, viewName stri
 	}
 	sort.Strings(finalCols)
 
-	var exprs []string
+	// types holds each replaced column's type in the view.
+	types := make(map[string]string)
+	exprs := make(map[string]string)
 	for _, col := range finalCols {
-		exprs = append(exprs, fmt.Sprintf(
-			`CASE WHEN "%s" IS NULL OR TRIM("%s") = '' THEN []::VARCHAR[] ELSE string_split("%s", ', ') END AS "%s"`,
-			col, col, col, col,
-		))
+		listType := "VARCHAR[]"
+		if intListColumns[col] {
+			listType = "INTEGER[]"
+		}
+		types[col] = listType
+		exprs[col] = fmt.Sprintf(
+			`CASE WHEN "%s" IS NULL OR TRIM("%s") = '' THEN []::%s ELSE CAST(string_split("%s", ', ') AS %s) END AS "%s"`,
+			col, col, listType, col, listType, col,
+		)
+	}
+	for _, col := range jsonLists {
+		types[col] = "VARCHAR[]"
+		exprs[col] = fmt.Sprintf(`CAST(TRY_CAST("%s" AS JSON) AS VARCHAR[]) AS "%s"`, col, col)
+	}
+
+	// Layer 4: JSON casting, skipped without the json extension; Execute
+	// decodes the JSON strings instead.
+	if c.caps.JSON { // c.mu is held by ensureView
+		for col := range jsonCastColumns {
+			if schema[col] == "VARCHAR" {
+				types[col] = "JSON"
+				exprs[col] = fmt.Sprintf(`TRY_CAST("%s" AS JSON) AS "%s"`, col, col)
+			}
+		}
 	}
 
-	// Layer 4: JSON casting
-	var jsonCols []string
-	for col := range jsonCastColumns {
-		jsonCols = append(jsonCols, col)
-	}
-	sort.Strings(jsonCols)
-	for _, col := range jsonCols {
-		if schema[col] == "VARCHAR" {
-			exprs = append(exprs, fmt.Sprintf(`TRY_CAST("%s" AS JSON) AS "%s"`, col, col))
+	// Layer 5: column projection.
+	if keep != nil {
+		for col, dtype := range schema {
+			if keep[col] {
+				continue
+			}
+			if t, ok := types[col]; ok {
+				dtype = t
+			}
+			exprs[col] = fmt.Sprintf(`CAST(NULL AS %s) AS "%s"`, dtype, col)
 		}
 	}
 
 	if len(exprs) == 0 {
 		return "", nil
 	}
-	return " REPLACE (" + strings.Join(exprs, ", ") + ")", nil
+	cols := make([]string, 0, len(exprs))
+	for col := range exprs {
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+	replaced := make([]string, len(cols))
+	for i, col := range cols {
+		replaced[i] = exprs[col]
+	}
+	return " REPLACE (" + strings.Join(replaced, ", ") + ")", nil
 }
 
-func (c *Connection) registerLegalitiesView(ctx context.Context, pathStr string) error {
+func (c *Connection) registerLegalitiesView(ctx context.Context, path, pathStr string, keep map[string]bool) error {
+	query, formatCols, err := c.legalitiesQuery(ctx, pathStr, keep)
+	if err != nil {
+		return err
+	}
+	if c.materializeLegalities {
+		var version string
+		if isRemotePath(path) {
+			version = c.cache.RemoteVersion(ctx)
+		}
+		err = c.materializeLegalitiesTable(ctx, query, legalitiesSource(path, version, formatCols))
+	} else {
+		err = c.replaceWith(ctx, "card_legalities", "VIEW")
+		if err == nil {
+			_, err = c.db.ExecContext(ctx, "CREATE OR REPLACE VIEW card_legalities AS "+query)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("mtgjson: register legalities view: %w", err)
+	}
+	c.registeredViews["card_legalities"] = true
+	c.Logger().Debug("Registered legalities view", "view", "card_legalities", "formats", len(formatCols), "file", path, "materialized", c.materializeLegalities)
+	return nil
+}
+
+// legalitiesQuery returns a query unpivoting the cardLegalities.parquet at
+// pathStr, a SQL path literal, into (uuid, format, status) rows, and the
+// format columns it reads. keep, if not nil, limits the formats.
+func (c *Connection) legalitiesQuery(ctx context.Context, pathStr string, keep map[string]bool) (string, []string, error) {
 	rows, err := c.db.QueryContext(ctx, fmt.Sprintf(
-		"SELECT column_name FROM (DESCRIBE SELECT * FROM read_parquet('%s'))", pathStr,
+		"SELECT column_name FROM (DESCRIBE SELECT * FROM read_parquet(%s))", pathStr,
 	))
 	if err != nil {
-		return err
+		return "", nil, err
 	}
 	defer rows.Close()
 
@@ -229,46 +397,150 @@ func (c *Connection) registerLegalitiesView(ctx context.Context, pathStr string)
 	for rows.Next() {
 		var col string
 		if err := rows.Scan(&col); err != nil {
-			return err
+			return "", nil, err
 		}
 		allCols = append(allCols, col)
 	}
+	if err := rows.Err(); err != nil {
+		return "", nil, err
+	}
 
 	staticCols := map[string]bool{"uuid": true}
 	var formatCols []string
 	for _, c := range allCols {
-		if !staticCols[c] {
+		if !staticCols[c] && (keep == nil || keep[c]) {
 			formatCols = append(formatCols, c)
 		}
 	}
 
-	if len(formatCols) == 0 {
-		_, err = c.db.ExecContext(ctx, fmt.Sprintf(
-			"CREATE OR REPLACE VIEW card_legalities AS SELECT * FROM read_parquet('%s')", pathStr,
-		))
-	} else {
+	query := fmt.Sprintf("SELECT * FROM read_parquet(%s)", pathStr)
+	if len(formatCols) > 0 {
 		colsSQL := make([]string, len(formatCols))
 		for i, col := range formatCols {
 			colsSQL[i] = `"` + col + `"`
 		}
-		_, err = c.db.ExecContext(ctx, fmt.Sprintf(
-			"CREATE OR REPLACE VIEW card_legalities AS "+
-				"SELECT uuid, format, status FROM ("+
-				"  UNPIVOT (SELECT * FROM read_parquet('%s'))"+
+		query = fmt.Sprintf(
+			"SELECT uuid, format, status FROM ("+
+				"  UNPIVOT (SELECT * FROM read_parquet(%s))"+
 				"  ON %s"+
 				"  INTO NAME format VALUE status"+
 				") WHERE status IS NOT NULL",
 			pathStr, strings.Join(colsSQL, ", "),
-		))
+		)
 	}
-	if err != nil {
-		return fmt.Errorf("mtgjson: register legalities view: %w", err)
+	return query, formatCols, nil
+}
+
+// legalitiesTableIndex speeds up the format and status lookups that every
+// legality query filters on.
+const legalitiesTableIndex = "CREATE INDEX card_legalities_format_idx ON card_legalities (format, status, uuid)"
+
+// materializeLegalitiesTable stores the unpivoted legalities as an indexed
+// table. The table's comment records source; with a persistent database the
+// table is reused by later processes until the file or format list changes.
+func (c *Connection) materializeLegalitiesTable(ctx context.Context, query, source string) error {
+	var comment sql.NullString
+	err := c.db.QueryRowContext(ctx,
+		"SELECT comment FROM duckdb_tables() WHERE table_name = 'card_legalities' "+
+			"AND database_name = current_database() AND schema_name = current_schema()").Scan(&comment)
+	if err == nil && comment.String == source {
+		return nil
+	}
+	if err := c.replaceWith(ctx, "card_legalities", "TABLE"); err != nil {
+		return err
+	}
+	for _, stmt := range []string{
+		"CREATE OR REPLACE TABLE card_legalities AS " + query,
+		legalitiesTableIndex,
+		"COMMENT ON TABLE card_legalities IS '" + strings.ReplaceAll(source, "'", "''") + "'",
+	} {
+		if _, err := c.db.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
 	}
-	c.registeredViews["card_legalities"] = true
-	slog.Debug("Registered legalities view", "formats", len(formatCols), "path", pathStr)
 	return nil
 }
 
+// legalitiesSource identifies the input of a materialized legalities table:
+// the parquet file, its size and modification time, and the formats kept.
+// A remote file has neither, so it is identified by its release version.
+func legalitiesSource(path, version string, formats []string) string {
+	if isRemotePath(path) {
+		return fmt.Sprintf("%s|%s|%s", path, version, strings.Join(formats, ","))
+	}
+	var size, mtime int64
+	if info, err := os.Stat(path); err == nil {
+		size, mtime = info.Size(), info.ModTime().UnixNano()
+	}
+	return fmt.Sprintf("%s|%d|%d|%s", path, size, mtime, strings.Join(formats, ","))
+}
+
+// replaceWith drops name if it exists as a different kind of object than
+// kind ("VIEW" or "TABLE"), which CREATE OR REPLACE cannot change.
+func (c *Connection) replaceWith(ctx context.Context, name, kind string) error {
+	existing, err := c.objectKind(ctx, name)
+	if err != nil || existing == "" || existing == kind {
+		return err
+	}
+	_, err = c.db.ExecContext(ctx, fmt.Sprintf("DROP %s %s", existing, name))
+	return err
+}
+
+// SQLPathLiteral quotes a file path as a DuckDB string literal for functions
+// such as read_parquet. Windows separators become forward slashes, so UNC
+// paths like \\server\share\x.parquet turn into //server/share/x.parquet,
+// and single quotes are doubled.
+func SQLPathLiteral(path string) string {
+	return pathLiteral(path, filepath.Separator)
+}
+
+func pathLiteral(path string, sep rune) string {
+	if sep == '\\' {
+		path = strings.ReplaceAll(path, `\`, "/")
+	}
+	return "'" + strings.ReplaceAll(path, "'", "''") + "'"
+}
+
+// tempSubdir is the directory, inside the configured or OS temp dir, that
+// holds the SDK's temp files, so that orphan cleanup never touches files of
+// other programs sharing the temp dir.
+const tempSubdir = "mtgjson-sdk"
+
+// tempFilePrefix starts the name of every temp file the SDK creates.
+const tempFilePrefix = "mtgjson_"
+
+// orphanedTempAge is how old an SDK temp file must be before it is treated as
+// left behind by a crashed process. Live files exist for seconds at most.
+const orphanedTempAge = time.Hour
+
+// tempDir returns the SDK's tempSubdir of the configured temp directory, or
+// of the OS default, creating it if needed.
+func tempDir(cache *CacheManager) string {
+	base := os.TempDir()
+	if cache != nil && cache.TempDir != "" {
+		base = cache.TempDir
+	}
+	dir := filepath.Join(base, tempSubdir)
+	_ = os.MkdirAll(dir, 0o700) // a failure surfaces when a file is created
+	return dir
+}
+
+// removeOrphanedTempFiles deletes SDK temp files in dir older than age.
+// Errors are ignored: cleanup is best effort.
+func removeOrphanedTempFiles(logger *slog.Logger, dir string, age time.Duration) {
+	matches, _ := filepath.Glob(filepath.Join(dir, tempFilePrefix+"*"))
+	cutoff := time.Now().Add(-age)
+	for _, path := range matches {
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() || info.ModTime().After(cutoff) {
+			continue
+		}
+		if os.Remove(path) == nil {
+			logger.Debug("Removed orphaned temp file", "file", path)
+		}
+	}
+}
+
 // RegisterTableFromData creates a DuckDB table from a slice of maps.
 // Primarily used by unit tests with small sample data.
 func (c *Connection) RegisterTableFromData(ctx context.Context, tableName string, data []map[string]any) error {
@@ -285,20 +557,31 @@ func (c *Connection) RegisterTableFromData(ctx context.Context, tableName string
 		return err
 	}
 
-	tmpPath := filepath.Join(os.TempDir(), fmt.Sprintf("mtgjson_%s_%d.json", tableName, os.Getpid()))
-	if err := os.WriteFile(tmpPath, jsonBytes, 0o644); err != nil {
-		return err
+	f, err := os.CreateTemp(tempDir(c.cache), tempFilePrefix+tableName+"_*.json")
+	if err != nil {
+		return fmt.Errorf("mtgjson: create temp file: %w", err)
 	}
+	tmpPath := f.Name()
 	defer os.Remove(tmpPath)
+	_, err = f.Write(jsonBytes)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("mtgjson: write temp file: %w", err)
+	}
 
-	fwd := filepath.ToSlash(tmpPath)
+	src := SQLPathLiteral(tmpPath)
 	_, err = c.db.ExecContext(ctx, fmt.Sprintf(
-		"CREATE TABLE %s AS SELECT * FROM read_json_auto('%s')", tableName, fwd,
+		"CREATE TABLE %s AS SELECT * FROM read_json_auto(%s)", tableName, src,
 	))
 	if err != nil {
 		return fmt.Errorf("mtgjson: create table %s: %w", tableName, err)
 	}
-	c.registeredViews[tableName] = true
+	if err := c.buildDerived(ctx, tableName, c.Capabilities()); err != nil {
+		return err
+	}
+	c.markRegistered(tableName)
 	return nil
 }
 
@@ -308,32 +591,50 @@ func (c *Connection) RegisterTableFromNdjson(ctx context.Context, tableName, ndj
 	if err != nil {
 		return err
 	}
-	fwd := filepath.ToSlash(ndjsonPath)
+	src := SQLPathLiteral(ndjsonPath)
 	_, err = c.db.ExecContext(ctx, fmt.Sprintf(
-		"CREATE TABLE %s AS SELECT * FROM read_json_auto('%s', format='newline_delimited')",
-		tableName, fwd,
+		"CREATE TABLE %s AS SELECT * FROM read_json_auto(%s, format='newline_delimited')",
+		tableName, src,
 	))
 	if err != nil {
 		return fmt.Errorf("mtgjson: create table %s: %w", tableName, err)
 	}
-	c.registeredViews[tableName] = true
+	if err := c.buildDerived(ctx, tableName, c.Capabilities()); err != nil {
+		return err
+	}
+	c.markRegistered(tableName)
 	return nil
 }
 
 // Execute runs SQL and returns results as []map[string]any.
 func (c *Connection) Execute(ctx context.Context, query string, params ...any) ([]map[string]any, error) {
-	rows, err := c.db.QueryContext(ctx, query, params...)
+	var result []map[string]any
+	err := c.ExecuteEach(ctx, func(row map[string]any) error {
+		result = append(result, row)
+		return nil
+	}, query, params...)
 	if err != nil {
 		return nil, err
 	}
+	return result, nil
+}
+
+// ExecuteEach runs SQL and calls fn with each row, shaped as in Execute,
+// reading rows one at a time. It stops at the first error fn returns.
+func (c *Connection) ExecuteEach(ctx context.Context, fn func(row map[string]any) error, query string, params ...any) (err error) {
+	n := 0
+	defer c.observeQuery(time.Now(), &n, &err)
+	rows, err := c.db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return err
+	}
 	defer rows.Close()
 
 	cols, err := rows.Columns()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	var result []map[string]any
 	for rows.Next() {
 		values := make([]any, len(cols))
 		ptrs := make([]any, len(cols))
@@ -341,19 +642,34 @@ func (c *Connection) Execute(ctx context.Context, query string, params ...any) (
 			ptrs[i] = &values[i]
 		}
 		if err := rows.Scan(ptrs...); err != nil {
-			return nil, err
+			return err
 		}
 		row := make(map[string]any, len(cols))
 		for i, col := range cols {
 			row[col] = coerceValue(values[i])
 		}
-		result = append(result, row)
+		n++
+		if err := fn(row); err != nil {
+			return err
+		}
 	}
-	return result, rows.Err()
+	return rows.Err()
+}
+
+// Exec runs a statement that returns no rows, such as ATTACH or INSERT.
+func (c *Connection) Exec(ctx context.Context, query string, params ...any) (err error) {
+	defer c.observeQuery(time.Now(), nil, &err)
+	_, err = c.db.ExecContext(ctx, query, params...)
+	return err
 }
 
 // ExecuteJSON runs SQL wrapped in to_json(list(...)) and returns a raw JSON string.
-func (c *Connection) ExecuteJSON(ctx context.Context, query string, params ...any) (string, error) {
+// Without the json extension the rows are encoded in Go instead.
+func (c *Connection) ExecuteJSON(ctx context.Context, query string, params ...any) (_ string, err error) {
+	defer c.observeQuery(time.Now(), nil, &err)
+	if !c.Capabilities().JSON {
+		return c.encodeRowsJSON(ctx, query, params...)
+	}
 	wrapped := fmt.Sprintf("SELECT CAST(to_json(list(sub)) AS VARCHAR) FROM (%s) sub", query)
 	row := c.db.QueryRowContext(ctx, wrapped, params...)
 	var result sql.NullString
@@ -376,7 +692,8 @@ func (c *Connection) ExecuteInto(ctx context.Context, dst any, query string, par
 }
 
 // ExecuteScalar runs SQL and returns a single scalar value.
-func (c *Connection) ExecuteScalar(ctx context.Context, query string, params ...any) (any, error) {
+func (c *Connection) ExecuteScalar(ctx context.Context, query string, params ...any) (_ any, err error) {
+	defer c.observeQuery(time.Now(), nil, &err)
 	row := c.db.QueryRowContext(ctx, query, params...)
 	var val any
 	if err := row.Scan(&val); err != nil {
@@ -388,12 +705,121 @@ func (c *Connection) ExecuteScalar(ctx context.Context, query string, params ...
 	return val, nil
 }
 
-// Raw returns the underlying *sql.DB for advanced usage.
+// Raw returns the underlying *sql.DB for advanced usage. It is not part of
+// Backend; query modules use Exec and ExecuteEach instead.
 func (c *Connection) Raw() *sql.DB {
 	return c.db
 }
 
-// ClearViews resets the registered views set (used by Refresh).
+func (c *Connection) markRegistered(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.registeredViews[name] = true
+}
+
+// ResetViews forgets the given views so the next EnsureViews registers them
+// again, downloading the data if it is missing.
+func (c *Connection) ResetViews(names ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, name := range names {
+		delete(c.registeredViews, name)
+	}
+}
+
+// OverrideView makes a view read source's file instead of its own, keeping
+// only rows that match where (a SQL condition, or "" for all rows). The view
+// is registered again on its next use, and the override survives ResetViews,
+// DropViews and ClearViews until RestoreView is called.
+func (c *Connection) OverrideView(name, source, where string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.overrides[name] = viewOverride{source: source, where: where}
+	delete(c.registeredViews, name)
+}
+
+// SetViewColumns limits a view to the given parquet columns, for
+// memory-constrained use. Other columns read as typed NULLs, so queries
+// that use them still run but match or return nothing for them. "uuid" is
+// always kept. For card_legalities the columns are format names. No
+// columns removes the limit. The view is registered again on its next use.
+func (c *Connection) SetViewColumns(name string, columns ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(columns) == 0 {
+		delete(c.columns, name)
+	} else {
+		keep := map[string]bool{"uuid": true}
+		for _, col := range columns {
+			keep[col] = true
+		}
+		c.columns[name] = keep
+	}
+	delete(c.registeredViews, name)
+}
+
+// MaterializeLegalities sets whether card_legalities is an indexed table,
+// built once from the unpivoted parquet file, rather than a view that
+// unpivots on every query. Tables cost memory (or disk, with a file DSN,
+// where they are reused across processes) but make joins on format and
+// status much faster. The view is registered again on its next use.
+func (c *Connection) MaterializeLegalities(on bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.materializeLegalities = on
+	delete(c.registeredViews, "card_legalities")
+}
+
+// RestoreView removes a view's override; it is registered again from its own
+// file on next use.
+func (c *Connection) RestoreView(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.overrides[name]; ok {
+		delete(c.overrides, name)
+		delete(c.registeredViews, name)
+	}
+}
+
+// DropViews drops the given views or tables from DuckDB and forgets them, so
+// the next EnsureViews registers them again from freshly resolved files.
+func (c *Connection) DropViews(ctx context.Context, names ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, name := range names {
+		kind, err := c.objectKind(ctx, name)
+		if err != nil {
+			return err
+		}
+		if kind != "" {
+			if _, err := c.db.ExecContext(ctx, fmt.Sprintf("DROP %s IF EXISTS %s", kind, name)); err != nil {
+				return fmt.Errorf("mtgjson: drop %s: %w", name, err)
+			}
+		}
+		delete(c.registeredViews, name)
+	}
+	return nil
+}
+
+// objectKind returns "VIEW" or "TABLE" for a DuckDB object, or "" if it
+// does not exist.
+func (c *Connection) objectKind(ctx context.Context, name string) (string, error) {
+	var tableType string
+	err := c.db.QueryRowContext(ctx,
+		"SELECT table_type FROM information_schema.tables WHERE table_name = $1", name).Scan(&tableType)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("mtgjson: look up %s: %w", name, err)
+	}
+	if tableType == "VIEW" {
+		return "VIEW", nil
+	}
+	return "TABLE", nil
+}
+
+// ClearViews resets the registered views set without dropping the views.
 func (c *Connection) ClearViews() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -2,7 +2,13 @@ package db
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func testConnection(t *testing.T) *Connection {
@@ -184,3 +190,514 @@ func TestConnectionViews(t *testing.T) {
 		t.Fatal("expected HasView to return true")
 	}
 }
+
+func TestConnectionConcurrentRegisterAndViews(t *testing.T) {
+	conn := testConnection(t)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("concurrent_%d", i)
+			if err := conn.RegisterTableFromData(ctx, name, []map[string]any{{"x": i}}); err != nil {
+				t.Error(err)
+				return
+			}
+			_ = conn.Views()
+			if !conn.HasView(name) {
+				t.Errorf("expected view %s", name)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if got := len(conn.Views()); got != 8 {
+		t.Fatalf("expected 8 views, got %d", got)
+	}
+}
+
+func TestEnsureOptionalViews(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		cfg := DefaultConfig()
+		cfg.CacheDir = t.TempDir()
+		cfg.Offline = true
+		cfg.Strict = strict
+		cache, err := NewCacheManager(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn, err := NewConnection(cache)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		err = conn.EnsureOptionalViews(context.Background(), "all_prices_today")
+		if strict && err == nil {
+			t.Fatal("expected an error in strict mode")
+		}
+		if !strict && err != nil {
+			t.Fatalf("expected the error to be logged, got %v", err)
+		}
+		if conn.HasView("all_prices_today") {
+			t.Fatal("a failed load must not mark the view registered")
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := conn.EnsureOptionalViews(ctx, "all_prices_today"); err == nil {
+			t.Fatal("expected a cancelled context to surface the error")
+		}
+	}
+}
+
+func TestConnectionResetViews(t *testing.T) {
+	conn := testConnection(t)
+	if err := conn.RegisterTableFromData(context.Background(), "reset_me", []map[string]any{{"x": 1}}); err != nil {
+		t.Fatal(err)
+	}
+	conn.ResetViews("reset_me", "never_registered")
+	if conn.HasView("reset_me") {
+		t.Fatal("expected reset_me to be forgotten")
+	}
+}
+
+func TestConnectionDropViews(t *testing.T) {
+	conn := testConnection(t)
+	ctx := context.Background()
+	if err := conn.RegisterTableFromData(ctx, "drop_table", []map[string]any{{"x": 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Raw().ExecContext(ctx, "CREATE VIEW drop_view AS SELECT * FROM drop_table"); err != nil {
+		t.Fatal(err)
+	}
+	conn.markRegistered("drop_view")
+
+	if err := conn.DropViews(ctx, "drop_view", "drop_table", "missing"); err != nil {
+		t.Fatal(err)
+	}
+	if conn.HasView("drop_view") || conn.HasView("drop_table") {
+		t.Fatalf("expected dropped views to be forgotten, got %v", conn.Views())
+	}
+	val, err := conn.ExecuteScalar(ctx,
+		"SELECT COUNT(*) FROM information_schema.tables WHERE table_name IN ('drop_view', 'drop_table')")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, ok := val.(int64); !ok || n != 0 {
+		t.Fatalf("expected the DuckDB objects to be dropped, %v remain", val)
+	}
+}
+
+func TestRegisterTableFromDataTempDir(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.TempDir = filepath.Join(t.TempDir(), "tmp")
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// Two connections in one process share the temp dir and table name.
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		conn, err := NewConnection(cache)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				if err := conn.RegisterTableFromData(ctx, "shared", []map[string]any{{"x": i}}); err != nil {
+					t.Error(err)
+					return
+				}
+				val, err := conn.ExecuteScalar(ctx, "SELECT x FROM shared")
+				if err != nil || ToInt(val) != i {
+					t.Errorf("connection %d read %v (%v)", i, val, err)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	entries, err := os.ReadDir(filepath.Join(cfg.TempDir, tempSubdir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected temp files to be removed, found %d", len(entries))
+	}
+}
+
+func TestNewConnectionRemovesOrphanedTempFiles(t *testing.T) {
+	dir := t.TempDir()
+	sdkDir := filepath.Join(dir, tempSubdir)
+	if err := os.MkdirAll(sdkDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	old := filepath.Join(sdkDir, "mtgjson_cards_123.json")
+	fresh := filepath.Join(sdkDir, "mtgjson_sets_456.json")
+	other := filepath.Join(sdkDir, "unrelated.json")
+	// Another program's file in the shared temp dir, with the same prefix.
+	shared := filepath.Join(dir, "mtgjson_other_tool.json")
+	for _, path := range []string{old, fresh, other, shared} {
+		if err := os.WriteFile(path, []byte("[]"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stale := time.Now().Add(-2 * orphanedTempAge)
+	for _, path := range []string{old, other, shared} {
+		if err := os.Chtimes(path, stale, stale); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.TempDir = dir
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := NewConnection(cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+
+	if fileExists(old) {
+		t.Fatal("expected the orphaned temp file to be removed")
+	}
+	if !fileExists(fresh) || !fileExists(other) || !fileExists(shared) {
+		t.Fatal("expected fresh SDK files and unrelated files to be kept")
+	}
+}
+
+func TestPathLiteral(t *testing.T) {
+	tests := []struct {
+		path string
+		sep  rune
+		want string
+	}{
+		{`C:\Users\me\mtgjson-sdk\parquet\cards.parquet`, '\\', `'C:/Users/me/mtgjson-sdk/parquet/cards.parquet'`},
+		{`\\fileserver\share\mtgjson\cards.parquet`, '\\', `'//fileserver/share/mtgjson/cards.parquet'`},
+		{`\\?\UNC\fileserver\share\cards.parquet`, '\\', `'//?/UNC/fileserver/share/cards.parquet'`},
+		{`C:\Users\o'brien\cards.parquet`, '\\', `'C:/Users/o''brien/cards.parquet'`},
+		{`/home/o'brien/.cache/cards.parquet`, '/', `'/home/o''brien/.cache/cards.parquet'`},
+		{`/tmp/back\slash.parquet`, '/', `'/tmp/back\slash.parquet'`},
+	}
+	for _, tt := range tests {
+		if got := pathLiteral(tt.path, tt.sep); got != tt.want {
+			t.Errorf("pathLiteral(%q) = %s, want %s", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRegisterTableFromDataQuotedTempDir(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.TempDir = filepath.Join(t.TempDir(), "o'brien")
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := NewConnection(cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if err := conn.RegisterTableFromData(context.Background(), "quoted", []map[string]any{{"x": 1}}); err != nil {
+		t.Fatalf("expected a quote in the temp path to be escaped: %v", err)
+	}
+}
+
+func TestEnsureViewsIntListColumns(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.Offline = true
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := NewConnection(cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	ctx := context.Background()
+
+	path := filepath.Join(cfg.CacheDir, "parquet", "cards.parquet")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	_, err = conn.Raw().ExecContext(ctx, fmt.Sprintf(
+		"COPY (SELECT 'a' AS uuid, '2, 4, 6' AS attractionLights, 'Artifact' AS types "+
+			"UNION ALL SELECT 'b', NULL, 'Instant') TO %s (FORMAT PARQUET)", SQLPathLiteral(path)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.EnsureViews(ctx, "cards"); err != nil {
+		t.Fatal(err)
+	}
+	val, err := conn.ExecuteScalar(ctx, "SELECT typeof(attractionLights) FROM cards LIMIT 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val != "INTEGER[]" {
+		t.Fatalf("expected attractionLights as INTEGER[], got %v", val)
+	}
+	val, err = conn.ExecuteScalar(ctx, "SELECT uuid FROM cards WHERE list_contains(attractionLights, 6)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val != "a" {
+		t.Fatalf("expected card a to be lit on 6, got %v", val)
+	}
+}
+
+func TestOpenConnectionDSN(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dsn := filepath.Join(t.TempDir(), "mtgjson.duckdb")
+	ctx := context.Background()
+
+	conn, err := OpenConnection(cache, dsn, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Raw().ExecContext(ctx, "CREATE TABLE kept AS SELECT 42 AS answer"); err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+
+	conn, err = OpenConnection(cache, dsn, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	val, err := conn.ExecuteScalar(ctx, "SELECT answer FROM kept")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(val) != "42" {
+		t.Fatalf("expected the table to persist in the database file, got %v", val)
+	}
+}
+
+func TestOpenConnectionOptions(t *testing.T) {
+	cache, err := NewCacheManager(DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := OpenConnection(cache, "", map[string]string{"threads": "1", "memory_limit": "256MB"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	ctx := context.Background()
+	val, err := conn.ExecuteScalar(ctx, "SELECT current_setting('threads')")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(val) != "1" {
+		t.Errorf("expected threads = 1, got %v", val)
+	}
+	val, err = conn.ExecuteScalar(ctx, "SELECT current_setting('memory_limit')")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(val); !strings.Contains(s, "MiB") && !strings.Contains(s, "MB") {
+		t.Errorf("expected a 256MB memory limit, got %v", val)
+	}
+
+	if _, err := OpenConnection(cache, "", map[string]string{"threads; DROP": "1"}); err == nil {
+		t.Error("expected an invalid option name to be rejected")
+	}
+	if _, err := OpenConnection(cache, "", map[string]string{"no_such_setting": "1"}); err == nil {
+		t.Error("expected an unknown DuckDB setting to fail")
+	}
+}
+
+func TestWithDuckDBOptions(t *testing.T) {
+	got, err := withDuckDBOptions("data.duckdb?access_mode=read_only", map[string]string{"threads": "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "data.duckdb?access_mode=read_only&threads=2" {
+		t.Errorf("unexpected DSN %q", got)
+	}
+}
+
+func TestSetViewColumns(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.Offline = true
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := NewConnection(cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	ctx := context.Background()
+
+	if err := os.MkdirAll(filepath.Join(cfg.CacheDir, "parquet"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeParquet(t, conn, filepath.Join(cfg.CacheDir, "parquet", "cards.parquet"),
+		`SELECT 'a' AS uuid, 'Shock' AS name, 'Instant' AS types, 'Shock deals 2 damage.' AS text, 2 AS edhrecRank`)
+	writeParquet(t, conn, filepath.Join(cfg.CacheDir, "parquet", "cardLegalities.parquet"),
+		`SELECT 'a' AS uuid, 'Legal' AS standard, 'Legal' AS modern`)
+
+	conn.SetViewColumns("cards", "name")
+	conn.SetViewColumns("card_legalities", "modern")
+	if err := conn.EnsureViews(ctx, "cards", "card_legalities"); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := conn.Execute(ctx, "SELECT uuid, name, text, typeof(types) AS t, typeof(edhrecRank) AS r FROM cards")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || rows[0]["uuid"] != "a" || rows[0]["name"] != "Shock" || rows[0]["text"] != nil {
+		t.Fatalf("expected uuid and name kept and text dropped, got %v", rows)
+	}
+	if rows[0]["t"] != "VARCHAR[]" || rows[0]["r"] != "INTEGER" {
+		t.Errorf("expected dropped columns to keep their types, got %v and %v", rows[0]["t"], rows[0]["r"])
+	}
+	formats, err := conn.Execute(ctx, "SELECT format FROM card_legalities")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(formats) != 1 || formats[0]["format"] != "modern" {
+		t.Errorf("expected only modern legalities, got %v", formats)
+	}
+
+	conn.SetViewColumns("cards")
+	if err := conn.EnsureViews(ctx, "cards"); err != nil {
+		t.Fatal(err)
+	}
+	if val, err := conn.ExecuteScalar(ctx, "SELECT text FROM cards"); err != nil || val != "Shock deals 2 damage." {
+		t.Errorf("expected all columns after removing the limit, got %v (%v)", val, err)
+	}
+}
+
+func TestMaterializeLegalities(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.Offline = true
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dsn := filepath.Join(t.TempDir(), "mtgjson.duckdb")
+	ctx := context.Background()
+	open := func(materialize bool) *Connection {
+		t.Helper()
+		conn, err := OpenConnection(cache, dsn, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn.MaterializeLegalities(materialize)
+		return conn
+	}
+
+	conn := open(true)
+	path := filepath.Join(cfg.CacheDir, "parquet", "cardLegalities.parquet")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeParquet(t, conn, path, `SELECT * FROM (VALUES
+		('a', 'Legal', 'Legal'), ('b', NULL, 'Banned')
+	) t(uuid, standard, modern)`)
+	if err := conn.EnsureViews(ctx, "card_legalities"); err != nil {
+		t.Fatal(err)
+	}
+	if kind, err := conn.objectKind(ctx, "card_legalities"); err != nil || kind != "TABLE" {
+		t.Fatalf("expected a table, got %q (%v)", kind, err)
+	}
+	if n, err := conn.ExecuteScalar(ctx, "SELECT COUNT(*) FROM duckdb_indexes() WHERE table_name = 'card_legalities'"); err != nil || ScalarToInt(n) != 1 {
+		t.Fatalf("expected an index on card_legalities, got %v (%v)", n, err)
+	}
+	if val, err := conn.ExecuteScalar(ctx, "SELECT uuid FROM card_legalities WHERE format = 'modern' AND status = 'Banned'"); err != nil || val != "b" {
+		t.Fatalf("expected b banned in modern, got %v (%v)", val, err)
+	}
+	// Mark the table to tell a reused table from a rebuilt one.
+	if _, err := conn.Raw().ExecContext(ctx, "INSERT INTO card_legalities VALUES ('marker', 'vintage', 'Legal')"); err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+
+	conn = open(true)
+	if err := conn.EnsureViews(ctx, "card_legalities"); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := conn.ExecuteScalar(ctx, "SELECT COUNT(*) FROM card_legalities WHERE uuid = 'marker'"); ScalarToInt(n) != 1 {
+		t.Error("expected the table reused by a later process")
+	}
+	conn.Close()
+
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	conn = open(true)
+	if err := conn.EnsureViews(ctx, "card_legalities"); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := conn.ExecuteScalar(ctx, "SELECT COUNT(*) FROM card_legalities"); ScalarToInt(n) != 3 {
+		t.Errorf("expected the table rebuilt after the file changed, got %d rows", ScalarToInt(n))
+	}
+
+	conn.MaterializeLegalities(false)
+	if err := conn.EnsureViews(ctx, "card_legalities"); err != nil {
+		t.Fatal(err)
+	}
+	if kind, err := conn.objectKind(ctx, "card_legalities"); err != nil || kind != "VIEW" {
+		t.Errorf("expected a view again, got %q (%v)", kind, err)
+	}
+	conn.Close()
+}
+
+func TestWideLegalitiesView(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.Offline = true
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := NewConnection(cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	ctx := context.Background()
+
+	path := filepath.Join(cfg.CacheDir, "parquet", "cardLegalities.parquet")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeParquet(t, conn, path, `SELECT 'a' AS uuid, 'Legal' AS standard, 'Banned' AS modern`)
+	if err := conn.EnsureViews(ctx, "card_legalities", "card_legalities_wide"); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := conn.Execute(ctx, "SELECT * FROM card_legalities_wide")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || rows[0]["standard"] != "Legal" || rows[0]["modern"] != "Banned" {
+		t.Errorf("expected the per-format columns, got %v", rows)
+	}
+}
@@ -0,0 +1,98 @@
+package db
+
+import "slices"
+
+// Dataset names an MTGJSON dataset: a view registered by EnsureViews (a
+// key of ParquetFiles) or a JSON file loaded by LoadJSON (a key of
+// JSONFiles). It is an alias of string, so the constants below can be
+// passed wherever a view name is taken.
+type Dataset = string
+
+// Datasets published on the MTGJSON CDN.
+const (
+	DatasetCards                    Dataset = "cards"
+	DatasetTokens                   Dataset = "tokens"
+	DatasetSets                     Dataset = "sets"
+	DatasetCardIdentifiers          Dataset = "card_identifiers"
+	DatasetCardLegalities           Dataset = "card_legalities"
+	DatasetCardForeignData          Dataset = "card_foreign_data"
+	DatasetCardRulings              Dataset = "card_rulings"
+	DatasetCardPurchaseURLs         Dataset = "card_purchase_urls"
+	DatasetSetTranslations          Dataset = "set_translations"
+	DatasetTokenIdentifiers         Dataset = "token_identifiers"
+	DatasetSetBoosterContentWeights Dataset = "set_booster_content_weights"
+	DatasetSetBoosterContents       Dataset = "set_booster_contents"
+	DatasetSetBoosterSheetCards     Dataset = "set_booster_sheet_cards"
+	DatasetSetBoosterSheets         Dataset = "set_booster_sheets"
+	DatasetAllPrintings             Dataset = "all_printings" // both a view and AllPrintings.json.gz
+	DatasetAllPricesToday           Dataset = "all_prices_today"
+	DatasetAllPrices                Dataset = "all_prices"
+	DatasetTCGPlayerSKUs            Dataset = "tcgplayer_skus"
+	DatasetSealedProducts           Dataset = "sealed_products"
+	DatasetSetDecks                 Dataset = "set_decks"
+	DatasetKeywords                 Dataset = "keywords"
+	DatasetCardTypes                Dataset = "card_types"
+	DatasetDeckList                 Dataset = "deck_list"
+	DatasetEnumValues               Dataset = "enum_values"
+	DatasetMeta                     Dataset = "meta"
+)
+
+// DatasetLegalityHistory is the view over the cache's legality history,
+// written by RecordLegalities rather than downloaded.
+const DatasetLegalityHistory Dataset = "legality_history"
+
+// DatasetInfo describes a dataset in the Registry.
+type DatasetInfo struct {
+	Name        Dataset
+	File        string // path on the CDN, relative to CDNBase
+	Format      string // "parquet" (a view) or "json"
+	ApproxSize  int64  // rough download size in bytes; real sizes vary by release
+	Description string
+}
+
+const mb = 1 << 20
+
+// registry lists the CDN datasets in the order Registry returns them.
+var registry = []DatasetInfo{
+	{Name: DatasetCards, Format: "parquet", ApproxSize: 80 * mb, Description: "Every card printing, one row per face"},
+	{Name: DatasetTokens, Format: "parquet", ApproxSize: 5 * mb, Description: "Token, emblem, dungeon and art series printings"},
+	{Name: DatasetSets, Format: "parquet", ApproxSize: 10 * mb, Description: "Sets, with nested booster and sealed product data"},
+	{Name: DatasetCardIdentifiers, Format: "parquet", ApproxSize: 30 * mb, Description: "Third-party IDs per printing (Scryfall, TCGplayer, MTGO, ...)"},
+	{Name: DatasetCardLegalities, Format: "parquet", ApproxSize: 5 * mb, Description: "Format legality per printing, unpivoted to (uuid, format, status)"},
+	{Name: DatasetCardForeignData, Format: "parquet", ApproxSize: 60 * mb, Description: "Translated names and text per printing"},
+	{Name: DatasetCardRulings, Format: "parquet", ApproxSize: 15 * mb, Description: "Rulings per printing"},
+	{Name: DatasetCardPurchaseURLs, Format: "parquet", ApproxSize: 10 * mb, Description: "Store links per printing"},
+	{Name: DatasetSetTranslations, Format: "parquet", ApproxSize: 1 * mb, Description: "Translated set names"},
+	{Name: DatasetTokenIdentifiers, Format: "parquet", ApproxSize: 2 * mb, Description: "Third-party IDs per token printing"},
+	{Name: DatasetSetBoosterContentWeights, Format: "parquet", ApproxSize: 1 * mb, Description: "Booster pack layouts and their weights"},
+	{Name: DatasetSetBoosterContents, Format: "parquet", ApproxSize: 1 * mb, Description: "Cards drawn from each sheet per pack layout"},
+	{Name: DatasetSetBoosterSheetCards, Format: "parquet", ApproxSize: 10 * mb, Description: "Cards on each booster sheet with their weights"},
+	{Name: DatasetSetBoosterSheets, Format: "parquet", ApproxSize: 1 * mb, Description: "Booster sheets and their foil and balance flags"},
+	{Name: DatasetAllPrintings, Format: "parquet", ApproxSize: 400 * mb, Description: "Every set with its cards and tokens nested"},
+	{Name: DatasetAllPricesToday, Format: "parquet", ApproxSize: 40 * mb, Description: "Prices from the latest release, per provider and finish"},
+	{Name: DatasetAllPrices, Format: "parquet", ApproxSize: 1000 * mb, Description: "90 days of price history"},
+	{Name: DatasetTCGPlayerSKUs, Format: "parquet", ApproxSize: 50 * mb, Description: "TCGplayer SKUs per printing, condition and language"},
+	{Name: DatasetSealedProducts, Format: "parquet", ApproxSize: 5 * mb, Description: "Sealed products and their contents"},
+	{Name: DatasetSetDecks, Format: "parquet", ApproxSize: 5 * mb, Description: "Preconstructed decks per set"},
+	{Name: DatasetKeywords, Format: "json", ApproxSize: 1 * mb, Description: "Keyword abilities, actions and ability words"},
+	{Name: DatasetCardTypes, Format: "json", ApproxSize: 1 * mb, Description: "Card types with their sub- and supertypes"},
+	{Name: DatasetDeckList, Format: "json", ApproxSize: 1 * mb, Description: "Index of preconstructed decks"},
+	{Name: DatasetEnumValues, Format: "json", ApproxSize: 1 * mb, Description: "Enumerated values of MTGJSON fields"},
+	{Name: DatasetMeta, Format: "json", ApproxSize: 1 << 10, Description: "Release version and date"},
+	{Name: DatasetAllPrintings, Format: "json", ApproxSize: 150 * mb, Description: "AllPrintings.json.gz, the whole dataset as JSON"},
+}
+
+// Registry returns the datasets on the MTGJSON CDN with their files, rough
+// sizes and descriptions, parquet views first. File reflects ParquetFiles
+// and JSONFiles, including any overrides made to them.
+func Registry() []DatasetInfo {
+	out := slices.Clone(registry)
+	for i := range out {
+		files := ParquetFiles
+		if out[i].Format == "json" {
+			files = JSONFiles
+		}
+		out[i].File = files[out[i].Name]
+	}
+	return out
+}
@@ -0,0 +1,29 @@
+package db
+
+import "testing"
+
+func TestRegistryCoversFiles(t *testing.T) {
+	listed := map[string]map[string]bool{"parquet": {}, "json": {}}
+	for _, d := range Registry() {
+		if d.File == "" {
+			t.Errorf("%s (%s) has no file", d.Name, d.Format)
+		}
+		if d.ApproxSize <= 0 || d.Description == "" {
+			t.Errorf("%s (%s) lacks a size or description", d.Name, d.Format)
+		}
+		listed[d.Format][d.Name] = true
+	}
+	for name := range ParquetFiles {
+		if !listed["parquet"][name] {
+			t.Errorf("parquet dataset %s is missing from the registry", name)
+		}
+	}
+	for name := range JSONFiles {
+		if !listed["json"][name] {
+			t.Errorf("json dataset %s is missing from the registry", name)
+		}
+	}
+	if got := Registry()[0].File; got != ParquetFiles[DatasetCards] {
+		t.Errorf("expected cards first, from ParquetFiles, got %s", got)
+	}
+}
@@ -0,0 +1,138 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// LatestPricesTable holds each price series' most recent row from
+// all_prices_today: one row per uuid, source, provider, price type and
+// finish. It is rebuilt whenever all_prices_today is registered, so price
+// queries can join it directly instead of finding the latest date per row.
+const LatestPricesTable = "latest_prices"
+
+// DeckCardsTable indexes set_decks by card: one row per card and board
+// (mainBoard, sideBoard or commander) of each preconstructed deck, with the
+// deck's code, name, type, set code and release date. It is rebuilt
+// whenever set_decks is registered. It needs the json extension.
+const DeckCardsTable = "deck_cards"
+
+// derivedTable is a summary table built from a view when it is registered.
+type derivedTable struct {
+	name string
+	// query returns the table's SELECT given the source's columns, or ""
+	// when it cannot be built from them.
+	query func(cols map[string]bool, caps Capabilities) string
+	index string // optional CREATE INDEX statement
+}
+
+// derivedTables lists the tables built from each view. They are sorted on
+// the columns queries filter by, so DuckDB's zone maps skip most row groups.
+var derivedTables = map[string][]derivedTable{
+	"all_prices_today": {{
+		name:  LatestPricesTable,
+		query: latestPricesQuery,
+		index: "CREATE INDEX latest_prices_uuid_idx ON latest_prices (uuid)",
+	}},
+	"set_decks": {{
+		name:  DeckCardsTable,
+		query: deckCardsQuery,
+		index: "CREATE INDEX deck_cards_uuid_idx ON deck_cards (uuid)",
+	}},
+}
+
+func latestPricesQuery(cols map[string]bool, _ Capabilities) string {
+	for _, col := range []string{"uuid", "source", "provider", "price_type", "finish", "date"} {
+		if !cols[col] {
+			return ""
+		}
+	}
+	return "SELECT * FROM all_prices_today " +
+		"QUALIFY date = MAX(date) OVER (PARTITION BY uuid, source, provider, price_type, finish) " +
+		"ORDER BY provider, finish, price_type, uuid"
+}
+
+// deckCardsQuery unnests each board through JSON, which reads the boards
+// whether they are JSON strings, JSON values or lists of structs.
+func deckCardsQuery(cols map[string]bool, caps Capabilities) string {
+	if !caps.JSON || !cols["code"] {
+		return ""
+	}
+	column := func(name string) string {
+		if cols[name] {
+			return name
+		}
+		return "NULL"
+	}
+	var boards []string
+	for _, board := range []string{"mainBoard", "sideBoard", "commander"} {
+		if cols[board] {
+			boards = append(boards, fmt.Sprintf(
+				"SELECT code, %s AS name, %s AS type, %s AS setCode, %s AS releaseDate, "+
+					"'%s' AS board, unnest(CAST(CAST(%s AS JSON) AS JSON[])) AS card FROM set_decks",
+				column("name"), column("type"), column("setCode"), column("releaseDate"), board, board))
+		}
+	}
+	if len(boards) == 0 {
+		return ""
+	}
+	return "SELECT card->>'uuid' AS uuid, code AS deckCode, CAST(name AS VARCHAR) AS deckName, " +
+		"CAST(type AS VARCHAR) AS deckType, CAST(setCode AS VARCHAR) AS setCode, " +
+		"CAST(releaseDate AS VARCHAR) AS releaseDate, board, " +
+		"COALESCE(TRY_CAST(card->>'count' AS INTEGER), 1) AS count, " +
+		"COALESCE(TRY_CAST(card->>'isFoil' AS BOOLEAN), false) AS isFoil " +
+		"FROM (" + strings.Join(boards, " UNION ALL ") + ") " +
+		"ORDER BY uuid, releaseDate, deckCode"
+}
+
+// buildDerived rebuilds the tables derived from view, skipping those its
+// columns cannot provide. If a table cannot be created, e.g. in a read-only
+// database, a view with the same query is registered instead.
+func (c *Connection) buildDerived(ctx context.Context, view string, caps Capabilities) error {
+	tables := derivedTables[view]
+	if len(tables) == 0 {
+		return nil
+	}
+	rows, err := c.db.QueryContext(ctx, "SELECT column_name FROM (DESCRIBE SELECT * FROM "+view+")")
+	if err != nil {
+		return fmt.Errorf("mtgjson: describe %s: %w", view, err)
+	}
+	cols := map[string]bool{}
+	for rows.Next() {
+		var col string
+		if err := rows.Scan(&col); err != nil {
+			rows.Close()
+			return err
+		}
+		cols[col] = true
+	}
+	rows.Close()
+
+	for _, t := range tables {
+		query := t.query(cols, caps)
+		if query == "" {
+			c.Logger().Debug("Skipped derived table", "view", t.name, "source", view)
+			continue
+		}
+		err := c.replaceWith(ctx, t.name, "TABLE")
+		if err == nil {
+			_, err = c.db.ExecContext(ctx, "CREATE OR REPLACE TABLE "+t.name+" AS "+query)
+		}
+		if err == nil && t.index != "" {
+			_, err = c.db.ExecContext(ctx, t.index)
+		}
+		if err != nil {
+			c.Logger().Debug("Falling back to a view for derived table", "view", t.name, "error", err)
+			err = c.replaceWith(ctx, t.name, "VIEW")
+			if err == nil {
+				_, err = c.db.ExecContext(ctx, "CREATE OR REPLACE VIEW "+t.name+" AS "+query)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("mtgjson: build %s: %w", t.name, err)
+		}
+		c.Logger().Debug("Built derived table", "view", t.name, "source", view)
+	}
+	return nil
+}
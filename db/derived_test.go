@@ -0,0 +1,36 @@
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLatestPricesRebuiltOnLoad(t *testing.T) {
+	conn := testConnection(t)
+	ctx := context.Background()
+	price := func(date string, value float64) map[string]any {
+		return map[string]any{
+			"uuid": "u1", "source": "paper", "provider": "tcgplayer",
+			"price_type": "retail", "finish": "normal", "date": date, "price": value,
+		}
+	}
+
+	if err := conn.RegisterTableFromData(ctx, "all_prices_today", []map[string]any{
+		price("2024-01-01", 1), price("2024-01-02", 2),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := conn.ExecuteScalar(ctx, "SELECT price FROM latest_prices"); err != nil || ToFloat64(v) != 2 {
+		t.Fatalf("expected the newest price 2, got %v (%v)", v, err)
+	}
+
+	// Loading new prices replaces the table.
+	if err := conn.RegisterTableFromData(ctx, "all_prices_today", []map[string]any{
+		price("2024-01-03", 3),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := conn.ExecuteScalar(ctx, "SELECT price FROM latest_prices"); err != nil || ToFloat64(v) != 3 {
+		t.Fatalf("expected the reloaded price 3, got %v (%v)", v, err)
+	}
+}
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// diffRowHash is the per-row expression DiffParquet compares, keyed by the
+// views it supports. Price rows leave out the date, which changes with every
+// build even when the price does not.
+var diffRowHash = map[string]string{
+	"cards":            "md5(CAST(t AS VARCHAR))",
+	"all_prices_today": "md5(concat_ws('|', source, provider, currency, price_type, finish, CAST(price AS VARCHAR)))",
+}
+
+// CanDiff reports whether DiffParquet supports a view.
+func CanDiff(view string) bool {
+	_, ok := diffRowHash[view]
+	return ok
+}
+
+// DiffParquet compares two releases of a view's parquet file by card UUID.
+// A UUID is added or removed when it appears in only one file, and modified
+// when its rows differ; for prices that means any price changed, appeared or
+// disappeared. Each list is sorted.
+func (c *Connection) DiffParquet(ctx context.Context, view, oldPath, newPath string) (added, removed, modified []string, err error) {
+	rowHash, ok := diffRowHash[view]
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("mtgjson: cannot diff view %q", view)
+	}
+	side := func(path string) string {
+		return fmt.Sprintf("SELECT uuid, md5(string_agg(h, ',' ORDER BY h)) AS h "+
+			"FROM (SELECT uuid, %s AS h FROM read_parquet(%s) t) GROUP BY uuid",
+			rowHash, SQLPathLiteral(path))
+	}
+	rows, err := c.Raw().QueryContext(ctx, fmt.Sprintf(
+		"WITH o AS (%s), n AS (%s) "+
+			"SELECT COALESCE(n.uuid, o.uuid), o.uuid IS NULL, n.uuid IS NULL "+
+			"FROM o FULL OUTER JOIN n ON o.uuid = n.uuid "+
+			"WHERE o.h IS DISTINCT FROM n.h", side(oldPath), side(newPath)))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("mtgjson: diff %s: %w", view, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var uuid string
+		var isNew, isGone bool
+		if err := rows.Scan(&uuid, &isNew, &isGone); err != nil {
+			return nil, nil, nil, err
+		}
+		switch {
+		case isNew:
+			added = append(added, uuid)
+		case isGone:
+			removed = append(removed, uuid)
+		default:
+			modified = append(modified, uuid)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, nil, err
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Strings(modified)
+	return added, removed, modified, nil
+}
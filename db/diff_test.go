@@ -0,0 +1,59 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func writeParquet(t *testing.T, conn *Connection, path, query string) {
+	t.Helper()
+	if _, err := conn.Raw().ExecContext(context.Background(),
+		"COPY ("+query+") TO "+SQLPathLiteral(path)+" (FORMAT PARQUET)"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiffParquet(t *testing.T) {
+	conn := testConnection(t)
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	oldCards, newCards := filepath.Join(dir, "old_cards.parquet"), filepath.Join(dir, "new_cards.parquet")
+	writeParquet(t, conn, oldCards, `SELECT * FROM (VALUES
+		('a', 'Lightning Bolt', '{R}'), ('b', 'Counterspell', '{U}{U}'), ('c', 'Giant Growth', '{G}')
+	) t(uuid, name, manaCost)`)
+	writeParquet(t, conn, newCards, `SELECT * FROM (VALUES
+		('a', 'Lightning Bolt', '{R}'), ('b', 'Counterspell', '{U}{U}{U}'), ('d', 'Shock', '{R}')
+	) t(uuid, name, manaCost)`)
+	added, removed, modified, err := conn.DiffParquet(ctx, "cards", oldCards, newCards)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(added, []string{"d"}) || !slices.Equal(removed, []string{"c"}) || !slices.Equal(modified, []string{"b"}) {
+		t.Errorf("unexpected card diff: added %v, removed %v, modified %v", added, removed, modified)
+	}
+
+	oldPrices, newPrices := filepath.Join(dir, "old_prices.parquet"), filepath.Join(dir, "new_prices.parquet")
+	writeParquet(t, conn, oldPrices, `SELECT * FROM (VALUES
+		('a', 'paper', 'tcgplayer', 'USD', 'retail', 'normal', '2024-01-01', 1.0),
+		('b', 'paper', 'tcgplayer', 'USD', 'retail', 'normal', '2024-01-01', 2.0)
+	) t(uuid, source, provider, currency, price_type, finish, date, price)`)
+	// A new date alone is not a change.
+	writeParquet(t, conn, newPrices, `SELECT * FROM (VALUES
+		('a', 'paper', 'tcgplayer', 'USD', 'retail', 'normal', '2024-01-02', 1.0),
+		('b', 'paper', 'tcgplayer', 'USD', 'retail', 'normal', '2024-01-02', 2.5)
+	) t(uuid, source, provider, currency, price_type, finish, date, price)`)
+	added, removed, modified, err = conn.DiffParquet(ctx, "all_prices_today", oldPrices, newPrices)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(added) != 0 || len(removed) != 0 || !slices.Equal(modified, []string{"b"}) {
+		t.Errorf("expected only b's price to change, got added %v, removed %v, modified %v", added, removed, modified)
+	}
+
+	if _, _, _, err := conn.DiffParquet(ctx, "sets", oldCards, newCards); err == nil || CanDiff("sets") {
+		t.Error("expected sets not to be diffable")
+	}
+}
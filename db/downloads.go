@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrWouldDownload is returned, wrapped, when Config.NoImplicitDownloads
+// is set and a call needs a file that is not cached. Contexts from
+// AllowDownloads, as used by SDK.EnsureViews and Refresh, still download.
+var ErrWouldDownload = errors.New("mtgjson: data is not cached and implicit downloads are disabled")
+
+// DownloadNotice announces a download a call is about to block on, before
+// the first byte is requested.
+type DownloadNotice struct {
+	Dataset    Dataset   // view or JSON name, e.g. "cards"; "" if not in the Registry
+	Filename   string    // CDN file name
+	ApproxSize int64     // rough size from the Registry in bytes, 0 if unknown
+	Refresh    bool      // replacing a stale cached copy rather than a first use
+	Deadline   time.Time // the call's context deadline, zero if it has none
+}
+
+// DownloadNoticeFunc receives download notices. It runs on the calling
+// goroutine before the download starts, so it should return quickly.
+type DownloadNoticeFunc func(DownloadNotice)
+
+// assumedDownloadRate is the transfer rate, in bytes per second, below
+// which a context deadline is reported as likely to expire mid-download.
+const assumedDownloadRate = 5 << 20
+
+type allowDownloadsKey struct{}
+
+// AllowDownloads returns a context under which files are downloaded even
+// when Config.NoImplicitDownloads is set, for explicit loads such as
+// warming a cache.
+func AllowDownloads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allowDownloadsKey{}, true)
+}
+
+// downloadsAllowed reports whether ctx may download missing files.
+func (m *CacheManager) downloadsAllowed(ctx context.Context) bool {
+	return !m.noImplicitDownloads || ctx.Value(allowDownloadsKey{}) != nil
+}
+
+// noticeDownload passes the DownloadNoticeFunc a notice that filename is
+// about to be downloaded, and logs a warning when ctx's deadline leaves too
+// little time for a file its size.
+func (m *CacheManager) noticeDownload(ctx context.Context, filename string, refresh bool) {
+	n := DownloadNotice{Filename: filename, Refresh: refresh}
+	for _, d := range registry {
+		files := ParquetFiles
+		if d.Format == "json" {
+			files = JSONFiles
+		}
+		if files[d.Name] == filename {
+			n.Dataset, n.ApproxSize = d.Name, d.ApproxSize
+			break
+		}
+	}
+	n.Deadline, _ = ctx.Deadline()
+	if !n.Deadline.IsZero() && n.ApproxSize > 0 {
+		needed := time.Duration(float64(n.ApproxSize) / assumedDownloadRate * float64(time.Second))
+		if remaining := time.Until(n.Deadline); remaining < needed {
+			m.Logger().Warn("Context deadline may expire during MTGJSON download", "dataset", n.Dataset,
+				"file", filename, "approx_mb", n.ApproxSize>>20, "remaining", remaining.Round(time.Second))
+		}
+	}
+	if m.onDownload != nil {
+		m.onDownload(n)
+	}
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNoImplicitDownloads(t *testing.T) {
+	cdn := &fakeCDN{version: "v1", etags: map[string]string{"parquet/cards.parquet": `"c1"`}, gets: make(map[string]int)}
+	srv := httptest.NewServer(cdn)
+	defer srv.Close()
+
+	var notices []DownloadNotice
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.MirrorURL = srv.URL
+	cfg.NoImplicitDownloads = true
+	cfg.OnDownload = func(n DownloadNotice) { notices = append(notices, n) }
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := cache.EnsureParquet(context.Background(), DatasetCards); !errors.Is(err, ErrWouldDownload) {
+		t.Fatalf("expected ErrWouldDownload, got %v", err)
+	}
+	if len(notices) != 0 || cdn.gets["parquet/cards.parquet"] != 0 {
+		t.Fatalf("expected no download, got notices %v", notices)
+	}
+
+	ctx, cancel := context.WithTimeout(AllowDownloads(context.Background()), time.Minute)
+	defer cancel()
+	if _, err := cache.EnsureParquet(ctx, DatasetCards); err != nil {
+		t.Fatal(err)
+	}
+	if len(notices) != 1 {
+		t.Fatalf("expected one notice, got %v", notices)
+	}
+	n := notices[0]
+	if n.Dataset != DatasetCards || n.Filename != ParquetFiles[DatasetCards] || n.ApproxSize == 0 || n.Refresh || n.Deadline.IsZero() {
+		t.Errorf("unexpected notice %+v", n)
+	}
+
+	// Once cached, implicit calls read the file without downloading.
+	if _, err := cache.EnsureParquet(context.Background(), DatasetCards); err != nil {
+		t.Fatal(err)
+	}
+	if len(notices) != 1 {
+		t.Errorf("expected no further downloads, got %v", notices)
+	}
+}
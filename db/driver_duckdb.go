@@ -0,0 +1,9 @@
+//go:build cgo
+
+package db
+
+import _ "github.com/marcboeker/go-duckdb" // DuckDB driver registration
+
+// DuckDBAvailable reports whether the DuckDB driver is compiled in. It needs
+// cgo; see the lite package for lookups in CGO_ENABLED=0 builds.
+const DuckDBAvailable = true
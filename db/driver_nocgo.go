@@ -0,0 +1,7 @@
+//go:build !cgo
+
+package db
+
+// DuckDBAvailable reports whether the DuckDB driver is compiled in. It needs
+// cgo; see the lite package for lookups in CGO_ENABLED=0 builds.
+const DuckDBAvailable = false
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// lockPollInterval is how often lockFile retries a lock held by another
+// process.
+const lockPollInterval = 50 * time.Millisecond
+
+// cacheLockFile is the lock file guarding version.txt and datasets.json
+// across processes sharing a cache dir.
+const cacheLockFile = ".mtgjson.lock"
+
+// lockFile takes an exclusive advisory lock on path (flock on Unix,
+// LockFileEx on Windows), creating the file if needed, and waits until it is
+// free or ctx is done. The lock coordinates processes sharing a cache dir;
+// goroutines within a process must still use their own mutexes. Lock files
+// are left in place, since removing one would let two processes lock
+// different files of the same name.
+func lockFile(ctx context.Context, path string) (unlock func(), err error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("mtgjson: create lock dir: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("mtgjson: open lock %s: %w", path, err)
+	}
+	for {
+		ok, err := tryLock(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("mtgjson: lock %s: %w", path, err)
+		}
+		if ok {
+			return func() {
+				unlockFile(f)
+				f.Close()
+			}, nil
+		}
+		select {
+		case <-ctx.Done():
+			f.Close()
+			return nil, ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// lockCache takes the cache-wide lock for updating version.txt and
+// datasets.json. Those updates are short, so it waits without a deadline.
+func (m *CacheManager) lockCache() (unlock func(), err error) {
+	return lockFile(context.Background(), filepath.Join(m.CacheDir, cacheLockFile))
+}
+
+// writeFileAtomic replaces path through a rename, so readers in other
+// processes never see a partial write.
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
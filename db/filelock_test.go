@@ -0,0 +1,115 @@
+package db
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestLockHelperProcess holds a lock for TestLockFileAcrossProcesses until
+// its stdin is closed.
+func TestLockHelperProcess(t *testing.T) {
+	path := os.Getenv("MTGJSON_LOCK_HELPER")
+	if path == "" {
+		t.Skip("helper process only")
+	}
+	unlock, err := lockFile(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout.WriteString("locked\n")
+	bufio.NewReader(os.Stdin).ReadString('\n')
+	unlock()
+}
+
+func TestLockFileAcrossProcesses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.lock")
+	cmd := exec.Command(os.Args[0], "-test.run=^TestLockHelperProcess$")
+	cmd.Env = append(os.Environ(), "MTGJSON_LOCK_HELPER="+path)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer cmd.Wait()
+	if line, err := bufio.NewReader(stdout).ReadString('\n'); err != nil || line != "locked\n" {
+		stdin.Close()
+		t.Fatalf("helper did not lock: %q (%v)", line, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := lockFile(ctx, path); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the lock to be held by the helper, got %v", err)
+	}
+
+	stdin.Close()
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	unlock, err := lockFile(ctx, path)
+	if err != nil {
+		t.Fatalf("expected the lock after the helper released it: %v", err)
+	}
+	unlock()
+}
+
+// Two cache managers on one dir lock separate file handles, as separate
+// processes would.
+func TestSharedCacheDownloadsOnce(t *testing.T) {
+	cdn := &fakeCDN{
+		version: "v1",
+		etags:   map[string]string{"parquet/cards.parquet": `"c1"`},
+		gets:    make(map[string]int),
+	}
+	srv := httptest.NewServer(cdn)
+	defer srv.Close()
+
+	dir := t.TempDir()
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for i := 0; i < 4; i++ {
+		cfg := DefaultConfig()
+		cfg.CacheDir = dir
+		cache, err := NewCacheManager(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cache.baseURL = srv.URL
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := cache.EnsureParquet(context.Background(), "cards")
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	cdn.mu.Lock()
+	gets := cdn.gets["parquet/cards.parquet"]
+	cdn.mu.Unlock()
+	if gets != 1 {
+		t.Errorf("expected one download across cache managers, got %d", gets)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "version.txt"))
+	if err != nil || string(data) != "v1" {
+		t.Errorf("unexpected version.txt %q (%v)", data, err)
+	}
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// imagesDir is the cache subdirectory EnsureImage downloads into.
+const imagesDir = "images"
+
+// EnsureImage downloads an image, such as a Scryfall card image, into the
+// cache's images directory and returns its local path. Each URL is
+// downloaded once and reused after that; in offline mode only images already
+// in the cache are returned.
+func (m *CacheManager) EnsureImage(ctx context.Context, imageURL string) (string, error) {
+	u, err := url.Parse(imageURL)
+	if err != nil {
+		return "", fmt.Errorf("mtgjson: image %s: %w", imageURL, err)
+	}
+	sum := sha256.Sum256([]byte(imageURL))
+	local := filepath.Join(m.CacheDir, imagesDir, hex.EncodeToString(sum[:16])+path.Ext(u.Path))
+	if _, err := os.Stat(local); err == nil {
+		return local, nil
+	}
+	if m.Offline {
+		return "", fmt.Errorf("mtgjson: image %s is not cached and offline mode is on", imageURL)
+	}
+	if err := os.MkdirAll(filepath.Dir(local), 0o755); err != nil {
+		return "", fmt.Errorf("mtgjson: create images dir: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return "", err
+	}
+	// Scryfall asks API clients to identify themselves.
+	req.Header.Set("User-Agent", "mtgjson-sdk-go")
+	req.Header.Set("Accept", "image/*")
+	resp, err := m.httpClient().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("mtgjson: download %s: %w", imageURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("mtgjson: download %s: HTTP %d", imageURL, resp.StatusCode)
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(local), ".image-*")
+	if err != nil {
+		return "", err
+	}
+	tmp := f.Name()
+	_, err = io.Copy(f, resp.Body)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, local)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("mtgjson: download %s: %w", imageURL, err)
+	}
+	return local, nil
+}
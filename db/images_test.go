@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+func TestEnsureImage(t *testing.T) {
+	var gets atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gets.Add(1)
+		if r.URL.Path == "/missing.jpg" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("jpeg bytes"))
+	}))
+	defer srv.Close()
+
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	path, err := cache.EnsureImage(ctx, srv.URL+"/normal/front/a/b/ab.jpg?1700000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Dir(path) != filepath.Join(cfg.CacheDir, "images") || filepath.Ext(path) != ".jpg" {
+		t.Errorf("unexpected cache path %s", path)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "jpeg bytes" {
+		t.Errorf("unexpected image %q", data)
+	}
+	again, err := cache.EnsureImage(ctx, srv.URL+"/normal/front/a/b/ab.jpg?1700000000")
+	if err != nil || again != path || gets.Load() != 1 {
+		t.Errorf("expected the cached image reused, got %s after %d requests (%v)", again, gets.Load(), err)
+	}
+	if _, err := cache.EnsureImage(ctx, srv.URL+"/missing.jpg"); err == nil {
+		t.Error("expected an error for a missing image")
+	}
+
+	cache.Offline = true
+	if _, err := cache.EnsureImage(ctx, srv.URL+"/other.jpg"); err == nil {
+		t.Error("expected an error for an uncached image offline")
+	}
+	if _, err := cache.EnsureImage(ctx, srv.URL+"/normal/front/a/b/ab.jpg?1700000000"); err != nil {
+		t.Errorf("expected the cached image offline, got %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// LegalityHistoryFile is the cache file RecordLegalities keeps legality
+// changes in, registered as the legality_history view. It is local to the
+// cache: the CDN only publishes current legalities.
+const LegalityHistoryFile = "legality_history.parquet"
+
+// localParquetFiles are views over files the SDK writes into the cache
+// itself rather than downloads.
+var localParquetFiles = map[string]string{
+	DatasetLegalityHistory: LegalityHistoryFile,
+}
+
+var isoDate = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+
+// RecordLegalities adds the current card_legalities to the legality
+// history, dated date (YYYY-MM-DD, normally the release's Meta date). Only
+// changes are stored: a row (uuid, format, status, date) for every pair
+// whose status differs from its latest recorded one, with a NULL status for
+// a pair no longer listed. The first call records every pair. Recording
+// the same release twice adds nothing. Returns the number of rows added.
+func (c *Connection) RecordLegalities(ctx context.Context, date string) (int, error) {
+	if !isoDate.MatchString(date) {
+		return 0, fmt.Errorf("mtgjson: legality date %q is not YYYY-MM-DD", date)
+	}
+	if err := c.EnsureViews(ctx, "card_legalities"); err != nil {
+		return 0, err
+	}
+	return c.recordLegalities(ctx, "SELECT uuid, format, CAST(status AS VARCHAR) AS status FROM card_legalities", date)
+}
+
+// RecordLegalitiesFile is like RecordLegalities but reads the legalities
+// from the cardLegalities.parquet at path, such as the cached copy of a
+// release about to be replaced, without downloading or registering views.
+func (c *Connection) RecordLegalitiesFile(ctx context.Context, path, date string) (int, error) {
+	if !isoDate.MatchString(date) {
+		return 0, fmt.Errorf("mtgjson: legality date %q is not YYYY-MM-DD", date)
+	}
+	query, _, err := c.legalitiesQuery(ctx, SQLPathLiteral(path), nil)
+	if err != nil {
+		return 0, fmt.Errorf("mtgjson: read legalities %s: %w", path, err)
+	}
+	return c.recordLegalities(ctx, "SELECT uuid, format, CAST(status AS VARCHAR) AS status FROM ("+query+")", date)
+}
+
+// recordLegalities adds the changes between the legality history and cur,
+// a query of (uuid, format, status) rows, dated date.
+func (c *Connection) recordLegalities(ctx context.Context, cur, date string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The temp table lives on one pooled connection.
+	conn, err := c.db.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	history := "SELECT NULL::VARCHAR AS uuid, NULL::VARCHAR AS format, NULL::VARCHAR AS status, NULL::VARCHAR AS date WHERE false"
+	if path := c.cache.Path(LegalityHistoryFile); fileExists(path) {
+		history = "SELECT uuid, format, status, date FROM read_parquet(" + SQLPathLiteral(path) + ")"
+	}
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf(
+		"CREATE OR REPLACE TEMP TABLE legality_changes AS "+
+			"WITH h AS (%s), "+
+			"latest AS (SELECT uuid, format, status FROM h "+
+			"  QUALIFY ROW_NUMBER() OVER (PARTITION BY uuid, format ORDER BY date DESC) = 1), "+
+			"cur AS (%s) "+
+			"SELECT COALESCE(cur.uuid, latest.uuid) AS uuid, COALESCE(cur.format, latest.format) AS format, "+
+			"  cur.status, %s AS date "+
+			"FROM cur FULL OUTER JOIN latest ON cur.uuid = latest.uuid AND cur.format = latest.format "+
+			"WHERE cur.status IS DISTINCT FROM latest.status",
+		history, cur, "'"+date+"'")); err != nil {
+		return 0, fmt.Errorf("mtgjson: record legalities: %w", err)
+	}
+	defer conn.ExecContext(context.WithoutCancel(ctx), "DROP TABLE IF EXISTS legality_changes")
+
+	var n int
+	if err := conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM legality_changes").Scan(&n); err != nil {
+		return 0, fmt.Errorf("mtgjson: record legalities: %w", err)
+	}
+	if n == 0 {
+		return 0, nil
+	}
+
+	dest := filepath.Join(c.cache.CacheDir, LegalityHistoryFile)
+	tmp := dest + ".tmp"
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf(
+		"COPY (SELECT * FROM (%s UNION ALL SELECT * FROM legality_changes) ORDER BY format, uuid, date) "+
+			"TO %s (FORMAT parquet)", history, SQLPathLiteral(tmp))); err != nil {
+		os.Remove(tmp)
+		return 0, fmt.Errorf("mtgjson: write legality history: %w", err)
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		os.Remove(tmp)
+		return 0, fmt.Errorf("mtgjson: write legality history: %w", err)
+	}
+	// The view is registered again over the new file on its next use.
+	delete(c.registeredViews, "legality_history")
+	c.Logger().Info("Recorded legality changes", "file", dest, "rows", n, "date", date)
+	return n, nil
+}
+
+// CachedReleaseDate returns the date (YYYY-MM-DD) of the MTGJSON release a
+// cached file came from, without contacting the CDN: the date in the cached
+// Meta.json if it is from the same release, otherwise the date in the
+// version's build metadata ("5.2.2+20240101"). Returns "" if unknown.
+func (m *CacheManager) CachedReleaseDate(filename string) string {
+	version := m.DatasetVersion(filename)
+	if data, err := readJSONFile(m.Path(JSONFiles[DatasetMeta])); err == nil {
+		if d, ok := data["data"].(map[string]any); ok {
+			v, _ := d["version"].(string)
+			date, _ := d["date"].(string)
+			if isoDate.MatchString(date) && v == version {
+				return date
+			}
+		}
+	}
+	if _, build, ok := strings.Cut(version, "+"); ok && len(build) == 8 {
+		if date := build[:4] + "-" + build[4:6] + "-" + build[6:]; isoDate.MatchString(date) {
+			return date
+		}
+	}
+	return ""
+}
@@ -0,0 +1,148 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// listSampleRows is how many rows detectListColumns inspects per file.
+const listSampleRows = 2000
+
+// maxListItemLen bounds the items of a comma-separated list. Longer pieces
+// are prose, such as rules text, that happens to contain commas.
+const maxListItemLen = 60
+
+// listKind is how a VARCHAR column stores lists, if it does.
+type listKind int
+
+const (
+	notList   listKind = iota
+	csvList            // "Goblin, Wizard"
+	jsonArray          // ["Goblin","Wizard"]
+)
+
+// detectListColumns samples the given VARCHAR columns of a parquet file and
+// classifies each with classifyListSample. Columns that are not lists are
+// left out of the result.
+func (c *Connection) detectListColumns(ctx context.Context, pathStr string, cols []string) (map[string]listKind, error) {
+	kinds := make(map[string]listKind)
+	if len(cols) == 0 {
+		return kinds, nil
+	}
+	quoted := make([]string, len(cols))
+	for i, col := range cols {
+		quoted[i] = `"` + col + `"`
+	}
+	rows, err := c.db.QueryContext(ctx, fmt.Sprintf(
+		"SELECT %s FROM read_parquet(%s) USING SAMPLE reservoir(%d ROWS) REPEATABLE (1)",
+		strings.Join(quoted, ", "), pathStr, listSampleRows,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("mtgjson: sample %s: %w", pathStr, err)
+	}
+	defer rows.Close()
+
+	samples := make([][]string, len(cols))
+	dest := make([]any, len(cols))
+	vals := make([]sql.NullString, len(cols))
+	for i := range vals {
+		dest[i] = &vals[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		for i, v := range vals {
+			if v.Valid {
+				samples[i] = append(samples[i], v.String)
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for i, col := range cols {
+		if kind := classifyListSample(samples[i]); kind != notList {
+			kinds[col] = kind
+		}
+	}
+	return kinds, nil
+}
+
+// classifyListSample decides from sampled values whether a column holds
+// lists. Every non-empty value must be a JSON array of strings, or else
+// every value must split on ", " into short single-line items, some value
+// must have several items, and most items of multi-item values must recur
+// in other distinct values. The recurrence test is what tells the subtypes
+// "Goblin, Wizard" apart from the name "Jace, the Mind Sculptor".
+func classifyListSample(values []string) listKind {
+	distinct := make(map[string]bool)
+	arrays := 0
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if strings.HasPrefix(v, "[") {
+			var items []string
+			if json.Unmarshal([]byte(v), &items) != nil {
+				return notList
+			}
+			arrays++
+			continue
+		}
+		if strings.ContainsAny(v, "\n\r") {
+			return notList
+		}
+		distinct[v] = true
+	}
+	switch {
+	case arrays > 0 && len(distinct) == 0:
+		return jsonArray
+	case arrays > 0:
+		return notList
+	}
+
+	// containing counts, for each item, the distinct values it appears in.
+	containing := make(map[string]int)
+	multi := 0
+	for v := range distinct {
+		items := strings.Split(v, ", ")
+		if len(items) > 1 {
+			multi++
+		}
+		seen := make(map[string]bool, len(items))
+		for _, item := range items {
+			if item == "" || len(item) > maxListItemLen || strings.HasSuffix(item, ".") {
+				return notList
+			}
+			if !seen[item] {
+				seen[item] = true
+				containing[item]++
+			}
+		}
+	}
+	if multi == 0 {
+		return notList
+	}
+	var items, recurring int
+	for v := range distinct {
+		parts := strings.Split(v, ", ")
+		if len(parts) < 2 {
+			continue
+		}
+		for _, item := range parts {
+			items++
+			if containing[item] > 1 {
+				recurring++
+			}
+		}
+	}
+	if recurring*5 >= items*4 {
+		return csvList
+	}
+	return notList
+}
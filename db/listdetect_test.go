@@ -0,0 +1,78 @@
+package db
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClassifyListSample(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   listKind
+	}{
+		{"subtypes", []string{"Goblin", "Goblin, Wizard", "Human, Wizard", "Human", "Wizard"}, csvList},
+		{"colors", []string{"R", "W, U", "U", "W", "", "U, R"}, csvList},
+		{"legendary names", []string{"Jace, the Mind Sculptor", "Jace, Vryn's Prodigy", "Lightning Bolt", "Jace Beleren"}, notList},
+		{"rules text", []string{"Flying, haste", "Flying", "Haste.", "Flying, haste\nWhen this enters, draw a card."}, notList},
+		{"sentences", []string{"Fire, fire, fire.", "fire"}, notList},
+		{"no separators", []string{"Instant", "Sorcery", "Instant"}, notList},
+		{"json arrays", []string{`["paper","mtgo"]`, `["paper"]`, ""}, jsonArray},
+		{"json objects", []string{`[{"a":1}]`}, notList},
+		{"mixed", []string{`["paper"]`, "paper, mtgo"}, notList},
+		{"empty", nil, notList},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyListSample(tt.values); got != tt.want {
+				t.Errorf("classifyListSample(%q) = %v, want %v", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnsureViewsDetectsListColumns(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.Offline = true
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := NewConnection(cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	ctx := context.Background()
+
+	path := filepath.Join(cfg.CacheDir, "parquet", "sets.parquet")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeParquet(t, conn, path, `SELECT * FROM (VALUES
+		('MH2', 'Modern Horizons 2', 'English, Japanese', 'paper, mtgo'),
+		('A25', 'Masters 25', 'English', 'paper, mtgo'),
+		('WAR', 'War of the Spark', 'Japanese, English', 'mtgo'),
+		('ELD', 'Throne of Eldraine, Collector', 'English', 'paper')
+	) t(code, name, newLanguages, releasedOn)`)
+	if err := conn.EnsureViews(ctx, "sets"); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := conn.Execute(ctx, "SELECT typeof(name) AS n, typeof(newLanguages) AS l, typeof(releasedOn) AS r FROM sets LIMIT 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows[0]["n"] != "VARCHAR" || rows[0]["l"] != "VARCHAR[]" || rows[0]["r"] != "VARCHAR[]" {
+		t.Errorf("expected only the list columns converted, got %v", rows[0])
+	}
+	val, err := conn.ExecuteScalar(ctx, "SELECT code FROM sets WHERE list_contains(newLanguages, 'Japanese') AND code <> 'MH2'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val != "WAR" {
+		t.Errorf("expected WAR to list Japanese, got %v", val)
+	}
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"log/slog"
+)
+
+// newLogger returns the logger the SDK writes to: l, or slog.Default when
+// l is nil, with records below level dropped when level is set. It returns
+// nil when neither is set, meaning slog.Default at the time of each log.
+func newLogger(l *slog.Logger, level slog.Leveler) *slog.Logger {
+	if level == nil {
+		return l
+	}
+	if l == nil {
+		l = slog.Default()
+	}
+	return slog.New(&levelHandler{level: level, Handler: l.Handler()})
+}
+
+// levelHandler drops records below level before the wrapped handler sees
+// them, so the SDK can be quieter than the rest of the program. It cannot
+// enable levels the wrapped handler drops.
+type levelHandler struct {
+	level slog.Leveler
+	slog.Handler
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return l >= h.level.Level() && h.Handler.Enabled(ctx, l)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{level: h.level, Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{level: h.level, Handler: h.Handler.WithGroup(name)}
+}
+
+// Logger returns the logger the cache manager, its connections and the
+// query modules write to. Records use the keys "view", "file", "duration"
+// and "rows" where they apply.
+func (m *CacheManager) Logger() *slog.Logger {
+	if m == nil || m.logger == nil {
+		return slog.Default()
+	}
+	return m.logger
+}
+
+// Logger returns the logger of the connection's cache manager.
+func (c *Connection) Logger() *slog.Logger {
+	return c.cache.Logger()
+}
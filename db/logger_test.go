@@ -0,0 +1,56 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLoggerReceivesQueryLogs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	for _, tc := range []struct {
+		level slog.Leveler
+		want  bool
+	}{
+		{nil, true},
+		{slog.LevelWarn, false},
+	} {
+		buf.Reset()
+		cfg := DefaultConfig()
+		cfg.CacheDir = t.TempDir()
+		cfg.Offline = true
+		cfg.Logger = logger
+		cfg.LogLevel = tc.level
+		cache, err := NewCacheManager(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn, err := NewConnection(cache)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := conn.Execute(context.Background(), "SELECT 1 UNION ALL SELECT 2"); err != nil {
+			t.Fatal(err)
+		}
+		conn.Close()
+
+		out := buf.String()
+		got := strings.Contains(out, "msg=Query") && strings.Contains(out, "rows=2") && strings.Contains(out, "duration=")
+		if got != tc.want {
+			t.Errorf("level %v: query logged = %v, want %v; output:\n%s", tc.level, got, tc.want, out)
+		}
+	}
+}
+
+func TestLoggerDefault(t *testing.T) {
+	var m *CacheManager
+	if m.Logger() != slog.Default() {
+		t.Fatal("expected slog.Default without a cache manager")
+	}
+	if newLogger(nil, nil) != nil {
+		t.Fatal("expected nil (slog.Default at log time) without a logger or level")
+	}
+}
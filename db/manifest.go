@@ -0,0 +1,207 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+)
+
+// manifestFile records per-file versions and HTTP validators in the cache dir.
+const manifestFile = "datasets.json"
+
+// datasetEntry is what is known about one cached CDN file.
+type datasetEntry struct {
+	Version      string `json:"version"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// readManifest reads datasets.json from dir, which is CacheDir or BaseDir.
+func readManifest(dir string) map[string]datasetEntry {
+	entries := make(map[string]datasetEntry)
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if err != nil {
+		return entries
+	}
+	_ = json.Unmarshal(data, &entries)
+	return entries
+}
+
+func (m *CacheManager) writeManifest(entries map[string]datasetEntry) {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = writeFileAtomic(filepath.Join(m.CacheDir, manifestFile), data)
+}
+
+func (m *CacheManager) recordDataset(filename string, entry datasetEntry) {
+	m.manifestMu.Lock()
+	defer m.manifestMu.Unlock()
+	unlock, err := m.lockCache()
+	if err != nil {
+		return
+	}
+	defer unlock()
+	entries := readManifest(m.CacheDir)
+	entries[filename] = entry
+	m.writeManifest(entries)
+}
+
+// DatasetVersion returns the MTGJSON version a cached file was downloaded
+// from, falling back to the cache-wide version for files cached before
+// per-file tracking. Files read from the base cache use its datasets.json
+// and version.txt unless a refresh has since confirmed them unchanged.
+// Returns "" if unknown.
+func (m *CacheManager) DatasetVersion(filename string) string {
+	m.manifestMu.Lock()
+	entry, ok := readManifest(m.CacheDir)[filename]
+	m.manifestMu.Unlock()
+	if ok && entry.Version != "" {
+		return entry.Version
+	}
+	if m.inBase(filename) {
+		if entry, ok := readManifest(m.BaseDir)[filename]; ok && entry.Version != "" {
+			return entry.Version
+		}
+		return readVersion(m.BaseDir)
+	}
+	return m.LocalVersion()
+}
+
+// datasetStale reports whether a cached file predates the CDN's version.
+func (m *CacheManager) datasetStale(ctx context.Context, filename string) bool {
+	remote := m.RemoteVersion(ctx)
+	if remote == "" {
+		return false // can't check, assume fresh
+	}
+	local := m.DatasetVersion(filename)
+	return local == "" || local != remote
+}
+
+// previousSuffix marks the copy of a changed file kept by RefreshDatasets.
+const previousSuffix = ".prev"
+
+// RefreshDatasets compares every cached file with the CDN after a new MTGJSON
+// release. Files whose ETag or Last-Modified changed (or that cannot be
+// compared) are removed so they are downloaded again on next use; unchanged
+// files are stamped with the new version and kept. Changed files named in
+// keep are moved to PreviousPath instead of being removed, for diffing
+// against the new release; the caller removes them. Files in the base cache
+// are never touched: changed ones are downloaded into CacheDir on next use.
+// With KeepVersions set, the outgoing release is first archived; see
+// Versions. Returns the CDN file names that changed.
+func (m *CacheManager) RefreshDatasets(ctx context.Context, keep ...string) ([]string, error) {
+	remote := m.RemoteVersion(ctx)
+	if remote == "" {
+		return nil, fmt.Errorf("mtgjson: MTGJSON version unavailable")
+	}
+	unlock, err := m.lockCache()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	m.manifestMu.Lock()
+	entries := readManifest(m.CacheDir)
+	m.manifestMu.Unlock()
+	var baseEntries map[string]datasetEntry
+	if m.BaseDir != "" {
+		baseEntries = readManifest(m.BaseDir)
+	}
+	if old := m.LocalVersion(); m.KeepVersions > 0 && old != "" && old != remote {
+		if err := m.snapshotVersion(old, entries); err != nil {
+			return nil, err
+		}
+	}
+
+	var changed []string
+	for _, filename := range m.cachedFiles() {
+		entry, ok := entries[filename]
+		inBase := m.inBase(filename)
+		if !ok && inBase {
+			entry, ok = baseEntries[filename]
+		}
+		same := false
+		if ok {
+			var err error
+			if same, err = m.unchangedOnCDN(ctx, filename, entry); err != nil {
+				return nil, err
+			}
+		}
+		if same {
+			entry.Version = remote
+			entries[filename] = entry
+			continue
+		}
+		path := filepath.Join(m.CacheDir, filename)
+		switch {
+		case inBase:
+			// The base cache is read-only. Dropping the entry leaves its
+			// copy stale, so the new release is downloaded into CacheDir
+			// on next use.
+		case slices.Contains(keep, filename):
+			os.Remove(path + previousSuffix)
+			if err := os.Rename(path, path+previousSuffix); err != nil {
+				return nil, fmt.Errorf("mtgjson: keep previous %s: %w", filename, err)
+			}
+		default:
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return nil, fmt.Errorf("mtgjson: remove stale %s: %w", filename, err)
+			}
+		}
+		delete(entries, filename)
+		changed = append(changed, filename)
+	}
+
+	m.manifestMu.Lock()
+	m.writeManifest(entries)
+	m.manifestMu.Unlock()
+	m.saveVersion(remote)
+	return changed, nil
+}
+
+// PreviousPath returns where RefreshDatasets keeps the prior release of a
+// changed CDN file.
+func (m *CacheManager) PreviousPath(filename string) string {
+	return filepath.Join(m.CacheDir, filename) + previousSuffix
+}
+
+// unchangedOnCDN issues a HEAD request and compares the file's validators with
+// those recorded at download time. Files without validators count as changed.
+func (m *CacheManager) unchangedOnCDN(ctx context.Context, filename string, entry datasetEntry) (bool, error) {
+	resp, err := m.cdnDo(ctx, http.MethodHead, filename)
+	if err != nil {
+		return false, fmt.Errorf("mtgjson: check %s: %w", filename, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, nil
+	}
+	if etag := resp.Header.Get("ETag"); etag != "" && entry.ETag != "" {
+		return etag == entry.ETag, nil
+	}
+	if lm := resp.Header.Get("Last-Modified"); lm != "" && entry.LastModified != "" {
+		return lm == entry.LastModified, nil
+	}
+	return false, nil
+}
+
+// cachedFiles lists the known CDN files present in the cache dir or the
+// base cache.
+func (m *CacheManager) cachedFiles() []string {
+	var files []string
+	for _, set := range []map[string]string{ParquetFiles, JSONFiles} {
+		for _, filename := range set {
+			if fileExists(m.Path(filename)) {
+				files = append(files, filename)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files
+}
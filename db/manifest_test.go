@@ -0,0 +1,168 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// fakeCDN serves Meta.json and files with per-file ETags, counting GETs.
+type fakeCDN struct {
+	mu      sync.Mutex
+	version string
+	etags   map[string]string
+	gets    map[string]int
+}
+
+func (f *fakeCDN) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	name := r.URL.Path[1:]
+	if name == "Meta.json" {
+		fmt.Fprintf(w, `{"data":{"version":%q}}`, f.version)
+		return
+	}
+	etag, ok := f.etags[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	if r.Method == http.MethodGet {
+		f.gets[name]++
+		fmt.Fprintf(w, "%s@%s", name, etag)
+	}
+}
+
+func TestRefreshDatasetsOnlyChangedFiles(t *testing.T) {
+	cdn := &fakeCDN{
+		version: "v1",
+		etags:   map[string]string{"parquet/cards.parquet": `"c1"`, "parquet/AllPricesToday.parquet": `"p1"`},
+		gets:    make(map[string]int),
+	}
+	srv := httptest.NewServer(cdn)
+	defer srv.Close()
+
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.baseURL = srv.URL
+	ctx := context.Background()
+	for _, view := range []string{"cards", "all_prices_today"} {
+		if _, err := cache.EnsureParquet(ctx, view); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if v := cache.DatasetVersion("parquet/cards.parquet"); v != "v1" {
+		t.Fatalf("expected cards at v1, got %q", v)
+	}
+
+	// A new release in which only prices changed.
+	cdn.mu.Lock()
+	cdn.version = "v2"
+	cdn.etags["parquet/AllPricesToday.parquet"] = `"p2"`
+	cdn.mu.Unlock()
+	cache.ResetRemoteVersion()
+	if !cache.IsStale(ctx) {
+		t.Fatal("expected the cache to be stale")
+	}
+
+	changed, err := cache.RefreshDatasets(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 1 || changed[0] != "parquet/AllPricesToday.parquet" {
+		t.Fatalf("expected only prices to change, got %v", changed)
+	}
+	if v := cache.DatasetVersion("parquet/cards.parquet"); v != "v2" {
+		t.Fatalf("expected unchanged cards to be stamped v2, got %q", v)
+	}
+	for _, view := range []string{"cards", "all_prices_today"} {
+		if _, err := cache.EnsureParquet(ctx, view); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cdn.mu.Lock()
+	cardGets, priceGets := cdn.gets["parquet/cards.parquet"], cdn.gets["parquet/AllPricesToday.parquet"]
+	cdn.mu.Unlock()
+	if cardGets != 1 || priceGets != 2 {
+		t.Fatalf("expected cards downloaded once and prices twice, got %d and %d", cardGets, priceGets)
+	}
+	data, err := os.ReadFile(filepath.Join(cfg.CacheDir, "parquet", "AllPricesToday.parquet"))
+	if err != nil || string(data) != `parquet/AllPricesToday.parquet@"p2"` {
+		t.Fatalf("expected the new prices file, got %q (%v)", data, err)
+	}
+	if v := cache.DatasetVersion("parquet/AllPricesToday.parquet"); v != "v2" {
+		t.Fatalf("expected prices at v2, got %q", v)
+	}
+}
+
+func TestRefreshDatasetsKeepsPrevious(t *testing.T) {
+	cdn := &fakeCDN{
+		version: "v1",
+		etags:   map[string]string{"parquet/cards.parquet": `"c1"`, "parquet/AllPricesToday.parquet": `"p1"`},
+		gets:    make(map[string]int),
+	}
+	srv := httptest.NewServer(cdn)
+	defer srv.Close()
+
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.baseURL = srv.URL
+	ctx := context.Background()
+	for _, view := range []string{"cards", "all_prices_today"} {
+		if _, err := cache.EnsureParquet(ctx, view); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cdn.mu.Lock()
+	cdn.version = "v2"
+	cdn.etags["parquet/cards.parquet"] = `"c2"`
+	cdn.etags["parquet/AllPricesToday.parquet"] = `"p2"`
+	cdn.mu.Unlock()
+	cache.ResetRemoteVersion()
+
+	changed, err := cache.RefreshDatasets(ctx, "parquet/AllPricesToday.parquet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 2 {
+		t.Fatalf("expected both files to change, got %v", changed)
+	}
+	data, err := os.ReadFile(cache.PreviousPath("parquet/AllPricesToday.parquet"))
+	if err != nil || string(data) != `parquet/AllPricesToday.parquet@"p1"` {
+		t.Fatalf("expected the previous prices file to be kept, got %q (%v)", data, err)
+	}
+	if _, err := os.Stat(cache.PreviousPath("parquet/cards.parquet")); !os.IsNotExist(err) {
+		t.Errorf("expected cards not to be kept, got %v", err)
+	}
+	if fileExists(filepath.Join(cfg.CacheDir, "parquet", "AllPricesToday.parquet")) {
+		t.Error("expected the stale prices file to be moved aside")
+	}
+}
+
+func TestDatasetVersionFallsBackToCacheVersion(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.saveVersion("5.2.2")
+	if v := cache.DatasetVersion("parquet/cards.parquet"); v != "5.2.2" {
+		t.Fatalf("expected fallback to version.txt, got %q", v)
+	}
+}
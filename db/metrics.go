@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Metrics receives counts and timings of the SDK's data and query work, for
+// monitoring; see the prommetrics package for a Prometheus exporter. Methods
+// are called concurrently from the goroutines doing the work, so they must
+// be safe for concurrent use and return quickly.
+type Metrics interface {
+	// Download reports one file fetched from the CDN or mirror: the bytes
+	// received, how long it took, and why it failed, if it did.
+	Download(file string, bytes int64, d time.Duration, err error)
+	// CacheHit reports a file served without a download, from the cache
+	// directory or the Store.
+	CacheHit(file string)
+	// Query reports one SQL query run through Execute, ExecuteEach, Exec,
+	// ExecuteJSON, ExecuteInto, ExecuteScalar or ExecuteToWriter.
+	Query(d time.Duration, err error)
+}
+
+// NopMetrics discards all metrics. It is the default.
+type NopMetrics struct{}
+
+func (NopMetrics) Download(string, int64, time.Duration, error) {}
+func (NopMetrics) CacheHit(string)                              {}
+func (NopMetrics) Query(time.Duration, error)                   {}
+
+// metrics returns the Metrics the connection reports to.
+func (c *Connection) metrics() Metrics {
+	if c.cache == nil || c.cache.metrics == nil {
+		return NopMetrics{}
+	}
+	return c.cache.metrics
+}
+
+// observeQuery reports a query started at start, and the error *err it
+// returned, to the connection's Metrics and logs it at debug level with its
+// row count when rows is not nil. Call it deferred.
+func (c *Connection) observeQuery(start time.Time, rows *int, err *error) {
+	d := time.Since(start)
+	c.metrics().Query(d, *err)
+	if logger := c.Logger(); logger.Enabled(context.Background(), slog.LevelDebug) {
+		attrs := []any{"duration", d}
+		if rows != nil {
+			attrs = append(attrs, "rows", *rows)
+		}
+		if *err != nil {
+			attrs = append(attrs, "error", *err)
+		}
+		logger.Debug("Query", attrs...)
+	}
+}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordedMetrics struct {
+	mu        sync.Mutex
+	downloads map[string]int64 // file -> bytes
+	hits      map[string]int
+	queries   int
+	failed    int
+}
+
+func (r *recordedMetrics) Download(file string, bytes int64, _ time.Duration, _ error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.downloads[file] += bytes
+}
+
+func (r *recordedMetrics) CacheHit(file string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hits[file]++
+}
+
+func (r *recordedMetrics) Query(_ time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries++
+	if err != nil {
+		r.failed++
+	}
+}
+
+func TestMetricsDownloadsAndCacheHits(t *testing.T) {
+	cdn := &fakeCDN{
+		version: "v1",
+		etags:   map[string]string{"parquet/cards.parquet": `"c1"`},
+		gets:    make(map[string]int),
+	}
+	srv := httptest.NewServer(cdn)
+	defer srv.Close()
+
+	m := &recordedMetrics{downloads: map[string]int64{}, hits: map[string]int{}}
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.Metrics = m
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.baseURL = srv.URL
+	ctx := context.Background()
+	for range 2 {
+		if _, err := cache.EnsureParquet(ctx, "cards"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	body := int64(len(`parquet/cards.parquet@"c1"`))
+	if got := m.downloads["parquet/cards.parquet"]; got != body {
+		t.Errorf("expected %d bytes downloaded, got %d", body, got)
+	}
+	if got := m.hits["parquet/cards.parquet"]; got != 1 {
+		t.Errorf("expected 1 cache hit, got %d", got)
+	}
+}
+
+func TestMetricsQueries(t *testing.T) {
+	m := &recordedMetrics{}
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.Offline = true
+	cfg.Metrics = m
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := NewConnection(cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	ctx := context.Background()
+
+	conn.Execute(ctx, "SELECT 1")
+	conn.ExecuteScalar(ctx, "SELECT 1")
+	var rows []map[string]any
+	conn.ExecuteInto(ctx, &rows, "SELECT 1 AS one")
+	conn.Execute(ctx, "SELECT * FROM no_such_table")
+	if m.queries != 4 || m.failed != 1 {
+		t.Fatalf("expected 4 queries with 1 failure, got %d with %d", m.queries, m.failed)
+	}
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultMirrorCooldown is how long a base URL that failed is tried only
+// after the others.
+const DefaultMirrorCooldown = 5 * time.Minute
+
+// mirrorHealth remembers which base URLs failed recently.
+type mirrorHealth struct {
+	mu        sync.Mutex
+	deadUntil map[string]time.Time
+}
+
+// bases returns the base URLs to try, in order: the primary (the CDN or
+// MirrorURL), then the fallbacks, with those that failed within the
+// cooldown moved to the end as a last resort.
+func (m *CacheManager) bases() []string {
+	all := append([]string{m.baseURL}, m.fallbacks...)
+	if len(all) == 1 {
+		return all
+	}
+	now := time.Now()
+	m.health.mu.Lock()
+	defer m.health.mu.Unlock()
+	var live, dead []string
+	for _, base := range all {
+		if now.Before(m.health.deadUntil[base]) {
+			dead = append(dead, base)
+		} else {
+			live = append(live, base)
+		}
+	}
+	return append(live, dead...)
+}
+
+// setHealthy records whether base answered, starting its cooldown if not.
+func (m *CacheManager) setHealthy(base string, ok bool) {
+	m.health.mu.Lock()
+	defer m.health.mu.Unlock()
+	if ok {
+		delete(m.health.deadUntil, base)
+		return
+	}
+	if m.health.deadUntil == nil {
+		m.health.deadUntil = map[string]time.Time{}
+	}
+	m.health.deadUntil[base] = time.Now().Add(m.cooldown)
+}
+
+// cdnDo requests filename from each base URL in turn until one answers
+// without a network error, 5xx or 429, which send it to the next URL. The
+// last URL's answer is returned whatever it is, so with a single base URL
+// this is a plain request.
+func (m *CacheManager) cdnDo(ctx context.Context, method, filename string) (*http.Response, error) {
+	bases := m.bases()
+	for i, base := range bases {
+		req, err := m.cdnRequest(ctx, method, base, filename)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := m.httpClient().Do(req)
+		failed := err != nil || resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		if len(bases) == 1 || ctx.Err() != nil {
+			return resp, err
+		}
+		m.setHealthy(base, !failed)
+		if !failed || i == len(bases)-1 {
+			return resp, err
+		}
+		if err == nil {
+			resp.Body.Close()
+			err = fmt.Errorf("HTTP %d", resp.StatusCode)
+		}
+		m.Logger().Warn("Mirror failed; trying the next", "file", filename, "url", base, "error", err)
+	}
+	panic("unreachable")
+}
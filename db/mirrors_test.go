@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFallbackMirrors(t *testing.T) {
+	var primaryHits atomic.Int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryHits.Add(1)
+		http.Error(w, "down", http.StatusBadGateway)
+	}))
+	defer primary.Close()
+	backup := httptest.NewServer(&fakeCDN{
+		version: "v1",
+		etags:   map[string]string{"parquet/cards.parquet": `"c1"`, "parquet/sets.parquet": `"s1"`},
+		gets:    make(map[string]int),
+	})
+	defer backup.Close()
+
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.MirrorURL = primary.URL
+	cfg.FallbackMirrors = []string{backup.URL + "/"}
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if v := cache.RemoteVersion(ctx); v != "v1" {
+		t.Fatalf("expected the backup's version, got %q", v)
+	}
+	path, err := cache.EnsureParquet(ctx, "cards")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != `parquet/cards.parquet@"c1"` {
+		t.Fatalf("unexpected file contents %q", data)
+	}
+	if _, err := cache.EnsureParquet(ctx, "sets"); err != nil {
+		t.Fatal(err)
+	}
+	// Only the first request reaches the primary; it is then in cooldown.
+	if n := primaryHits.Load(); n != 1 {
+		t.Fatalf("expected 1 request to the failed primary, got %d", n)
+	}
+	if bases := cache.bases(); bases[0] != backup.URL || bases[1] != primary.URL {
+		t.Fatalf("expected the primary last during its cooldown, got %v", bases)
+	}
+}
+
+func TestFallbackMirrorsAllDown(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "busy", http.StatusTooManyRequests)
+	}))
+	defer down.Close()
+
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.MirrorURL = down.URL
+	cfg.FallbackMirrors = []string{down.URL + "/second"}
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.EnsureParquet(context.Background(), "cards"); err == nil {
+		t.Fatal("expected an error when every mirror fails")
+	}
+}
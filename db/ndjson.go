@@ -0,0 +1,70 @@
+package db
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ExecuteToWriter runs SQL and writes each row to w as one line of JSON
+// (NDJSON), reading rows one at a time so memory stays constant however
+// large the result. Rows have the same shape ExecuteJSON gives them. DuckDB
+// encodes the rows when the json extension is available; otherwise they are
+// encoded in Go.
+func (c *Connection) ExecuteToWriter(ctx context.Context, w io.Writer, query string, params ...any) (err error) {
+	if !c.Capabilities().JSON {
+		return writeNDJSON(ctx, c, w, query, params...)
+	}
+	defer c.observeQuery(time.Now(), nil, &err)
+	wrapped := fmt.Sprintf("SELECT CAST(to_json(sub) AS VARCHAR) FROM (%s) sub", query)
+	rows, err := c.db.QueryContext(ctx, wrapped, params...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	bw := bufio.NewWriter(w)
+	for rows.Next() {
+		var line sql.NullString
+		if err := rows.Scan(&line); err != nil {
+			return err
+		}
+		bw.WriteString(line.String)
+		if err := bw.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// WriteNDJSON is ExecuteToWriter for any Backend. Rows of other backends
+// are read with ExecuteEach and encoded in Go.
+func WriteNDJSON(ctx context.Context, b Backend, w io.Writer, query string, params ...any) error {
+	if c, ok := b.(*Connection); ok {
+		return c.ExecuteToWriter(ctx, w, query, params...)
+	}
+	return writeNDJSON(ctx, b, w, query, params...)
+}
+
+func writeNDJSON(ctx context.Context, b Backend, w io.Writer, query string, params ...any) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	err := b.ExecuteEach(ctx, func(row map[string]any) error {
+		for col, v := range row {
+			if t, ok := v.(time.Time); ok && t.Equal(t.Truncate(24*time.Hour)) {
+				row[col] = t.Format(time.DateOnly)
+			}
+		}
+		return enc.Encode(row)
+	}, query, params...)
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,47 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestExecuteToWriter(t *testing.T) {
+	conn := testConnection(t)
+	ctx := context.Background()
+	query := "SELECT * FROM (VALUES (1, 'Lightning Bolt', DATE '2024-01-02'), (2, 'Counterspell', NULL)) t(id, name, released) " +
+		"WHERE id >= $1 ORDER BY id"
+
+	for _, caps := range []Capabilities{{JSON: true, JaroWinkler: true}, {JaroWinkler: true}} {
+		if caps.JSON && !conn.Capabilities().JSON {
+			continue
+		}
+		conn.SetCapabilities(caps)
+		var buf bytes.Buffer
+		if err := conn.ExecuteToWriter(ctx, &buf, query, 1); err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("json=%v: expected 2 lines, got %q", caps.JSON, buf.String())
+		}
+		var row struct {
+			ID       int     `json:"id"`
+			Name     string  `json:"name"`
+			Released *string `json:"released"`
+		}
+		if err := json.Unmarshal([]byte(lines[0]), &row); err != nil {
+			t.Fatal(err)
+		}
+		if row.ID != 1 || row.Name != "Lightning Bolt" || row.Released == nil || *row.Released != "2024-01-02" {
+			t.Errorf("json=%v: unexpected first row %s", caps.JSON, lines[0])
+		}
+
+		buf.Reset()
+		if err := conn.ExecuteToWriter(ctx, &buf, query, 3); err != nil || buf.Len() != 0 {
+			t.Errorf("json=%v: expected no output for no rows, got %q (%v)", caps.JSON, buf.String(), err)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package db
+
+import (
+	"sync"
+	"time"
+)
+
+// Progress describes the state of one file download.
+type Progress struct {
+	Filename   string        // CDN file name
+	Downloaded int64         // bytes received so far
+	Total      int64         // expected bytes, 0 if unknown
+	Rate       float64       // average bytes per second since the download started
+	ETA        time.Duration // estimated time remaining, 0 if unknown
+	Done       bool          // set on the final event for the file
+	Err        error         // why the download failed, on a final event
+}
+
+// ProgressEventFunc receives download progress. It runs on its own goroutine per
+// download and never blocks the transfer: while it is busy, newer events
+// replace older undelivered ones, so a slow callback sees fewer events
+// rather than slowing the download. Events for a file arrive in order and
+// the final one, with Done set, is always delivered, possibly after the
+// download has returned.
+type ProgressEventFunc func(Progress)
+
+// progressEvents combines the Config progress callbacks into one
+// ProgressEventFunc, or returns nil if neither is set.
+func progressEvents(fn ProgressFunc, events ProgressEventFunc) ProgressEventFunc {
+	if fn == nil {
+		return events
+	}
+	return func(p Progress) {
+		fn(p.Filename, p.Downloaded, p.Total)
+		if events != nil {
+			events(p)
+		}
+	}
+}
+
+// progressReporter hands progress events from a download to a
+// ProgressEventFunc, keeping only the latest undelivered event.
+type progressReporter struct {
+	fn    ProgressEventFunc
+	start time.Time
+
+	mu     sync.Mutex
+	latest Progress
+	wake   chan struct{}
+}
+
+func newProgressReporter(fn ProgressEventFunc) *progressReporter {
+	r := &progressReporter{fn: fn, start: time.Now(), wake: make(chan struct{}, 1)}
+	go r.loop()
+	return r
+}
+
+func (r *progressReporter) loop() {
+	for range r.wake {
+		r.mu.Lock()
+		p := r.latest
+		r.mu.Unlock()
+		r.fn(p)
+		if p.Done {
+			// A tick woken before finish can read the final event, so
+			// finish's own wake-up must not deliver it again.
+			return
+		}
+	}
+}
+
+// report records the current state without waiting for the callback.
+func (r *progressReporter) report(filename string, downloaded, total int64) {
+	p := Progress{Filename: filename, Downloaded: downloaded, Total: total}
+	if elapsed := time.Since(r.start).Seconds(); elapsed > 0 {
+		p.Rate = float64(downloaded) / elapsed
+	}
+	if total > 0 && p.Rate > 0 && downloaded < total {
+		p.ETA = time.Duration(float64(total-downloaded) / p.Rate * float64(time.Second))
+	}
+	r.publish(p)
+}
+
+// finish queues the final event and stops the reporter once it has been
+// delivered. report must not be called afterwards.
+func (r *progressReporter) finish(filename string, downloaded, total int64, err error) {
+	p := Progress{Filename: filename, Downloaded: downloaded, Total: total, Done: true, Err: err}
+	if elapsed := time.Since(r.start).Seconds(); elapsed > 0 {
+		p.Rate = float64(downloaded) / elapsed
+	}
+	r.publish(p)
+	close(r.wake)
+}
+
+func (r *progressReporter) publish(p Progress) {
+	r.mu.Lock()
+	r.latest = p
+	r.mu.Unlock()
+	select {
+	case r.wake <- struct{}{}:
+	default: // an event is already pending; the loop will pick up p
+	}
+}
@@ -0,0 +1,160 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func progressCache(t *testing.T, fn ProgressEventFunc) *CacheManager {
+	t.Helper()
+	body := bytes.Repeat([]byte("x"), 4<<20)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/big.bin" {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeContent(w, r, "big.bin", time.Time{}, bytes.NewReader(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.OnProgressEvent = fn
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.baseURL = srv.URL
+	return cache
+}
+
+func TestProgressSlowCallbackDoesNotStall(t *testing.T) {
+	events := make(chan Progress, 1024)
+	cache := progressCache(t, func(p Progress) {
+		time.Sleep(50 * time.Millisecond)
+		events <- p
+	})
+
+	start := time.Now()
+	if err := cache.downloadFile(context.Background(), "big.bin", filepath.Join(cache.CacheDir, "big.bin")); err != nil {
+		t.Fatal(err)
+	}
+	// 4MB is 64 chunks; delivering each would take over 3s.
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("download waited for the callback: took %v", elapsed)
+	}
+
+	var last Progress
+	for p := range events {
+		if p.Downloaded < last.Downloaded {
+			t.Fatalf("events out of order: %d after %d", p.Downloaded, last.Downloaded)
+		}
+		last = p
+		if p.Done {
+			break
+		}
+	}
+	if last.Err != nil || last.Downloaded != 4<<20 || last.Total != 4<<20 {
+		t.Errorf("unexpected final event %+v", last)
+	}
+	if last.Rate <= 0 {
+		t.Errorf("expected a transfer rate, got %+v", last)
+	}
+}
+
+func TestProgressETA(t *testing.T) {
+	r := &progressReporter{start: time.Now().Add(-time.Second), wake: make(chan struct{}, 1)}
+	r.report("f", 100, 400)
+	p := r.latest
+	if p.Rate < 90 || p.Rate > 110 {
+		t.Errorf("expected about 100 B/s, got %v", p.Rate)
+	}
+	if p.ETA < 2500*time.Millisecond || p.ETA > 3500*time.Millisecond {
+		t.Errorf("expected about 3s remaining, got %v", p.ETA)
+	}
+	r.report("f", 100, 0)
+	if r.latest.ETA != 0 {
+		t.Errorf("expected no ETA for an unknown size, got %v", r.latest.ETA)
+	}
+}
+
+func TestProgressFinalEventOnError(t *testing.T) {
+	done := make(chan Progress, 1)
+	cache := progressCache(t, func(p Progress) {
+		if p.Done {
+			done <- p
+		}
+	})
+	if err := cache.downloadFile(context.Background(), "missing.bin", filepath.Join(cache.CacheDir, "missing.bin")); err == nil {
+		t.Fatal("expected an error for a missing file")
+	}
+	select {
+	case p := <-done:
+		if p.Err == nil || p.Filename != "missing.bin" {
+			t.Errorf("expected a final event carrying the error, got %+v", p)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no final event")
+	}
+}
+
+func TestProgressFuncFinalCall(t *testing.T) {
+	done := make(chan [2]int64, 1)
+	cache := progressCache(t, nil)
+	cache.onProgress = progressEvents(func(filename string, downloaded, total int64) {
+		if filename == "big.bin" && downloaded == total {
+			done <- [2]int64{downloaded, total}
+		}
+	}, nil)
+	if err := cache.downloadFile(context.Background(), "big.bin", filepath.Join(cache.CacheDir, "big.bin")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-done:
+		if got[0] != 4<<20 {
+			t.Errorf("expected the full size on the last call, got %v", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no final call")
+	}
+}
+
+func TestProgressFinalEventDeliveredOnce(t *testing.T) {
+	finals := 0
+	r := &progressReporter{
+		fn: func(p Progress) {
+			if p.Done {
+				finals++
+			}
+		},
+		start: time.Now(),
+		wake:  make(chan struct{}, 1),
+	}
+	r.publish(Progress{Filename: "f", Downloaded: 1})
+
+	// Let the loop take the pending tick, then run finish's steps before it
+	// reads the event.
+	r.mu.Lock()
+	stopped := make(chan struct{})
+	go func() {
+		r.loop()
+		close(stopped)
+	}()
+	for len(r.wake) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	r.latest = Progress{Filename: "f", Downloaded: 1, Done: true}
+	r.wake <- struct{}{}
+	close(r.wake)
+	r.mu.Unlock()
+
+	<-stopped
+	if finals != 1 {
+		t.Errorf("expected the final event once, got %d", finals)
+	}
+}
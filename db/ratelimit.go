@@ -0,0 +1,106 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket: up to burst requests may start at once,
+// and perSecond on average after that. Waiters reserve their token up front,
+// so they are served in arrival order.
+type rateLimiter struct {
+	mu        sync.Mutex
+	perSecond float64
+	burst     float64
+	tokens    float64
+	last      time.Time
+}
+
+func newRateLimiter(perSecond float64, burst int) *rateLimiter {
+	burst = max(burst, 1)
+	return &rateLimiter{perSecond: perSecond, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// wait blocks until the caller may send a request, or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.perSecond)
+	l.last = now
+	l.tokens--
+	delay := time.Duration(-l.tokens / l.perSecond * float64(time.Second))
+	l.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++ // give the reservation back
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// sharedLimiters holds one limiter per base URL and rate, so that every
+// CacheManager in the process talking to the same CDN or mirror draws from
+// the same budget.
+var (
+	sharedLimitersMu sync.Mutex
+	sharedLimiters   = map[string]*rateLimiter{}
+)
+
+func sharedLimiter(baseURL string, perSecond float64, burst int) *rateLimiter {
+	key := fmt.Sprintf("%s|%g|%d", baseURL, perSecond, burst)
+	sharedLimitersMu.Lock()
+	defer sharedLimitersMu.Unlock()
+	l, ok := sharedLimiters[key]
+	if !ok {
+		l = newRateLimiter(perSecond, burst)
+		sharedLimiters[key] = l
+	}
+	return l
+}
+
+// flight is one in-progress fetch shared by concurrent callers.
+type flight struct {
+	done chan struct{}
+	val  string
+}
+
+var (
+	flightsMu sync.Mutex
+	flights   = map[string]*flight{}
+)
+
+// singleFlight runs fetch once for concurrent callers with the same key,
+// across CacheManagers, handing each the result. A caller whose ctx ends
+// stops waiting and gets "".
+func singleFlight(ctx context.Context, key string, fetch func() string) string {
+	flightsMu.Lock()
+	if f, ok := flights[key]; ok {
+		flightsMu.Unlock()
+		select {
+		case <-f.done:
+			return f.val
+		case <-ctx.Done():
+			return ""
+		}
+	}
+	f := &flight{done: make(chan struct{})}
+	flights[key] = f
+	flightsMu.Unlock()
+
+	f.val = fetch()
+	flightsMu.Lock()
+	delete(flights, key)
+	flightsMu.Unlock()
+	close(f.done)
+	return f.val
+}
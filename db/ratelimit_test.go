@@ -0,0 +1,113 @@
+package db
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterPacesRequests(t *testing.T) {
+	l := newRateLimiter(50, 2)
+	ctx := context.Background()
+	start := time.Now()
+	for range 4 {
+		if err := l.wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Two requests burst, the other two wait 20ms each.
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Fatalf("expected pacing of about 40ms, got %v", elapsed)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := newRateLimiter(0.001, 1).wait(cancelled); err != nil {
+		t.Fatalf("first request should use the burst, got %v", err)
+	}
+	slow := newRateLimiter(0.001, 1)
+	slow.wait(ctx)
+	if err := slow.wait(cancelled); err == nil {
+		t.Fatal("expected the cancelled wait to fail")
+	}
+}
+
+func TestRateLimitSharesMetaRequests(t *testing.T) {
+	var metas atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		metas.Add(1)
+		<-release
+		w.Write([]byte(`{"data":{"version":"5.2.2+20240101"}}`))
+	}))
+	defer srv.Close()
+
+	var managers []*CacheManager
+	for range 5 {
+		cfg := DefaultConfig()
+		cfg.CacheDir = t.TempDir()
+		cfg.MirrorURL = srv.URL
+		cfg.RateLimit = 100
+		cfg.RateBurst = 10
+		cache, err := NewCacheManager(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		managers = append(managers, cache)
+	}
+	if managers[0].limiter != managers[4].limiter {
+		t.Fatal("expected managers with the same base URL and limit to share a limiter")
+	}
+
+	var wg sync.WaitGroup
+	versions := make([]string, len(managers))
+	for i, m := range managers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			versions[i] = m.RemoteVersion(context.Background())
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	for _, v := range versions {
+		if v != "5.2.2+20240101" {
+			t.Fatalf("unexpected versions %v", versions)
+		}
+	}
+	if n := metas.Load(); n != 1 {
+		t.Fatalf("expected 1 Meta.json request, got %d", n)
+	}
+}
+
+func TestRateLimitPacesRedirects(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://shop.example/card", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.MirrorURL = srv.URL
+	cfg.RateLimit = 50
+	cfg.RateBurst = 1
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	for range 3 {
+		if _, err := cache.ResolveRedirect(context.Background(), srv.URL+"/links/abc"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// One request uses the burst, the other two wait 20ms each.
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Fatalf("expected redirects paced by the limiter, got %v", elapsed)
+	}
+}
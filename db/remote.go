@@ -0,0 +1,66 @@
+package db
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// RemoteParquetURL returns the CDN (or mirror) URL of a view's parquet file
+// when Config.RemoteParquet has the view read over HTTP instead of
+// downloaded. It reports false when the file is already cached, which is
+// faster to read, when offline, and when custom headers or an
+// AuthProvider are configured, since DuckDB's HTTP reader would not send
+// them.
+func (m *CacheManager) RemoteParquetURL(view string) (string, bool) {
+	if !m.remoteParquet || m.Offline || len(m.headers) > 0 || m.auth != nil {
+		return "", false
+	}
+	if len(m.remoteViews) > 0 && !slices.Contains(m.remoteViews, view) {
+		return "", false
+	}
+	filename, ok := ParquetFiles[view]
+	if !ok || fileExists(m.Path(filename)) {
+		return "", false
+	}
+	return m.baseURL + "/" + filename, true
+}
+
+// parquetPath returns where to read a view's parquet file: its CDN URL when
+// it is read remotely and DuckDB's httpfs extension loads, otherwise the
+// cached file, downloading it if needed. Callers hold c.mu.
+func (c *Connection) parquetPath(ctx context.Context, view string) (string, error) {
+	if url, ok := c.cache.RemoteParquetURL(view); ok && c.loadHTTPFS(ctx) {
+		return url, nil
+	}
+	return c.cache.EnsureParquet(ctx, view)
+}
+
+// loadHTTPFS loads DuckDB's httpfs extension, installing it if needed, and
+// reports whether it is available. The outcome is remembered; when it
+// fails, remote views fall back to downloads. Callers hold c.mu.
+func (c *Connection) loadHTTPFS(ctx context.Context) bool {
+	if c.httpfs == 0 {
+		c.httpfs = -1
+		_, err := c.db.ExecContext(ctx, "LOAD httpfs")
+		if err != nil {
+			if _, err = c.db.ExecContext(ctx, "INSTALL httpfs"); err == nil {
+				_, err = c.db.ExecContext(ctx, "LOAD httpfs")
+			}
+		}
+		if err != nil {
+			c.Logger().Warn("DuckDB httpfs unavailable; downloading remote parquet files instead", "error", err)
+			return false
+		}
+		// Reuse file metadata between queries instead of re-reading it.
+		c.db.ExecContext(ctx, "SET enable_http_metadata_cache = true")
+		c.httpfs = 1
+	}
+	return c.httpfs == 1
+}
+
+// isRemotePath reports whether a path from parquetPath is a URL read over
+// httpfs, where every scan of the file is a network transfer.
+func isRemotePath(path string) bool {
+	return strings.HasPrefix(path, "https://") || strings.HasPrefix(path, "http://")
+}
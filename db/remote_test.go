@@ -0,0 +1,62 @@
+package db
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRemoteParquetURL(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.MirrorURL = "https://mirror.example.com/api/v5"
+	cfg.RemoteParquet = true
+	cfg.RemoteParquetViews = []string{"cards", "sets"}
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	url, ok := cache.RemoteParquetURL("cards")
+	if !ok || url != "https://mirror.example.com/api/v5/parquet/cards.parquet" {
+		t.Fatalf("expected the mirror URL, got %q, %v", url, ok)
+	}
+	if _, ok := cache.RemoteParquetURL("tokens"); ok {
+		t.Fatal("expected views outside RemoteParquetViews to be downloaded")
+	}
+
+	// A cached file is read locally.
+	path := filepath.Join(cfg.CacheDir, "parquet", "sets.parquet")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("PAR1"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.RemoteParquetURL("sets"); ok {
+		t.Fatal("expected a cached file to be read locally")
+	}
+
+	// httpfs would not send custom headers.
+	cfg.Headers = http.Header{"X-Api-Key": {"secret"}}
+	cache, err = NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.RemoteParquetURL("cards"); ok {
+		t.Fatal("expected remote reads to be disabled with custom headers")
+	}
+}
+
+func TestRemotePathSources(t *testing.T) {
+	url := "https://mirror.example.com/api/v5/parquet/cardLegalities.parquet"
+	if !isRemotePath(url) || isRemotePath(filepath.Join(t.TempDir(), "cardLegalities.parquet")) {
+		t.Fatal("expected only the URL to be remote")
+	}
+	a := legalitiesSource(url, "5.2.2+20240101", []string{"modern"})
+	b := legalitiesSource(url, "5.2.2+20240201", []string{"modern"})
+	if a == b {
+		t.Fatalf("expected a new release to change the remote source, got %q twice", a)
+	}
+}
@@ -199,6 +199,8 @@ func (b *SQLBuilder) Offset(n int) *SQLBuilder {
 
 // Build returns the final SQL string and parameter list.
 // The SQL uses $1, $2, ... placeholders matching the parameter positions.
+// LIMIT and OFFSET are bound as the last parameters, so queries that differ
+// only in paging share the same SQL text.
 func (b *SQLBuilder) Build() (string, []any) {
 	var parts []string
 
@@ -229,15 +231,18 @@ func (b *SQLBuilder) Build() (string, []any) {
 		parts = append(parts, "ORDER BY "+strings.Join(b.orderBys, ", "))
 	}
 
+	params := append([]any(nil), b.params...)
 	if b.limitVal != nil {
-		parts = append(parts, fmt.Sprintf("LIMIT %d", *b.limitVal))
+		params = append(params, *b.limitVal)
+		parts = append(parts, fmt.Sprintf("LIMIT $%d", len(params)))
 	}
 
 	if b.offsetVal != nil {
-		parts = append(parts, fmt.Sprintf("OFFSET %d", *b.offsetVal))
+		params = append(params, *b.offsetVal)
+		parts = append(parts, fmt.Sprintf("OFFSET $%d", len(params)))
 	}
 
-	return strings.Join(parts, "\n"), b.params
+	return strings.Join(parts, "\n"), params
 }
 
 // AddWhere exposes direct addition of a WHERE clause for query modules.
@@ -192,10 +192,10 @@ func TestFullQuery(t *testing.T) {
 	if !strings.Contains(sql, "ORDER BY avg_price DESC") {
 		t.Errorf("expected ORDER BY, got: %s", sql)
 	}
-	if !strings.Contains(sql, "LIMIT 10") {
-		t.Errorf("expected LIMIT 10, got: %s", sql)
+	if !strings.Contains(sql, "LIMIT $4") {
+		t.Errorf("expected LIMIT $4, got: %s", sql)
 	}
-	if len(params) != 3 || params[0] != "abc-123" || params[1] != "2024-01-01" || params[2] != 1.0 {
+	if len(params) != 4 || params[0] != "abc-123" || params[1] != "2024-01-01" || params[2] != 1.0 || params[3] != 10 {
 		t.Errorf("unexpected params: %v", params)
 	}
 }
@@ -212,9 +212,9 @@ func TestLimitRejectsNegative(t *testing.T) {
 
 func TestLimitAcceptsZero(t *testing.T) {
 	q := NewSQLBuilder("t").Limit(0)
-	sql, _ := q.Build()
-	if !strings.Contains(sql, "LIMIT 0") {
-		t.Errorf("expected LIMIT 0, got: %s", sql)
+	sql, params := q.Build()
+	if !strings.Contains(sql, "LIMIT $1") || len(params) != 1 || params[0] != 0 {
+		t.Errorf("expected LIMIT $1 bound to 0, got: %s %v", sql, params)
 	}
 }
 
@@ -289,19 +289,26 @@ func TestJoin(t *testing.T) {
 
 func TestOffsetOnly(t *testing.T) {
 	q := NewSQLBuilder("t").Offset(10)
-	sql, _ := q.Build()
-	if !strings.Contains(sql, "OFFSET 10") {
-		t.Errorf("expected OFFSET 10, got: %s", sql)
+	sql, params := q.Build()
+	if !strings.Contains(sql, "OFFSET $1") || len(params) != 1 || params[0] != 10 {
+		t.Errorf("expected OFFSET $1 bound to 10, got: %s %v", sql, params)
 	}
 }
 
 func TestLimitAndOffset(t *testing.T) {
-	q := NewSQLBuilder("t").Limit(10).Offset(20)
-	sql, _ := q.Build()
-	if !strings.Contains(sql, "LIMIT 10") {
-		t.Errorf("expected LIMIT 10, got: %s", sql)
+	q := NewSQLBuilder("t").WhereEq("x", 1).Limit(10).Offset(20)
+	sql, params := q.Build()
+	if !strings.Contains(sql, "LIMIT $2") {
+		t.Errorf("expected LIMIT $2, got: %s", sql)
+	}
+	if !strings.Contains(sql, "OFFSET $3") {
+		t.Errorf("expected OFFSET $3, got: %s", sql)
+	}
+	if len(params) != 3 || params[1] != 10 || params[2] != 20 {
+		t.Errorf("unexpected params: %v", params)
 	}
-	if !strings.Contains(sql, "OFFSET 20") {
-		t.Errorf("expected OFFSET 20, got: %s", sql)
+	// Building again must not grow the builder's own parameters.
+	if _, again := q.Build(); len(again) != 3 {
+		t.Errorf("expected Build to be repeatable, got %v", again)
 	}
 }
@@ -0,0 +1,148 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Store keeps cached files outside CacheDir, so that deployments without
+// persistent disk, such as serverless functions, can reuse downloads between
+// invocations. DuckDB reads local files only, so CacheDir (typically under
+// /tmp) still stages every file: on a miss or a stale copy there, the
+// CacheManager fetches the file from the Store when it holds the current
+// release, and otherwise downloads it from the CDN and uploads it.
+//
+// Keys are CDN file names such as "parquet/cards.parquet". Each file is
+// accompanied by a small "<key>.meta.json" object recording its version.
+type Store interface {
+	// Get returns the object stored under key, or an error wrapping
+	// fs.ErrNotExist if there is none.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Put stores size bytes read from r under key, replacing any object.
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+}
+
+// storeMetaSuffix names the object holding a stored file's datasetEntry.
+const storeMetaSuffix = ".meta.json"
+
+// DirStore is a Store in a directory, such as a network file system mount.
+type DirStore string
+
+// Get opens the file for key.
+func (d DirStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(string(d), filepath.FromSlash(key)))
+}
+
+// Put writes the file for key, replacing it atomically.
+func (d DirStore) Put(_ context.Context, key string, r io.Reader, _ int64) error {
+	return copyToFile(filepath.Join(string(d), filepath.FromSlash(key)), r)
+}
+
+// copyToFile writes r to path through a temp file in the same directory, so
+// readers never see a partial file.
+func copyToFile(path string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// fetchFromStore copies filename from the Store into localPath if the Store
+// holds the current release (or any release when the CDN version is
+// unknown). It reports whether it did; failures are logged and left to the
+// CDN download.
+func (m *CacheManager) fetchFromStore(ctx context.Context, filename, localPath string) bool {
+	if m.store == nil {
+		return false
+	}
+	entry, err := m.storedEntry(ctx, filename)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			m.Logger().Warn("Failed to read cache store", "file", filename, "error", err)
+		}
+		return false
+	}
+	if remote := m.RemoteVersion(ctx); remote != "" && entry.Version != remote {
+		return false
+	}
+	rc, err := m.store.Get(ctx, filename)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			m.Logger().Warn("Failed to read cache store", "file", filename, "error", err)
+		}
+		return false
+	}
+	defer rc.Close()
+	if err := copyToFile(localPath, rc); err != nil {
+		m.Logger().Warn("Failed to copy from cache store", "file", filename, "error", err)
+		return false
+	}
+	m.Logger().Info("Restored from cache store", "file", filename, "version", entry.Version)
+	m.recordDataset(filename, entry)
+	return true
+}
+
+func (m *CacheManager) storedEntry(ctx context.Context, filename string) (datasetEntry, error) {
+	var entry datasetEntry
+	rc, err := m.store.Get(ctx, filename+storeMetaSuffix)
+	if err != nil {
+		return entry, err
+	}
+	defer rc.Close()
+	err = json.NewDecoder(rc).Decode(&entry)
+	return entry, err
+}
+
+// pushToStore uploads a freshly downloaded file and then its metadata, so a
+// metadata object always describes a complete upload. Failures are logged:
+// the local copy is still usable.
+func (m *CacheManager) pushToStore(ctx context.Context, filename, localPath string) {
+	if m.store == nil {
+		return
+	}
+	m.manifestMu.Lock()
+	entry := readManifest(m.CacheDir)[filename]
+	m.manifestMu.Unlock()
+	meta, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	err = func() error {
+		f, err := os.Open(localPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		if err := m.store.Put(ctx, filename, f, info.Size()); err != nil {
+			return err
+		}
+		return m.store.Put(ctx, filename+storeMetaSuffix, bytes.NewReader(meta), int64(len(meta)))
+	}()
+	if err != nil {
+		m.Logger().Warn("Failed to upload to cache store", "file", filename, "error", err)
+	}
+}
@@ -0,0 +1,121 @@
+package db
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// versionsDir is the cache subdirectory holding one snapshot per archived
+// MTGJSON version.
+const versionsDir = "versions"
+
+// snapshotVersion saves the cached files of version into versions/<version>
+// before RefreshDatasets replaces them, then prunes snapshots beyond
+// KeepVersions. Files are hard-linked where possible, so unchanged files
+// share disk space with the live cache. Callers hold the cache lock.
+func (m *CacheManager) snapshotVersion(version string, entries map[string]datasetEntry) error {
+	dir := filepath.Join(m.CacheDir, versionsDir, version)
+	if fileExists(dir) {
+		return nil
+	}
+	tmp := dir + ".partial"
+	os.RemoveAll(tmp)
+	if err := os.MkdirAll(tmp, 0o755); err != nil {
+		return fmt.Errorf("mtgjson: snapshot %s: %w", version, err)
+	}
+	for _, filename := range m.cachedFiles() {
+		dst := filepath.Join(tmp, filename)
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return fmt.Errorf("mtgjson: snapshot %s: %w", version, err)
+		}
+		if err := linkOrCopy(m.Path(filename), dst); err != nil {
+			os.RemoveAll(tmp)
+			return fmt.Errorf("mtgjson: snapshot %s of %s: %w", filename, version, err)
+		}
+	}
+	kept := make(map[string]datasetEntry, len(entries))
+	for filename, entry := range entries {
+		if fileExists(filepath.Join(tmp, filename)) {
+			kept[filename] = entry
+		}
+	}
+	snapshot := &CacheManager{CacheDir: tmp}
+	snapshot.writeManifest(kept)
+	snapshot.saveVersion(version)
+	if err := os.Rename(tmp, dir); err != nil {
+		os.RemoveAll(tmp)
+		return fmt.Errorf("mtgjson: snapshot %s: %w", version, err)
+	}
+	versions := m.Versions()
+	for _, old := range versions[min(m.KeepVersions, len(versions)):] {
+		os.RemoveAll(filepath.Join(m.CacheDir, versionsDir, old))
+	}
+	return nil
+}
+
+// Versions returns the archived MTGJSON versions kept by KeepVersions,
+// newest first.
+func (m *CacheManager) Versions() []string {
+	dirs, err := os.ReadDir(filepath.Join(m.CacheDir, versionsDir))
+	if err != nil {
+		return nil
+	}
+	var versions []string
+	for _, d := range dirs {
+		if d.IsDir() && !strings.HasSuffix(d.Name(), ".partial") {
+			versions = append(versions, d.Name())
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versionKey(versions[i]) > versionKey(versions[j])
+	})
+	return versions
+}
+
+// VersionDir returns the cache directory holding an archived version's
+// files, laid out like CacheDir, or an error if it was not kept.
+func (m *CacheManager) VersionDir(version string) (string, error) {
+	if version == "" || strings.ContainsAny(version, `/\`) || strings.Contains(version, "..") {
+		return "", fmt.Errorf("mtgjson: invalid version %q", version)
+	}
+	dir := filepath.Join(m.CacheDir, versionsDir, version)
+	if !fileExists(filepath.Join(dir, "version.txt")) {
+		return "", fmt.Errorf("mtgjson: version %q is not archived (have %v)", version, m.Versions())
+	}
+	return dir, nil
+}
+
+// versionKey orders MTGJSON versions such as "5.2.2+20240101" by their
+// build date, falling back to the whole string.
+func versionKey(version string) string {
+	if _, date, ok := strings.Cut(version, "+"); ok {
+		return date
+	}
+	return version
+}
+
+// linkOrCopy hard-links src to dst, copying it if links are unsupported,
+// e.g. across file systems.
+func linkOrCopy(src, dst string) error {
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestRefreshDatasetsKeepsVersions(t *testing.T) {
+	cdn := &fakeCDN{
+		version: "5.2.2+20240101",
+		etags:   map[string]string{"parquet/cards.parquet": `"c1"`, "parquet/AllPricesToday.parquet": `"p1"`},
+		gets:    make(map[string]int),
+	}
+	srv := httptest.NewServer(cdn)
+	defer srv.Close()
+
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.KeepVersions = 2
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.baseURL = srv.URL
+	ctx := context.Background()
+	load := func() {
+		t.Helper()
+		for _, view := range []string{"cards", "all_prices_today"} {
+			if _, err := cache.EnsureParquet(ctx, view); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	release := func(version, prices string) {
+		t.Helper()
+		cdn.mu.Lock()
+		cdn.version = version
+		cdn.etags["parquet/AllPricesToday.parquet"] = prices
+		cdn.mu.Unlock()
+		cache.ResetRemoteVersion()
+		if _, err := cache.RefreshDatasets(ctx); err != nil {
+			t.Fatal(err)
+		}
+		load()
+	}
+
+	load()
+	release("5.2.2+20240201", `"p2"`)
+	dir, err := cache.VersionDir("5.2.2+20240101")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "parquet", "AllPricesToday.parquet"))
+	if err != nil || string(data) != `parquet/AllPricesToday.parquet@"p1"` {
+		t.Fatalf("expected the old prices in the snapshot, got %q (%v)", data, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "parquet", "cards.parquet")); string(data) != `parquet/cards.parquet@"c1"` {
+		t.Fatalf("expected the unchanged cards in the snapshot, got %q", data)
+	}
+	if readVersion(dir) != "5.2.2+20240101" {
+		t.Fatalf("expected the snapshot to carry its version, got %q", readVersion(dir))
+	}
+
+	release("5.2.3+20240301", `"p3"`)
+	release("5.2.3+20240401", `"p4"`)
+	if got, want := cache.Versions(), []string{"5.2.3+20240301", "5.2.2+20240201"}; !slices.Equal(got, want) {
+		t.Fatalf("expected the two newest snapshots %v, got %v", want, got)
+	}
+	if _, err := cache.VersionDir("5.2.2+20240101"); err == nil {
+		t.Fatal("expected the oldest snapshot to be pruned")
+	}
+	if _, err := cache.VersionDir("../x"); err == nil {
+		t.Fatal("expected an invalid version to be rejected")
+	}
+}
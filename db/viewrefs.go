@@ -0,0 +1,159 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+// ReferencedViews returns the SDK views a query reads, in order of first
+// appearance: names from ParquetFiles (and card_legalities_wide) that follow
+// FROM or JOIN, including each table of a comma-separated FROM list. A
+// derived table such as LatestPricesTable stands for the view it is built
+// from. String literals, comments and schema-qualified names are skipped,
+// and other tables are ignored.
+func ReferencedViews(query string) []string {
+	toks := sqlTokens(query)
+	var views []string
+	seen := map[string]bool{}
+	add := func(i int) {
+		if i >= len(toks) || !toks[i].ident || i+1 < len(toks) && toks[i+1].text == "." {
+			return
+		}
+		name := strings.ToLower(toks[i].text)
+		if source, ok := derivedSource(name); ok {
+			name = source
+		}
+		if _, ok := ParquetFiles[name]; (ok || name == "card_legalities_wide") && !seen[name] {
+			seen[name] = true
+			views = append(views, name)
+		}
+	}
+	for i, tok := range toks {
+		if !tok.ident {
+			continue
+		}
+		switch strings.ToUpper(tok.text) {
+		case "JOIN":
+			add(i + 1)
+		case "FROM":
+			// FROM a [AS] x, b y, ...
+			for j := i + 1; j < len(toks); {
+				add(j)
+				j++
+				if j < len(toks) && strings.EqualFold(toks[j].text, "AS") {
+					j++
+				}
+				if j < len(toks) && toks[j].ident && !sqlKeywords[strings.ToUpper(toks[j].text)] {
+					j++
+				}
+				if j >= len(toks) || toks[j].text != "," {
+					break
+				}
+				j++
+			}
+		}
+	}
+	return views
+}
+
+// derivedSource returns the view the derived table name is built from.
+func derivedSource(name string) (string, bool) {
+	for view, tables := range derivedTables {
+		for _, t := range tables {
+			if t.name == name {
+				return view, true
+			}
+		}
+	}
+	return "", false
+}
+
+// sqlKeywords are the words that can follow a FROM item, so they are not
+// mistaken for its alias.
+var sqlKeywords = map[string]bool{
+	"WHERE": true, "GROUP": true, "ORDER": true, "LIMIT": true, "OFFSET": true,
+	"HAVING": true, "QUALIFY": true, "WINDOW": true, "UNION": true, "EXCEPT": true,
+	"INTERSECT": true, "JOIN": true, "INNER": true, "LEFT": true, "RIGHT": true,
+	"FULL": true, "CROSS": true, "NATURAL": true, "POSITIONAL": true, "ASOF": true,
+	"ANTI": true, "SEMI": true, "ON": true, "USING": true, "SAMPLE": true,
+	"TABLESAMPLE": true, "PIVOT": true, "UNPIVOT": true,
+}
+
+type sqlToken struct {
+	text  string
+	ident bool // a word or a quoted identifier
+}
+
+// sqlTokens splits a query into words, quoted identifiers and single
+// punctuation characters, dropping string literals, comments and spaces.
+func sqlTokens(query string) []sqlToken {
+	var toks []sqlToken
+	r := []rune(query)
+	for i := 0; i < len(r); {
+		c := r[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '-' && i+1 < len(r) && r[i+1] == '-':
+			for i < len(r) && r[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(r) && r[i+1] == '*':
+			i += 2
+			for i < len(r) && !(r[i] == '*' && i+1 < len(r) && r[i+1] == '/') {
+				i++
+			}
+			i += 2
+		case c == '\'' || c == '"':
+			j := i + 1
+			for j < len(r) {
+				if r[j] == c {
+					if j+1 < len(r) && r[j+1] == c { // doubled quote
+						j += 2
+						continue
+					}
+					break
+				}
+				j++
+			}
+			if c == '"' {
+				toks = append(toks, sqlToken{text: strings.ReplaceAll(string(r[i+1:min(j, len(r))]), `""`, `"`), ident: true})
+			}
+			i = j + 1
+		case c == '_' || unicode.IsLetter(c):
+			j := i
+			for j < len(r) && (r[j] == '_' || unicode.IsLetter(r[j]) || unicode.IsDigit(r[j])) {
+				j++
+			}
+			toks = append(toks, sqlToken{text: string(r[i:j]), ident: true})
+			i = j
+		default:
+			toks = append(toks, sqlToken{text: string(c)})
+			i++
+		}
+	}
+	return toks
+}
+
+// EnsureReferencedViews registers the SDK views query reads (see
+// ReferencedViews), so raw SQL works without calling EnsureViews first.
+// Tables of the same name created directly in DuckDB are left alone.
+func (c *Connection) EnsureReferencedViews(ctx context.Context, query string) error {
+	for _, name := range ReferencedViews(query) {
+		if c.HasView(name) {
+			continue
+		}
+		kind, err := c.objectKind(ctx, name)
+		if err != nil {
+			return err
+		}
+		if kind == "TABLE" {
+			continue
+		}
+		if err := c.ensureView(ctx, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
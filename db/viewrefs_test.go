@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestReferencedViews(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"SELECT name FROM cards WHERE manaValue = $1", []string{"cards"}},
+		{"SELECT * FROM cards c JOIN sets s ON c.setCode = s.code", []string{"cards", "sets"}},
+		{"select * from Cards AS c, card_legalities l, sets where c.uuid = l.uuid", []string{"cards", "card_legalities", "sets"}},
+		{`SELECT * FROM "tokens" LEFT JOIN token_identifiers USING (uuid)`, []string{"tokens", "token_identifiers"}},
+		{"WITH x AS (SELECT uuid FROM all_prices_today) SELECT * FROM x JOIN cards USING (uuid)", []string{"all_prices_today", "cards"}},
+		{"SELECT 'FROM sets' AS s -- JOIN tokens\n/* FROM card_rulings */ FROM my_table", nil},
+		{"SELECT * FROM main.cards, read_parquet('x.parquet')", nil},
+		{"SELECT * FROM cards UNION ALL SELECT * FROM cards", []string{"cards"}},
+		{"SELECT * FROM latest_prices JOIN deck_cards USING (uuid) JOIN all_prices_today USING (uuid)", []string{"all_prices_today", "set_decks"}},
+	}
+	for _, tt := range tests {
+		if got := ReferencedViews(tt.query); !slices.Equal(got, tt.want) {
+			t.Errorf("ReferencedViews(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestEnsureReferencedViews(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.Offline = true
+	cache, err := NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := NewConnection(cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	ctx := context.Background()
+	if _, err := conn.Raw().ExecContext(ctx, "CREATE TABLE sets AS SELECT 'A25' AS code"); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.EnsureReferencedViews(ctx, "SELECT * FROM sets"); err != nil {
+		t.Fatalf("expected a user table to satisfy the reference, got %v", err)
+	}
+	if err := conn.EnsureReferencedViews(ctx, "SELECT * FROM cards"); err == nil {
+		t.Fatal("expected offline mode to fail loading an uncached view")
+	}
+}
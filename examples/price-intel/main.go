@@ -22,8 +22,10 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	mtgjson "github.com/mtgjson/mtgjson-sdk-go"
+	"github.com/mtgjson/mtgjson-sdk-go/db"
 	"github.com/mtgjson/mtgjson-sdk-go/models"
 	"github.com/mtgjson/mtgjson-sdk-go/queries"
 )
@@ -39,11 +41,12 @@ func main() {
 
 	ctx := context.Background()
 
-	sdk, err := mtgjson.New(mtgjson.WithProgress(func(name string, current, total int64) {
-		if total > 0 {
-			pct := float64(current) / float64(total) * 100
-			fmt.Fprintf(os.Stderr, "\r  downloading %s... %.0f%%", name, pct)
-			if current == total {
+	sdk, err := mtgjson.New(mtgjson.WithProgressEvents(func(p db.Progress) {
+		if p.Total > 0 {
+			pct := float64(p.Downloaded) / float64(p.Total) * 100
+			fmt.Fprintf(os.Stderr, "\r  downloading %s... %.0f%% (%.1f MB/s, %s left)",
+				p.Filename, pct, p.Rate/1e6, p.ETA.Round(time.Second))
+			if p.Done {
 				fmt.Fprintln(os.Stderr)
 			}
 		}
@@ -2,7 +2,10 @@ module github.com/mtgjson/mtgjson-sdk-go
 
 go 1.25.0
 
-require github.com/marcboeker/go-duckdb v1.8.5
+require (
+	github.com/marcboeker/go-duckdb v1.8.5
+	golang.org/x/sys v0.41.0
+)
 
 require (
 	github.com/apache/arrow-go/v18 v18.5.2 // indirect
@@ -17,7 +20,6 @@ require (
 	golang.org/x/exp v0.0.0-20260218203240-3dfff04db8fa // indirect
 	golang.org/x/mod v0.33.0 // indirect
 	golang.org/x/sync v0.19.0 // indirect
-	golang.org/x/sys v0.41.0 // indirect
 	golang.org/x/telemetry v0.0.0-20260306145045-e526e8a188f5 // indirect
 	golang.org/x/tools v0.42.0 // indirect
 	golang.org/x/xerrors v0.0.0-20240903120638-7835f813f4da // indirect
@@ -0,0 +1,200 @@
+// Package goldfish simulates opening hands and the first turns of a deck
+// played against no opponent ("goldfishing") to estimate how reliably it hits
+// land drops and casts its spells.
+package goldfish
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"time"
+
+	"github.com/mtgjson/mtgjson-sdk-go/analysis"
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+const (
+	openingHandSize   = 7
+	defaultTurns      = 6
+	defaultIterations = 10000
+)
+
+type config struct {
+	rng        *rand.Rand
+	turns      int
+	iterations int
+	onThePlay  bool
+}
+
+// Option configures a simulation.
+type Option func(*config)
+
+// WithRand sets the random source used to shuffle the deck. Pass a seeded
+// source for reproducible results; defaults to one seeded from the clock.
+func WithRand(r *rand.Rand) Option {
+	return func(c *config) { c.rng = r }
+}
+
+// WithTurns sets the number of turns simulated per game (default 6).
+func WithTurns(n int) Option {
+	return func(c *config) { c.turns = n }
+}
+
+// WithIterations sets the number of games simulated (default 10000).
+func WithIterations(n int) Option {
+	return func(c *config) { c.iterations = n }
+}
+
+// WithOnTheDraw simulates games on the draw instead of on the play.
+func WithOnTheDraw() Option {
+	return func(c *config) { c.onThePlay = false }
+}
+
+// card is the per-card data the simulation needs, precomputed once.
+type card struct {
+	land      bool
+	manaValue int
+	pips      map[string]int
+	produced  []string
+}
+
+// Simulate goldfishes the deck and returns per-turn distribution statistics.
+// Each turn the player draws (except on turn 1 on the play), plays a land if
+// one is in hand, then casts spells from hand, largest first, while mana
+// allows. Colored costs are checked against the colors produced by lands in
+// play without tracking which land pays for which pip. Mulligans are not
+// simulated.
+func Simulate(cards []models.CardDeck, opts ...Option) (*models.GoldfishStats, error) {
+	cfg := &config{turns: defaultTurns, iterations: defaultIterations, onThePlay: true}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.turns < 1 || cfg.iterations < 1 {
+		return nil, fmt.Errorf("mtgjson: goldfish turns and iterations must be positive")
+	}
+	if cfg.rng == nil {
+		cfg.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+
+	var deck []card
+	for _, c := range cards {
+		cc := card{
+			land:      slices.Contains(c.Types, "Land"),
+			manaValue: int(c.ManaValue),
+			produced:  c.ProducedMana,
+		}
+		if c.ManaCost != nil {
+			cc.pips = analysis.ParsePips(*c.ManaCost)
+		}
+		for n := 0; n < c.Count; n++ {
+			deck = append(deck, cc)
+		}
+	}
+	if len(deck) < openingHandSize {
+		return nil, fmt.Errorf("mtgjson: goldfish needs at least %d cards, got %d", openingHandSize, len(deck))
+	}
+
+	stats := &models.GoldfishStats{
+		Iterations:       cfg.iterations,
+		OnThePlay:        cfg.onThePlay,
+		OpeningHandLands: make([]float64, openingHandSize+1),
+		Turns:            make([]models.GoldfishTurn, cfg.turns),
+	}
+	for i := range stats.Turns {
+		stats.Turns[i].Turn = i + 1
+	}
+
+	library := make([]card, len(deck))
+	for it := 0; it < cfg.iterations; it++ {
+		copy(library, deck)
+		cfg.rng.Shuffle(len(library), func(i, j int) { library[i], library[j] = library[j], library[i] })
+		hand := slices.Clone(library[:openingHandSize])
+		next := openingHandSize
+
+		lands := 0
+		for _, c := range hand {
+			if c.land {
+				lands++
+			}
+		}
+		stats.OpeningHandLands[lands]++
+
+		var inPlay []card
+		for t := range stats.Turns {
+			if (t > 0 || !cfg.onThePlay) && next < len(library) {
+				hand = append(hand, library[next])
+				next++
+			}
+			if i := slices.IndexFunc(hand, func(c card) bool { return c.land }); i >= 0 {
+				inPlay = append(inPlay, hand[i])
+				hand = slices.Delete(hand, i, i+1)
+			}
+
+			castable := 0
+			for _, c := range hand {
+				if !c.land && canCast(c, len(inPlay), inPlay) {
+					castable++
+				}
+			}
+			slices.SortStableFunc(hand, func(a, b card) int { return b.manaValue - a.manaValue })
+			mana, spent, cast := len(inPlay), 0, 0
+			kept := hand[:0]
+			for _, c := range hand {
+				if !c.land && canCast(c, mana, inPlay) {
+					mana -= c.manaValue
+					spent += c.manaValue
+					cast++
+					continue
+				}
+				kept = append(kept, c)
+			}
+			hand = kept
+
+			ts := &stats.Turns[t]
+			ts.MeanLandsInPlay += float64(len(inPlay))
+			ts.MeanCastable += float64(castable)
+			ts.MeanManaSpent += float64(spent)
+			if len(inPlay) >= t+1 {
+				ts.HitLandDrop++
+			}
+			if cast > 0 {
+				ts.CastAny++
+			}
+		}
+	}
+
+	n := float64(cfg.iterations)
+	for i := range stats.OpeningHandLands {
+		stats.OpeningHandLands[i] /= n
+		stats.MeanOpeningLands += float64(i) * stats.OpeningHandLands[i]
+	}
+	for i := range stats.Turns {
+		ts := &stats.Turns[i]
+		ts.MeanLandsInPlay /= n
+		ts.MeanCastable /= n
+		ts.MeanManaSpent /= n
+		ts.HitLandDrop /= n
+		ts.CastAny /= n
+	}
+	return stats, nil
+}
+
+// canCast reports whether c fits in the available mana and every colored pip
+// can be produced by the lands in play.
+func canCast(c card, mana int, lands []card) bool {
+	if c.manaValue > mana {
+		return false
+	}
+	for color, n := range c.pips {
+		sources := 0
+		for _, l := range lands {
+			if slices.Contains(l.produced, color) {
+				sources++
+			}
+		}
+		if sources < n {
+			return false
+		}
+	}
+	return true
+}
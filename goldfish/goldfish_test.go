@@ -0,0 +1,91 @@
+package goldfish
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+func strPtr(s string) *string { return &s }
+
+func sampleDeck() []models.CardDeck {
+	return []models.CardDeck{
+		{CardSet: models.CardSet{Name: "Mountain", Types: []string{"Land"}, ProducedMana: []string{"R"}}, Count: 24},
+		{CardSet: models.CardSet{Name: "Lightning Bolt", Types: []string{"Instant"}, ManaCost: strPtr("{R}"), ManaValue: 1}, Count: 20},
+		{CardSet: models.CardSet{Name: "Counterspell", Types: []string{"Instant"}, ManaCost: strPtr("{U}{U}"), ManaValue: 2}, Count: 16},
+	}
+}
+
+func TestSimulateDeterministicWithSeed(t *testing.T) {
+	a, err := Simulate(sampleDeck(), WithRand(rand.New(rand.NewSource(42))), WithIterations(500))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Simulate(sampleDeck(), WithRand(rand.New(rand.NewSource(42))), WithIterations(500))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range a.Turns {
+		if a.Turns[i] != b.Turns[i] {
+			t.Fatalf("turn %d differs with the same seed: %+v vs %+v", i+1, a.Turns[i], b.Turns[i])
+		}
+	}
+}
+
+func TestSimulateStatistics(t *testing.T) {
+	stats, err := Simulate(sampleDeck(), WithRand(rand.New(rand.NewSource(1))), WithIterations(5000), WithTurns(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats.Turns) != 4 || !stats.OnThePlay {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	total := 0.0
+	for _, p := range stats.OpeningHandLands {
+		total += p
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Fatalf("opening hand distribution sums to %f", total)
+	}
+	// 24 lands in 60 cards: expected 2.8 lands in 7 cards.
+	if math.Abs(stats.MeanOpeningLands-2.8) > 0.1 {
+		t.Fatalf("unexpected mean opening lands: %f", stats.MeanOpeningLands)
+	}
+	for _, ts := range stats.Turns {
+		if ts.MeanLandsInPlay > float64(ts.Turn) || ts.HitLandDrop < 0 || ts.HitLandDrop > 1 {
+			t.Fatalf("unexpected turn stats: %+v", ts)
+		}
+		// Only red mana is available, so mana spent is bounded by Bolts cast.
+		if ts.MeanManaSpent > ts.MeanLandsInPlay {
+			t.Fatalf("spent more mana than available: %+v", ts)
+		}
+	}
+}
+
+func TestSimulateColorRequirements(t *testing.T) {
+	deck := []models.CardDeck{
+		{CardSet: models.CardSet{Name: "Mountain", Types: []string{"Land"}, ProducedMana: []string{"R"}}, Count: 30},
+		{CardSet: models.CardSet{Name: "Counterspell", Types: []string{"Instant"}, ManaCost: strPtr("{U}{U}"), ManaValue: 2}, Count: 30},
+	}
+	stats, err := Simulate(deck, WithRand(rand.New(rand.NewSource(7))), WithIterations(200), WithOnTheDraw())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ts := range stats.Turns {
+		if ts.CastAny != 0 || ts.MeanCastable != 0 {
+			t.Fatalf("blue spells should never be castable off red lands: %+v", ts)
+		}
+	}
+}
+
+func TestSimulateErrors(t *testing.T) {
+	small := []models.CardDeck{{CardSet: models.CardSet{Name: "Mountain", Types: []string{"Land"}}, Count: 5}}
+	if _, err := Simulate(small); err == nil {
+		t.Fatal("expected error for deck smaller than an opening hand")
+	}
+	if _, err := Simulate(sampleDeck(), WithTurns(0)); err == nil {
+		t.Fatal("expected error for zero turns")
+	}
+}
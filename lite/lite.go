@@ -0,0 +1,190 @@
+// Package lite provides card and set lookups from AllPrintings.json without
+// DuckDB, for CGO_ENABLED=0 builds where the SDK cannot open a database. The
+// whole file is indexed in memory, so only exact lookups are offered; use the
+// SDK for search, prices and SQL.
+package lite
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mtgjson/mtgjson-sdk-go/db"
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+// Index holds every printing and set from AllPrintings.json. It is read-only
+// after loading and safe for concurrent use.
+type Index struct {
+	cards  map[string]*models.CardSet
+	byName map[string][]*models.CardSet
+	sets   map[string]*models.SetList
+	bySet  map[string][]models.CardSet
+}
+
+// Load indexes AllPrintings.json.gz from the cache, downloading it first if
+// it is missing or stale (unless the cache is offline).
+func Load(ctx context.Context, cache *db.CacheManager) (*Index, error) {
+	path, err := cache.EnsureJSON(ctx, "all_printings")
+	if err != nil {
+		return nil, err
+	}
+	return LoadFile(path)
+}
+
+// LoadFile indexes an AllPrintings file, gzip-compressed if the name ends
+// in .gz.
+func LoadFile(path string) (*Index, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("mtgjson: read %s: %w", path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+	x, err := decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("mtgjson: read %s: %w", path, err)
+	}
+	return x, nil
+}
+
+// decode streams the "data" object one set at a time, so the raw file is
+// never held in memory.
+func decode(r io.Reader) (*Index, error) {
+	x := &Index{
+		cards:  make(map[string]*models.CardSet),
+		byName: make(map[string][]*models.CardSet),
+		sets:   make(map[string]*models.SetList),
+		bySet:  make(map[string][]models.CardSet),
+	}
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if key != "data" {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := expectDelim(dec, '{'); err != nil {
+			return nil, err
+		}
+		for dec.More() {
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			var set struct {
+				models.SetList
+				Cards []models.CardSet `json:"cards"`
+			}
+			if err := dec.Decode(&set); err != nil {
+				return nil, err
+			}
+			x.add(set.SetList, set.Cards)
+		}
+		if err := expectDelim(dec, '}'); err != nil {
+			return nil, err
+		}
+	}
+	for _, printings := range x.byName {
+		sort.Slice(printings, func(i, j int) bool {
+			if printings[i].SetCode != printings[j].SetCode {
+				return printings[i].SetCode > printings[j].SetCode
+			}
+			return printings[i].Number < printings[j].Number
+		})
+	}
+	return x, nil
+}
+
+func (x *Index) add(set models.SetList, cards []models.CardSet) {
+	code := strings.ToUpper(set.Code)
+	set.Decks, set.SealedProduct = nil, nil
+	x.sets[code] = &set
+	x.bySet[code] = cards
+	for i := range cards {
+		c := &cards[i]
+		x.cards[c.UUID] = c
+		x.byName[c.Name] = append(x.byName[c.Name], c)
+	}
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != want {
+		return fmt.Errorf("expected %q, got %v", want, tok)
+	}
+	return nil
+}
+
+// GetByUUID returns a printing by its MTGJSON UUID, or nil if not found.
+func (x *Index) GetByUUID(uuid string) *models.CardSet {
+	c, ok := x.cards[uuid]
+	if !ok {
+		return nil
+	}
+	card := *c
+	return &card
+}
+
+// GetByName returns all printings of a card by exact name, newest set code
+// first like Cards().GetByName, optionally restricted to one set.
+func (x *Index) GetByName(name string, setCode ...string) []models.CardSet {
+	var cards []models.CardSet
+	for _, c := range x.byName[name] {
+		if len(setCode) > 0 && setCode[0] != "" && !strings.EqualFold(c.SetCode, setCode[0]) {
+			continue
+		}
+		cards = append(cards, *c)
+	}
+	return cards
+}
+
+// Set returns a set by code (case-insensitive), or nil if not found. Decks
+// and sealed products are not kept.
+func (x *Index) Set(code string) *models.SetList {
+	s, ok := x.sets[strings.ToUpper(code)]
+	if !ok {
+		return nil
+	}
+	set := *s
+	return &set
+}
+
+// SetCards returns the printings in a set in file order, or nil if the set
+// is not found.
+func (x *Index) SetCards(code string) []models.CardSet {
+	cards := x.bySet[strings.ToUpper(code)]
+	if cards == nil {
+		return nil
+	}
+	return append([]models.CardSet(nil), cards...)
+}
+
+// Count returns the number of printings indexed.
+func (x *Index) Count() int {
+	return len(x.cards)
+}
@@ -0,0 +1,125 @@
+package lite
+
+import (
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mtgjson/mtgjson-sdk-go/db"
+)
+
+const sampleAllPrintings = `{
+  "meta": {"date": "2024-01-01", "version": "5.2.2+20240101"},
+  "data": {
+    "A25": {
+      "code": "A25", "name": "Masters 25", "type": "masters", "releaseDate": "2018-03-16",
+      "totalSetSize": 249, "keyruneCode": "A25",
+      "cards": [
+        {"uuid": "bolt-a25", "name": "Lightning Bolt", "setCode": "A25", "number": "141",
+         "manaCost": "{R}", "text": "Lightning Bolt deals 3 damage to any target.",
+         "identifiers": {"scryfallId": "s-1"}, "legalities": {"modern": "Legal"}}
+      ],
+      "tokens": [{"uuid": "token-1", "name": "Goblin"}]
+    },
+    "M10": {
+      "code": "M10", "name": "Magic 2010", "type": "core", "releaseDate": "2009-07-17",
+      "cards": [
+        {"uuid": "bolt-m10", "name": "Lightning Bolt", "setCode": "M10", "number": "146"},
+        {"uuid": "gg-m10", "name": "Giant Growth", "setCode": "M10", "number": "183"}
+      ]
+    }
+  }
+}`
+
+func writeAllPrintings(t *testing.T, dir string) string {
+	t.Helper()
+	path := filepath.Join(dir, "AllPrintings.json.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	if _, err := gz.Write([]byte(sampleAllPrintings)); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFile(t *testing.T) {
+	x, err := LoadFile(writeAllPrintings(t, t.TempDir()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x.Count() != 3 {
+		t.Fatalf("expected 3 printings, got %d", x.Count())
+	}
+
+	bolt := x.GetByUUID("bolt-a25")
+	if bolt == nil || bolt.GetText() != "Lightning Bolt deals 3 damage to any target." {
+		t.Fatalf("unexpected card %+v", bolt)
+	}
+	if bolt.IdentifiersData.ScryfallId == nil || *bolt.IdentifiersData.ScryfallId != "s-1" {
+		t.Errorf("expected nested identifiers to decode, got %+v", bolt.IdentifiersData)
+	}
+	if x.GetByUUID("token-1") != nil {
+		t.Error("expected tokens not to be indexed as cards")
+	}
+
+	printings := x.GetByName("Lightning Bolt")
+	if len(printings) != 2 || printings[0].SetCode != "M10" || printings[1].SetCode != "A25" {
+		t.Errorf("expected M10 then A25 printings, got %+v", printings)
+	}
+	if got := x.GetByName("Lightning Bolt", "a25"); len(got) != 1 || got[0].UUID != "bolt-a25" {
+		t.Errorf("expected the A25 printing only, got %+v", got)
+	}
+
+	set := x.Set("m10")
+	if set == nil || set.Name != "Magic 2010" {
+		t.Fatalf("unexpected set %+v", set)
+	}
+	if cards := x.SetCards("M10"); len(cards) != 2 || cards[1].Name != "Giant Growth" {
+		t.Errorf("unexpected set cards %+v", cards)
+	}
+	if x.Set("NOPE") != nil || x.SetCards("NOPE") != nil {
+		t.Error("expected nil for an unknown set")
+	}
+}
+
+func TestLoadFromCache(t *testing.T) {
+	cfg := db.DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.Offline = true
+	cache, err := db.NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(context.Background(), cache); err == nil {
+		t.Fatal("expected an error when AllPrintings is not cached offline")
+	}
+	writeAllPrintings(t, cfg.CacheDir)
+	x, err := Load(context.Background(), cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x.Count() != 3 {
+		t.Errorf("expected 3 printings, got %d", x.Count())
+	}
+}
+
+func TestLoadFileMalformed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "AllPrintings.json")
+	if err := os.WriteFile(path, []byte(`{"data": [1, 2]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected an error for a data array")
+	}
+}
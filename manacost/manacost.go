@@ -0,0 +1,303 @@
+// Package manacost parses mana costs such as "{2}{U/R}{X}" into typed
+// symbols, and matches cards by the symbols in their cost, either in Go or
+// through a regular expression the SDK's card search can run in SQL.
+package manacost
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Kind is the kind of a mana symbol.
+type Kind int
+
+const (
+	Generic         Kind = iota // {0}, {2}, {15}: that much mana of any type
+	Colored                     // {W}, {U}, {B}, {R}, {G}
+	Colorless                   // {C}: colorless mana specifically
+	Variable                    // {X}, {Y}, {Z}
+	Snow                        // {S}: mana from a snow source
+	Hybrid                      // {W/U}, {C/W}: either half
+	MonoHybrid                  // {2/W}: two generic or one colored
+	Phyrexian                   // {W/P}: one colored or 2 life
+	PhyrexianHybrid             // {W/U/P}: either color or 2 life
+	Half                        // {HW}, {½}: half a mana (Un-sets)
+	Infinite                    // {∞} (Un-sets)
+)
+
+var kindNames = [...]string{
+	"generic", "colored", "colorless", "variable", "snow", "hybrid",
+	"mono-hybrid", "phyrexian", "phyrexian hybrid", "half", "infinite",
+}
+
+// String returns the kind's name, e.g. "phyrexian hybrid".
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return "Kind(" + strconv.Itoa(int(k)) + ")"
+	}
+	return kindNames[k]
+}
+
+// colors are the five colors in WUBRG order.
+var colors = []string{"W", "U", "B", "R", "G"}
+
+// Symbol is one {…} symbol of a mana cost.
+type Symbol struct {
+	Text    string   // as written, braces included, e.g. "{W/U}"
+	Kind    Kind     // what can pay for it
+	Colors  []string // colors it can be paid with, in WUBRG order; empty if none
+	Generic int      // the number of a Generic symbol, or 2 for MonoHybrid
+}
+
+// ManaValue is the symbol's contribution to a card's mana value: 0 for X,
+// half for {HW} and the largest way to pay it otherwise (rule 202.3).
+func (s Symbol) ManaValue() float64 {
+	switch s.Kind {
+	case Generic, MonoHybrid:
+		return float64(s.Generic)
+	case Variable:
+		return 0
+	case Half:
+		return 0.5
+	case Infinite:
+		return math.Inf(1)
+	}
+	return 1
+}
+
+// Cost is a parsed mana cost.
+type Cost []Symbol
+
+// Parse splits a mana cost into its symbols. Whitespace and the " // "
+// between the faces of a split card are skipped; anything else outside
+// braces, or an unknown symbol, is an error. An empty cost parses to nil.
+func Parse(cost string) (Cost, error) {
+	var out Cost
+	rest := cost
+	for {
+		rest = strings.TrimLeft(rest, " /")
+		if rest == "" {
+			return out, nil
+		}
+		if rest[0] != '{' {
+			return nil, fmt.Errorf("mtgjson: unexpected %q in mana cost %q", rest, cost)
+		}
+		end := strings.IndexByte(rest, '}')
+		if end < 0 {
+			return nil, fmt.Errorf("mtgjson: unclosed symbol in mana cost %q", cost)
+		}
+		sym, ok := parseSymbol(rest[1:end])
+		if !ok {
+			return nil, fmt.Errorf("mtgjson: unknown symbol %s in mana cost %q", rest[:end+1], cost)
+		}
+		out = append(out, sym)
+		rest = rest[end+1:]
+	}
+}
+
+// MustParse is like Parse but panics on a malformed cost. It is meant for
+// costs written in code.
+func MustParse(cost string) Cost {
+	c, err := Parse(cost)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func parseSymbol(inner string) (Symbol, bool) {
+	s := Symbol{Text: "{" + inner + "}"}
+	if n, err := strconv.Atoi(inner); err == nil && n >= 0 {
+		s.Kind, s.Generic = Generic, n
+		return s, true
+	}
+	switch {
+	case isColor(inner):
+		s.Kind, s.Colors = Colored, []string{inner}
+	case inner == "C":
+		s.Kind = Colorless
+	case inner == "X" || inner == "Y" || inner == "Z":
+		s.Kind = Variable
+	case inner == "S":
+		s.Kind = Snow
+	case inner == "½":
+		s.Kind = Half
+	case inner == "∞":
+		s.Kind = Infinite
+	case len(inner) == 2 && inner[0] == 'H' && isColor(inner[1:]):
+		s.Kind, s.Colors = Half, []string{inner[1:]}
+	default:
+		return parseSplit(s, strings.Split(inner, "/"))
+	}
+	return s, true
+}
+
+// parseSplit parses the hybrid and Phyrexian symbols, whose halves are
+// separated by slashes.
+func parseSplit(s Symbol, parts []string) (Symbol, bool) {
+	phyrexian := len(parts) > 1 && parts[len(parts)-1] == "P"
+	if phyrexian {
+		parts = parts[:len(parts)-1]
+	}
+	switch {
+	case len(parts) == 1 && phyrexian && isColor(parts[0]):
+		s.Kind, s.Colors = Phyrexian, parts
+	case len(parts) == 2 && parts[0] == "2" && isColor(parts[1]) && !phyrexian:
+		s.Kind, s.Generic, s.Colors = MonoHybrid, 2, parts[1:]
+	case len(parts) == 2 && parts[0] == "C" && isColor(parts[1]) && !phyrexian:
+		s.Kind, s.Colors = Hybrid, parts[1:]
+	case len(parts) == 2 && isColor(parts[0]) && isColor(parts[1]) && parts[0] != parts[1]:
+		s.Kind, s.Colors = Hybrid, sortColors(parts)
+		if phyrexian {
+			s.Kind = PhyrexianHybrid
+		}
+	default:
+		return s, false
+	}
+	return s, true
+}
+
+func isColor(s string) bool {
+	return slices.Contains(colors, s)
+}
+
+func sortColors(cs []string) []string {
+	out := slices.Clone(cs)
+	slices.SortFunc(out, func(a, b string) int {
+		return slices.Index(colors, a) - slices.Index(colors, b)
+	})
+	return out
+}
+
+// String joins the symbols back into a cost.
+func (c Cost) String() string {
+	var b strings.Builder
+	for _, s := range c {
+		b.WriteString(s.Text)
+	}
+	return b.String()
+}
+
+// ManaValue sums the symbols' mana values.
+func (c Cost) ManaValue() float64 {
+	var mv float64
+	for _, s := range c {
+		mv += s.ManaValue()
+	}
+	return mv
+}
+
+// Colors returns the colors of the cost's symbols in WUBRG order, which is
+// the color of a card with this cost (rule 202.2).
+func (c Cost) Colors() []string {
+	seen := map[string]bool{}
+	for _, s := range c {
+		for _, color := range s.Colors {
+			seen[color] = true
+		}
+	}
+	var out []string
+	for _, color := range colors {
+		if seen[color] {
+			out = append(out, color)
+		}
+	}
+	return out
+}
+
+// Devotion counts the symbols that are any of the given colors, so a
+// hybrid {W/U} adds one to devotion to white, to blue, and to white and
+// blue alike (rule 700.5).
+func (c Cost) Devotion(colors ...string) int {
+	n := 0
+	for _, s := range c {
+		if slices.ContainsFunc(s.Colors, func(color string) bool { return slices.Contains(colors, color) }) {
+			n++
+		}
+	}
+	return n
+}
+
+// Count returns the number of symbols p matches.
+func (c Cost) Count(p Predicate) int {
+	n := 0
+	for _, s := range c {
+		if p.Match(s) {
+			n++
+		}
+	}
+	return n
+}
+
+// Has reports whether p matches any of the symbols.
+func (c Cost) Has(p Predicate) bool {
+	return slices.ContainsFunc(c, p.Match)
+}
+
+// Predicate matches mana symbols. Its Pattern matches the same symbols
+// inside a mana cost string, so a predicate can filter cards in SQL as
+// well as parsed costs in Go (see SearchCardsParams.ManaSymbols).
+type Predicate struct {
+	match   func(Symbol) bool
+	pattern string
+}
+
+// Match reports whether s is one of the predicate's symbols.
+func (p Predicate) Match(s Symbol) bool {
+	return p.match != nil && p.match(s)
+}
+
+// Pattern is a regular expression (RE2, as DuckDB's regexp_matches takes)
+// matching a mana cost that contains one of the predicate's symbols.
+func (p Predicate) Pattern() string {
+	return p.pattern
+}
+
+// Symbol predicates.
+var (
+	// IsPhyrexian matches Phyrexian symbols, hybrid ones included.
+	IsPhyrexian = kinds(`\{[^}]*/P\}`, Phyrexian, PhyrexianHybrid)
+	// IsHybrid matches symbols payable with either of two kinds of mana.
+	IsHybrid = kinds(`\{[^}/]+/[^}/P]+(/P)?\}`, Hybrid, MonoHybrid, PhyrexianHybrid)
+	// IsVariable matches {X}, {Y} and {Z}.
+	IsVariable = kinds(`\{[XYZ]\}`, Variable)
+	// IsSnow matches {S}.
+	IsSnow = kinds(`\{S\}`, Snow)
+	// IsColorless matches {C}, which only colorless mana pays for.
+	IsColorless = kinds(`\{C\}`, Colorless)
+	// IsHalf matches the Un-set half mana symbols.
+	IsHalf = kinds(`\{(H[WUBRG]|½)\}`, Half)
+)
+
+func kinds(pattern string, ks ...Kind) Predicate {
+	return Predicate{
+		match:   func(s Symbol) bool { return slices.Contains(ks, s.Kind) },
+		pattern: pattern,
+	}
+}
+
+// OfColor matches symbols that can be paid with mana of color, one of W,
+// U, B, R or G: {R}, {R/G}, {2/R}, {R/P} and {HR} all match "R".
+func OfColor(color string) Predicate {
+	color = strings.ToUpper(color)
+	if !isColor(color) {
+		return Predicate{pattern: `$.^`}
+	}
+	return Predicate{
+		match:   func(s Symbol) bool { return slices.Contains(s.Colors, color) },
+		pattern: `\{([^}]*/)?H?` + color + `(/[^}]*)?\}`,
+	}
+}
+
+// Exactly matches one symbol as written, e.g. "{W/U}" or "W/U".
+func Exactly(symbol string) Predicate {
+	inner := strings.TrimSuffix(strings.TrimPrefix(symbol, "{"), "}")
+	return Predicate{
+		match:   func(s Symbol) bool { return s.Text == "{"+inner+"}" },
+		pattern: `\{` + regexp.QuoteMeta(inner) + `\}`,
+	}
+}
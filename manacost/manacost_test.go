@@ -0,0 +1,103 @@
+package manacost
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cost, err := Parse("{2}{U/R}{X}{W/P}{2/G}{B/G/P}{C}{S}{HW}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Kind{Generic, Hybrid, Variable, Phyrexian, MonoHybrid, PhyrexianHybrid, Colorless, Snow, Half}
+	var got []Kind
+	for _, s := range cost {
+		got = append(got, s.Kind)
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected kinds %v, got %v", want, got)
+	}
+	if !slices.Equal(cost[1].Colors, []string{"U", "R"}) || cost[0].Generic != 2 {
+		t.Errorf("unexpected symbols: %+v", cost[:2])
+	}
+	if cost.String() != "{2}{U/R}{X}{W/P}{2/G}{B/G/P}{C}{S}{HW}" {
+		t.Errorf("unexpected String: %s", cost)
+	}
+	if mv := cost.ManaValue(); mv != 9.5 {
+		t.Errorf("expected mana value 9.5, got %v", mv)
+	}
+	if colors := cost.Colors(); !slices.Equal(colors, []string{"W", "U", "B", "R", "G"}) {
+		t.Errorf("unexpected colors: %v", colors)
+	}
+
+	if cost, err := Parse("{1}{R} // {1}{U}"); err != nil || len(cost) != 4 {
+		t.Errorf("expected a split card's four symbols, got %v (%v)", cost, err)
+	}
+	if cost, err := Parse(""); err != nil || cost != nil {
+		t.Errorf("expected nil for an empty cost, got %v (%v)", cost, err)
+	}
+	for _, bad := range []string{"{R", "R", "{Q}", "{W/W}", "{2/W/P}"} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q): expected an error", bad)
+		}
+	}
+}
+
+func TestDevotion(t *testing.T) {
+	cost := MustParse("{1}{W}{W}{W/U}{U/P}")
+	for _, tc := range []struct {
+		colors []string
+		want   int
+	}{
+		{[]string{"W"}, 3},
+		{[]string{"U"}, 2},
+		{[]string{"W", "U"}, 4},
+		{[]string{"B"}, 0},
+	} {
+		if got := cost.Devotion(tc.colors...); got != tc.want {
+			t.Errorf("Devotion(%v) = %d, want %d", tc.colors, got, tc.want)
+		}
+	}
+}
+
+func TestPredicates(t *testing.T) {
+	costs := []string{"{R}", "{U}{U}", "{1}{R/P}", "{W/U/P}", "{2/W}", "{X}{G}", "{S}{C}", "{HR}", "{G/W}"}
+	for _, tc := range []struct {
+		name string
+		pred Predicate
+		want []string
+	}{
+		{"IsPhyrexian", IsPhyrexian, []string{"{1}{R/P}", "{W/U/P}"}},
+		{"IsHybrid", IsHybrid, []string{"{W/U/P}", "{2/W}", "{G/W}"}},
+		{"IsVariable", IsVariable, []string{"{X}{G}"}},
+		{"IsSnow", IsSnow, []string{"{S}{C}"}},
+		{"IsColorless", IsColorless, []string{"{S}{C}"}},
+		{"IsHalf", IsHalf, []string{"{HR}"}},
+		{"OfColor(R)", OfColor("r"), []string{"{R}", "{1}{R/P}", "{HR}"}},
+		{"OfColor(W)", OfColor("W"), []string{"{W/U/P}", "{2/W}", "{G/W}"}},
+		{"OfColor(Q)", OfColor("Q"), nil},
+		{"Exactly", Exactly("W/U/P"), []string{"{W/U/P}"}},
+	} {
+		re := regexp.MustCompile(tc.pred.Pattern())
+		var parsed, matched []string
+		for _, c := range costs {
+			if MustParse(c).Has(tc.pred) {
+				parsed = append(parsed, c)
+			}
+			if re.MatchString(c) {
+				matched = append(matched, c)
+			}
+		}
+		if !slices.Equal(parsed, tc.want) {
+			t.Errorf("%s: Has matched %v, want %v", tc.name, parsed, tc.want)
+		}
+		if !slices.Equal(matched, tc.want) {
+			t.Errorf("%s: Pattern matched %v, want %v", tc.name, matched, tc.want)
+		}
+	}
+	if n := MustParse("{W/P}{W/P}{1}").Count(IsPhyrexian); n != 2 {
+		t.Errorf("expected 2 Phyrexian symbols, got %d", n)
+	}
+}
@@ -0,0 +1,42 @@
+package models
+
+// ManabaseReport is the result of a manabase analysis for a deck.
+type ManabaseReport struct {
+	DeckSize     int                `json:"deck_size"`
+	LandCount    int                `json:"land_count"`
+	Requirements []ColorRequirement `json:"requirements"`
+	Valid        bool               `json:"valid"`
+}
+
+// ColorRequirement compares the sources a deck needs for one color (W, U, B,
+// R, G or C for colorless) with the sources its lands provide.
+type ColorRequirement struct {
+	Color       string `json:"color"`
+	Pips        int    `json:"pips"`
+	Required    int    `json:"required"`
+	Sources     int    `json:"sources"`
+	Shortfall   int    `json:"shortfall"`
+	DrivingCard string `json:"driving_card,omitempty"`
+	DrivingCost string `json:"driving_cost,omitempty"`
+}
+
+// GoldfishStats summarizes a goldfish simulation of a deck.
+// OpeningHandLands[n] is the fraction of opening hands with n lands.
+type GoldfishStats struct {
+	Iterations       int            `json:"iterations"`
+	OnThePlay        bool           `json:"on_the_play"`
+	OpeningHandLands []float64      `json:"opening_hand_lands"`
+	MeanOpeningLands float64        `json:"mean_opening_lands"`
+	Turns            []GoldfishTurn `json:"turns"`
+}
+
+// GoldfishTurn holds averages across simulated games for one turn.
+// HitLandDrop and CastAny are probabilities in [0, 1].
+type GoldfishTurn struct {
+	Turn            int     `json:"turn"`
+	MeanLandsInPlay float64 `json:"mean_lands_in_play"`
+	HitLandDrop     float64 `json:"hit_land_drop"`
+	MeanCastable    float64 `json:"mean_castable"`
+	MeanManaSpent   float64 `json:"mean_mana_spent"`
+	CastAny         float64 `json:"cast_any"`
+}
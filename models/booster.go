@@ -15,12 +15,68 @@ type BoosterPack struct {
 	Weight   int            `json:"weight"`
 }
 
-// BoosterSheet defines a sheet from which cards are drawn.
+// BoosterSheet defines a sheet from which cards are drawn. Jumpstart-style
+// sheets list half-deck names in Decks instead of cards.
 type BoosterSheet struct {
 	AllowDuplicates *bool          `json:"allowDuplicates,omitempty"`
 	BalanceColors   *bool          `json:"balanceColors,omitempty"`
 	Cards           map[string]int `json:"cards"`
+	Decks           map[string]int `json:"decks,omitempty"`
 	Foil            bool           `json:"foil"`
 	Fixed           *bool          `json:"fixed,omitempty"`
 	TotalWeight     int            `json:"totalWeight"`
 }
+
+// BoosterBox is a simulated booster box with its summary statistics.
+type BoosterBox struct {
+	Packs   [][]CardSet `json:"packs"`
+	Summary BoxSummary  `json:"summary"`
+}
+
+// PackBatch is a batch of simulated packs with their combined summary and
+// how many times each card (by UUID) was pulled, for pull-rate estimates.
+type PackBatch struct {
+	Packs   [][]CardSet    `json:"packs"`
+	Summary BoxSummary     `json:"summary"`
+	Pulls   map[string]int `json:"pulls"`
+}
+
+// BoxSummary describes the contents of one simulated booster box.
+// TotalValue prices foil pulls at foil prices; cards without a price count
+// as zero.
+type BoxSummary struct {
+	Packs       int     `json:"packs"`
+	Cards       int     `json:"cards"`
+	Rares       int     `json:"rares"`
+	Mythics     int     `json:"mythics"`
+	Foils       int     `json:"foils"`
+	UniqueCards int     `json:"unique_cards"`
+	Duplicates  int     `json:"duplicates"`
+	TotalValue  float64 `json:"total_value"`
+}
+
+// BoxDistribution aggregates many simulated boxes of the same product.
+type BoxDistribution struct {
+	Boxes           int          `json:"boxes"`
+	PacksPerBox     int          `json:"packs_per_box"`
+	MeanValue       float64      `json:"mean_value"`
+	MinValue        float64      `json:"min_value"`
+	MaxValue        float64      `json:"max_value"`
+	P10Value        float64      `json:"p10_value"`
+	P50Value        float64      `json:"p50_value"`
+	P90Value        float64      `json:"p90_value"`
+	MeanRares       float64      `json:"mean_rares"`
+	MeanMythics     float64      `json:"mean_mythics"`
+	MeanFoils       float64      `json:"mean_foils"`
+	MeanUniqueCards float64      `json:"mean_unique_cards"`
+	MeanDuplicates  float64      `json:"mean_duplicates"`
+	Summaries       []BoxSummary `json:"summaries"`
+}
+
+// JumpstartPack is an opened Jumpstart-style pack: one themed half-deck.
+type JumpstartPack struct {
+	SetCode  string    `json:"set_code"`
+	DeckCode string    `json:"deck_code"`
+	Theme    string    `json:"theme"`
+	Cards    []CardSet `json:"cards"`
+}
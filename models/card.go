@@ -1,5 +1,7 @@
 package models
 
+//go:generate go run ./internal/accessorgen -type CardSet -o card_accessors.go
+
 // CardSet is the primary card model for most queries.
 // It represents a card as it appears in a specific set printing.
 type CardSet struct {
@@ -210,6 +212,13 @@ type CardToken struct {
 	SourceProducts  *SourceProducts `json:"sourceProducts,omitempty"`
 }
 
+// IdentifierMatch is a card found by an external ID of unknown type, with the
+// identifier column that matched.
+type IdentifierMatch struct {
+	CardSet
+	IDColumn string `json:"id_column"`
+}
+
 // CardDeck is a card in a preconstructed deck with count and foil flags.
 type CardDeck struct {
 	CardSet
@@ -225,8 +234,85 @@ type CardSetDeck struct {
 	UUID   string `json:"uuid"`
 }
 
+// FirstPrinting is the earliest printing of a card, resolved from its
+// printings and the release dates of their sets.
+type FirstPrinting struct {
+	Name        string `json:"name"`
+	UUID        string `json:"uuid"`
+	SetCode     string `json:"setCode"`
+	SetName     string `json:"setName"`
+	ReleaseDate string `json:"releaseDate"`
+	Number      string `json:"number"`
+}
+
+// PreconAppearance is one board of a preconstructed deck that includes a
+// printing of a card, for reprint tracking. SetCode is the deck's set and
+// PrintingSetCode the set of the printing in it.
+type PreconAppearance struct {
+	DeckCode        string  `json:"deckCode"`
+	DeckName        string  `json:"deckName"`
+	DeckType        string  `json:"deckType"`
+	SetCode         string  `json:"setCode"`
+	ReleaseDate     *string `json:"releaseDate,omitempty"`
+	UUID            string  `json:"uuid"`
+	PrintingSetCode string  `json:"printingSetCode"`
+	Number          string  `json:"number"`
+	Board           string  `json:"board"` // mainBoard, sideBoard or commander
+	Count           int     `json:"count"`
+	IsFoil          bool    `json:"isFoil"`
+}
+
+// LegalityChange is a recorded change of a card's status in a format.
+// Status is "" when the card stopped being listed in the format.
+type LegalityChange struct {
+	UUID           string  `json:"uuid"`
+	Name           string  `json:"name"`
+	Format         string  `json:"format"`
+	Status         string  `json:"status"`
+	PreviousStatus *string `json:"previousStatus,omitempty"`
+	Date           string  `json:"date"`
+}
+
 // CardLegality is a lightweight result for banned/restricted queries.
 type CardLegality struct {
 	Name string `json:"name"`
 	UUID string `json:"uuid"`
 }
+
+// CardSynergy is a card scored by how much it shares with another card.
+type CardSynergy struct {
+	UUID               string   `json:"uuid"`
+	Name               string   `json:"name"`
+	Score              float64  `json:"score"`
+	SharedKeywords     []string `json:"shared_keywords"`
+	SharedSubtypes     []string `json:"shared_subtypes"`
+	SharedProducedMana []string `json:"shared_produced_mana"`
+	SharedTextTokens   []string `json:"shared_text_tokens"`
+}
+
+// SubtypeCensus summarizes the cards of one subtype (e.g. all Elves).
+// ByColor counts distinct card names per color ("C" for colorless); ByRarity
+// and BySet count printings.
+type SubtypeCensus struct {
+	Subtype     string         `json:"subtype"`
+	Format      string         `json:"format,omitempty"`
+	UniqueCards int            `json:"unique_cards"`
+	Printings   int            `json:"printings"`
+	ByColor     map[string]int `json:"by_color"`
+	ByRarity    map[string]int `json:"by_rarity"`
+	BySet       map[string]int `json:"by_set"`
+	Cards       []CardSet      `json:"cards"`
+}
+
+// TokenGenerator is a card that creates a given token. MatchedBy lists how it
+// was found: "reverse_related" (token's relatedCards data) and/or "text"
+// (rules-text pattern).
+type TokenGenerator struct {
+	UUID      string   `json:"uuid"`
+	Name      string   `json:"name"`
+	SetCode   string   `json:"setCode"`
+	Number    string   `json:"number"`
+	Type      string   `json:"type"`
+	Text      *string  `json:"text,omitempty"`
+	MatchedBy []string `json:"matched_by"`
+}
@@ -0,0 +1,729 @@
+// Code generated by accessorgen -type CardSet; DO NOT EDIT.
+
+package models
+
+import (
+	"reflect"
+	"slices"
+)
+
+// GetASCIIName returns ASCIIName, or its zero value if unset.
+func (c *CardSet) GetASCIIName() string {
+	if c.ASCIIName == nil {
+		var zero string
+		return zero
+	}
+	return *c.ASCIIName
+}
+
+// GetFaceName returns FaceName, or its zero value if unset.
+func (c *CardSet) GetFaceName() string {
+	if c.FaceName == nil {
+		var zero string
+		return zero
+	}
+	return *c.FaceName
+}
+
+// GetManaCost returns ManaCost, or its zero value if unset.
+func (c *CardSet) GetManaCost() string {
+	if c.ManaCost == nil {
+		var zero string
+		return zero
+	}
+	return *c.ManaCost
+}
+
+// GetConvertedManaCost returns ConvertedManaCost, or its zero value if unset.
+func (c *CardSet) GetConvertedManaCost() float64 {
+	if c.ConvertedManaCost == nil {
+		var zero float64
+		return zero
+	}
+	return *c.ConvertedManaCost
+}
+
+// GetFaceManaValue returns FaceManaValue, or its zero value if unset.
+func (c *CardSet) GetFaceManaValue() float64 {
+	if c.FaceManaValue == nil {
+		var zero float64
+		return zero
+	}
+	return *c.FaceManaValue
+}
+
+// GetText returns Text, or its zero value if unset.
+func (c *CardSet) GetText() string {
+	if c.Text == nil {
+		var zero string
+		return zero
+	}
+	return *c.Text
+}
+
+// GetSide returns Side, or its zero value if unset.
+func (c *CardSet) GetSide() string {
+	if c.Side == nil {
+		var zero string
+		return zero
+	}
+	return *c.Side
+}
+
+// GetPower returns Power, or its zero value if unset.
+func (c *CardSet) GetPower() string {
+	if c.Power == nil {
+		var zero string
+		return zero
+	}
+	return *c.Power
+}
+
+// GetToughness returns Toughness, or its zero value if unset.
+func (c *CardSet) GetToughness() string {
+	if c.Toughness == nil {
+		var zero string
+		return zero
+	}
+	return *c.Toughness
+}
+
+// GetLoyalty returns Loyalty, or its zero value if unset.
+func (c *CardSet) GetLoyalty() string {
+	if c.Loyalty == nil {
+		var zero string
+		return zero
+	}
+	return *c.Loyalty
+}
+
+// GetDefense returns Defense, or its zero value if unset.
+func (c *CardSet) GetDefense() string {
+	if c.Defense == nil {
+		var zero string
+		return zero
+	}
+	return *c.Defense
+}
+
+// GetHand returns Hand, or its zero value if unset.
+func (c *CardSet) GetHand() string {
+	if c.Hand == nil {
+		var zero string
+		return zero
+	}
+	return *c.Hand
+}
+
+// GetLife returns Life, or its zero value if unset.
+func (c *CardSet) GetLife() string {
+	if c.Life == nil {
+		var zero string
+		return zero
+	}
+	return *c.Life
+}
+
+// GetArtist returns Artist, or its zero value if unset.
+func (c *CardSet) GetArtist() string {
+	if c.Artist == nil {
+		var zero string
+		return zero
+	}
+	return *c.Artist
+}
+
+// GetWatermark returns Watermark, or its zero value if unset.
+func (c *CardSet) GetWatermark() string {
+	if c.Watermark == nil {
+		var zero string
+		return zero
+	}
+	return *c.Watermark
+}
+
+// GetSignature returns Signature, or its zero value if unset.
+func (c *CardSet) GetSignature() string {
+	if c.Signature == nil {
+		var zero string
+		return zero
+	}
+	return *c.Signature
+}
+
+// GetSecurityStamp returns SecurityStamp, or its zero value if unset.
+func (c *CardSet) GetSecurityStamp() string {
+	if c.SecurityStamp == nil {
+		var zero string
+		return zero
+	}
+	return *c.SecurityStamp
+}
+
+// GetDuelDeck returns DuelDeck, or its zero value if unset.
+func (c *CardSet) GetDuelDeck() string {
+	if c.DuelDeck == nil {
+		var zero string
+		return zero
+	}
+	return *c.DuelDeck
+}
+
+// GetFlavorText returns FlavorText, or its zero value if unset.
+func (c *CardSet) GetFlavorText() string {
+	if c.FlavorText == nil {
+		var zero string
+		return zero
+	}
+	return *c.FlavorText
+}
+
+// GetFlavorName returns FlavorName, or its zero value if unset.
+func (c *CardSet) GetFlavorName() string {
+	if c.FlavorName == nil {
+		var zero string
+		return zero
+	}
+	return *c.FlavorName
+}
+
+// GetFaceFlavorName returns FaceFlavorName, or its zero value if unset.
+func (c *CardSet) GetFaceFlavorName() string {
+	if c.FaceFlavorName == nil {
+		var zero string
+		return zero
+	}
+	return *c.FaceFlavorName
+}
+
+// GetOriginalText returns OriginalText, or its zero value if unset.
+func (c *CardSet) GetOriginalText() string {
+	if c.OriginalText == nil {
+		var zero string
+		return zero
+	}
+	return *c.OriginalText
+}
+
+// GetOriginalType returns OriginalType, or its zero value if unset.
+func (c *CardSet) GetOriginalType() string {
+	if c.OriginalType == nil {
+		var zero string
+		return zero
+	}
+	return *c.OriginalType
+}
+
+// GetPrintedName returns PrintedName, or its zero value if unset.
+func (c *CardSet) GetPrintedName() string {
+	if c.PrintedName == nil {
+		var zero string
+		return zero
+	}
+	return *c.PrintedName
+}
+
+// GetPrintedText returns PrintedText, or its zero value if unset.
+func (c *CardSet) GetPrintedText() string {
+	if c.PrintedText == nil {
+		var zero string
+		return zero
+	}
+	return *c.PrintedText
+}
+
+// GetPrintedType returns PrintedType, or its zero value if unset.
+func (c *CardSet) GetPrintedType() string {
+	if c.PrintedType == nil {
+		var zero string
+		return zero
+	}
+	return *c.PrintedType
+}
+
+// GetFacePrintedName returns FacePrintedName, or its zero value if unset.
+func (c *CardSet) GetFacePrintedName() string {
+	if c.FacePrintedName == nil {
+		var zero string
+		return zero
+	}
+	return *c.FacePrintedName
+}
+
+// IsFullArtFlag returns IsFullArt, or its zero value if unset.
+func (c *CardSet) IsFullArtFlag() bool {
+	if c.IsFullArt == nil {
+		var zero bool
+		return zero
+	}
+	return *c.IsFullArt
+}
+
+// IsOnlineOnlyFlag returns IsOnlineOnly, or its zero value if unset.
+func (c *CardSet) IsOnlineOnlyFlag() bool {
+	if c.IsOnlineOnly == nil {
+		var zero bool
+		return zero
+	}
+	return *c.IsOnlineOnly
+}
+
+// IsOversizedFlag returns IsOversized, or its zero value if unset.
+func (c *CardSet) IsOversizedFlag() bool {
+	if c.IsOversized == nil {
+		var zero bool
+		return zero
+	}
+	return *c.IsOversized
+}
+
+// IsPromoFlag returns IsPromo, or its zero value if unset.
+func (c *CardSet) IsPromoFlag() bool {
+	if c.IsPromo == nil {
+		var zero bool
+		return zero
+	}
+	return *c.IsPromo
+}
+
+// IsReprintFlag returns IsReprint, or its zero value if unset.
+func (c *CardSet) IsReprintFlag() bool {
+	if c.IsReprint == nil {
+		var zero bool
+		return zero
+	}
+	return *c.IsReprint
+}
+
+// IsTextlessFlag returns IsTextless, or its zero value if unset.
+func (c *CardSet) IsTextlessFlag() bool {
+	if c.IsTextless == nil {
+		var zero bool
+		return zero
+	}
+	return *c.IsTextless
+}
+
+// IsFunnyFlag returns IsFunny, or its zero value if unset.
+func (c *CardSet) IsFunnyFlag() bool {
+	if c.IsFunny == nil {
+		var zero bool
+		return zero
+	}
+	return *c.IsFunny
+}
+
+// IsRebalancedFlag returns IsRebalanced, or its zero value if unset.
+func (c *CardSet) IsRebalancedFlag() bool {
+	if c.IsRebalanced == nil {
+		var zero bool
+		return zero
+	}
+	return *c.IsRebalanced
+}
+
+// IsAlternativeFlag returns IsAlternative, or its zero value if unset.
+func (c *CardSet) IsAlternativeFlag() bool {
+	if c.IsAlternative == nil {
+		var zero bool
+		return zero
+	}
+	return *c.IsAlternative
+}
+
+// IsStorySpotlightFlag returns IsStorySpotlight, or its zero value if unset.
+func (c *CardSet) IsStorySpotlightFlag() bool {
+	if c.IsStorySpotlight == nil {
+		var zero bool
+		return zero
+	}
+	return *c.IsStorySpotlight
+}
+
+// IsTimeshiftedFlag returns IsTimeshifted, or its zero value if unset.
+func (c *CardSet) IsTimeshiftedFlag() bool {
+	if c.IsTimeshifted == nil {
+		var zero bool
+		return zero
+	}
+	return *c.IsTimeshifted
+}
+
+// HasContentWarningFlag returns HasContentWarning, or its zero value if unset.
+func (c *CardSet) HasContentWarningFlag() bool {
+	if c.HasContentWarning == nil {
+		var zero bool
+		return zero
+	}
+	return *c.HasContentWarning
+}
+
+// HasAlternativeDeckLimitFlag returns HasAlternativeDeckLimit, or its zero value if unset.
+func (c *CardSet) HasAlternativeDeckLimitFlag() bool {
+	if c.HasAlternativeDeckLimit == nil {
+		var zero bool
+		return zero
+	}
+	return *c.HasAlternativeDeckLimit
+}
+
+// IsReservedFlag returns IsReserved, or its zero value if unset.
+func (c *CardSet) IsReservedFlag() bool {
+	if c.IsReserved == nil {
+		var zero bool
+		return zero
+	}
+	return *c.IsReserved
+}
+
+// IsGameChangerFlag returns IsGameChanger, or its zero value if unset.
+func (c *CardSet) IsGameChangerFlag() bool {
+	if c.IsGameChanger == nil {
+		var zero bool
+		return zero
+	}
+	return *c.IsGameChanger
+}
+
+// GetEDHRECRank returns EDHRECRank, or its zero value if unset.
+func (c *CardSet) GetEDHRECRank() int {
+	if c.EDHRECRank == nil {
+		var zero int
+		return zero
+	}
+	return *c.EDHRECRank
+}
+
+// GetEDHRECSaltiness returns EDHRECSaltiness, or its zero value if unset.
+func (c *CardSet) GetEDHRECSaltiness() float64 {
+	if c.EDHRECSaltiness == nil {
+		var zero float64
+		return zero
+	}
+	return *c.EDHRECSaltiness
+}
+
+// GetFirstPrinting returns FirstPrinting, or its zero value if unset.
+func (c *CardSet) GetFirstPrinting() string {
+	if c.FirstPrinting == nil {
+		var zero string
+		return zero
+	}
+	return *c.FirstPrinting
+}
+
+// GetOriginalReleaseDate returns OriginalReleaseDate, or its zero value if unset.
+func (c *CardSet) GetOriginalReleaseDate() string {
+	if c.OriginalReleaseDate == nil {
+		var zero string
+		return zero
+	}
+	return *c.OriginalReleaseDate
+}
+
+// GetLeadershipSkills returns LeadershipSkills, or its zero value if unset.
+func (c *CardSet) GetLeadershipSkills() LeadershipSkills {
+	if c.LeadershipSkills == nil {
+		var zero LeadershipSkills
+		return zero
+	}
+	return *c.LeadershipSkills
+}
+
+// GetRelatedCards returns RelatedCards, or its zero value if unset.
+func (c *CardSet) GetRelatedCards() RelatedCards {
+	if c.RelatedCards == nil {
+		var zero RelatedCards
+		return zero
+	}
+	return *c.RelatedCards
+}
+
+// GetSourceProducts returns SourceProducts, or its zero value if unset.
+func (c *CardSet) GetSourceProducts() SourceProducts {
+	if c.SourceProducts == nil {
+		var zero SourceProducts
+		return zero
+	}
+	return *c.SourceProducts
+}
+
+// Equal reports whether c and other hold the same data.
+func (c *CardSet) Equal(other *CardSet) bool {
+	return len(c.Diff(other)) == 0
+}
+
+// Diff returns the JSON names of the fields that differ between c and
+// other, in declaration order. A nil and a set pointer always differ.
+func (c *CardSet) Diff(other *CardSet) []string {
+	var diff []string
+	if c.UUID != other.UUID {
+		diff = append(diff, "uuid")
+	}
+	if c.Name != other.Name {
+		diff = append(diff, "name")
+	}
+	if !equalPtr(c.ASCIIName, other.ASCIIName) {
+		diff = append(diff, "asciiName")
+	}
+	if !equalPtr(c.FaceName, other.FaceName) {
+		diff = append(diff, "faceName")
+	}
+	if c.Type != other.Type {
+		diff = append(diff, "type")
+	}
+	if !slices.Equal(c.Types, other.Types) {
+		diff = append(diff, "types")
+	}
+	if !slices.Equal(c.Subtypes, other.Subtypes) {
+		diff = append(diff, "subtypes")
+	}
+	if !slices.Equal(c.Supertypes, other.Supertypes) {
+		diff = append(diff, "supertypes")
+	}
+	if !slices.Equal(c.Colors, other.Colors) {
+		diff = append(diff, "colors")
+	}
+	if !slices.Equal(c.ColorIdentity, other.ColorIdentity) {
+		diff = append(diff, "colorIdentity")
+	}
+	if !slices.Equal(c.ColorIndicator, other.ColorIndicator) {
+		diff = append(diff, "colorIndicator")
+	}
+	if !slices.Equal(c.ProducedMana, other.ProducedMana) {
+		diff = append(diff, "producedMana")
+	}
+	if !equalPtr(c.ManaCost, other.ManaCost) {
+		diff = append(diff, "manaCost")
+	}
+	if c.ManaValue != other.ManaValue {
+		diff = append(diff, "manaValue")
+	}
+	if !equalPtr(c.ConvertedManaCost, other.ConvertedManaCost) {
+		diff = append(diff, "convertedManaCost")
+	}
+	if !equalPtr(c.FaceManaValue, other.FaceManaValue) {
+		diff = append(diff, "faceManaValue")
+	}
+	if !equalPtr(c.Text, other.Text) {
+		diff = append(diff, "text")
+	}
+	if c.Layout != other.Layout {
+		diff = append(diff, "layout")
+	}
+	if !equalPtr(c.Side, other.Side) {
+		diff = append(diff, "side")
+	}
+	if !equalPtr(c.Power, other.Power) {
+		diff = append(diff, "power")
+	}
+	if !equalPtr(c.Toughness, other.Toughness) {
+		diff = append(diff, "toughness")
+	}
+	if !equalPtr(c.Loyalty, other.Loyalty) {
+		diff = append(diff, "loyalty")
+	}
+	if !equalPtr(c.Defense, other.Defense) {
+		diff = append(diff, "defense")
+	}
+	if !equalPtr(c.Hand, other.Hand) {
+		diff = append(diff, "hand")
+	}
+	if !equalPtr(c.Life, other.Life) {
+		diff = append(diff, "life")
+	}
+	if c.SetCode != other.SetCode {
+		diff = append(diff, "setCode")
+	}
+	if c.Number != other.Number {
+		diff = append(diff, "number")
+	}
+	if c.Rarity != other.Rarity {
+		diff = append(diff, "rarity")
+	}
+	if !equalPtr(c.Artist, other.Artist) {
+		diff = append(diff, "artist")
+	}
+	if c.BorderColor != other.BorderColor {
+		diff = append(diff, "borderColor")
+	}
+	if c.FrameVersion != other.FrameVersion {
+		diff = append(diff, "frameVersion")
+	}
+	if !equalPtr(c.Watermark, other.Watermark) {
+		diff = append(diff, "watermark")
+	}
+	if !equalPtr(c.Signature, other.Signature) {
+		diff = append(diff, "signature")
+	}
+	if !equalPtr(c.SecurityStamp, other.SecurityStamp) {
+		diff = append(diff, "securityStamp")
+	}
+	if c.Language != other.Language {
+		diff = append(diff, "language")
+	}
+	if !equalPtr(c.DuelDeck, other.DuelDeck) {
+		diff = append(diff, "duelDeck")
+	}
+	if !equalPtr(c.FlavorText, other.FlavorText) {
+		diff = append(diff, "flavorText")
+	}
+	if !equalPtr(c.FlavorName, other.FlavorName) {
+		diff = append(diff, "flavorName")
+	}
+	if !equalPtr(c.FaceFlavorName, other.FaceFlavorName) {
+		diff = append(diff, "faceFlavorName")
+	}
+	if !equalPtr(c.OriginalText, other.OriginalText) {
+		diff = append(diff, "originalText")
+	}
+	if !equalPtr(c.OriginalType, other.OriginalType) {
+		diff = append(diff, "originalType")
+	}
+	if !equalPtr(c.PrintedName, other.PrintedName) {
+		diff = append(diff, "printedName")
+	}
+	if !equalPtr(c.PrintedText, other.PrintedText) {
+		diff = append(diff, "printedText")
+	}
+	if !equalPtr(c.PrintedType, other.PrintedType) {
+		diff = append(diff, "printedType")
+	}
+	if !equalPtr(c.FacePrintedName, other.FacePrintedName) {
+		diff = append(diff, "facePrintedName")
+	}
+	if !slices.Equal(c.ArtistIDs, other.ArtistIDs) {
+		diff = append(diff, "artistIds")
+	}
+	if !slices.Equal(c.Availability, other.Availability) {
+		diff = append(diff, "availability")
+	}
+	if !slices.Equal(c.BoosterTypes, other.BoosterTypes) {
+		diff = append(diff, "boosterTypes")
+	}
+	if !slices.Equal(c.Finishes, other.Finishes) {
+		diff = append(diff, "finishes")
+	}
+	if !slices.Equal(c.FrameEffects, other.FrameEffects) {
+		diff = append(diff, "frameEffects")
+	}
+	if !slices.Equal(c.Keywords, other.Keywords) {
+		diff = append(diff, "keywords")
+	}
+	if !slices.Equal(c.Printings, other.Printings) {
+		diff = append(diff, "printings")
+	}
+	if !slices.Equal(c.PromoTypes, other.PromoTypes) {
+		diff = append(diff, "promoTypes")
+	}
+	if !slices.Equal(c.Variations, other.Variations) {
+		diff = append(diff, "variations")
+	}
+	if !slices.Equal(c.OtherFaceIDs, other.OtherFaceIDs) {
+		diff = append(diff, "otherFaceIds")
+	}
+	if !slices.Equal(c.CardParts, other.CardParts) {
+		diff = append(diff, "cardParts")
+	}
+	if !slices.Equal(c.OriginalPrintings, other.OriginalPrintings) {
+		diff = append(diff, "originalPrintings")
+	}
+	if !slices.Equal(c.RebalancedPrintings, other.RebalancedPrintings) {
+		diff = append(diff, "rebalancedPrintings")
+	}
+	if !slices.Equal(c.Subsets, other.Subsets) {
+		diff = append(diff, "subsets")
+	}
+	if !slices.Equal(c.AttractionLights, other.AttractionLights) {
+		diff = append(diff, "attractionLights")
+	}
+	if !equalPtr(c.IsFullArt, other.IsFullArt) {
+		diff = append(diff, "isFullArt")
+	}
+	if !equalPtr(c.IsOnlineOnly, other.IsOnlineOnly) {
+		diff = append(diff, "isOnlineOnly")
+	}
+	if !equalPtr(c.IsOversized, other.IsOversized) {
+		diff = append(diff, "isOversized")
+	}
+	if !equalPtr(c.IsPromo, other.IsPromo) {
+		diff = append(diff, "isPromo")
+	}
+	if !equalPtr(c.IsReprint, other.IsReprint) {
+		diff = append(diff, "isReprint")
+	}
+	if !equalPtr(c.IsTextless, other.IsTextless) {
+		diff = append(diff, "isTextless")
+	}
+	if !equalPtr(c.IsFunny, other.IsFunny) {
+		diff = append(diff, "isFunny")
+	}
+	if !equalPtr(c.IsRebalanced, other.IsRebalanced) {
+		diff = append(diff, "isRebalanced")
+	}
+	if !equalPtr(c.IsAlternative, other.IsAlternative) {
+		diff = append(diff, "isAlternative")
+	}
+	if !equalPtr(c.IsStorySpotlight, other.IsStorySpotlight) {
+		diff = append(diff, "isStorySpotlight")
+	}
+	if !equalPtr(c.IsTimeshifted, other.IsTimeshifted) {
+		diff = append(diff, "isTimeshifted")
+	}
+	if !equalPtr(c.HasContentWarning, other.HasContentWarning) {
+		diff = append(diff, "hasContentWarning")
+	}
+	if !equalPtr(c.HasAlternativeDeckLimit, other.HasAlternativeDeckLimit) {
+		diff = append(diff, "hasAlternativeDeckLimit")
+	}
+	if !equalPtr(c.IsReserved, other.IsReserved) {
+		diff = append(diff, "isReserved")
+	}
+	if !equalPtr(c.IsGameChanger, other.IsGameChanger) {
+		diff = append(diff, "isGameChanger")
+	}
+	if !equalPtr(c.EDHRECRank, other.EDHRECRank) {
+		diff = append(diff, "edhrecRank")
+	}
+	if !equalPtr(c.EDHRECSaltiness, other.EDHRECSaltiness) {
+		diff = append(diff, "edhrecSaltiness")
+	}
+	if !equalPtr(c.FirstPrinting, other.FirstPrinting) {
+		diff = append(diff, "firstPrinting")
+	}
+	if !equalPtr(c.OriginalReleaseDate, other.OriginalReleaseDate) {
+		diff = append(diff, "originalReleaseDate")
+	}
+	if !reflect.DeepEqual(c.IdentifiersData, other.IdentifiersData) {
+		diff = append(diff, "identifiers")
+	}
+	if !reflect.DeepEqual(c.LegalitiesData, other.LegalitiesData) {
+		diff = append(diff, "legalities")
+	}
+	if !reflect.DeepEqual(c.PurchaseUrlsData, other.PurchaseUrlsData) {
+		diff = append(diff, "purchaseUrls")
+	}
+	if !reflect.DeepEqual(c.LeadershipSkills, other.LeadershipSkills) {
+		diff = append(diff, "leadershipSkills")
+	}
+	if !reflect.DeepEqual(c.RelatedCards, other.RelatedCards) {
+		diff = append(diff, "relatedCards")
+	}
+	if !reflect.DeepEqual(c.RulingsData, other.RulingsData) {
+		diff = append(diff, "rulings")
+	}
+	if !reflect.DeepEqual(c.ForeignDataList, other.ForeignDataList) {
+		diff = append(diff, "foreignData")
+	}
+	if !reflect.DeepEqual(c.SourceProducts, other.SourceProducts) {
+		diff = append(diff, "sourceProducts")
+	}
+	return diff
+}
+
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,56 @@
+package models
+
+// CollectionEntry is a quantity of a card in a collection, deck or want list.
+// A card is identified by UUID or Scryfall ID, or by Name alone: want-list
+// entries then accept any printing, and collection entries count as the
+// newest paper printing. Quantity must be positive. Board is set for deck
+// entries ("main", "side", "commander", "maybe"). PurchasePrice and Added
+// ("YYYY-MM-DD") are written by CSV formats that have columns for them.
+type CollectionEntry struct {
+	UUID          string   `json:"uuid,omitempty"`
+	ScryfallID    string   `json:"scryfallId,omitempty"`
+	Name          string   `json:"name,omitempty"`
+	Quantity      int      `json:"quantity"`
+	Finish        string   `json:"finish,omitempty"`
+	Condition     string   `json:"condition,omitempty"`
+	Language      string   `json:"language,omitempty"`
+	Board         string   `json:"board,omitempty"`
+	PurchasePrice *float64 `json:"purchasePrice,omitempty"`
+	Added         string   `json:"added,omitempty"`
+}
+
+// TradeParty is one side of a trade: the cards it has and the cards it wants.
+type TradeParty struct {
+	Collection []CollectionEntry `json:"collection"`
+	Wants      []CollectionEntry `json:"wants"`
+}
+
+// TradeItem is a priced card that moves from one party to the other.
+type TradeItem struct {
+	UUID      string  `json:"uuid"`
+	Name      string  `json:"name"`
+	SetCode   string  `json:"setCode"`
+	Number    string  `json:"number"`
+	Finish    string  `json:"finish"`
+	Quantity  int     `json:"quantity"`
+	UnitPrice float64 `json:"unit_price"`
+}
+
+// TradeProposal is a price-balanced trade suggestion between two parties.
+// Balance is ReceiveValue minus GiveValue: a positive balance is the cash the
+// first party would add to even out the trade.
+type TradeProposal struct {
+	Give         []TradeItem `json:"give"`
+	Receive      []TradeItem `json:"receive"`
+	GiveValue    float64     `json:"give_value"`
+	ReceiveValue float64     `json:"receive_value"`
+	Balance      float64     `json:"balance"`
+}
+
+// Tag is a user annotation attached to a card or product UUID.
+type Tag struct {
+	UUID      string  `json:"uuid"`
+	Tag       string  `json:"tag"`
+	Note      *string `json:"note,omitempty"`
+	CreatedAt string  `json:"created_at"`
+}
@@ -1,5 +1,15 @@
 package models
 
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
 // DeckList is a summary deck entry without card details.
 type DeckList struct {
 	Code        string  `json:"code"`
@@ -26,3 +36,80 @@ type Deck struct {
 	Tokens             []CardToken `json:"tokens,omitempty"`
 	SourceSetCodes     []string    `json:"sourceSetCodes,omitempty"`
 }
+
+// DeckUpgrade is a card suggested for a preconstructed deck. Score sums the
+// related-card scores against the deck cards listed in Synergies; Price is
+// the cheapest current printing, identified by SetCode and Number.
+type DeckUpgrade struct {
+	UUID           string   `json:"uuid"`
+	Name           string   `json:"name"`
+	Score          float64  `json:"score"`
+	Synergies      []string `json:"synergies"`
+	SharedKeywords []string `json:"shared_keywords,omitempty"`
+	SharedSubtypes []string `json:"shared_subtypes,omitempty"`
+	SetCode        string   `json:"setCode"`
+	Number         string   `json:"number"`
+	Price          float64  `json:"price"`
+	Currency       string   `json:"currency"`
+}
+
+// Hash returns the deck's Cockatrice deck hash, the 8-character identifier
+// Cockatrice shows for a deck and that tournament tools use to tell decks
+// apart. The commander counts as part of the main deck. Cards are named as
+// Cockatrice names them: split cards by their full name, other multi-face
+// cards by their front face.
+func (d *Deck) Hash() string {
+	var names []string
+	add := func(cards []CardDeck, prefix string) {
+		for _, c := range cards {
+			name := prefix + strings.ToLower(deckHashName(c.CardSet))
+			for range c.Count {
+				names = append(names, name)
+			}
+		}
+	}
+	add(d.MainBoard, "")
+	add(d.Commander, "")
+	add(d.SideBoard, "SB:")
+	sort.Strings(names)
+	sum := sha1.Sum([]byte(strings.Join(names, ";")))
+	n := uint64(sum[0])<<32 | uint64(sum[1])<<24 | uint64(sum[2])<<16 | uint64(sum[3])<<8 | uint64(sum[4])
+	hash := strconv.FormatUint(n, 32)
+	return strings.Repeat("0", 8-len(hash)) + hash
+}
+
+// Digest returns a SHA-256 hex digest of the deck's sorted card names and
+// counts per board (main, commander and sideboard). Unlike Hash it is long
+// enough to never collide in practice, for deduplicating stored decks.
+// Printings and finishes do not affect it.
+func (d *Deck) Digest() string {
+	h := sha256.New()
+	for _, board := range []struct {
+		name  string
+		cards []CardDeck
+	}{{"main", d.MainBoard}, {"commander", d.Commander}, {"side", d.SideBoard}} {
+		counts := make(map[string]int)
+		for _, c := range board.cards {
+			counts[c.Name] += c.Count
+		}
+		names := make([]string, 0, len(counts))
+		for name := range counts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(h, "[%s]\n", board.name)
+		for _, name := range names {
+			fmt.Fprintf(h, "%d %s\n", counts[name], name)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// deckHashName is the name Cockatrice gives a card.
+func deckHashName(c CardSet) string {
+	if c.Layout == "split" || c.Layout == "aftermath" {
+		return c.Name
+	}
+	front, _, _ := strings.Cut(c.Name, " // ")
+	return front
+}
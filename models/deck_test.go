@@ -0,0 +1,83 @@
+package models
+
+import "testing"
+
+func deckCard(name, layout string, count int) CardDeck {
+	return CardDeck{CardSet: CardSet{Name: name, Layout: layout}, Count: count}
+}
+
+func TestDeckHash(t *testing.T) {
+	deck := Deck{
+		MainBoard: []CardDeck{deckCard("Mountain", "normal", 16), deckCard("Lightning Bolt", "normal", 4)},
+		SideBoard: []CardDeck{deckCard("Fire // Ice", "split", 2)},
+	}
+	// sha1 of the sorted "lightning bolt;...;mountain;SB:fire // ice" list,
+	// first 40 bits in base 32, as Cockatrice computes it.
+	if got := deck.Hash(); got != "g7887jcs" {
+		t.Errorf("Hash() = %s, want g7887jcs", got)
+	}
+
+	reordered := Deck{
+		MainBoard: []CardDeck{deckCard("Lightning Bolt", "normal", 1), deckCard("Mountain", "normal", 16), deckCard("Lightning Bolt", "normal", 3)},
+		SideBoard: deck.SideBoard,
+	}
+	if reordered.Hash() != deck.Hash() || reordered.Digest() != deck.Digest() {
+		t.Error("expected card order and split entries not to change the hash")
+	}
+
+	moved := Deck{MainBoard: append(deck.MainBoard, deck.SideBoard...)}
+	if moved.Hash() == deck.Hash() || moved.Digest() == deck.Digest() {
+		t.Error("expected moving cards to the main deck to change the hash")
+	}
+}
+
+func TestDeckHashNames(t *testing.T) {
+	mdfc := Deck{MainBoard: []CardDeck{deckCard("Delver of Secrets // Insectile Aberration", "transform", 4)}}
+	front := Deck{MainBoard: []CardDeck{deckCard("Delver of Secrets", "transform", 4)}}
+	if mdfc.Hash() != front.Hash() {
+		t.Error("expected a multi-face card hashed by its front face")
+	}
+	if len(mdfc.Digest()) != 64 {
+		t.Errorf("expected a SHA-256 hex digest, got %q", mdfc.Digest())
+	}
+}
+
+func TestDeckExport(t *testing.T) {
+	bolt := deckCard("Lightning Bolt", "normal", 3)
+	bolt.SetCode, bolt.Number = "a25", "141"
+	reprint := deckCard("Lightning Bolt", "normal", 1)
+	reprint.SetCode, reprint.Number = "M10", "146"
+	deck := Deck{
+		Commander: []CardDeck{deckCard("Esika, God of the Tree // The Prismatic Bridge", "modal_dfc", 1)},
+		MainBoard: []CardDeck{bolt, reprint, deckCard("Fire // Ice", "split", 2)},
+		SideBoard: []CardDeck{deckCard("Mountain", "normal", 4)},
+	}
+	for _, tc := range []struct {
+		format DeckFormat
+		want   string
+	}{
+		{DeckArena, "Commander\n1 Esika, God of the Tree\n\nDeck\n3 Lightning Bolt (A25) 141\n1 Lightning Bolt (M10) 146\n2 Fire // Ice\n\nSideboard\n4 Mountain\n"},
+		{DeckText, "Commander\n1 Esika, God of the Tree\n\nDeck\n4 Lightning Bolt\n2 Fire // Ice\n\nSideboard\n4 Mountain\n"},
+		{DeckMTGO, "4 Lightning Bolt\n2 Fire/Ice\n\n1 Esika, God of the Tree\n4 Mountain\n"},
+	} {
+		got, err := deck.Export(tc.format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want {
+			t.Errorf("Export(%s) = %q, want %q", tc.format, got, tc.want)
+		}
+	}
+	if _, err := deck.Export("cockatrice"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+
+	cards := []CardSet{{UUID: "a", Name: "Counterspell"}, {UUID: "b", Name: "Island"}}
+	got, err := DeckOf(cards, map[string]int{"b": 20}).Export(DeckText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "Deck\n1 Counterspell\n20 Island\n" {
+		t.Errorf("unexpected export of search results: %q", got)
+	}
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DeckFormat is a text deck list format understood by a game client or
+// deck site.
+type DeckFormat string
+
+const (
+	// DeckArena is MTG Arena's import format: "Commander", "Deck" and
+	// "Sideboard" sections with lines like "4 Lightning Bolt (A25) 141".
+	DeckArena DeckFormat = "arena"
+	// DeckMTGO is Magic Online's .txt format: the main deck, a blank line
+	// and the sideboard, with the commander at the top of the sideboard.
+	DeckMTGO DeckFormat = "mtgo"
+	// DeckText is a plain list with Arena's sections and no printings.
+	DeckText DeckFormat = "text"
+)
+
+// Export writes the deck as a deck list in the given format. Arena lines
+// keep each printing; the other formats merge printings of a card into one
+// line. Split cards are named by both halves ("Fire // Ice", "Fire/Ice" on
+// MTGO) and other multi-face cards by their front face.
+func (d *Deck) Export(format DeckFormat) (string, error) {
+	var b strings.Builder
+	switch format {
+	case DeckArena, DeckText:
+		printings := format == DeckArena
+		for _, board := range []struct {
+			name  string
+			cards []CardDeck
+		}{{"Commander", d.Commander}, {"Deck", d.MainBoard}, {"Sideboard", d.SideBoard}} {
+			lines := deckLines(board.cards, printings, deckHashName)
+			if len(lines) == 0 && board.name != "Deck" {
+				continue
+			}
+			if b.Len() > 0 {
+				b.WriteString("\n")
+			}
+			b.WriteString(board.name + "\n")
+			for _, line := range lines {
+				b.WriteString(line + "\n")
+			}
+		}
+	case DeckMTGO:
+		for _, line := range deckLines(d.MainBoard, false, mtgoName) {
+			b.WriteString(line + "\n")
+		}
+		side := deckLines(append(append([]CardDeck{}, d.Commander...), d.SideBoard...), false, mtgoName)
+		if len(side) > 0 {
+			b.WriteString("\n")
+			for _, line := range side {
+				b.WriteString(line + "\n")
+			}
+		}
+	default:
+		return "", fmt.Errorf("mtgjson: unknown deck format %q", format)
+	}
+	return b.String(), nil
+}
+
+// DeckOf builds a deck whose main board holds cards, such as search
+// results, ready for Export. counts maps a card's UUID to its number of
+// copies; cards missing from it get one.
+func DeckOf(cards []CardSet, counts map[string]int) *Deck {
+	d := &Deck{}
+	for _, c := range cards {
+		n, ok := counts[c.UUID]
+		if !ok {
+			n = 1
+		}
+		d.MainBoard = append(d.MainBoard, CardDeck{CardSet: c, Count: n})
+	}
+	return d
+}
+
+// deckLines returns a board's "count name" lines in order of first
+// appearance, merging entries with the same name, or with the same
+// printing when printings is set (which adds "(SET) number").
+func deckLines(cards []CardDeck, printings bool, name func(CardSet) string) []string {
+	var keys []string
+	counts := map[string]int{}
+	for _, c := range cards {
+		if c.Count <= 0 {
+			continue
+		}
+		key := name(c.CardSet)
+		if printings && c.SetCode != "" {
+			key += " (" + strings.ToUpper(c.SetCode) + ")"
+			if c.Number != "" {
+				key += " " + c.Number
+			}
+		}
+		if _, ok := counts[key]; !ok {
+			keys = append(keys, key)
+		}
+		counts[key] += c.Count
+	}
+	lines := make([]string, len(keys))
+	for i, key := range keys {
+		lines[i] = strconv.Itoa(counts[key]) + " " + key
+	}
+	return lines
+}
+
+// mtgoName is the name Magic Online gives a card: split cards as
+// "Fire/Ice", other multi-face cards by their front face.
+func mtgoName(c CardSet) string {
+	return strings.ReplaceAll(deckHashName(c), " // ", "/")
+}
@@ -0,0 +1,152 @@
+// Command accessorgen writes nil-safe accessors and Equal/Diff methods for a
+// model struct. Each pointer field gets a method returning the zero value when
+// it is nil: GetX for most fields and XFlag for *bool fields, whose names
+// (IsPromo, HasContentWarning) would otherwise clash with the field.
+//
+// Usage, from a go:generate directive in the file declaring the type:
+//
+//	//go:generate go run ./internal/accessorgen -type CardSet -o card_accessors.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// basicTypes are compared with == and returned by value from accessors.
+var basicTypes = map[string]bool{"string": true, "bool": true, "int": true, "float64": true}
+
+type field struct {
+	name, json string
+	typ        ast.Expr
+}
+
+func main() {
+	typeName := flag.String("type", "", "struct type to generate methods for")
+	out := flag.String("o", "", "output file")
+	flag.Parse()
+	src := os.Getenv("GOFILE")
+	if *typeName == "" || *out == "" || src == "" {
+		log.Fatal("accessorgen: -type and -o are required and must run via go generate")
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, src, nil, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fields, err := structFields(file, *typeName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	code, err := generate(file.Name.Name, *typeName, fields)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, code, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func structFields(file *ast.File, typeName string) ([]field, error) {
+	var st *ast.StructType
+	ast.Inspect(file, func(n ast.Node) bool {
+		if ts, ok := n.(*ast.TypeSpec); ok && ts.Name.Name == typeName {
+			st, _ = ts.Type.(*ast.StructType)
+			return false
+		}
+		return st == nil
+	})
+	if st == nil {
+		return nil, fmt.Errorf("accessorgen: struct %s not found", typeName)
+	}
+	var fields []field
+	for _, f := range st.Fields.List {
+		var tag string
+		if f.Tag != nil {
+			tag = reflect.StructTag(strings.Trim(f.Tag.Value, "`")).Get("json")
+		}
+		for _, name := range f.Names {
+			json, _, _ := strings.Cut(tag, ",")
+			if json == "" {
+				json = name.Name
+			}
+			fields = append(fields, field{name: name.Name, json: json, typ: f.Type})
+		}
+	}
+	return fields, nil
+}
+
+func generate(pkg, typeName string, fields []field) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by accessorgen -type %s; DO NOT EDIT.\n\n", typeName)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	b.WriteString("import (\n\t\"reflect\"\n\t\"slices\"\n)\n\n")
+
+	for _, f := range fields {
+		star, ok := f.typ.(*ast.StarExpr)
+		if !ok {
+			continue
+		}
+		elem := exprString(star.X)
+		method := "Get" + f.name
+		if elem == "bool" {
+			method = f.name + "Flag"
+		}
+		fmt.Fprintf(&b, "// %s returns %s, or its zero value if unset.\n", method, f.name)
+		fmt.Fprintf(&b, "func (c *%s) %s() %s {\n", typeName, method, elem)
+		fmt.Fprintf(&b, "\tif c.%s == nil {\n\t\tvar zero %s\n\t\treturn zero\n\t}\n", f.name, elem)
+		fmt.Fprintf(&b, "\treturn *c.%s\n}\n\n", f.name)
+	}
+
+	fmt.Fprintf(&b, "// Equal reports whether c and other hold the same data.\n")
+	fmt.Fprintf(&b, "func (c *%s) Equal(other *%s) bool {\n\treturn len(c.Diff(other)) == 0\n}\n\n", typeName, typeName)
+
+	fmt.Fprintf(&b, "// Diff returns the JSON names of the fields that differ between c and\n")
+	fmt.Fprintf(&b, "// other, in declaration order. A nil and a set pointer always differ.\n")
+	fmt.Fprintf(&b, "func (c *%s) Diff(other *%s) []string {\n\tvar diff []string\n", typeName, typeName)
+	for _, f := range fields {
+		fmt.Fprintf(&b, "\tif %s {\n\t\tdiff = append(diff, %q)\n\t}\n", differs(f), f.json)
+	}
+	b.WriteString("\treturn diff\n}\n\n")
+
+	b.WriteString("func equalPtr[T comparable](a, b *T) bool {\n")
+	b.WriteString("\tif a == nil || b == nil {\n\t\treturn a == b\n\t}\n\treturn *a == *b\n}\n")
+	return format.Source(b.Bytes())
+}
+
+// differs returns the expression reporting that field f differs.
+func differs(f field) string {
+	switch t := f.typ.(type) {
+	case *ast.Ident:
+		if basicTypes[t.Name] {
+			return fmt.Sprintf("c.%s != other.%s", f.name, f.name)
+		}
+	case *ast.StarExpr:
+		if id, ok := t.X.(*ast.Ident); ok && basicTypes[id.Name] {
+			return fmt.Sprintf("!equalPtr(c.%s, other.%s)", f.name, f.name)
+		}
+	case *ast.ArrayType:
+		if id, ok := t.Elt.(*ast.Ident); ok && t.Len == nil && basicTypes[id.Name] {
+			return fmt.Sprintf("!slices.Equal(c.%s, other.%s)", f.name, f.name)
+		}
+	}
+	return fmt.Sprintf("!reflect.DeepEqual(c.%s, other.%s)", f.name, f.name)
+}
+
+func exprString(e ast.Expr) string {
+	var b bytes.Buffer
+	if err := format.Node(&b, token.NewFileSet(), e); err != nil {
+		log.Fatal(err)
+	}
+	return b.String()
+}
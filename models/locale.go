@@ -0,0 +1,84 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// isoDate is the layout of MTGJSON dates.
+const isoDate = "2006-01-02"
+
+// Locale controls how exports write prices and dates, and imports read them,
+// for tools that use a regional format such as "1,50" and "16.10.2024". The
+// zero value uses MTGJSON's own formats: "1.50" and "2024-10-16".
+type Locale struct {
+	// Decimal is the decimal separator; "" means ".".
+	Decimal string
+	// DateLayout is a time layout for dates; "" means "2006-01-02".
+	DateLayout string
+}
+
+// Common export locales. LocaleFrench also suits Italian and Spanish tools.
+var (
+	LocaleISO    = Locale{}
+	LocaleUS     = Locale{Decimal: ".", DateLayout: "01/02/2006"}
+	LocaleUK     = Locale{Decimal: ".", DateLayout: "02/01/2006"}
+	LocaleGerman = Locale{Decimal: ",", DateLayout: "02.01.2006"}
+	LocaleFrench = Locale{Decimal: ",", DateLayout: "02/01/2006"}
+)
+
+// FormatPrice writes a price with two decimals, e.g. "1,50".
+func (l Locale) FormatPrice(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 2, 64)
+	if l.Decimal != "" && l.Decimal != "." {
+		s = strings.Replace(s, ".", l.Decimal, 1)
+	}
+	return s
+}
+
+// FormatDate rewrites an MTGJSON "YYYY-MM-DD" date (or an RFC 3339
+// timestamp's date) in the locale's layout. Other values are returned
+// unchanged.
+func (l Locale) FormatDate(date string) string {
+	if l.DateLayout == "" || len(date) < len(isoDate) {
+		return date
+	}
+	t, err := time.Parse(isoDate, date[:len(isoDate)])
+	if err != nil {
+		return date
+	}
+	return t.Format(l.DateLayout)
+}
+
+// ParsePrice reads a price written by FormatPrice or by a tool using the
+// locale, ignoring a leading currency symbol: "1,50", "$1.50".
+func (l Locale) ParsePrice(s string) (float64, error) {
+	v := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(s), "$€£"))
+	if l.Decimal != "" && l.Decimal != "." {
+		v = strings.Replace(v, l.Decimal, ".", 1)
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("mtgjson: invalid price %q", s)
+	}
+	return f, nil
+}
+
+// ParseDate reads a date in the locale's layout, or an MTGJSON
+// "YYYY-MM-DD" date or timestamp, and returns it as "YYYY-MM-DD".
+func (l Locale) ParseDate(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if len(s) >= len(isoDate) {
+		if t, err := time.Parse(isoDate, s[:len(isoDate)]); err == nil {
+			return t.Format(isoDate), nil
+		}
+	}
+	if l.DateLayout != "" {
+		if t, err := time.Parse(l.DateLayout, s); err == nil {
+			return t.Format(isoDate), nil
+		}
+	}
+	return "", fmt.Errorf("mtgjson: invalid date %q", s)
+}
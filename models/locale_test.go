@@ -0,0 +1,41 @@
+package models
+
+import "testing"
+
+func TestLocale(t *testing.T) {
+	tests := []struct {
+		l           Locale
+		price, date string
+	}{
+		{LocaleISO, "1.50", "2024-10-16"},
+		{LocaleUS, "1.50", "10/16/2024"},
+		{LocaleGerman, "1,50", "16.10.2024"},
+		{LocaleFrench, "1,50", "16/10/2024"},
+	}
+	for _, tt := range tests {
+		if got := tt.l.FormatPrice(1.5); got != tt.price {
+			t.Errorf("%+v: FormatPrice = %q, want %q", tt.l, got, tt.price)
+		}
+		if got := tt.l.FormatDate("2024-10-16"); got != tt.date {
+			t.Errorf("%+v: FormatDate = %q, want %q", tt.l, got, tt.date)
+		}
+		if got, err := tt.l.ParsePrice(tt.price); err != nil || got != 1.5 {
+			t.Errorf("%+v: ParsePrice(%q) = %v, %v", tt.l, tt.price, got, err)
+		}
+		if got, err := tt.l.ParseDate(tt.date); err != nil || got != "2024-10-16" {
+			t.Errorf("%+v: ParseDate(%q) = %q, %v", tt.l, tt.date, got, err)
+		}
+	}
+	if got, err := LocaleGerman.ParseDate("2024-10-16 12:34:56"); err != nil || got != "2024-10-16" {
+		t.Errorf("expected ISO timestamps in any locale, got %q (%v)", got, err)
+	}
+	if _, err := LocaleUS.ParsePrice("lots"); err == nil {
+		t.Error("expected an error for an unparsable price")
+	}
+	if got := LocaleGerman.FormatDate("2024-10-16T12:00:00Z"); got != "16.10.2024" {
+		t.Errorf("expected a timestamp's date, got %q", got)
+	}
+	if got := LocaleGerman.FormatDate("soon"); got != "soon" {
+		t.Errorf("expected unparsable dates unchanged, got %q", got)
+	}
+}
@@ -2,18 +2,19 @@ package models
 
 // PriceRow is a single flattened price data point from the prices table.
 type PriceRow struct {
-	UUID     string  `json:"uuid"`
-	Source   string  `json:"source"`
-	Provider string  `json:"provider"`
-	Currency string  `json:"currency"`
-	Category string  `json:"category"`
-	Finish   string  `json:"finish"`
-	Date     string  `json:"date"`
-	Price    float64 `json:"price"`
+	UUID      string  `json:"uuid"`
+	Source    string  `json:"source"`
+	Provider  string  `json:"provider"`
+	Currency  string  `json:"currency"`
+	PriceType string  `json:"price_type"`
+	Finish    string  `json:"finish"`
+	Date      string  `json:"date"`
+	Price     float64 `json:"price"`
 }
 
 // PriceTrend contains aggregate price statistics over time.
 type PriceTrend struct {
+	Currency   string  `json:"currency"`
 	MinPrice   float64 `json:"min_price"`
 	MaxPrice   float64 `json:"max_price"`
 	AvgPrice   float64 `json:"avg_price"`
@@ -24,12 +25,37 @@ type PriceTrend struct {
 
 // FinancialSummary contains aggregate price data for a set.
 type FinancialSummary struct {
+	TotalValue float64                  `json:"total_value"`
+	AvgValue   float64                  `json:"avg_value"`
+	MinValue   float64                  `json:"min_value"`
+	MaxValue   float64                  `json:"max_value"`
+	CardCount  int64                    `json:"card_count"`
+	Date       string                   `json:"date"`
+	ByRarity   []RarityFinancialSummary `json:"by_rarity,omitempty"`
+}
+
+// RarityFinancialSummary contains aggregate price data for one rarity within a set.
+type RarityFinancialSummary struct {
+	Rarity     string  `json:"rarity"`
 	TotalValue float64 `json:"total_value"`
 	AvgValue   float64 `json:"avg_value"`
 	MinValue   float64 `json:"min_value"`
 	MaxValue   float64 `json:"max_value"`
 	CardCount  int64   `json:"card_count"`
-	Date       string  `json:"date"`
+}
+
+// BoxValue compares the expected value of a booster box's packs with the
+// market price of the sealed box.
+type BoxValue struct {
+	SetCode     string   `json:"setCode"`
+	BoosterType string   `json:"boosterType"`
+	PackEV      float64  `json:"pack_ev"`
+	PacksPerBox int      `json:"packs_per_box"`
+	BoxEV       float64  `json:"box_ev"`
+	SealedUUID  *string  `json:"sealed_uuid,omitempty"`
+	SealedName  *string  `json:"sealed_name,omitempty"`
+	BoxPrice    *float64 `json:"box_price,omitempty"`
+	EVRatio     *float64 `json:"ev_ratio,omitempty"`
 }
 
 // PricePrinting represents a card printing with its price info.
@@ -39,6 +65,49 @@ type PricePrinting struct {
 	Number   string  `json:"cheapest_number"`
 	UUID     string  `json:"cheapest_uuid"`
 	MinPrice float64 `json:"min_price"`
+	Currency string  `json:"currency"`
+}
+
+// CardValue is a priced printing with its price per mana value and per
+// power, nil where the card has no mana value or numeric power.
+type CardValue struct {
+	UUID              string   `json:"uuid"`
+	Name              string   `json:"name"`
+	SetCode           string   `json:"setCode"`
+	Number            string   `json:"number"`
+	ManaValue         float64  `json:"manaValue"`
+	Power             *string  `json:"power,omitempty"`
+	Price             float64  `json:"price"`
+	Currency          string   `json:"currency"`
+	PricePerManaValue *float64 `json:"price_per_mana_value,omitempty"`
+	PricePerPower     *float64 `json:"price_per_power,omitempty"`
+}
+
+// CompletionCost is the cost of collecting every distinct card name in a
+// set, either from the set's own printings or from the cheapest printing
+// in any set.
+type CompletionCost struct {
+	SetCode         string           `json:"set_code"`
+	Currency        string           `json:"currency"`
+	Names           int              `json:"names"`
+	Priced          int              `json:"priced"`
+	InSetSingleton  float64          `json:"in_set_singleton"`
+	InSetPlayset    float64          `json:"in_set_playset"`
+	AnySetSingleton float64          `json:"any_set_singleton"`
+	AnySetPlayset   float64          `json:"any_set_playset"`
+	Missing         []string         `json:"missing,omitempty"`
+	Cards           []CompletionCard `json:"cards"`
+}
+
+// CompletionCard is one card name's cheapest printing within the set and
+// across all sets.
+type CompletionCard struct {
+	Name            string   `json:"name"`
+	InSetUUID       *string  `json:"inSetUuid,omitempty"`
+	InSetPrice      *float64 `json:"inSetPrice,omitempty"`
+	CheapestUUID    *string  `json:"cheapestUuid,omitempty"`
+	CheapestSetCode *string  `json:"cheapestSetCode,omitempty"`
+	CheapestPrice   *float64 `json:"cheapestPrice,omitempty"`
 }
 
 // ExpensivePrinting represents an expensive card printing.
@@ -48,4 +117,32 @@ type ExpensivePrinting struct {
 	Number   string  `json:"priciest_number"`
 	UUID     string  `json:"priciest_uuid"`
 	MaxPrice float64 `json:"max_price"`
+	Currency string  `json:"currency"`
+}
+
+// PriceSpread is the retail minus buylist spread for a printing from one provider.
+type PriceSpread struct {
+	UUID         string  `json:"uuid"`
+	Name         string  `json:"name,omitempty"`
+	SetCode      string  `json:"setCode,omitempty"`
+	Number       string  `json:"number,omitempty"`
+	Provider     string  `json:"provider"`
+	Finish       string  `json:"finish"`
+	RetailPrice  float64 `json:"retail_price"`
+	BuylistPrice float64 `json:"buylist_price"`
+	Spread       float64 `json:"spread"`
+	Date         string  `json:"date"`
+}
+
+// VendorPrice is a card's latest paper price at one vendor for one finish,
+// with the vendor's display name and currency. Retail or buylist is nil when
+// the vendor does not list it.
+type VendorPrice struct {
+	Vendor       string   `json:"vendor"`
+	VendorName   string   `json:"vendor_name"`
+	Finish       string   `json:"finish"`
+	Currency     string   `json:"currency"`
+	RetailPrice  *float64 `json:"retail_price,omitempty"`
+	BuylistPrice *float64 `json:"buylist_price,omitempty"`
+	Date         string   `json:"date"`
 }
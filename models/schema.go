@@ -0,0 +1,146 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+)
+
+// JSONSchemaDialect is the JSON Schema draft produced by JSONSchema. OpenAPI
+// 3.1 uses the same dialect, so OpenAPIComponents schemas match it.
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema returns a JSON Schema document describing how v (a model value
+// such as CardSet{} or a pointer to one) marshals to JSON. Nested structs are
+// placed under $defs and referenced by type name. Fields without omitempty
+// are required; pointer, slice and map fields without it may also be null.
+func JSONSchema(v any) map[string]any {
+	g := &schemaGen{refPrefix: "#/$defs/", defs: map[string]any{}}
+	t := derefType(reflect.TypeOf(v))
+	root := g.schemaFor(t)
+	doc := map[string]any{"$schema": JSONSchemaDialect}
+	if t.Kind() == reflect.Struct && t.Name() != "" {
+		// Inline the root type rather than referencing its own definition.
+		root = g.defs[t.Name()].(map[string]any)
+		delete(g.defs, t.Name())
+		doc["title"] = t.Name()
+	}
+	for k, val := range root {
+		doc[k] = val
+	}
+	if len(g.defs) > 0 {
+		doc["$defs"] = g.defs
+	}
+	return doc
+}
+
+// OpenAPIComponents returns an OpenAPI 3.1 components object whose schemas
+// describe the given models and every struct type they contain, keyed by
+// type name, e.g. OpenAPIComponents(CardSet{}, SetList{}, CardToken{}).
+func OpenAPIComponents(vs ...any) map[string]any {
+	g := &schemaGen{refPrefix: "#/components/schemas/", defs: map[string]any{}}
+	for _, v := range vs {
+		g.schemaFor(derefType(reflect.TypeOf(v)))
+	}
+	return map[string]any{"schemas": g.defs}
+}
+
+// schemaGen collects the definitions of named struct types as it walks them.
+type schemaGen struct {
+	refPrefix string
+	defs      map[string]any
+}
+
+func (g *schemaGen) schemaFor(t reflect.Type) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.schemaFor(t.Elem())
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		if _, ok := g.defs[t.Name()]; !ok {
+			g.defs[t.Name()] = map[string]any{} // placeholder for recursive types
+			g.defs[t.Name()] = g.structSchema(t)
+		}
+		return map[string]any{"$ref": g.refPrefix + t.Name()}
+	default:
+		return map[string]any{}
+	}
+}
+
+func (g *schemaGen) structSchema(t reflect.Type) map[string]any {
+	props := map[string]any{}
+	required := []string{}
+	g.addFields(t, props, &required)
+	s := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// addFields adds t's JSON properties, flattening embedded structs the way
+// encoding/json does.
+func (g *schemaGen) addFields(t reflect.Type, props map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && derefType(f.Type).Kind() == reflect.Struct {
+			g.addFields(derefType(f.Type), props, required)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		s := g.schemaFor(f.Type)
+		omitEmpty := strings.Contains(opts, "omitempty")
+		if !omitEmpty {
+			*required = append(*required, name)
+			switch f.Type.Kind() {
+			case reflect.Pointer, reflect.Slice, reflect.Map:
+				s = nullable(s)
+			}
+		}
+		props[name] = s
+	}
+}
+
+// nullable allows null in addition to schema s.
+func nullable(s map[string]any) map[string]any {
+	if typ, ok := s["type"].(string); ok {
+		out := make(map[string]any, len(s))
+		for k, v := range s {
+			out[k] = v
+		}
+		out["type"] = []string{typ, "null"}
+		return out
+	}
+	return map[string]any{"anyOf": []any{s, map[string]any{"type": "null"}}}
+}
+
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestJSONSchemaCardSet(t *testing.T) {
+	doc := JSONSchema(CardSet{})
+	if doc["$schema"] != JSONSchemaDialect || doc["title"] != "CardSet" {
+		t.Fatalf("unexpected header: %v, %v", doc["$schema"], doc["title"])
+	}
+	props := doc["properties"].(map[string]any)
+	required := doc["required"].([]string)
+	if !slices.Contains(required, "uuid") || slices.Contains(required, "asciiName") {
+		t.Errorf("expected uuid required and asciiName optional, got %v", required)
+	}
+	if got := props["identifiers"].(map[string]any)["$ref"]; got != "#/$defs/Identifiers" {
+		t.Errorf("expected identifiers to reference $defs, got %v", got)
+	}
+	defs := doc["$defs"].(map[string]any)
+	for _, name := range []string{"Identifiers", "Legalities", "PurchaseUrls", "ForeignData"} {
+		if _, ok := defs[name]; !ok {
+			t.Errorf("expected $defs to include %s", name)
+		}
+	}
+	if _, ok := defs["CardSet"]; ok {
+		t.Error("expected the root type to be inlined, not in $defs")
+	}
+
+	// Every key encoding/json writes for a zero value must be described.
+	data, err := json.Marshal(CardSet{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	for key := range fields {
+		if _, ok := props[key]; !ok {
+			t.Errorf("marshaled key %q missing from schema", key)
+		}
+	}
+}
+
+func TestJSONSchemaNullable(t *testing.T) {
+	props := JSONSchema(CardSet{})["properties"].(map[string]any)
+	types := props["types"].(map[string]any)
+	if !slices.Equal(types["type"].([]string), []string{"array", "null"}) {
+		t.Errorf("expected a nil slice to be nullable, got %v", types)
+	}
+	if ascii := props["asciiName"].(map[string]any); ascii["type"] != "string" {
+		t.Errorf("expected an omitempty pointer to be a plain string, got %v", ascii)
+	}
+}
+
+func TestOpenAPIComponents(t *testing.T) {
+	schemas := OpenAPIComponents(IdentifierMatch{}, SetList{}, CardToken{})["schemas"].(map[string]any)
+	for _, name := range []string{"IdentifierMatch", "SetList", "CardToken", "Identifiers", "SealedProduct"} {
+		if _, ok := schemas[name]; !ok {
+			t.Errorf("expected component %s", name)
+		}
+	}
+	match := schemas["IdentifierMatch"].(map[string]any)["properties"].(map[string]any)
+	if _, ok := match["uuid"]; !ok {
+		t.Error("expected embedded CardSet fields to be flattened")
+	}
+	if _, ok := match["id_column"]; !ok {
+		t.Error("expected id_column")
+	}
+	ref := match["legalities"].(map[string]any)["$ref"]
+	if ref != "#/components/schemas/Legalities" {
+		t.Errorf("unexpected ref %v", ref)
+	}
+}
@@ -81,3 +81,11 @@ type SealedProductVariableConfig struct {
 	Chance *int `json:"chance,omitempty"`
 	Weight *int `json:"weight,omitempty"`
 }
+
+// SealedCategoryCount is the number of sealed products in one category,
+// with the count for each subtype within it ("" for products without one).
+type SealedCategoryCount struct {
+	Category string         `json:"category"`
+	Products int            `json:"products"`
+	Subtypes map[string]int `json:"subtypes"`
+}
@@ -54,3 +54,112 @@ type DeckSet struct {
 	Schemes            []CardSetDeck `json:"schemes,omitempty"`
 	SourceSetCodes     []string      `json:"sourceSetCodes,omitempty"`
 }
+
+// SetAssets is what a UI needs to render a set header. IconURL is Scryfall's
+// SVG set symbol and KeyruneClass the CSS class for the Keyrune icon font,
+// both derived from the set's keyruneCode.
+type SetAssets struct {
+	Code         string       `json:"code"`
+	Name         string       `json:"name"`
+	Type         string       `json:"type"`
+	ReleaseDate  string       `json:"releaseDate"`
+	TotalSetSize int          `json:"totalSetSize"`
+	Block        *string      `json:"block,omitempty"`
+	ParentCode   *string      `json:"parentCode,omitempty"`
+	KeyruneCode  string       `json:"keyruneCode"`
+	KeyruneClass string       `json:"keyrune_class"`
+	IconURL      string       `json:"icon_url"`
+	Translations Translations `json:"translations,omitempty"`
+}
+
+// LanguageCount is a language of the foreign data and how many cards have
+// a translation in it.
+type LanguageCount struct {
+	Language string `json:"language"`
+	Cards    int    `json:"cards"`
+}
+
+// LanguageCoverage reports how many of a set's cards are translated into
+// each language. Coverage is Translated divided by Cards, from 0 to 1.
+type LanguageCoverage struct {
+	SetCode   string               `json:"set_code"`
+	Cards     int                  `json:"cards"`
+	Languages []LanguageTranslated `json:"languages"`
+}
+
+// LanguageTranslated is one language's row of a LanguageCoverage.
+type LanguageTranslated struct {
+	Language   string  `json:"language"`
+	Translated int     `json:"translated"`
+	Coverage   float64 `json:"coverage"`
+}
+
+// CardAvailability lists the languages and sets a card is printed in.
+// Languages is the union of its printings' languages.
+type CardAvailability struct {
+	Name      string                 `json:"name"`
+	Languages []string               `json:"languages"`
+	Printings []PrintingAvailability `json:"printings"`
+}
+
+// PrintingAvailability is one printing's row of a CardAvailability.
+// Languages holds the printing's own language and those it has foreign
+// data for, with LocalizedNames keyed by language; SetLanguages are the
+// languages the set was printed in.
+type PrintingAvailability struct {
+	UUID           string            `json:"uuid"`
+	SetCode        string            `json:"setCode"`
+	SetName        string            `json:"setName"`
+	ReleaseDate    string            `json:"releaseDate"`
+	Number         string            `json:"number"`
+	Language       string            `json:"language"`
+	Languages      []string          `json:"languages"`
+	LocalizedNames map[string]string `json:"localizedNames,omitempty"`
+	SetLanguages   []string          `json:"setLanguages,omitempty"`
+}
+
+// RarityBreakdown counts a set's cards by rarity and, when the set has
+// booster data, lists the cards on each booster sheet.
+type RarityBreakdown struct {
+	SetCode  string        `json:"set_code"`
+	Cards    int           `json:"cards"`
+	Rarities []RarityCount `json:"rarities"`
+	Sheets   []PrintSheet  `json:"sheets,omitempty"`
+}
+
+// RarityCount is one rarity's row of a RarityBreakdown. Share is Cards
+// divided by the set's total, from 0 to 1.
+type RarityCount struct {
+	Rarity string  `json:"rarity"`
+	Cards  int     `json:"cards"`
+	Share  float64 `json:"share"`
+}
+
+// PrintSheet is a booster sheet reconstructed from a set's booster data.
+// Slots are ordered by set and collector number; a slot's Weight is how
+// often the card appears on the sheet relative to TotalWeight.
+type PrintSheet struct {
+	BoosterType string      `json:"booster_type"`
+	Sheet       string      `json:"sheet"`
+	Foil        bool        `json:"foil"`
+	Rarities    []string    `json:"rarities"`
+	TotalWeight int         `json:"total_weight"`
+	Slots       []SheetSlot `json:"slots"`
+}
+
+// SheetSlot is one card on a PrintSheet. Name, Number, Rarity and SetCode
+// are empty when the card is missing from the cards data.
+type SheetSlot struct {
+	UUID    string `json:"uuid"`
+	Name    string `json:"name"`
+	SetCode string `json:"setCode"`
+	Number  string `json:"number"`
+	Rarity  string `json:"rarity"`
+	Weight  int    `json:"weight"`
+}
+
+// SetSubset is a named group of cards within a set, such as a bonus sheet.
+type SetSubset struct {
+	Name  string    `json:"name"`
+	Cards []CardSet `json:"cards"`
+}
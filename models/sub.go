@@ -6,6 +6,43 @@ type Meta struct {
 	Version string `json:"version"`
 }
 
+// RefreshResult reports the outcome of an SDK refresh. When Stale is false
+// nothing was reloaded and both versions are the cached version. Changed
+// lists the CDN files that differed from the cache; Reloaded lists the
+// registered views that were reloaded from them. Changes holds the row-level
+// differences of reloaded views when change subscribers are registered.
+type RefreshResult struct {
+	Stale      bool        `json:"stale"`
+	OldVersion string      `json:"old_version"`
+	NewVersion string      `json:"new_version"`
+	Changed    []string    `json:"changed,omitempty"`
+	Reloaded   []string    `json:"reloaded,omitempty"`
+	Changes    []ChangeSet `json:"changes,omitempty"`
+}
+
+// ChangeSet lists the card UUIDs whose rows in a view differ between two
+// MTGJSON releases. For all_prices_today, Modified means a price changed.
+type ChangeSet struct {
+	View       string   `json:"view"`
+	OldVersion string   `json:"old_version"`
+	NewVersion string   `json:"new_version"`
+	Added      []string `json:"added,omitempty"`
+	Removed    []string `json:"removed,omitempty"`
+	Modified   []string `json:"modified,omitempty"`
+}
+
+// Fingerprint identifies the contents of a loaded view. Two environments
+// with equal fingerprints for a view hold identical data with high
+// probability. SampleHash covers the SampleSize rows with the smallest row
+// hashes, so it does not depend on row order; Hash combines it with Rows.
+type Fingerprint struct {
+	View       string `json:"view"`
+	Rows       int64  `json:"rows"`
+	SampleSize int64  `json:"sample_size"`
+	SampleHash string `json:"sample_hash"`
+	Hash       string `json:"hash"`
+}
+
 // Identifiers contains all external identifier mappings for a card.
 type Identifiers struct {
 	CardKingdomEtchedId      *string `json:"cardKingdomEtchedId,omitempty"`
@@ -82,6 +119,16 @@ type PurchaseUrls struct {
 	TcgplayerEtched   *string `json:"tcgplayerEtched,omitempty"`
 }
 
+// PurchaseLink is one of a card's purchase URLs resolved to the vendor's own
+// page. Kind is the purchaseUrls key, e.g. "tcgplayerEtched"; URL includes
+// any affiliate codes configured for the vendor.
+type PurchaseLink struct {
+	Vendor      string `json:"vendor"`
+	Kind        string `json:"kind"`
+	RedirectURL string `json:"redirect_url"`
+	URL         string `json:"url"`
+}
+
 // RelatedCards contains references to other related cards.
 type RelatedCards struct {
 	ReverseRelated []string `json:"reverseRelated,omitempty"`
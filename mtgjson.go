@@ -2,11 +2,19 @@ package mtgjsonsdk
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"image"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/mtgjson/mtgjson-sdk-go/booster"
+	"github.com/mtgjson/mtgjson-sdk-go/contactsheet"
 	"github.com/mtgjson/mtgjson-sdk-go/db"
 	"github.com/mtgjson/mtgjson-sdk-go/models"
 	"github.com/mtgjson/mtgjson-sdk-go/queries"
@@ -15,9 +23,29 @@ import (
 // SDK is the main entry point for querying MTGJSON card data.
 // It auto-downloads Parquet data from the MTGJSON CDN and provides
 // a typed, queryable Go API for the full dataset.
+//
+// An SDK is safe for concurrent use by multiple goroutines: views and JSON
+// data are loaded once on first use, even when several goroutines ask for
+// them at the same time.
 type SDK struct {
 	conn  *db.Connection
+	back  db.Backend // what the query modules read; conn unless WithBackend
 	cache *db.CacheManager
+	cfg   *db.Config // as built by New's options, for AsOf
+
+	excludeCasual bool
+	excludeSets   queries.SetExclusions
+	translit      queries.Transliterator
+	affiliates    map[string]url.Values
+	asOf          string // archived version a read-only SDK is bound to
+
+	legalityHistory bool // record legalities on every Refresh
+
+	changeMu   sync.Mutex // guards changeSubs and nextSub
+	changeSubs map[int]func(models.ChangeSet)
+	nextSub    int
+
+	mu sync.Mutex // guards the lazily created query modules below
 
 	cards       *queries.CardQuery
 	sets        *queries.SetQuery
@@ -29,9 +57,20 @@ type SDK struct {
 	enums       *queries.EnumQuery
 	skus        *queries.SkuQuery
 	sealed      *queries.SealedQuery
+	formats     *queries.FormatQuery
+	trades      *queries.TradeQuery
+	collections *queries.CollectionQuery
+	subtypes    *queries.SubtypeQuery
+	foreign     *queries.ForeignDataQuery
+	tags        *queries.TagQuery
+	searches    *queries.SavedSearchQuery
+	purchase    *queries.PurchaseQuery
 	booster     *booster.BoosterSimulator
 }
 
+// annotationsFile is the DuckDB file in the cache dir holding user tags.
+const annotationsFile = "annotations.duckdb"
+
 // New creates a new SDK instance with the given options.
 func New(opts ...Option) (*SDK, error) {
 	cfg := db.DefaultConfig()
@@ -42,15 +81,35 @@ func New(opts ...Option) (*SDK, error) {
 	if err != nil {
 		return nil, err
 	}
-	conn, err := db.NewConnection(cache)
+	conn, err := db.OpenConnection(cache, cfg.DSN, cfg.DuckDBOptions)
 	if err != nil {
 		cache.Close()
 		return nil, err
 	}
-	return &SDK{
-		conn:  conn,
-		cache: cache,
-	}, nil
+	for view, columns := range cfg.ViewColumns {
+		conn.SetViewColumns(view, columns...)
+	}
+	if cfg.MaterializeLegalities {
+		conn.MaterializeLegalities(true)
+	}
+	s := &SDK{
+		conn:          conn,
+		back:          conn,
+		cache:         cache,
+		cfg:           cfg,
+		excludeCasual: cfg.ExcludeCasualLayouts,
+		translit:      cfg.Transliterator,
+		affiliates:    cfg.AffiliateCodes,
+		excludeSets: queries.SetExclusions{
+			OnlineOnly:  cfg.ExcludeOnlineOnly,
+			Memorabilia: cfg.ExcludeMemorabilia,
+		},
+	}
+	if cfg.Backend != nil {
+		s.back = cfg.Backend
+	}
+	s.legalityHistory = cfg.LegalityHistory
+	return s, nil
 }
 
 // Close releases all resources (DuckDB connection and HTTP client).
@@ -60,63 +119,101 @@ func (s *SDK) Close() error {
 }
 
 // Cards returns the card query interface.
-func (s *SDK) Cards() *queries.CardQuery {
+func (s *SDK) Cards() queries.CardAPI {
+	return s.cardQuery()
+}
+
+// cardQuery returns the concrete card module, which the saved search and
+// purchase link modules are built on and other modules returning cards take
+// their keywords from.
+func (s *SDK) cardQuery() *queries.CardQuery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.cards == nil {
-		s.cards = queries.NewCardQuery(s.conn)
+		if s.enums == nil {
+			s.enums = queries.NewEnumQuery(s.cache)
+		}
+		s.cards = queries.NewCardQuery(s.back,
+			queries.WithKeywords(s.enums),
+			queries.WithCasualLayoutsExcluded(s.excludeCasual),
+			queries.WithCardSetExclusions(s.excludeSets),
+			queries.WithTransliterator(s.translit))
 	}
 	return s.cards
 }
 
 // Sets returns the set query interface.
-func (s *SDK) Sets() *queries.SetQuery {
+func (s *SDK) Sets() queries.SetAPI {
+	cards := s.cardQuery()
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.sets == nil {
-		s.sets = queries.NewSetQuery(s.conn)
+		s.sets = queries.NewSetQuery(s.back, queries.WithSetExclusions(s.excludeSets), queries.WithSetCards(cards))
 	}
 	return s.sets
 }
 
 // Tokens returns the token query interface.
-func (s *SDK) Tokens() *queries.TokenQuery {
+func (s *SDK) Tokens() queries.TokenAPI {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.tokens == nil {
-		s.tokens = queries.NewTokenQuery(s.conn)
+		s.tokens = queries.NewTokenQuery(s.back)
 	}
 	return s.tokens
 }
 
 // Legalities returns the legality query interface.
-func (s *SDK) Legalities() *queries.LegalityQuery {
+func (s *SDK) Legalities() queries.LegalityAPI {
+	cards := s.cardQuery()
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.legalities == nil {
-		s.legalities = queries.NewLegalityQuery(s.conn)
+		s.legalities = queries.NewLegalityQuery(s.back, queries.WithLegalityCards(cards))
 	}
 	return s.legalities
 }
 
 // Identifiers returns the identifier cross-reference query interface.
-func (s *SDK) Identifiers() *queries.IdentifierQuery {
+func (s *SDK) Identifiers() queries.IdentifierAPI {
+	cards := s.cardQuery()
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.identifiers == nil {
-		s.identifiers = queries.NewIdentifierQuery(s.conn)
+		s.identifiers = queries.NewIdentifierQuery(s.back, queries.WithIdentifierCards(cards))
 	}
 	return s.identifiers
 }
 
 // Prices returns the price query interface.
-func (s *SDK) Prices() *queries.PriceQuery {
+func (s *SDK) Prices() queries.PriceAPI {
+	cards := s.cardQuery()
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.prices == nil {
-		s.prices = queries.NewPriceQuery(s.conn)
+		s.prices = queries.NewPriceQuery(s.back, queries.WithPriceCards(cards))
 	}
 	return s.prices
 }
 
 // Decks returns the deck query interface.
-func (s *SDK) Decks() *queries.DeckQuery {
+func (s *SDK) Decks() queries.DeckAPI {
+	cards := s.cardQuery()
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.decks == nil {
-		s.decks = queries.NewDeckQuery(s.cache)
+		if s.prices == nil {
+			s.prices = queries.NewPriceQuery(s.back, queries.WithPriceCards(cards))
+		}
+		s.decks = queries.NewDeckQuery(s.cache, queries.WithUpgradeSources(cards, s.prices))
 	}
 	return s.decks
 }
 
 // Enums returns the enum query interface.
-func (s *SDK) Enums() *queries.EnumQuery {
+func (s *SDK) Enums() queries.EnumAPI {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.enums == nil {
 		s.enums = queries.NewEnumQuery(s.cache)
 	}
@@ -124,25 +221,118 @@ func (s *SDK) Enums() *queries.EnumQuery {
 }
 
 // Skus returns the TCGPlayer SKU query interface.
-func (s *SDK) Skus() *queries.SkuQuery {
+func (s *SDK) Skus() queries.SkuAPI {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.skus == nil {
-		s.skus = queries.NewSkuQuery(s.conn)
+		s.skus = queries.NewSkuQuery(s.back)
 	}
 	return s.skus
 }
 
 // Sealed returns the sealed product query interface.
-func (s *SDK) Sealed() *queries.SealedQuery {
+func (s *SDK) Sealed() queries.SealedAPI {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.sealed == nil {
-		s.sealed = queries.NewSealedQuery(s.conn)
+		s.sealed = queries.NewSealedQuery(s.back)
 	}
 	return s.sealed
 }
 
+// Formats returns the format rotation query interface.
+func (s *SDK) Formats() queries.FormatAPI {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.formats == nil {
+		s.formats = queries.NewFormatQuery(s.back)
+	}
+	return s.formats
+}
+
+// Trades returns the want-list and trade matching interface.
+func (s *SDK) Trades() queries.TradeAPI {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.trades == nil {
+		s.trades = queries.NewTradeQuery(s.back)
+	}
+	return s.trades
+}
+
+// Collections returns the collection and deck CSV import/export interface.
+func (s *SDK) Collections() queries.CollectionAPI {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.collections == nil {
+		s.collections = queries.NewCollectionQuery(s.back)
+	}
+	return s.collections
+}
+
+// Tags returns the user tags and notes interface. Tags are stored in
+// annotations.duckdb in the cache dir and are kept across refreshes.
+func (s *SDK) Tags() queries.TagAPI {
+	cards := s.cardQuery()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tags == nil {
+		s.tags = queries.NewTagQuery(s.back, filepath.Join(s.cache.CacheDir, annotationsFile), queries.WithTagCards(cards))
+	}
+	return s.tags
+}
+
+// SavedSearches returns the named card search registry, stored next to the
+// tags in annotations.duckdb in the cache dir.
+func (s *SDK) SavedSearches() queries.SavedSearchAPI {
+	cards := s.cardQuery()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.searches == nil {
+		s.searches = queries.NewSavedSearchQuery(cards, filepath.Join(s.cache.CacheDir, annotationsFile))
+	}
+	return s.searches
+}
+
+// PurchaseLinks returns the purchase link resolver, which turns MTGJSON's
+// redirect links into vendor URLs carrying the configured affiliate codes.
+func (s *SDK) PurchaseLinks() queries.PurchaseAPI {
+	cards := s.cardQuery()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.purchase == nil {
+		s.purchase = queries.NewPurchaseQuery(cards, s.cache, s.affiliates)
+	}
+	return s.purchase
+}
+
+// Subtypes returns the subtype (tribal) census interface.
+func (s *SDK) Subtypes() queries.SubtypeAPI {
+	cards := s.cardQuery()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.subtypes == nil {
+		s.subtypes = queries.NewSubtypeQuery(s.back, queries.WithSubtypeCards(cards))
+	}
+	return s.subtypes
+}
+
+// ForeignData returns the translation coverage interface.
+func (s *SDK) ForeignData() queries.ForeignDataAPI {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.foreign == nil {
+		s.foreign = queries.NewForeignDataQuery(s.back)
+	}
+	return s.foreign
+}
+
 // Booster returns the booster simulator interface.
-func (s *SDK) Booster() *booster.BoosterSimulator {
+func (s *SDK) Booster() queries.BoosterAPI {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.booster == nil {
-		s.booster = booster.NewBoosterSimulator(s.conn)
+		s.booster = booster.NewBoosterSimulator(s.back)
 	}
 	return s.booster
 }
@@ -170,19 +360,144 @@ func (s *SDK) Views() []string {
 	return s.conn.Views()
 }
 
-// SQL executes raw SQL against the DuckDB database.
+// SQL executes raw SQL against the DuckDB database, or the WithBackend
+// backend. The SDK views the query reads from (FROM and JOIN clauses) are
+// loaded first; views it reaches some other way, e.g. through a
+// user-defined macro, still need EnsureViews.
 func (s *SDK) SQL(ctx context.Context, query string, params ...any) ([]map[string]any, error) {
-	return s.conn.Execute(ctx, query, params...)
+	if conn, ok := s.back.(*db.Connection); ok {
+		if err := conn.EnsureReferencedViews(ctx, query); err != nil {
+			return nil, err
+		}
+	} else if err := s.back.EnsureOptionalViews(ctx, db.ReferencedViews(query)...); err != nil {
+		return nil, err
+	}
+	return s.back.Execute(ctx, query, params...)
 }
 
-// Refresh checks for new MTGJSON data and resets internal state if stale.
-// Returns true if data was stale and state was reset.
-func (s *SDK) Refresh(ctx context.Context) (bool, error) {
-	if !s.cache.IsStale(ctx) {
-		return false, nil
+// fingerprintSample is the number of rows hashed by Fingerprint.
+const fingerprintSample = 1000
+
+// Fingerprint computes a stable hash of a view's contents: its row count and
+// a hash of a deterministic sample of rows, chosen by row hash so the result
+// does not depend on scan order. Known MTGJSON views are loaded if needed;
+// other names must already be registered.
+func (s *SDK) Fingerprint(ctx context.Context, view string) (*models.Fingerprint, error) {
+	if !s.back.HasView(view) {
+		if _, ok := db.ParquetFiles[view]; !ok {
+			return nil, fmt.Errorf("mtgjson: view %q is not loaded", view)
+		}
+		if err := s.back.EnsureViews(ctx, view); err != nil {
+			return nil, err
+		}
+	}
+	rows, err := s.back.Execute(ctx, fmt.Sprintf(
+		"WITH h AS (SELECT md5(CAST(t AS VARCHAR)) AS h FROM %s t) "+
+			"SELECT (SELECT COUNT(*) FROM h) AS n, COUNT(*) AS sampled, "+
+			"COALESCE(md5(string_agg(h, ',' ORDER BY h)), '') AS sample_hash "+
+			"FROM (SELECT h FROM h ORDER BY h LIMIT $1)", view), fingerprintSample)
+	if err != nil {
+		return nil, fmt.Errorf("mtgjson: fingerprint %s: %w", view, err)
+	}
+	fp := &models.Fingerprint{
+		View:       view,
+		Rows:       int64(db.ScalarToInt(rows[0]["n"])),
+		SampleSize: int64(db.ScalarToInt(rows[0]["sampled"])),
+	}
+	fp.SampleHash, _ = rows[0]["sample_hash"].(string)
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%s", fp.Rows, fp.SampleHash)))
+	fp.Hash = hex.EncodeToString(sum[:])
+	return fp, nil
+}
+
+// Refresh checks for new MTGJSON data and, if the cache is stale, reloads
+// only the datasets that changed on the CDN. Unchanged files (often the large
+// cards parquet when only prices moved) are kept. Changed files are removed
+// from the cache; views registered on them are dropped, reloaded and checked
+// to carry the new version. Query modules are reset. Tables registered from
+// in-memory data are kept.
+//
+// When SubscribeChanges has subscribers, reloaded cards and today's prices
+// are diffed against the previous release; the change sets are returned in
+// the result and passed to every subscriber before Refresh returns.
+func (s *SDK) Refresh(ctx context.Context) (*models.RefreshResult, error) {
+	if s.asOf != "" {
+		return nil, fmt.Errorf("mtgjson: SDK bound to version %s is read-only", s.asOf)
 	}
-	s.conn.ClearViews()
+	s.changeMu.Lock()
+	subs := make([]func(models.ChangeSet), 0, len(s.changeSubs))
+	for _, fn := range s.changeSubs {
+		subs = append(subs, fn)
+	}
+	s.changeMu.Unlock()
+
+	result, err := s.refresh(ctx, len(subs) > 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, cs := range result.Changes {
+		for _, fn := range subs {
+			fn(cs)
+		}
+	}
+	return result, nil
+}
+
+func (s *SDK) refresh(ctx context.Context, diff bool) (*models.RefreshResult, error) {
+	ctx = db.AllowDownloads(ctx)
 	s.cache.ResetRemoteVersion()
+	result := &models.RefreshResult{OldVersion: s.cache.LocalVersion()}
+	if !s.cache.IsStale(ctx) {
+		result.NewVersion = result.OldVersion
+		return result, nil
+	}
+	remote := s.cache.RemoteVersion(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	legalitiesFile := db.ParquetFiles[db.DatasetCardLegalities]
+	if s.legalityHistory {
+		_, historyErr := os.Stat(s.cache.Path(db.LegalityHistoryFile))
+		_, legalitiesErr := os.Stat(s.cache.Path(legalitiesFile))
+		if historyErr != nil && legalitiesErr == nil {
+			// Start the history with the release being replaced. It is read
+			// from the cached file as is: the cache is already stale, so
+			// loading the view or Meta.json would fetch the new release.
+			if date := s.cache.CachedReleaseDate(legalitiesFile); date == "" {
+				s.cache.Logger().Warn("Cannot date cached legalities, not recording them", "file", legalitiesFile)
+			} else if _, err := s.conn.RecordLegalitiesFile(ctx, s.cache.Path(legalitiesFile), date); err != nil {
+				return nil, err
+			}
+		}
+	}
+	var keep []string
+	if diff {
+		for _, name := range s.conn.Views() {
+			if db.CanDiff(name) {
+				keep = append(keep, db.ParquetFiles[name])
+			}
+		}
+	}
+	changed, err := s.cache.RefreshDatasets(ctx, keep...)
+	for _, filename := range keep {
+		defer os.Remove(s.cache.PreviousPath(filename))
+	}
+	if err != nil {
+		return nil, err
+	}
+	changedFiles := make(map[string]bool, len(changed))
+	for _, filename := range changed {
+		changedFiles[filename] = true
+	}
+	var reload []string
+	for _, name := range s.conn.Views() {
+		if changedFiles[db.ParquetFiles[name]] {
+			reload = append(reload, name)
+		}
+	}
+	if err := s.conn.DropViews(ctx, reload...); err != nil {
+		return nil, err
+	}
 	s.cards = nil
 	s.sets = nil
 	s.tokens = nil
@@ -193,25 +508,216 @@ func (s *SDK) Refresh(ctx context.Context) (bool, error) {
 	s.enums = nil
 	s.skus = nil
 	s.sealed = nil
+	s.formats = nil
+	s.trades = nil
+	s.collections = nil
+	s.subtypes = nil
+	s.foreign = nil
+	s.tags = nil
+	s.searches = nil
+	s.purchase = nil
 	s.booster = nil
-	return true, nil
+
+	if err := s.conn.EnsureViews(ctx, reload...); err != nil {
+		return nil, err
+	}
+	for _, name := range reload {
+		if loaded := s.cache.DatasetVersion(db.ParquetFiles[name]); loaded != remote {
+			return nil, fmt.Errorf("mtgjson: refresh loaded %s version %q, want %q", name, loaded, remote)
+		}
+	}
+	if s.legalityHistory && changedFiles[legalitiesFile] {
+		if _, err := s.RecordLegalities(ctx); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range reload {
+		prev := s.cache.PreviousPath(db.ParquetFiles[name])
+		if _, err := os.Stat(prev); err != nil {
+			continue // not kept: diffing is off or the view cannot be diffed
+		}
+		added, removed, modified, err := s.conn.DiffParquet(ctx, name, prev, s.cache.Path(db.ParquetFiles[name]))
+		if err != nil {
+			return nil, err
+		}
+		result.Changes = append(result.Changes, models.ChangeSet{
+			View:       name,
+			OldVersion: result.OldVersion,
+			NewVersion: remote,
+			Added:      added,
+			Removed:    removed,
+			Modified:   modified,
+		})
+	}
+	result.Stale = true
+	result.NewVersion = remote
+	result.Changed = changed
+	result.Reloaded = reload
+	return result, nil
+}
+
+// RecordLegalities adds the cached release's legalities to the legality
+// history read by Legalities().StatusAsOf and BanHistory, dated by the
+// release's Meta date, and returns the number of changes recorded. With
+// WithLegalityHistory, Refresh calls it for every new release.
+func (s *SDK) RecordLegalities(ctx context.Context) (int, error) {
+	meta, err := s.Meta(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return s.conn.RecordLegalities(ctx, meta.Date)
+}
+
+// SubscribeChanges registers fn to receive the change set of every view
+// reloaded by a later Refresh (cards and today's prices). fn runs on the
+// goroutine calling Refresh, after the SDK has been updated, so it may use
+// the SDK. Call the returned function to unsubscribe.
+func (s *SDK) SubscribeChanges(fn func(models.ChangeSet)) (unsubscribe func()) {
+	s.changeMu.Lock()
+	defer s.changeMu.Unlock()
+	if s.changeSubs == nil {
+		s.changeSubs = make(map[int]func(models.ChangeSet))
+	}
+	id := s.nextSub
+	s.nextSub++
+	s.changeSubs[id] = fn
+	return func() {
+		s.changeMu.Lock()
+		defer s.changeMu.Unlock()
+		delete(s.changeSubs, id)
+	}
+}
+
+// ReloadPrices forgets the price views and loads today's prices again,
+// returning any download error. Use it to retry after a failed price load
+// without recreating the SDK; price history is reloaded on its next use.
+func (s *SDK) ReloadPrices(ctx context.Context) error {
+	s.conn.ResetViews("all_prices_today", "all_prices")
+	return s.conn.EnsureViews(db.AllowDownloads(ctx), "all_prices_today")
+}
+
+// PinPrices freezes all price queries to the snapshot of the given date
+// (YYYY-MM-DD) in the price history, so analyses give the same results after
+// prices are refreshed. Today's prices become that day's snapshot and price
+// history ends on it. Returns an error if the history has no prices for date.
+// The pin survives Refresh and ReloadPrices until UnpinPrices is called.
+func (s *SDK) PinPrices(ctx context.Context, date string) error {
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return fmt.Errorf("mtgjson: invalid price snapshot date %q, want YYYY-MM-DD", date)
+	}
+	path, err := s.cache.EnsureParquet(db.AllowDownloads(ctx), "all_prices")
+	if err != nil {
+		return err
+	}
+	rows, err := s.conn.Execute(ctx, fmt.Sprintf(
+		"SELECT COUNT(*) FILTER (WHERE CAST(date AS DATE) = CAST($1 AS DATE)) AS n, "+
+			"MIN(CAST(date AS DATE)) AS first_date, MAX(CAST(date AS DATE)) AS last_date "+
+			"FROM read_parquet(%s)", db.SQLPathLiteral(path)), date)
+	if err != nil {
+		return err
+	}
+	if db.ScalarToInt(rows[0]["n"]) == 0 {
+		return fmt.Errorf("mtgjson: no price snapshot for %s (history covers %s to %s)",
+			date, db.ToDateStr(rows[0]["first_date"]), db.ToDateStr(rows[0]["last_date"]))
+	}
+	s.conn.OverrideView("all_prices_today", "all_prices", fmt.Sprintf("CAST(date AS DATE) = DATE '%s'", date))
+	s.conn.OverrideView("all_prices", "all_prices", fmt.Sprintf("CAST(date AS DATE) <= DATE '%s'", date))
+	return nil
+}
+
+// UnpinPrices undoes PinPrices; price queries use the latest data again.
+func (s *SDK) UnpinPrices() {
+	s.conn.RestoreView("all_prices_today")
+	s.conn.RestoreView("all_prices")
+}
+
+// Versions returns the past MTGJSON releases kept by WithKeepVersions,
+// newest first, for AsOf.
+func (s *SDK) Versions() []string {
+	return s.cache.Versions()
+}
+
+// AsOf returns a read-only SDK bound to an archived MTGJSON release (see
+// Versions), so comparisons such as prices or legalities now versus last
+// month run entirely on cached data. It works offline, shares the options
+// s was created with (a DSN file is suffixed with the version; see
+// archiveDSN), and cannot Refresh. Close it when done.
+func (s *SDK) AsOf(version string) (*SDK, error) {
+	dir, err := s.cache.VersionDir(version)
+	if err != nil {
+		return nil, err
+	}
+	cfg := *s.cfg
+	cfg.CacheDir = dir
+	cfg.Offline = true
+	// The archive holds the whole release; a base cache, store, remote
+	// views or a WithBackend backend would serve the current one.
+	cfg.BaseCacheDir = ""
+	cfg.Store = nil
+	cfg.RemoteParquet = false
+	cfg.KeepVersions = 0
+	cfg.DSN = archiveDSN(cfg.DSN, version)
+	cfg.Backend = nil
+	cache, err := db.NewCacheManager(&cfg)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := db.OpenConnection(cache, cfg.DSN, cfg.DuckDBOptions)
+	if err != nil {
+		cache.Close()
+		return nil, err
+	}
+	for view, columns := range cfg.ViewColumns {
+		conn.SetViewColumns(view, columns...)
+	}
+	if cfg.MaterializeLegalities {
+		conn.MaterializeLegalities(true)
+	}
+	return &SDK{
+		conn:            conn,
+		back:            conn,
+		cache:           cache,
+		cfg:             &cfg,
+		excludeCasual:   s.excludeCasual,
+		translit:        s.translit,
+		affiliates:      s.affiliates,
+		excludeSets:     s.excludeSets,
+		legalityHistory: s.legalityHistory,
+		asOf:            version,
+	}, nil
+}
+
+// archiveDSN returns the DuckDB file an SDK bound to version opens when s
+// uses the file dsn: a sibling named after the version, since registering
+// its views in the same database would replace those of s. An in-memory
+// dsn is returned as is.
+func archiveDSN(dsn, version string) string {
+	path, params, _ := strings.Cut(dsn, "?")
+	if path == "" || path == ":memory:" {
+		return dsn
+	}
+	ext := filepath.Ext(path)
+	path = strings.TrimSuffix(path, ext) + "-" + version + ext
+	if params != "" {
+		path += "?" + params
+	}
+	return path
 }
 
 // ExportDB exports all loaded data to a persistent DuckDB file.
 func (s *SDK) ExportDB(ctx context.Context, path string) error {
-	pathStr := filepath.ToSlash(path)
+	pathStr := db.SQLPathLiteral(path)
 	os.Remove(path)
 
-	_, err := s.conn.Raw().ExecContext(ctx, fmt.Sprintf("ATTACH '%s' AS export_db", pathStr))
-	if err != nil {
+	if err := s.conn.Exec(ctx, fmt.Sprintf("ATTACH %s AS export_db", pathStr)); err != nil {
 		return fmt.Errorf("mtgjson: attach export db: %w", err)
 	}
 	defer func() {
-		s.conn.Raw().ExecContext(ctx, "DETACH export_db")
+		s.conn.Exec(ctx, "DETACH export_db")
 	}()
 
 	for _, viewName := range s.Views() {
-		_, err := s.conn.Raw().ExecContext(ctx, fmt.Sprintf(
+		err := s.conn.Exec(ctx, fmt.Sprintf(
 			"CREATE TABLE export_db.%s AS SELECT * FROM %s", viewName, viewName,
 		))
 		if err != nil {
@@ -221,6 +727,14 @@ func (s *SDK) ExportDB(ctx context.Context, path string) error {
 	return nil
 }
 
+// ContactSheet renders cards, such as an opened pack, as one image of their
+// Scryfall card images, downloaded once into the cache's images directory.
+// Encode it with image/png or image/jpeg; contactsheet.WriteHTML writes an
+// HTML page instead.
+func (s *SDK) ContactSheet(ctx context.Context, cards []models.CardSet, opts ...contactsheet.Option) (image.Image, error) {
+	return contactsheet.Render(ctx, s.cache, cards, opts...)
+}
+
 // Connection returns the underlying Connection for advanced usage.
 func (s *SDK) Connection() *db.Connection {
 	return s.conn
@@ -228,8 +742,21 @@ func (s *SDK) Connection() *db.Connection {
 
 // EnsureViews registers one or more views, downloading data if needed.
 // This is useful before calling SQL() to ensure the required tables exist.
+// Names are the keys of db.ParquetFiles, such as db.DatasetCards. It
+// downloads even under WithNoImplicitDownloads.
 func (s *SDK) EnsureViews(ctx context.Context, names ...string) error {
-	return s.conn.EnsureViews(ctx, names...)
+	return s.conn.EnsureViews(db.AllowDownloads(ctx), names...)
+}
+
+// Capabilities reports which optional DuckDB features were found at startup.
+// Missing ones are handled by slower Go fallbacks rather than failing queries.
+func (s *SDK) Capabilities() db.Capabilities {
+	return s.conn.Capabilities()
+}
+
+// CacheDir returns the directory holding the cached MTGJSON files.
+func (s *SDK) CacheDir() string {
+	return s.cache.CacheDir
 }
 
 // String returns a human-readable representation.
@@ -2,7 +2,19 @@ package mtgjsonsdk
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+
+	"github.com/mtgjson/mtgjson-sdk-go/db"
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+	"github.com/mtgjson/mtgjson-sdk-go/queries"
 )
 
 var sampleCardsRoot = []map[string]any{
@@ -97,6 +109,15 @@ func TestSDKSQL(t *testing.T) {
 	if len(rows) != 1 {
 		t.Fatalf("expected 1, got %d", len(rows))
 	}
+
+	writePricesParquet(t, sdk)
+	rows, err = sdk.SQL(ctx, "SELECT price FROM "+db.LatestPricesTable)
+	if err != nil {
+		t.Fatalf("expected the derived table to load its source view, got %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("expected 1 latest price, got %d", len(rows))
+	}
 }
 
 func TestSDKString(t *testing.T) {
@@ -106,3 +127,562 @@ func TestSDKString(t *testing.T) {
 		t.Fatal("expected non-empty string")
 	}
 }
+
+func TestSDKConcurrentAccessors(t *testing.T) {
+	sdk := setupSampleSDK(t)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	cards := make([]queries.CardAPI, 8)
+	for i := range cards {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cards[i] = sdk.Cards()
+			_ = sdk.Booster()
+			_ = sdk.Decks()
+			if _, err := sdk.Cards().GetByUUID(ctx, "card-uuid-001"); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	for _, q := range cards {
+		if q != cards[0] {
+			t.Fatal("expected every goroutine to share one CardQuery")
+		}
+	}
+}
+
+// writePricesParquet caches a one-row AllPricesToday.parquet for the SDK.
+func writePricesParquet(t *testing.T, sdk *SDK) {
+	t.Helper()
+	path := filepath.Join(sdk.cache.CacheDir, "parquet", "AllPricesToday.parquet")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	_, err := sdk.SQL(context.Background(), fmt.Sprintf(
+		"COPY (SELECT 'card-uuid-001' AS uuid, 'paper' AS source, 'tcgplayer' AS provider, 'USD' AS currency, "+
+			"'retail' AS price_type, 'normal' AS finish, '2024-01-01' AS date, 1.5 AS price) TO '%s' (FORMAT PARQUET)",
+		filepath.ToSlash(path)))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSDKPricesRetryAfterFailedLoad(t *testing.T) {
+	sdk := setupSampleSDK(t)
+	ctx := context.Background()
+
+	rows, err := sdk.Prices().Today(ctx, "card-uuid-001")
+	if err != nil || rows != nil {
+		t.Fatalf("expected no prices and no error without strict mode, got %v, %v", rows, err)
+	}
+	writePricesParquet(t, sdk)
+	rows, err = sdk.Prices().Today(ctx, "card-uuid-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("expected the failed load to be retried, got %v", rows)
+	}
+	if err := sdk.ReloadPrices(ctx); err != nil {
+		t.Fatalf("ReloadPrices: %v", err)
+	}
+	if !sdk.conn.HasView("all_prices_today") {
+		t.Fatal("expected prices to be registered after reload")
+	}
+}
+
+func TestSDKStrictPrices(t *testing.T) {
+	sdk, err := New(WithCacheDir(t.TempDir()), WithOffline(true), WithStrict(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sdk.Close() })
+	ctx := context.Background()
+
+	if _, err := sdk.Prices().Today(ctx, "card-uuid-001"); err == nil {
+		t.Fatal("expected strict mode to surface the price load error")
+	}
+	if err := sdk.ReloadPrices(ctx); err == nil {
+		t.Fatal("expected ReloadPrices to fail without cached prices")
+	}
+	writePricesParquet(t, sdk)
+	if err := sdk.ReloadPrices(ctx); err != nil {
+		t.Fatalf("ReloadPrices: %v", err)
+	}
+	rows, err := sdk.Prices().Today(ctx, "card-uuid-001")
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("expected one price row after reload, got %v, %v", rows, err)
+	}
+}
+
+func TestSDKNoImplicitDownloadsPrices(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+	sdk, err := New(WithCacheDir(t.TempDir()), WithMirror(srv.URL), WithNoImplicitDownloads(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sdk.Close() })
+
+	if _, err := sdk.Prices().Today(context.Background(), "card-uuid-001"); !errors.Is(err, db.ErrWouldDownload) {
+		t.Fatalf("expected ErrWouldDownload, got %v", err)
+	}
+}
+
+func TestSDKPinPrices(t *testing.T) {
+	sdk := setupSampleSDK(t)
+	ctx := context.Background()
+	path := filepath.Join(sdk.cache.CacheDir, "parquet", "AllPrices.parquet")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	_, err := sdk.SQL(ctx, fmt.Sprintf(
+		"COPY (SELECT 'card-uuid-001' AS uuid, 'paper' AS source, 'tcgplayer' AS provider, 'USD' AS currency, "+
+			"'retail' AS price_type, 'normal' AS finish, d AS date, CAST(p AS DOUBLE) AS price "+
+			"FROM (VALUES ('2024-01-01', 1.0), ('2024-01-02', 2.0), ('2024-01-03', 3.0)) t(d, p)) "+
+			"TO '%s' (FORMAT PARQUET)", filepath.ToSlash(path)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	writePricesParquet(t, sdk)
+
+	if err := sdk.PinPrices(ctx, "2024/01/02"); err == nil {
+		t.Fatal("expected an error for a malformed date")
+	}
+	if err := sdk.PinPrices(ctx, "2023-12-31"); err == nil {
+		t.Fatal("expected an error for a date missing from the history")
+	}
+	if err := sdk.PinPrices(ctx, "2024-01-02"); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := sdk.Prices().Today(ctx, "card-uuid-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || db.ToFloat64(rows[0]["price"]) != 2.0 {
+		t.Fatalf("expected the pinned 2.00 price, got %v", rows)
+	}
+	history, err := sdk.Prices().History(ctx, "card-uuid-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected history to end at the pinned date, got %d rows", len(history))
+	}
+	if err := sdk.ReloadPrices(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if rows, _ := sdk.Prices().Today(ctx, "card-uuid-001"); len(rows) != 1 || db.ToFloat64(rows[0]["price"]) != 2.0 {
+		t.Fatalf("expected the pin to survive ReloadPrices, got %v", rows)
+	}
+
+	sdk.UnpinPrices()
+	rows, err = sdk.Prices().Today(ctx, "card-uuid-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || db.ToDateStr(rows[0]["date"]) != "2024-01-01" {
+		t.Fatalf("expected today's prices after unpinning, got %v", rows)
+	}
+}
+
+func TestSDKFingerprint(t *testing.T) {
+	ctx := context.Background()
+	a, b := setupSampleSDK(t), setupSampleSDK(t)
+	fa, err := a.Fingerprint(ctx, "cards")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fb, err := b.Fingerprint(ctx, "cards")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fa.Rows != int64(len(sampleCardsRoot)) || fa.SampleSize != fa.Rows || fa.Hash == "" {
+		t.Fatalf("unexpected fingerprint: %+v", fa)
+	}
+	if *fa != *fb {
+		t.Fatalf("expected identical data to match: %+v vs %+v", fa, fb)
+	}
+
+	if _, err := b.SQL(ctx, "CREATE OR REPLACE TABLE cards AS SELECT * REPLACE ('Bolt' AS name) FROM cards"); err != nil {
+		t.Fatal(err)
+	}
+	fb, err = b.Fingerprint(ctx, "cards")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fb.Hash == fa.Hash || fb.Rows != fa.Rows {
+		t.Fatalf("expected changed data to change the hash only: %+v vs %+v", fa, fb)
+	}
+
+	if _, err := a.Fingerprint(ctx, "not_a_view"); err == nil {
+		t.Fatal("expected an error for an unknown view")
+	}
+}
+
+func TestSDKTagsPersist(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+	sdk, err := New(WithCacheDir(dir), WithOffline(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sdk.Tags().Add(ctx, "card-uuid-001", "favorite", "first bolt"); err != nil {
+		t.Fatal(err)
+	}
+	sdk.Close()
+
+	sdk, err = New(WithCacheDir(dir), WithOffline(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sdk.Close()
+	tags, err := sdk.Tags().ForUUID(ctx, "card-uuid-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 1 || tags[0].Tag != "favorite" {
+		t.Fatalf("expected the tag to persist, got %+v", tags)
+	}
+}
+
+func TestSDKRefreshNotStale(t *testing.T) {
+	sdk := setupSampleSDK(t)
+	if err := os.WriteFile(filepath.Join(sdk.cache.CacheDir, "version.txt"), []byte("5.2.2"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cards := sdk.Cards()
+
+	result, err := sdk.Refresh(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Stale || result.OldVersion != "5.2.2" || result.NewVersion != "5.2.2" {
+		t.Fatalf("unexpected refresh result: %+v", result)
+	}
+	if sdk.Cards() != cards || !sdk.conn.HasView("cards") {
+		t.Fatal("a fresh cache must not reset modules or views")
+	}
+}
+
+func TestSDKCacheDir(t *testing.T) {
+	dir := t.TempDir()
+	sdk, err := New(WithCacheDir(dir), WithOffline(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sdk.Close()
+	if sdk.CacheDir() != dir {
+		t.Fatalf("CacheDir() = %q, want %q", sdk.CacheDir(), dir)
+	}
+}
+
+func TestSDKCapabilities(t *testing.T) {
+	sdk := setupSampleSDK(t)
+	if caps := sdk.Capabilities(); !caps.JSON || !caps.JaroWinkler {
+		t.Errorf("expected both DuckDB features, got %+v", caps)
+	}
+}
+
+func TestSDKWithDSN(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "sdk.duckdb")
+	sdk, err := New(WithCacheDir(t.TempDir()), WithOffline(true), WithDSN(dsn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sdk.Close()
+	if _, err := sdk.SQL(context.Background(), "SELECT 1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dsn); err != nil {
+		t.Fatalf("expected the DuckDB file to be created: %v", err)
+	}
+}
+
+// countingBackend counts the queries run through a backend.
+type countingBackend struct {
+	db.Backend
+	queries int
+}
+
+func (b *countingBackend) ExecuteInto(ctx context.Context, dst any, query string, params ...any) error {
+	b.queries++
+	return b.Backend.ExecuteInto(ctx, dst, query, params...)
+}
+
+func (b *countingBackend) Execute(ctx context.Context, query string, params ...any) ([]map[string]any, error) {
+	b.queries++
+	return b.Backend.Execute(ctx, query, params...)
+}
+
+func (b *countingBackend) Exec(ctx context.Context, query string, params ...any) error {
+	b.queries++
+	return b.Backend.Exec(ctx, query, params...)
+}
+
+func TestSDKWithBackend(t *testing.T) {
+	backend := &countingBackend{Backend: setupSampleSDK(t).Connection()}
+	sdk, err := New(WithCacheDir(t.TempDir()), WithOffline(true), WithBackend(backend))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sdk.Close()
+
+	cards, err := sdk.Cards().GetByName(context.Background(), "Lightning Bolt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 1 || backend.queries == 0 {
+		t.Fatalf("expected the card from the backend, got %d cards after %d queries", len(cards), backend.queries)
+	}
+	ctx := context.Background()
+	for name, run := range map[string]func() error{
+		"SQL": func() error {
+			_, err := sdk.SQL(ctx, "SELECT COUNT(*) AS n FROM cards")
+			return err
+		},
+		"Fingerprint": func() error {
+			_, err := sdk.Fingerprint(ctx, "cards")
+			return err
+		},
+		"Tags": func() error {
+			return sdk.Tags().Add(ctx, "card-uuid-001", "favorite", "")
+		},
+	} {
+		backend.queries = 0
+		if err := run(); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if backend.queries == 0 {
+			t.Errorf("expected %s to go through the backend", name)
+		}
+	}
+	if sdk.Connection().HasView("cards") {
+		t.Error("expected the SDK's own connection to be left unused")
+	}
+}
+
+func TestSDKWithDuckDBOption(t *testing.T) {
+	sdk, err := New(WithCacheDir(t.TempDir()), WithOffline(true), WithDuckDBOption("threads", "1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sdk.Close()
+	rows, err := sdk.SQL(context.Background(), "SELECT current_setting('threads') AS threads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || fmt.Sprint(rows[0]["threads"]) != "1" {
+		t.Errorf("expected DuckDB to run with 1 thread, got %v", rows)
+	}
+}
+
+func TestSDKSubscribeChanges(t *testing.T) {
+	sdk := setupSampleSDK(t)
+	if err := os.WriteFile(filepath.Join(sdk.cache.CacheDir, "version.txt"), []byte("5.2.2"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	calls := 0
+	unsubscribe := sdk.SubscribeChanges(func(models.ChangeSet) { calls++ })
+	if len(sdk.changeSubs) != 1 {
+		t.Fatalf("expected one subscriber, got %d", len(sdk.changeSubs))
+	}
+	result, err := sdk.Refresh(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Stale || len(result.Changes) != 0 || calls != 0 {
+		t.Errorf("expected no change sets for a fresh cache, got %+v and %d calls", result, calls)
+	}
+	unsubscribe()
+	if len(sdk.changeSubs) != 0 {
+		t.Errorf("expected unsubscribe to remove the subscriber, got %d", len(sdk.changeSubs))
+	}
+}
+
+func TestSDKWithViewColumns(t *testing.T) {
+	dir := t.TempDir()
+	sdk, err := New(WithCacheDir(dir), WithOffline(true), WithViewColumns("cards", "name", "setCode"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sdk.Close()
+	ctx := context.Background()
+
+	if err := sdk.conn.RegisterTableFromData(ctx, "cards_src", sampleCardsRoot); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "parquet", "cards.parquet")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sdk.SQL(ctx, "COPY cards_src TO "+db.SQLPathLiteral(path)+" (FORMAT PARQUET)"); err != nil {
+		t.Fatal(err)
+	}
+
+	cards, err := sdk.Cards().Search(ctx, queries.SearchCardsParams{Name: "Lightning Bolt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 1 || cards[0].UUID != "card-uuid-001" || cards[0].SetCode != "A25" {
+		t.Fatalf("expected the projected card, got %+v", cards)
+	}
+	if cards[0].Text != nil || len(cards[0].Colors) != 0 {
+		t.Errorf("expected columns outside the list to be empty, got text %v, colors %v", cards[0].Text, cards[0].Colors)
+	}
+	cards, err = sdk.Cards().Search(ctx, queries.SearchCardsParams{Text: "damage"})
+	if err != nil {
+		t.Fatalf("expected a filter on a dropped column to run, got %v", err)
+	}
+	if len(cards) != 0 {
+		t.Errorf("expected no matches on a dropped column, got %d", len(cards))
+	}
+}
+
+func TestSDKAsOf(t *testing.T) {
+	sdk := setupSampleSDK(t)
+	if _, err := sdk.AsOf("5.2.2+20240101"); err == nil {
+		t.Fatal("expected error for a version that was not archived")
+	}
+	if _, err := sdk.AsOf("../parquet"); err == nil {
+		t.Fatal("expected error for an invalid version")
+	}
+
+	dir := filepath.Join(sdk.CacheDir(), "versions", "5.2.2+20240101")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "version.txt"), []byte("5.2.2+20240101"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := sdk.Versions(); len(got) != 1 || got[0] != "5.2.2+20240101" {
+		t.Fatalf("Versions = %v", got)
+	}
+	old, err := sdk.AsOf("5.2.2+20240101")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer old.Close()
+	if _, err := old.Refresh(context.Background()); err == nil {
+		t.Fatal("expected Refresh on an archived SDK to fail")
+	}
+}
+
+func TestSDKAsOfKeepsSettings(t *testing.T) {
+	ctx := context.Background()
+	dsn := filepath.Join(t.TempDir(), "sdk.duckdb")
+	sdk, err := New(WithCacheDir(t.TempDir()), WithOffline(true), WithDuckDBOption("threads", "1"),
+		WithMaterializedLegalities(true), WithDSN(dsn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sdk.Close()
+	dir := filepath.Join(sdk.CacheDir(), "versions", "5.2.2+20240101")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "version.txt"), []byte("5.2.2+20240101"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old, err := sdk.AsOf("5.2.2+20240101")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer old.Close()
+	rows, err := old.SQL(ctx, "SELECT current_setting('threads') AS threads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || fmt.Sprint(rows[0]["threads"]) != "1" {
+		t.Errorf("expected the archived SDK to keep the DuckDB options, got %v", rows)
+	}
+	if old.CacheDir() != dir || !old.cfg.MaterializeLegalities || !old.cache.Offline {
+		t.Errorf("expected the archive's offline cache with the original settings, got %+v", old.cfg)
+	}
+	if want := filepath.Join(filepath.Dir(dsn), "sdk-5.2.2+20240101.duckdb"); old.cfg.DSN != want {
+		t.Errorf("expected the archive in its own DuckDB file %s, got %s", want, old.cfg.DSN)
+	}
+}
+
+func TestWarmCacheUnknownDataset(t *testing.T) {
+	_, err := WarmCache(context.Background(), t.TempDir(), "no_such_view")
+	if err == nil || !strings.Contains(err.Error(), "no_such_view") {
+		t.Fatalf("expected an error naming the dataset, got %v", err)
+	}
+}
+
+func TestSDKRefreshRecordsOutgoingLegalities(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	legalitiesFile := db.ParquetFiles[db.DatasetCardLegalities]
+	scratch, err := New(WithCacheDir(t.TempDir()), WithOffline(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer scratch.Close()
+	writeLegalities := func(path, status string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := scratch.SQL(ctx, fmt.Sprintf("COPY (SELECT 'card-uuid-002' AS uuid, '%s' AS modern) TO %s (FORMAT parquet)",
+			status, db.SQLPathLiteral(path))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The cache holds the January release; the CDN has February's, which
+	// bans the card.
+	writeLegalities(filepath.Join(dir, legalitiesFile), "Legal")
+	newFile := filepath.Join(t.TempDir(), "new.parquet")
+	writeLegalities(newFile, "Banned")
+	newBytes, err := os.ReadFile(newFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		filepath.Join(dir, "Meta.json"):     `{"data":{"version":"5.2.2+20240101","date":"2024-01-01"}}`,
+		filepath.Join(dir, "version.txt"):   "5.2.2+20240101",
+		filepath.Join(dir, "datasets.json"): fmt.Sprintf(`{%q:{"version":"5.2.2+20240101","etag":"\"jan\""},"Meta.json":{"version":"5.2.2+20240101","etag":"\"jan\""}}`, legalitiesFile),
+	}
+	for path, data := range files {
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"feb"`)
+		switch r.URL.Path[1:] {
+		case "Meta.json":
+			fmt.Fprint(w, `{"data":{"version":"5.2.2+20240201","date":"2024-02-01"}}`)
+		case legalitiesFile:
+			w.Write(newBytes)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	sdk, err := New(WithCacheDir(dir), WithMirror(srv.URL), WithLegalityHistory(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sdk.Close()
+	if _, err := sdk.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := sdk.EnsureViews(ctx, db.DatasetLegalityHistory); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := sdk.SQL(ctx, "SELECT status, date FROM legality_history WHERE format = 'modern' ORDER BY date")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0]["status"] != "Legal" || rows[0]["date"] != "2024-01-01" ||
+		rows[1]["status"] != "Banned" || rows[1]["date"] != "2024-02-01" {
+		t.Fatalf("expected January's status then February's ban, got %v", rows)
+	}
+}
@@ -1,9 +1,13 @@
 package mtgjsonsdk
 
 import (
+	"log/slog"
+	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/mtgjson/mtgjson-sdk-go/db"
+	"github.com/mtgjson/mtgjson-sdk-go/queries"
 )
 
 // Option configures the SDK.
@@ -16,6 +20,92 @@ func WithCacheDir(dir string) Option {
 	}
 }
 
+// WithBaseCacheDir sets a read-only cache, such as one baked into a
+// container image, that is consulted for files missing from the cache dir.
+// Newer files are downloaded into the cache dir (which acts as a
+// per-process overlay); the base cache is never written.
+func WithBaseCacheDir(dir string) Option {
+	return func(c *db.Config) {
+		c.BaseCacheDir = dir
+	}
+}
+
+// WithStore keeps downloaded files in an object store or shared directory
+// as well as the cache dir, for deployments without persistent disk such
+// as serverless functions. The cache dir, typically under /tmp, stages files
+// for DuckDB; a cold one is filled from the store when it holds the current
+// MTGJSON release, and from the CDN (uploading to the store) otherwise.
+//
+//	store, err := db.NewS3Store(db.S3Config{Bucket: "my-bucket", Prefix: "mtgjson/"})
+//	sdk, err := mtgjson.New(mtgjson.WithCacheDir("/tmp/mtgjson"), mtgjson.WithStore(store))
+func WithStore(store db.Store) Option {
+	return func(c *db.Config) {
+		c.Store = store
+	}
+}
+
+// WithMirror downloads MTGJSON data from a mirror instead of the MTGJSON
+// CDN. The mirror must lay files out as the CDN's /api/v5 does, including
+// Meta.json.
+func WithMirror(baseURL string) Option {
+	return func(c *db.Config) {
+		c.MirrorURL = baseURL
+	}
+}
+
+// WithFallbackMirrors adds base URLs, laid out like the CDN's /api/v5, to
+// try in order when the CDN (or WithMirror's mirror) fails with a network
+// error, 5xx or 429: e.g. a backup mirror, then an internal cache server.
+// A URL that fails is tried only after the others until its cooldown ends.
+// Headers and WithAuth apply to every URL.
+func WithFallbackMirrors(baseURLs ...string) Option {
+	return func(c *db.Config) {
+		c.FallbackMirrors = append(c.FallbackMirrors, baseURLs...)
+	}
+}
+
+// WithRemoteParquet reads the named views (all parquet views if none are
+// given) straight from the CDN with DuckDB's httpfs extension, fetching
+// only the byte ranges each query needs, instead of downloading whole
+// parquet files first. Views already cached are read locally, and it is
+// ignored offline or with WithHeader or WithAuth, which httpfs cannot send.
+// If httpfs cannot be loaded, the files are downloaded as usual. Remote
+// views skip the sampling that finds new list columns, which would read
+// most of the file, and split only the known ones.
+func WithRemoteParquet(views ...string) Option {
+	return func(c *db.Config) {
+		c.RemoteParquet = true
+		c.RemoteParquetViews = append(c.RemoteParquetViews, views...)
+	}
+}
+
+// WithMirrorCooldown sets how long a failed base URL is tried last;
+// the default is db.DefaultMirrorCooldown.
+func WithMirrorCooldown(d time.Duration) Option {
+	return func(c *db.Config) {
+		c.MirrorCooldown = d
+	}
+}
+
+// WithHeader adds a header to every request to the CDN or mirror, such as an
+// API key for a private mirror.
+func WithHeader(key, value string) Option {
+	return func(c *db.Config) {
+		if c.Headers == nil {
+			c.Headers = make(http.Header)
+		}
+		c.Headers.Add(key, value)
+	}
+}
+
+// WithAuth authorizes every request to the CDN or mirror, for example with
+// db.BearerToken or a function that replaces req.URL with a signed URL.
+func WithAuth(auth db.AuthProvider) Option {
+	return func(c *db.Config) {
+		c.Auth = auth
+	}
+}
+
 // WithOffline disables network requests; only cached data is used.
 func WithOffline(offline bool) Option {
 	return func(c *db.Config) {
@@ -30,9 +120,221 @@ func WithTimeout(d time.Duration) Option {
 	}
 }
 
-// WithProgress sets a callback for download progress reporting.
+// WithStrict makes failures to load optional data, such as prices, return an
+// error instead of being logged and treated as no data.
+func WithStrict(strict bool) Option {
+	return func(c *db.Config) {
+		c.Strict = strict
+	}
+}
+
+// WithCasualLayoutsExcluded makes Cards().Search leave out Planechase planes,
+// Archenemy schemes and Vanguard cards unless a layout is asked for, which
+// suits constructed-format tooling. Cards().Planes and Schemes still work.
+func WithCasualLayoutsExcluded(exclude bool) Option {
+	return func(c *db.Config) {
+		c.ExcludeCasualLayouts = exclude
+	}
+}
+
+// WithOnlineOnlyExcluded makes Sets().List, Sets().Search and
+// Cards().Search leave out sets and cards released only on Arena or MTGO.
+// Params with IncludeAll, or a card search by set code, still see them.
+func WithOnlineOnlyExcluded(exclude bool) Option {
+	return func(c *db.Config) {
+		c.ExcludeOnlineOnly = exclude
+	}
+}
+
+// WithMemorabiliaExcluded makes Sets().List, Sets().Search and
+// Cards().Search leave out memorabilia, funny (Un-) and token sets unless a
+// set type, a set code or IncludeAll is given.
+func WithMemorabiliaExcluded(exclude bool) Option {
+	return func(c *db.Config) {
+		c.ExcludeMemorabilia = exclude
+	}
+}
+
+// WithKeepVersions makes Refresh archive the outgoing MTGJSON release in the
+// cache dir, keeping the k most recent, so SDK.AsOf can query them offline.
+// Unchanged files are hard-linked, so only changed datasets use more disk.
+func WithKeepVersions(k int) Option {
+	return func(c *db.Config) {
+		c.KeepVersions = k
+	}
+}
+
+// WithTransliterator makes Cards().Search match ASCII LocalizedName queries
+// against transliterated foreign names, e.g. romaji for Japanese:
+// queries.Kana handles names written in kana, and a custom Transliterator
+// can add kanji readings from a dictionary.
+func WithTransliterator(t queries.Transliterator) Option {
+	return func(c *db.Config) {
+		c.Transliterator = t
+	}
+}
+
+// WithAffiliateCode adds a query parameter, such as a partner or affiliate
+// code, to purchase URLs for a vendor ("tcgplayer", "cardKingdom" or
+// "cardmarket") returned by PurchaseLinks(). May be given more than once.
+func WithAffiliateCode(vendor, key, value string) Option {
+	return func(c *db.Config) {
+		if c.AffiliateCodes == nil {
+			c.AffiliateCodes = make(map[string]url.Values)
+		}
+		if c.AffiliateCodes[vendor] == nil {
+			c.AffiliateCodes[vendor] = url.Values{}
+		}
+		c.AffiliateCodes[vendor].Add(key, value)
+	}
+}
+
+// WithDSN opens DuckDB with a data source name instead of in memory, e.g. a
+// database file path or "md:my_db" for MotherDuck. Views are created in that
+// database, so a file DSN keeps them between runs.
+func WithDSN(dsn string) Option {
+	return func(c *db.Config) {
+		c.DSN = dsn
+	}
+}
+
+// WithBackend makes the query modules (Cards, Prices, Booster and the
+// rest) read from b instead of the SDK's own DuckDB connection, e.g. a proxy
+// to a shared DuckDB server or a test double. b must accept the DuckDB SQL
+// dialect and expose the SDK's view names; a SQLite database cannot be used.
+// SQL, Fingerprint, Tags and SavedSearches go through b as well; Refresh,
+// ExportDB and Connection still use the SDK's connection, which keeps
+// managing the cache.
+func WithBackend(b db.Backend) Option {
+	return func(c *db.Config) {
+		c.Backend = b
+	}
+}
+
+// WithDuckDBOption sets a DuckDB setting when the database is opened, e.g.
+// WithDuckDBOption("memory_limit", "512MB") or ("threads", "2") to keep
+// DuckDB within a small container. "temp_directory" is where DuckDB spills
+// data that does not fit in memory_limit.
+func WithDuckDBOption(key, value string) Option {
+	return func(c *db.Config) {
+		if c.DuckDBOptions == nil {
+			c.DuckDBOptions = make(map[string]string)
+		}
+		c.DuckDBOptions[key] = value
+	}
+}
+
+// WithViewColumns registers a view with only the listed parquet columns,
+// such as WithViewColumns("cards", "name", "setCode", "manaValue"), so that
+// queries read less data on memory-constrained hosts. Other columns read
+// as NULL: queries still run, but filters on them match nothing and they
+// come back empty in results. "uuid" is always kept.
+func WithViewColumns(view string, columns ...string) Option {
+	return func(c *db.Config) {
+		if c.ViewColumns == nil {
+			c.ViewColumns = make(map[string][]string)
+		}
+		c.ViewColumns[view] = columns
+	}
+}
+
+// WithMaterializedLegalities builds card_legalities once as a table indexed
+// on (format, status, uuid) instead of unpivoting the legalities file on
+// every query, for join-heavy legality queries. With WithDSN pointing at a
+// database file, the table is kept and reused by later processes until the
+// legalities file changes.
+func WithMaterializedLegalities(materialize bool) Option {
+	return func(c *db.Config) {
+		c.MaterializeLegalities = materialize
+	}
+}
+
+// WithLegalityHistory records the legalities of every release Refresh
+// loads into the cache's legality history, starting with the release
+// cached before the first Refresh, for Legalities().StatusAsOf and
+// BanHistory. Only changes are stored. See also SDK.RecordLegalities.
+func WithLegalityHistory(record bool) Option {
+	return func(c *db.Config) {
+		c.LegalityHistory = record
+	}
+}
+
+// WithTempDir sets the directory for the SDK's short-lived temp files,
+// which are kept in its mtgjson-sdk subdirectory. Defaults to os.TempDir().
+func WithTempDir(dir string) Option {
+	return func(c *db.Config) {
+		c.TempDir = dir
+	}
+}
+
+// WithProgress sets a callback for download progress reporting. The callback
+// runs off the download goroutine and may be slow without stalling
+// downloads; see db.ProgressEventFunc for delivery guarantees.
 func WithProgress(fn db.ProgressFunc) Option {
 	return func(c *db.Config) {
 		c.OnProgress = fn
 	}
 }
+
+// WithProgressEvents is WithProgress with each event's transfer rate, ETA,
+// completion and error, as a db.Progress.
+func WithProgressEvents(fn db.ProgressEventFunc) Option {
+	return func(c *db.Config) {
+		c.OnProgressEvent = fn
+	}
+}
+
+// WithDownloadNotice calls fn before a call blocks on a download, with the
+// dataset and its rough size, so callers can tell users why the first
+// query is slow.
+func WithDownloadNotice(fn db.DownloadNoticeFunc) Option {
+	return func(c *db.Config) {
+		c.OnDownload = fn
+	}
+}
+
+// WithNoImplicitDownloads makes queries fail fast with db.ErrWouldDownload
+// when their data is not cached, instead of downloading it. EnsureViews,
+// Refresh, ReloadPrices and PinPrices still download.
+func WithNoImplicitDownloads(fail bool) Option {
+	return func(c *db.Config) {
+		c.NoImplicitDownloads = fail
+	}
+}
+
+// WithMetrics reports downloads, cache hits and query timings to m, e.g. a
+// prommetrics.Collector served on a Prometheus scrape endpoint.
+func WithMetrics(m db.Metrics) Option {
+	return func(c *db.Config) {
+		c.Metrics = m
+	}
+}
+
+// WithRateLimit caps requests to the MTGJSON CDN (or mirror) at perSecond on
+// average, allowing bursts of up to burst requests. All SDKs in the process
+// with the same limit share it, and concurrent Meta.json version checks
+// collapse into one request, so a fleet of instances started together
+// stays polite. Downloads of the same file are already shared.
+func WithRateLimit(perSecond float64, burst int) Option {
+	return func(c *db.Config) {
+		c.RateLimit = perSecond
+		c.RateBurst = burst
+	}
+}
+
+// WithLogger sends the SDK's log records (downloads, view registration,
+// queries at debug level) to l instead of slog.Default.
+func WithLogger(l *slog.Logger) Option {
+	return func(c *db.Config) {
+		c.Logger = l
+	}
+}
+
+// WithLogLevel drops SDK log records below level, e.g. slog.LevelWarn to
+// silence download notices, without changing the program's other logging.
+// It applies to WithLogger's logger, or slog.Default.
+func WithLogLevel(level slog.Leveler) Option {
+	return func(c *db.Config) {
+		c.LogLevel = level
+	}
+}
@@ -0,0 +1,216 @@
+// Package prommetrics exports the SDK's db.Metrics in the Prometheus text
+// exposition format, without depending on the Prometheus client library:
+//
+//	metrics := prommetrics.New()
+//	sdk, err := mtgjson.New(mtgjson.WithMetrics(metrics))
+//	http.Handle("/metrics", metrics)
+//
+// The metrics are:
+//
+//	mtgjson_downloads_total{file, result}       files downloaded; result is "ok" or "error"
+//	mtgjson_download_bytes_total{file}          bytes downloaded
+//	mtgjson_download_duration_seconds           download time histogram
+//	mtgjson_cache_hits_total{file}              files served without a download
+//	mtgjson_queries_total{result}               SQL queries run
+//	mtgjson_query_duration_seconds              query time histogram
+//
+// Programs already using the Prometheus client can instead implement
+// db.Metrics with their own counters and histograms.
+package prommetrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mtgjson/mtgjson-sdk-go/db"
+)
+
+// Default histogram buckets, in seconds.
+var (
+	DownloadBuckets = []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300}
+	QueryBuckets    = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 5}
+)
+
+// Collector implements db.Metrics, keeping the counts in memory, and serves
+// them over HTTP for a Prometheus scrape. It is safe for concurrent use.
+type Collector struct {
+	mu            sync.Mutex
+	downloads     map[[2]string]uint64 // file, result
+	downloadBytes map[string]uint64
+	downloadTime  *histogram
+	cacheHits     map[string]uint64
+	queries       map[string]uint64 // result
+	queryTime     *histogram
+}
+
+var _ db.Metrics = (*Collector)(nil)
+
+// New returns an empty Collector using DownloadBuckets and QueryBuckets.
+func New() *Collector {
+	return &Collector{
+		downloads:     map[[2]string]uint64{},
+		downloadBytes: map[string]uint64{},
+		downloadTime:  newHistogram(DownloadBuckets),
+		cacheHits:     map[string]uint64{},
+		queries:       map[string]uint64{},
+		queryTime:     newHistogram(QueryBuckets),
+	}
+}
+
+// Download implements db.Metrics.
+func (c *Collector) Download(file string, bytes int64, d time.Duration, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.downloads[[2]string{file, result(err)}]++
+	c.downloadBytes[file] += uint64(max(bytes, 0))
+	c.downloadTime.observe(d.Seconds())
+}
+
+// CacheHit implements db.Metrics.
+func (c *Collector) CacheHit(file string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cacheHits[file]++
+}
+
+// Query implements db.Metrics.
+func (c *Collector) Query(d time.Duration, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queries[result(err)]++
+	c.queryTime.observe(d.Seconds())
+}
+
+func result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text format.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+
+	header(cw, "mtgjson_downloads_total", "counter", "Files downloaded from the MTGJSON CDN or mirror.")
+	keys := make([][2]string, 0, len(c.downloads))
+	for k := range c.downloads {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	for _, k := range keys {
+		fmt.Fprintf(cw, "mtgjson_downloads_total{file=%s,result=%s} %d\n", label(k[0]), label(k[1]), c.downloads[k])
+	}
+
+	header(cw, "mtgjson_download_bytes_total", "counter", "Bytes downloaded from the MTGJSON CDN or mirror.")
+	for _, file := range sortedKeys(c.downloadBytes) {
+		fmt.Fprintf(cw, "mtgjson_download_bytes_total{file=%s} %d\n", label(file), c.downloadBytes[file])
+	}
+
+	header(cw, "mtgjson_download_duration_seconds", "histogram", "Time taken by downloads.")
+	c.downloadTime.write(cw, "mtgjson_download_duration_seconds")
+
+	header(cw, "mtgjson_cache_hits_total", "counter", "Files served from the cache or store without a download.")
+	for _, file := range sortedKeys(c.cacheHits) {
+		fmt.Fprintf(cw, "mtgjson_cache_hits_total{file=%s} %d\n", label(file), c.cacheHits[file])
+	}
+
+	header(cw, "mtgjson_queries_total", "counter", "SQL queries run.")
+	for _, r := range sortedKeys(c.queries) {
+		fmt.Fprintf(cw, "mtgjson_queries_total{result=%s} %d\n", label(r), c.queries[r])
+	}
+
+	header(cw, "mtgjson_query_duration_seconds", "histogram", "Time taken by SQL queries.")
+	c.queryTime.write(cw, "mtgjson_query_duration_seconds")
+
+	if err := cw.w.Flush(); err != nil && cw.err == nil {
+		cw.err = err
+	}
+	return cw.n, cw.err
+}
+
+func header(w io.Writer, name, typ, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// label quotes a label value, escaping backslashes, quotes and newlines.
+func label(v string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v) + `"`
+}
+
+func sortedKeys(m map[string]uint64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// histogram is a Prometheus histogram: cumulative bucket counts are
+// computed when written.
+type histogram struct {
+	bounds []float64
+	counts []uint64 // per bucket, not cumulative; the last is +Inf
+	sum    float64
+	count  uint64
+}
+
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{bounds: bounds, counts: make([]uint64, len(bounds)+1)}
+}
+
+func (h *histogram) observe(v float64) {
+	i := sort.SearchFloat64s(h.bounds, v)
+	h.counts[i]++
+	h.sum += v
+	h.count++
+}
+
+func (h *histogram) write(w io.Writer, name string) {
+	var cum uint64
+	for i, bound := range h.bounds {
+		cum += h.counts[i]
+		fmt.Fprintf(w, "%s_bucket{le=%q} %d\n", name, strconv.FormatFloat(bound, 'g', -1, 64), cum)
+	}
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", name, h.count)
+	fmt.Fprintf(w, "%s_sum %s\n", name, strconv.FormatFloat(h.sum, 'g', -1, 64))
+	fmt.Fprintf(w, "%s_count %d\n", name, h.count)
+}
+
+// countingWriter counts bytes written and keeps the first error.
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
@@ -0,0 +1,43 @@
+package prommetrics
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCollectorExposition(t *testing.T) {
+	c := New()
+	c.Download("parquet/cards.parquet", 1000, 2*time.Second, nil)
+	c.Download("parquet/cards.parquet", 0, time.Second, errors.New("HTTP 503"))
+	c.CacheHit(`odd"name`)
+	c.Query(3*time.Millisecond, nil)
+	c.Query(2*time.Second, nil)
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	out := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE mtgjson_downloads_total counter\n",
+		`mtgjson_downloads_total{file="parquet/cards.parquet",result="error"} 1` + "\n",
+		`mtgjson_downloads_total{file="parquet/cards.parquet",result="ok"} 1` + "\n",
+		`mtgjson_download_bytes_total{file="parquet/cards.parquet"} 1000` + "\n",
+		`mtgjson_download_duration_seconds_bucket{le="1"} 1` + "\n",
+		`mtgjson_download_duration_seconds_bucket{le="2.5"} 2` + "\n",
+		"mtgjson_download_duration_seconds_sum 3\n",
+		`mtgjson_cache_hits_total{file="odd\"name"} 1` + "\n",
+		`mtgjson_queries_total{result="ok"} 2` + "\n",
+		`mtgjson_query_duration_seconds_bucket{le="0.005"} 1` + "\n",
+		`mtgjson_query_duration_seconds_bucket{le="+Inf"} 2` + "\n",
+		"mtgjson_query_duration_seconds_count 2\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
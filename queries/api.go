@@ -0,0 +1,249 @@
+package queries
+
+//go:generate go run ./internal/mockgen -o mock/mock.go
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/mtgjson/mtgjson-sdk-go/booster"
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+// The query modules are exposed through the interfaces below so that code
+// using the SDK can be unit tested against package mock instead of DuckDB.
+// Each concrete *XQuery implements its XAPI; run go generate after changing
+// a method set.
+
+// CardAPI is the method set of *CardQuery, returned by SDK.Cards.
+type CardAPI interface {
+	GetByUUID(ctx context.Context, uuid string) (*models.CardSet, error)
+	GetByUUIDs(ctx context.Context, uuids []string) ([]models.CardSet, error)
+	GetByName(ctx context.Context, name string, setCode ...string) ([]models.CardSet, error)
+	Search(ctx context.Context, p SearchCardsParams) ([]models.CardSet, error)
+	SearchToWriter(ctx context.Context, w io.Writer, p SearchCardsParams) error
+	SearchSQL(p SearchCardsParams) (string, []any)
+	GetPrintings(ctx context.Context, name string) ([]models.CardSet, error)
+	Spellbook(ctx context.Context, uuid string) ([]models.CardSet, error)
+	Planes(ctx context.Context) ([]models.CardSet, error)
+	Schemes(ctx context.Context) ([]models.CardSet, error)
+	GetAtomic(ctx context.Context, name string) ([]models.CardAtomic, error)
+	FirstPrinting(ctx context.Context, name string) (*models.FirstPrinting, error)
+	FindByScryfallID(ctx context.Context, scryfallID string) ([]models.CardSet, error)
+	Random(ctx context.Context, count int) ([]models.CardSet, error)
+	Related(ctx context.Context, uuid string, limit int) ([]models.CardSynergy, error)
+	PreconAppearances(ctx context.Context, name string) ([]models.PreconAppearance, error)
+	Count(ctx context.Context, filters ...Filter) (int, error)
+	CountSearch(ctx context.Context, p SearchCardsParams) (int, error)
+}
+
+// SetAPI is the method set of *SetQuery, returned by SDK.Sets.
+type SetAPI interface {
+	Get(ctx context.Context, code string) (*models.SetList, error)
+	Translations(ctx context.Context, code string) (models.Translations, error)
+	Assets(ctx context.Context, code string) (*models.SetAssets, error)
+	CardsInRange(ctx context.Context, code, from, to string) ([]models.CardSet, error)
+	Subsets(ctx context.Context, code string) ([]models.SetSubset, error)
+	GetByLocalizedName(ctx context.Context, name string) (*models.SetList, error)
+	List(ctx context.Context, p ListSetsParams) ([]models.SetList, error)
+	Search(ctx context.Context, p SearchSetsParams) ([]models.SetList, error)
+	GetFinancialSummary(ctx context.Context, setCode string, opts ...FinancialSummaryOption) (*models.FinancialSummary, error)
+	BoxValue(ctx context.Context, setCode, boosterType string, opts ...FinancialSummaryOption) (*models.BoxValue, error)
+	CompletionCost(ctx context.Context, code string, opts ...FinancialSummaryOption) (*models.CompletionCost, error)
+	RarityBreakdown(ctx context.Context, code string) (*models.RarityBreakdown, error)
+	Count(ctx context.Context) (int, error)
+}
+
+// TokenAPI is the method set of *TokenQuery, returned by SDK.Tokens.
+type TokenAPI interface {
+	GetByUUID(ctx context.Context, uuid string) (*models.CardToken, error)
+	GetByUUIDs(ctx context.Context, uuids []string) ([]models.CardToken, error)
+	GetByName(ctx context.Context, name string, setCode ...string) ([]models.CardToken, error)
+	Search(ctx context.Context, p SearchTokensParams) ([]models.CardToken, error)
+	ForSet(ctx context.Context, setCode string) ([]models.CardToken, error)
+	Stickers(ctx context.Context, setCode ...string) ([]models.CardToken, error)
+	Emblems(ctx context.Context, setCode ...string) ([]models.CardToken, error)
+	Dungeons(ctx context.Context, setCode ...string) ([]models.CardToken, error)
+	ArtCards(ctx context.Context, setCode ...string) ([]models.CardToken, error)
+	Count(ctx context.Context, filters ...Filter) (int, error)
+	Generators(ctx context.Context, token string) ([]models.TokenGenerator, error)
+}
+
+// LegalityAPI is the method set of *LegalityQuery, returned by SDK.Legalities.
+type LegalityAPI interface {
+	FormatsForCard(ctx context.Context, uuid string) (map[string]string, error)
+	LegalIn(ctx context.Context, formatName string, limit ...int) ([]models.CardSet, error)
+	IsLegal(ctx context.Context, uuid, formatName string) (bool, error)
+	Wide(ctx context.Context, uuids ...string) ([]map[string]any, error)
+	BannedIn(ctx context.Context, formatName string, limit ...int) ([]models.CardLegality, error)
+	RestrictedIn(ctx context.Context, formatName string, limit ...int) ([]models.CardLegality, error)
+	SuspendedIn(ctx context.Context, formatName string, limit ...int) ([]models.CardLegality, error)
+	NotLegalIn(ctx context.Context, formatName string, limit ...int) ([]models.CardLegality, error)
+	StatusAsOf(ctx context.Context, uuid, formatName, date string) (string, error)
+	BanHistory(ctx context.Context, formatName string) ([]models.LegalityChange, error)
+}
+
+// IdentifierAPI is the method set of *IdentifierQuery, returned by SDK.Identifiers.
+type IdentifierAPI interface {
+	FindBy(ctx context.Context, idType, value string) ([]models.CardSet, error)
+	FindByScryfallID(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByScryfallOracleID(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByScryfallIllustrationID(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByTCGPlayerID(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByTCGPlayerEtchedID(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByMTGOID(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByMTGOFoilID(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByMTGArenaID(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByMultiverseID(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByMCMID(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByMCMMetaID(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByCardKingdomID(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByCardKingdomFoilID(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByCardKingdomEtchedID(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByCardsphereID(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByCardsphereFoilID(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByAny(ctx context.Context, value string) ([]models.IdentifierMatch, error)
+	GetIdentifiers(ctx context.Context, uuid string) (map[string]any, error)
+}
+
+// PriceAPI is the method set of *PriceQuery, returned by SDK.Prices.
+type PriceAPI interface {
+	Get(ctx context.Context, uuid string) (map[string]any, error)
+	Today(ctx context.Context, uuid string, opts ...PriceFilterOption) ([]map[string]any, error)
+	History(ctx context.Context, uuid string, opts ...PriceHistoryOption) ([]map[string]any, error)
+	PriceTrend(ctx context.Context, uuid string, opts ...PriceFilterOption) (*models.PriceTrend, error)
+	CheapestPrinting(ctx context.Context, name string, opts ...PriceFilterOption) (map[string]any, error)
+	CheapestPrintings(ctx context.Context, opts ...PriceListOption) ([]models.PricePrinting, error)
+	MostExpensivePrintings(ctx context.Context, opts ...PriceListOption) ([]models.ExpensivePrinting, error)
+	Spread(ctx context.Context, uuid string, opts ...PriceFilterOption) ([]models.PriceSpread, error)
+	TopSpreads(ctx context.Context, opts ...PriceListOption) ([]models.PriceSpread, error)
+	ByVendor(ctx context.Context, uuid string) ([]models.VendorPrice, error)
+	ValueMetrics(ctx context.Context, p ValueMetricsParams) ([]models.CardValue, error)
+	ExportCardmarket(ctx context.Context, w io.Writer, opts ...ExportOption) error
+}
+
+// DeckAPI is the method set of *DeckQuery, returned by SDK.Decks.
+type DeckAPI interface {
+	List(ctx context.Context, params ListDecksParams) ([]models.DeckList, error)
+	Search(ctx context.Context, params SearchDecksParams) ([]models.DeckList, error)
+	Count(ctx context.Context) (int, error)
+	Upgrades(ctx context.Context, deckFile string, budget float64) ([]models.DeckUpgrade, error)
+}
+
+// EnumAPI is the method set of *EnumQuery, returned by SDK.Enums.
+type EnumAPI interface {
+	Keywords(ctx context.Context) (map[string]any, error)
+	CardTypes(ctx context.Context) (map[string]any, error)
+	EnumValues(ctx context.Context) (map[string]any, error)
+}
+
+// SkuAPI is the method set of *SkuQuery, returned by SDK.Skus.
+type SkuAPI interface {
+	Get(ctx context.Context, uuid string, opts ...SkuOption) ([]models.TcgplayerSkus, error)
+	Count(ctx context.Context, uuid string, opts ...SkuOption) (int, error)
+	FindBySkuID(ctx context.Context, skuID int) (map[string]any, error)
+	FindByProductID(ctx context.Context, productID int) ([]map[string]any, error)
+}
+
+// SealedAPI is the method set of *SealedQuery, returned by SDK.Sealed.
+type SealedAPI interface {
+	List(ctx context.Context, params ListSealedParams) ([]map[string]any, error)
+	Categories(ctx context.Context) ([]models.SealedCategoryCount, error)
+	Get(ctx context.Context, uuid string) (map[string]any, error)
+}
+
+// FormatAPI is the method set of *FormatQuery, returned by SDK.Formats.
+type FormatAPI interface {
+	CurrentStandardSets(ctx context.Context) ([]models.SetList, error)
+	StandardSetsAt(ctx context.Context, at time.Time) ([]models.SetList, error)
+	RotationDate(ctx context.Context, setCode string) (*time.Time, error)
+	WillRotateBy(ctx context.Context, date time.Time) ([]models.SetList, error)
+	PioneerSets(ctx context.Context) ([]models.SetList, error)
+}
+
+// TradeAPI is the method set of *TradeQuery, returned by SDK.Trades.
+type TradeAPI interface {
+	Match(ctx context.Context, have, want []models.CollectionEntry, opts ...PriceFilterOption) ([]models.TradeItem, error)
+	Propose(ctx context.Context, mine, theirs models.TradeParty, opts ...PriceFilterOption) (*models.TradeProposal, error)
+}
+
+// CollectionAPI is the method set of *CollectionQuery, returned by SDK.Collections.
+type CollectionAPI interface {
+	ImportCSV(ctx context.Context, r io.Reader, format CSVFormat, opts ...ExportOption) ([]models.CollectionEntry, error)
+	ExportCSV(ctx context.Context, w io.Writer, format CSVFormat, entries []models.CollectionEntry, opts ...ExportOption) error
+}
+
+// TagAPI is the method set of *TagQuery, returned by SDK.Tags.
+type TagAPI interface {
+	Add(ctx context.Context, uuid, tag, note string) error
+	Remove(ctx context.Context, uuid, tag string) error
+	ForUUID(ctx context.Context, uuid string) ([]models.Tag, error)
+	List(ctx context.Context) (map[string]int, error)
+	Tagged(ctx context.Context, tag string) ([]models.CardSet, error)
+}
+
+// SavedSearchAPI is the method set of *SavedSearchQuery, returned by SDK.SavedSearches.
+type SavedSearchAPI interface {
+	Save(ctx context.Context, name string, p SearchCardsParams) error
+	Get(ctx context.Context, name string) (*SearchCardsParams, error)
+	List(ctx context.Context) ([]string, error)
+	Delete(ctx context.Context, name string) error
+	Run(ctx context.Context, name string, vars map[string]string) ([]models.CardSet, error)
+}
+
+// PurchaseAPI is the method set of *PurchaseQuery, returned by SDK.PurchaseLinks.
+type PurchaseAPI interface {
+	Links(ctx context.Context, uuid string) ([]models.PurchaseLink, error)
+	Resolve(ctx context.Context, vendor, link string) (string, error)
+}
+
+// SubtypeAPI is the method set of *SubtypeQuery, returned by SDK.Subtypes.
+type SubtypeAPI interface {
+	Census(ctx context.Context, subtype string, opts ...CensusOption) (*models.SubtypeCensus, error)
+}
+
+// ForeignDataAPI is the method set of *ForeignDataQuery, returned by SDK.ForeignData.
+type ForeignDataAPI interface {
+	Languages(ctx context.Context) ([]models.LanguageCount, error)
+	CoverageForSet(ctx context.Context, code string) (*models.LanguageCoverage, error)
+	Availability(ctx context.Context, name string) (*models.CardAvailability, error)
+}
+
+// BoosterAPI is the method set of *booster.BoosterSimulator, returned by SDK.Booster.
+type BoosterAPI interface {
+	Configs(ctx context.Context, setCode string) (map[string]models.BoosterConfig, error)
+	AvailableTypes(ctx context.Context, setCode string) ([]string, error)
+	Catalog(ctx context.Context) (map[string][]string, error)
+	Config(ctx context.Context, setCode, boosterType string) (*models.BoosterConfig, error)
+	OpenPack(ctx context.Context, setCode, boosterType string) ([]models.CardSet, error)
+	OpenBox(ctx context.Context, setCode, boosterType string, packs int) ([][]models.CardSet, error)
+	OpenBoxSummary(ctx context.Context, setCode, boosterType string, packs int, opts ...booster.BoxOption) (*models.BoosterBox, error)
+	OpenPacksParallel(ctx context.Context, setCode, boosterType string, n int, opts ...booster.BoxOption) (*models.PackBatch, error)
+	SimulateBoxes(ctx context.Context, setCode, boosterType string, packs, boxes int, opts ...booster.BoxOption) (*models.BoxDistribution, error)
+	OpenJumpstartPack(ctx context.Context, setCode string) (*models.JumpstartPack, error)
+	SheetContents(ctx context.Context, setCode, boosterType, sheetName string) (map[string]int, error)
+	ExpectedValue(ctx context.Context, setCode, boosterType, provider string) (float64, error)
+}
+
+var (
+	_ CardAPI        = (*CardQuery)(nil)
+	_ SetAPI         = (*SetQuery)(nil)
+	_ TokenAPI       = (*TokenQuery)(nil)
+	_ LegalityAPI    = (*LegalityQuery)(nil)
+	_ IdentifierAPI  = (*IdentifierQuery)(nil)
+	_ PriceAPI       = (*PriceQuery)(nil)
+	_ DeckAPI        = (*DeckQuery)(nil)
+	_ EnumAPI        = (*EnumQuery)(nil)
+	_ SkuAPI         = (*SkuQuery)(nil)
+	_ SealedAPI      = (*SealedQuery)(nil)
+	_ FormatAPI      = (*FormatQuery)(nil)
+	_ TradeAPI       = (*TradeQuery)(nil)
+	_ CollectionAPI  = (*CollectionQuery)(nil)
+	_ TagAPI         = (*TagQuery)(nil)
+	_ SavedSearchAPI = (*SavedSearchQuery)(nil)
+	_ PurchaseAPI    = (*PurchaseQuery)(nil)
+	_ SubtypeAPI     = (*SubtypeQuery)(nil)
+	_ ForeignDataAPI = (*ForeignDataQuery)(nil)
+	_ BoosterAPI     = (*booster.BoosterSimulator)(nil)
+)
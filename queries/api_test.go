@@ -0,0 +1,44 @@
+package queries
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestAPIMethodSets fails when a query module gains an exported method that
+// its XAPI interface (and so package mock) does not declare.
+func TestAPIMethodSets(t *testing.T) {
+	pairs := []struct {
+		concrete, api any
+	}{
+		{(*CardQuery)(nil), (*CardAPI)(nil)},
+		{(*SetQuery)(nil), (*SetAPI)(nil)},
+		{(*TokenQuery)(nil), (*TokenAPI)(nil)},
+		{(*LegalityQuery)(nil), (*LegalityAPI)(nil)},
+		{(*IdentifierQuery)(nil), (*IdentifierAPI)(nil)},
+		{(*PriceQuery)(nil), (*PriceAPI)(nil)},
+		{(*DeckQuery)(nil), (*DeckAPI)(nil)},
+		{(*EnumQuery)(nil), (*EnumAPI)(nil)},
+		{(*SkuQuery)(nil), (*SkuAPI)(nil)},
+		{(*SealedQuery)(nil), (*SealedAPI)(nil)},
+		{(*FormatQuery)(nil), (*FormatAPI)(nil)},
+		{(*TradeQuery)(nil), (*TradeAPI)(nil)},
+		{(*CollectionQuery)(nil), (*CollectionAPI)(nil)},
+		{(*TagQuery)(nil), (*TagAPI)(nil)},
+		{(*SavedSearchQuery)(nil), (*SavedSearchAPI)(nil)},
+		{(*PurchaseQuery)(nil), (*PurchaseAPI)(nil)},
+		{(*SubtypeQuery)(nil), (*SubtypeAPI)(nil)},
+	}
+	for _, p := range pairs {
+		concrete := reflect.TypeOf(p.concrete)
+		api := reflect.TypeOf(p.api).Elem()
+		if concrete.NumMethod() != api.NumMethod() {
+			for i := 0; i < concrete.NumMethod(); i++ {
+				name := concrete.Method(i).Name
+				if _, ok := api.MethodByName(name); !ok {
+					t.Errorf("%s.%s is missing from %s; add it and run go generate", concrete.Elem().Name(), name, api.Name())
+				}
+			}
+		}
+	}
+}
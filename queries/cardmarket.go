@@ -0,0 +1,86 @@
+package queries
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mtgjson/mtgjson-sdk-go/db"
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+// cardmarketHeader is the header row written by ExportCardmarket.
+var cardmarketHeader = []string{"mcmId", "name", "set", "number", "price", "foilPrice", "currency"}
+
+// ExportCardmarket writes a Cardmarket product mapping as CSV: one row per
+// printing with a Cardmarket ID, with its latest Cardmarket retail price for
+// the normal and foil finish. Rows are streamed from DuckDB as they are read.
+// Prices are left empty when unavailable. Restrict to sets with
+// WithExportSets and pick the decimal separator with WithExportLocale.
+func (q *PriceQuery) ExportCardmarket(ctx context.Context, w io.Writer, opts ...ExportOption) error {
+	cfg := newExportConfig(opts)
+	if err := q.conn.EnsureViews(ctx, "cards", "card_identifiers"); err != nil {
+		return err
+	}
+	if err := q.ensure(ctx); err != nil {
+		return err
+	}
+
+	prices := "SELECT NULL::VARCHAR AS uuid, NULL::VARCHAR AS finish, NULL::DOUBLE AS price, NULL::VARCHAR AS currency WHERE false"
+	if q.conn.HasView("all_prices_today") {
+		prices = "SELECT uuid, finish, CAST(price AS DOUBLE) AS price, currency FROM " + db.LatestPricesTable + " " +
+			"WHERE provider = 'cardmarket' AND price_type = 'retail'"
+	}
+	parts := []string{
+		"WITH mcm AS (" + prices + ")",
+		"SELECT CAST(i.mcmId AS VARCHAR) AS mcmId, c.name, c.setCode, c.number,",
+		"  MAX(m.price) FILTER (WHERE m.finish = 'normal') AS price,",
+		"  MAX(m.price) FILTER (WHERE m.finish = 'foil') AS foilPrice,",
+		"  ANY_VALUE(m.currency) AS currency",
+		"FROM card_identifiers i",
+		"JOIN cards c ON c.uuid = i.uuid",
+		"LEFT JOIN mcm m ON m.uuid = i.uuid",
+		"WHERE i.mcmId IS NOT NULL",
+	}
+	var params []any
+	if len(cfg.setCodes) > 0 {
+		placeholders := make([]string, len(cfg.setCodes))
+		for i, code := range cfg.setCodes {
+			params = append(params, strings.ToUpper(code))
+			placeholders[i] = "$" + strconv.Itoa(i+1)
+		}
+		parts = append(parts, "AND UPPER(c.setCode) IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	parts = append(parts,
+		"GROUP BY i.mcmId, c.name, c.setCode, c.number",
+		"ORDER BY c.setCode, c.number, c.name",
+	)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(cardmarketHeader); err != nil {
+		return err
+	}
+	err := q.conn.ExecuteEach(ctx, func(row map[string]any) error {
+		mcmID, _ := row["mcmId"].(string)
+		name, _ := row["name"].(string)
+		set, _ := row["setCode"].(string)
+		number, _ := row["number"].(string)
+		currency, _ := row["currency"].(string)
+		return cw.Write([]string{mcmID, name, set, number,
+			csvPrice(row["price"], cfg.locale), csvPrice(row["foilPrice"], cfg.locale), currency})
+	}, strings.Join(parts, " "), params...)
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvPrice(p any, locale models.Locale) string {
+	if p == nil {
+		return ""
+	}
+	return locale.FormatPrice(db.ToFloat64(p))
+}
@@ -0,0 +1,78 @@
+package queries
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+var sampleCardmarketPrices = []map[string]any{
+	{
+		"uuid": "card-uuid-001", "source": "paper", "provider": "cardmarket",
+		"currency": "EUR", "price_type": "retail", "finish": "normal",
+		"date": "2024-01-02", "price": 1.10,
+	},
+	{
+		"uuid": "card-uuid-001", "source": "paper", "provider": "cardmarket",
+		"currency": "EUR", "price_type": "retail", "finish": "normal",
+		"date": "2024-01-03", "price": 1.25,
+	},
+	{
+		"uuid": "card-uuid-001", "source": "paper", "provider": "cardmarket",
+		"currency": "EUR", "price_type": "retail", "finish": "foil",
+		"date": "2024-01-03", "price": 3.5,
+	},
+}
+
+func TestExportCardmarket(t *testing.T) {
+	pq := setupPriceQuery(t, sampleCardmarketPrices...)
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	if err := pq.ExportCardmarket(ctx, &buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "mcmId,name,set,number,price,foilPrice,currency\n" +
+		"mcm-001,Lightning Bolt,A25,141,1.25,3.50,EUR\n" +
+		"mcm-002,Counterspell,MH2,267,,,\n"
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected export:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestExportCardmarketLocale(t *testing.T) {
+	pq := setupPriceQuery(t, sampleCardmarketPrices...)
+	var buf bytes.Buffer
+	if err := pq.ExportCardmarket(context.Background(), &buf, WithExportLocale(models.LocaleGerman)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `mcm-001,Lightning Bolt,A25,141,"1,25","3,50",EUR`) {
+		t.Errorf("expected decimal commas, got:\n%s", buf.String())
+	}
+}
+
+func TestExportCardmarketSetFilter(t *testing.T) {
+	pq := setupPriceQuery(t)
+	var buf bytes.Buffer
+	if err := pq.ExportCardmarket(context.Background(), &buf, WithExportSets("mh2")); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], "mcm-002,") {
+		t.Errorf("expected only the MH2 row, got %q", lines)
+	}
+}
+
+func TestExportCardmarketWithoutPrices(t *testing.T) {
+	pq := &PriceQuery{conn: setupSampleDB(t)}
+	var buf bytes.Buffer
+	if err := pq.ExportCardmarket(context.Background(), &buf); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(buf.String(), "\n"); n != 3 {
+		t.Errorf("expected a header and 2 rows without prices, got %q", buf.String())
+	}
+}
@@ -2,49 +2,119 @@ package queries
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/mtgjson/mtgjson-sdk-go/db"
+	"github.com/mtgjson/mtgjson-sdk-go/manacost"
 	"github.com/mtgjson/mtgjson-sdk-go/models"
 )
 
 // SearchCardsParams contains all optional filters for card search.
 // Zero values are ignored. Use pointer types for fields where zero is a valid filter.
 type SearchCardsParams struct {
-	Name          string
-	FuzzyName     string
-	LocalizedName string
-	SetCode       string
-	Colors        []string
-	ColorIdentity []string
-	Types         string
-	Rarity        string
-	LegalIn       string
-	ManaValue     *float64
-	ManaValueLTE  *float64
-	ManaValueGTE  *float64
-	Text          string
-	TextRegex     string
-	Power         string
-	Toughness     string
-	Artist        string
-	Keyword       string
-	IsPromo       *bool
-	Availability  string
-	Language      string
-	Layout        string
-	SetType       string
-	Limit         int // 0 means default (100)
-	Offset        int
+	Name             string
+	FuzzyName        string
+	LocalizedName    string
+	SetCode          string
+	Colors           []string
+	ColorIdentity    []string
+	ColorIdentityOp  ColorMatch // how ColorIdentity is compared; default ColorAtLeast
+	Types            string
+	Rarity           string
+	LegalIn          string
+	LegalInAll       []string // legal in every one of these formats, as well as LegalIn
+	LegalInAny       []string // legal in at least one of these formats
+	LegalStatus      []string // statuses that count as legal for LegalIn*; default "Legal"
+	ManaValue        *float64
+	ManaValueLTE     *float64
+	ManaValueGTE     *float64
+	NumberGTE        string // collector number at least this, numeric-aware: "9" < "10" < "10a"
+	NumberLTE        string // collector number at most this, numeric-aware
+	Text             string
+	TextRegex        string
+	ManaSymbols      []manacost.Predicate // mana cost has a symbol matching each, e.g. manacost.IsPhyrexian
+	RulingText       string               // a ruling (card_rulings) mentions this, case-insensitively, e.g. "layers"
+	Power            string
+	Toughness        string
+	Artist           string
+	Keyword          string // in keywords, or in the rules text of printings missing them
+	IsPromo          *bool
+	Availability     string
+	InBoosters       *bool  // true: only cards found in boosters; false: only cards that are not
+	BoosterType      string // only cards found in this booster type, e.g. "default" or "deck"
+	Subset           string // only cards in this subset (bonus sheet), e.g. "Multiverse Legends"
+	AttractionLights []int  // Attractions lit on all of these numbers (1-6)
+	Language         string
+	Layout           string
+	SetType          string
+	UniqueNames      bool // one printing per name, from the latest set, instead of every printing
+	IncludeAll       bool // ignore the query's SetExclusions
+	Limit            int  // 0 means default (100)
+	Offset           int
 }
 
+// ColorMatch is how SearchCardsParams.ColorIdentity is compared with a
+// card's color identity.
+type ColorMatch string
+
+const (
+	ColorAtLeast ColorMatch = "at_least" // has every listed color, maybe more (the default)
+	ColorExactly ColorMatch = "exactly"  // has the listed colors and no others
+	ColorAtMost  ColorMatch = "at_most"  // has no color outside the list, e.g. playable in a Jeskai Commander deck
+)
+
+// wubrg are the five colors, in color-pie order.
+var wubrg = []string{"W", "U", "B", "R", "G"}
+
+// casualLayouts are the layouts of cards played only in casual variants:
+// Planechase planes and phenomena, Archenemy schemes and Vanguard avatars.
+var casualLayouts = []any{"planar", "scheme", "vanguard"}
+
 // CardQuery provides methods to search, filter, and retrieve card data.
+// Printings it returns without keywords get them from their rules text
+// (see WithKeywords), except from SearchToWriter, which writes rows as
+// stored.
 type CardQuery struct {
-	conn *db.Connection
+	conn          db.Backend
+	excludeCasual bool
+	excludeSets   SetExclusions
+	translit      Transliterator
+	enums         *EnumQuery
+
+	keywordsMu sync.Mutex    // guards keywords
+	keywords   []textKeyword // loaded by textKeywords
+
+	translitMu      sync.Mutex // guards the two fields below
+	translitChecked bool       // ensureForeignASCII has run
+	hasForeignASCII bool       // foreignASCIITable exists
+}
+
+// CardQueryOption configures a CardQuery.
+type CardQueryOption func(*CardQuery)
+
+// WithCasualLayoutsExcluded makes Search skip planes, phenomena, schemes and
+// Vanguard cards unless SearchCardsParams.Layout asks for one of them.
+func WithCasualLayoutsExcluded(exclude bool) CardQueryOption {
+	return func(q *CardQuery) { q.excludeCasual = exclude }
+}
+
+// WithCardSetExclusions makes Search and CountSearch leave out cards from
+// the sets e excludes unless SearchCardsParams sets IncludeAll or SetCode.
+func WithCardSetExclusions(e SetExclusions) CardQueryOption {
+	return func(q *CardQuery) { q.excludeSets = e }
 }
 
-func NewCardQuery(conn *db.Connection) *CardQuery {
-	return &CardQuery{conn: conn}
+func NewCardQuery(conn db.Backend, opts ...CardQueryOption) *CardQuery {
+	q := &CardQuery{conn: conn}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
 }
 
 // GetByUUID returns a single card by its MTGJSON UUID, or nil if not found.
@@ -59,6 +129,7 @@ func (q *CardQuery) GetByUUID(ctx context.Context, uuid string) (*models.CardSet
 	if len(cards) == 0 {
 		return nil, nil
 	}
+	q.normalizeKeywords(ctx, cards)
 	return &cards[0], nil
 }
 
@@ -81,6 +152,7 @@ func (q *CardQuery) GetByUUIDs(ctx context.Context, uuids []string) ([]models.Ca
 	if err := q.conn.ExecuteInto(ctx, &cards, sql, params...); err != nil {
 		return nil, err
 	}
+	q.normalizeKeywords(ctx, cards)
 	return cards, nil
 }
 
@@ -99,14 +171,122 @@ func (q *CardQuery) GetByName(ctx context.Context, name string, setCode ...strin
 	if err := q.conn.ExecuteInto(ctx, &cards, sql, params...); err != nil {
 		return nil, err
 	}
+	q.normalizeKeywords(ctx, cards)
 	return cards, nil
 }
 
-// Search searches cards with flexible filters.
+// Search searches cards with flexible filters. The Keyword filter matches
+// printings without keywords on their rules text as well.
 func (q *CardQuery) Search(ctx context.Context, p SearchCardsParams) ([]models.CardSet, error) {
-	if err := q.conn.EnsureViews(ctx, "cards"); err != nil {
+	if err := q.conn.EnsureViews(ctx, q.searchViews(p)...); err != nil {
+		return nil, err
+	}
+	if err := q.ensureForeignASCII(ctx, p); err != nil {
+		return nil, err
+	}
+	if p.Keyword != "" {
+		q.textKeywords(ctx)
+	}
+	if p.FuzzyName != "" && !q.conn.Capabilities().JaroWinkler {
+		return q.searchFuzzyFallback(ctx, p)
+	}
+	sql, params := q.SearchSQL(p)
+	var cards []models.CardSet
+	if err := q.conn.ExecuteInto(ctx, &cards, sql, params...); err != nil {
 		return nil, err
 	}
+	q.normalizeKeywords(ctx, cards)
+	return cards, nil
+}
+
+// SearchToWriter writes the cards Search would return to w as NDJSON, one
+// card per line, streaming rows instead of building the result, so large
+// exports (with a high Limit) can be piped to a file or an HTTP response in
+// constant memory. Rows are written as stored, so keywords are not filled
+// in from rules text.
+func (q *CardQuery) SearchToWriter(ctx context.Context, w io.Writer, p SearchCardsParams) error {
+	if err := q.conn.EnsureViews(ctx, q.searchViews(p)...); err != nil {
+		return err
+	}
+	if err := q.ensureForeignASCII(ctx, p); err != nil {
+		return err
+	}
+	if p.Keyword != "" {
+		q.textKeywords(ctx)
+	}
+	if p.FuzzyName != "" && !q.conn.Capabilities().JaroWinkler {
+		cards, err := q.searchFuzzyFallback(ctx, p)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(w)
+		for _, c := range cards {
+			if err := enc.Encode(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	sql, params := q.SearchSQL(p)
+	return db.WriteNDJSON(ctx, q.conn, w, sql, params...)
+}
+
+// searchViews returns the views a search reads.
+func (q *CardQuery) searchViews(p SearchCardsParams) []string {
+	views := []string{"cards"}
+	if p.LocalizedName != "" {
+		views = append(views, "card_foreign_data")
+	}
+	if p.LegalIn != "" || len(p.LegalInAll) > 0 || len(p.LegalInAny) > 0 {
+		views = append(views, "card_legalities")
+	}
+	if p.RulingText != "" {
+		views = append(views, "card_rulings")
+	}
+	if p.SetType != "" || p.UniqueNames || q.excludesSets(p) && q.excludeSets.Memorabilia {
+		views = append(views, "sets")
+	}
+	return views
+}
+
+// SearchSQL returns the SQL and parameters Search would run for p, for
+// auditing, logging or hand-tuning. The query reads the cards view, plus
+// card_foreign_data, card_legalities, card_rulings or sets for LocalizedName,
+// LegalIn*, RulingText, SetType, UniqueNames and memorabilia exclusions,
+// which sdk.SQL loads itself.
+// Once a search has built card_foreign_ascii (see WithTransliterator), an
+// ASCII LocalizedName reads that table too. A Keyword filter uses the
+// Keywords.json loaded by an earlier Search, CountSearch or SearchToWriter,
+// or the embedded keywords if none has run.
+func (q *CardQuery) SearchSQL(p SearchCardsParams) (string, []any) {
+	b := q.searchBuilder(p)
+	if p.FuzzyName != "" {
+		idx := b.AddParam(p.FuzzyName)
+		b.OrderBy(
+			fmt.Sprintf("jaro_winkler_similarity(cards.name, $%d) DESC", idx),
+			"cards.number ASC",
+		)
+	} else {
+		b.OrderBy("cards.name ASC", "cards.number ASC")
+	}
+	b.Limit(searchLimit(p)).Offset(p.Offset)
+	return b.Build()
+}
+
+func searchLimit(p SearchCardsParams) int {
+	if p.Limit <= 0 {
+		return 100
+	}
+	return p.Limit
+}
+
+// excludesSets reports whether the query's SetExclusions apply to p.
+func (q *CardQuery) excludesSets(p SearchCardsParams) bool {
+	return q.excludeSets.any() && !p.IncludeAll && p.SetCode == ""
+}
+
+// searchBuilder applies p's filters, without ordering or paging.
+func (q *CardQuery) searchBuilder(p SearchCardsParams) *db.SQLBuilder {
 	b := db.NewSQLBuilder("cards")
 
 	if p.Name != "" {
@@ -134,12 +314,25 @@ func (q *CardQuery) Search(ctx context.Context, p SearchCardsParams) ([]models.C
 	if p.ManaValueGTE != nil {
 		b.WhereGTE("manaValue", *p.ManaValueGTE)
 	}
+	if p.NumberGTE != "" {
+		whereNumber(b, ">", p.NumberGTE)
+	}
+	if p.NumberLTE != "" {
+		whereNumber(b, "<", p.NumberLTE)
+	}
 	if p.Text != "" {
 		b.WhereLike("text", "%"+p.Text+"%")
 	}
 	if p.TextRegex != "" {
 		b.WhereRegex("text", p.TextRegex)
 	}
+	for _, pred := range p.ManaSymbols {
+		b.WhereRegex("manaCost", pred.Pattern())
+	}
+	if p.RulingText != "" {
+		idx := b.AddParam("%" + p.RulingText + "%")
+		b.AddWhere(fmt.Sprintf("cards.uuid IN (SELECT uuid FROM card_rulings WHERE text ILIKE $%d)", idx))
+	}
 	if p.Types != "" {
 		b.WhereLike("type", "%"+p.Types+"%")
 	}
@@ -157,6 +350,8 @@ func (q *CardQuery) Search(ctx context.Context, p SearchCardsParams) ([]models.C
 	}
 	if p.Layout != "" {
 		b.WhereEq("layout", p.Layout)
+	} else if q.excludeCasual {
+		b.Where("layout NOT IN ($1, $2, $3)", casualLayouts...)
 	}
 	if p.IsPromo != nil {
 		if *p.IsPromo {
@@ -171,76 +366,231 @@ func (q *CardQuery) Search(ctx context.Context, p SearchCardsParams) ([]models.C
 			b.AddWhere(fmt.Sprintf("list_contains(colors, $%d)", idx))
 		}
 	}
-	if len(p.ColorIdentity) > 0 {
-		for _, color := range p.ColorIdentity {
-			idx := b.AddParam(color)
-			b.AddWhere(fmt.Sprintf("list_contains(colorIdentity, $%d)", idx))
-		}
-	}
+	colorIdentityFilter(b, p.ColorIdentity, p.ColorIdentityOp)
 	if p.Keyword != "" {
-		idx := b.AddParam(p.Keyword)
-		b.AddWhere(fmt.Sprintf("list_contains(keywords, $%d)", idx))
+		if k, ok := lookupTextKeyword(q.loadedTextKeywords(), p.Keyword); ok {
+			// Printings missing the keywords column match on their text.
+			idx := b.AddParam(k.name)
+			re := b.AddParam(k.pattern)
+			b.AddWhere(fmt.Sprintf("(list_contains(keywords, $%d) OR (len(COALESCE(keywords, [])) = 0 "+
+				"AND regexp_matches(regexp_replace(COALESCE(text, ''), '%s', '', 'g'), $%d)))", idx, reminderTextSQL, re))
+		} else {
+			idx := b.AddParam(p.Keyword)
+			b.AddWhere(fmt.Sprintf("list_contains(keywords, $%d)", idx))
+		}
 	}
 	if p.Availability != "" {
 		idx := b.AddParam(p.Availability)
 		b.AddWhere(fmt.Sprintf("list_contains(availability, $%d)", idx))
 	}
-	if p.LocalizedName != "" {
-		if err := q.conn.EnsureViews(ctx, "card_foreign_data"); err != nil {
-			return nil, err
+	if p.InBoosters != nil {
+		if *p.InBoosters {
+			b.AddWhere("len(boosterTypes) > 0")
+		} else {
+			b.AddWhere("(boosterTypes IS NULL OR len(boosterTypes) = 0)")
 		}
+	}
+	if p.BoosterType != "" {
+		idx := b.AddParam(p.BoosterType)
+		b.AddWhere(fmt.Sprintf("list_contains(boosterTypes, $%d)", idx))
+	}
+	if p.Subset != "" {
+		idx := b.AddParam(p.Subset)
+		b.AddWhere(fmt.Sprintf("list_contains(subsets, $%d)", idx))
+	}
+	for _, light := range p.AttractionLights {
+		idx := b.AddParam(light)
+		b.AddWhere(fmt.Sprintf("list_contains(attractionLights, $%d)", idx))
+	}
+	if p.LocalizedName != "" {
 		b.Select("cards.*")
 		b.Join("JOIN card_foreign_data cfd ON cards.uuid = cfd.uuid")
-		if containsWildcard(p.LocalizedName) {
+		wildcard := containsWildcard(p.LocalizedName)
+		q.translitMu.Lock()
+		hasASCII := q.hasForeignASCII
+		q.translitMu.Unlock()
+		switch {
+		case hasASCII && wantsForeignASCII(p) && wildcard:
+			b.Where("(LOWER(cfd.name) LIKE LOWER($1) OR cfd.name IN (SELECT name FROM "+foreignASCIITable+" WHERE ascii LIKE $2))",
+				p.LocalizedName, foldASCII(p.LocalizedName))
+		case hasASCII && wantsForeignASCII(p):
+			b.Where("(cfd.name = $1 OR cfd.name IN (SELECT name FROM "+foreignASCIITable+" WHERE ascii = $2))",
+				p.LocalizedName, foldASCII(p.LocalizedName))
+		case wildcard:
 			b.WhereLike("cfd.name", p.LocalizedName)
-		} else {
+		default:
 			b.WhereEq("cfd.name", p.LocalizedName)
 		}
 	}
+	statuses := p.LegalStatus
+	if len(statuses) == 0 {
+		statuses = []string{"Legal"}
+	}
+	all := p.LegalInAll
 	if p.LegalIn != "" {
-		if err := q.conn.EnsureViews(ctx, "card_legalities"); err != nil {
-			return nil, err
-		}
-		b.Join("JOIN card_legalities cl ON cards.uuid = cl.uuid")
-		b.WhereEq("cl.format", p.LegalIn)
-		b.WhereEq("cl.status", "Legal")
+		all = append([]string{p.LegalIn}, all...)
+	}
+	if len(all) > 0 {
+		formats := slices.Compact(slices.Sorted(slices.Values(all)))
+		b.AddWhere(fmt.Sprintf(
+			"cards.uuid IN (SELECT uuid FROM card_legalities WHERE format IN (%s) AND status IN (%s) "+
+				"GROUP BY uuid HAVING COUNT(DISTINCT format) = %d)",
+			inParams(b, formats), inParams(b, statuses), len(formats)))
+	}
+	if len(p.LegalInAny) > 0 {
+		b.AddWhere(fmt.Sprintf(
+			"cards.uuid IN (SELECT uuid FROM card_legalities WHERE format IN (%s) AND status IN (%s))",
+			inParams(b, p.LegalInAny), inParams(b, statuses)))
+	}
+	if q.excludesSets(p) {
+		q.excludeSets.applyToCards(b, p.SetType)
 	}
 	if p.SetType != "" {
-		if err := q.conn.EnsureViews(ctx, "sets"); err != nil {
-			return nil, err
-		}
 		b.Select("cards.*")
 		b.Join("JOIN sets s ON cards.setCode = s.code")
 		b.WhereEq("s.type", p.SetType)
 	}
-
-	if p.FuzzyName != "" {
-		idx := b.AddParam(p.FuzzyName)
-		b.OrderBy(
-			fmt.Sprintf("jaro_winkler_similarity(cards.name, $%d) DESC", idx),
-			"cards.number ASC",
-		)
-	} else {
-		b.OrderBy("cards.name ASC", "cards.number ASC")
+	if p.UniqueNames {
+		return uniqueNames(b)
 	}
+	return b
+}
 
-	limit := p.Limit
-	if limit <= 0 {
-		limit = 100
+// uniqueNames wraps a filtered card query so that it yields one printing
+// per name among the matches: the one from the most recently released set,
+// ties broken by UUID.
+func uniqueNames(filtered *db.SQLBuilder) *db.SQLBuilder {
+	sql, params := filtered.Select("cards.uuid", "cards.name", "cards.setCode").Build()
+	return db.NewSQLBuilder("cards").Where(
+		"cards.uuid IN (SELECT arg_max(f.uuid, COALESCE(CAST(s.releaseDate AS VARCHAR), '') || f.uuid) "+
+			"FROM ("+sql+") f LEFT JOIN sets s ON s.code = f.setCode GROUP BY f.name)",
+		params...)
+}
+
+// inParams adds values as parameters and returns their placeholders,
+// comma-separated, for an IN list.
+func inParams(b *db.SQLBuilder, values []string) string {
+	placeholders := make([]string, len(values))
+	for i, v := range values {
+		placeholders[i] = fmt.Sprintf("$%d", b.AddParam(v))
+	}
+	return strings.Join(placeholders, ", ")
+}
+
+// colorIdentityFilter adds the WHERE clauses for a color identity match.
+// Colors are matched case-insensitively. AtMost and Exactly with no colors
+// match colorless cards; AtLeast with no colors matches everything.
+func colorIdentityFilter(b *db.SQLBuilder, colors []string, op ColorMatch) {
+	if len(colors) == 0 && op != ColorExactly && op != ColorAtMost {
+		return
+	}
+	listed := make(map[string]bool, len(colors))
+	for _, color := range colors {
+		listed[strings.ToUpper(color)] = true
+	}
+	if op != ColorAtMost {
+		for _, color := range colors {
+			idx := b.AddParam(strings.ToUpper(color))
+			b.AddWhere(fmt.Sprintf("list_contains(colorIdentity, $%d)", idx))
+		}
 	}
-	b.Limit(limit).Offset(p.Offset)
+	if op == ColorExactly || op == ColorAtMost {
+		var others []string
+		for _, color := range wubrg {
+			if !listed[color] {
+				others = append(others, color)
+			}
+		}
+		if len(others) > 0 {
+			b.AddWhere("NOT list_has_any(COALESCE(colorIdentity, []), [" + inParams(b, others) + "])")
+		}
+	}
+}
+
+// numberSQL is the leading integer of a collector number, NULL when it has
+// none (e.g. "S12"); numberSuffixSQL is the rest, such as "a" or "★".
+const (
+	numberSQL       = "TRY_CAST(regexp_extract(number, '^[0-9]+') AS INTEGER)"
+	numberSuffixSQL = "regexp_replace(number, '^[0-9]+', '')"
+)
+
+// whereNumber keeps collector numbers on the op (">" or "<") side of bound,
+// bound included, comparing leading integers first so "9" < "10" < "10a".
+// Numbers without a leading integer only match bounds without one, which
+// are compared as plain strings.
+func whereNumber(b *db.SQLBuilder, op, bound string) {
+	n, rest := splitNumber(bound)
+	if n < 0 {
+		b.AddWhere(fmt.Sprintf("number %s= $%d", op, b.AddParam(bound)))
+		return
+	}
+	ni, ri := b.AddParam(n), b.AddParam(rest)
+	b.AddWhere(fmt.Sprintf("(%[1]s %[3]s $%[4]d OR (%[1]s = $%[4]d AND %[2]s %[3]s= $%[5]d))",
+		numberSQL, numberSuffixSQL, op, ni, ri))
+}
+
+// GetPrintings returns all printings of a card across all sets.
+func (q *CardQuery) GetPrintings(ctx context.Context, name string) ([]models.CardSet, error) {
+	return q.GetByName(ctx, name)
+}
 
+// Spellbook resolves an Alchemy card's spellbook (relatedCards.spellbook) to
+// one printing per spellbook card, preferring printings from the card's own
+// set, ordered by name. Returns nil if the card is not found or has no
+// spellbook. The SDK has no deck legality validation, so spellbook cards are
+// not checked against Alchemy or Historic; conjured cards are not part of a
+// decklist, and LegalityAPI.IsLegal on them reports their own legality.
+func (q *CardQuery) Spellbook(ctx context.Context, uuid string) ([]models.CardSet, error) {
+	card, err := q.GetByUUID(ctx, uuid)
+	if err != nil || card == nil {
+		return nil, err
+	}
+	if card.RelatedCards == nil || len(card.RelatedCards.Spellbook) == 0 {
+		return nil, nil
+	}
+	names := make([]any, len(card.RelatedCards.Spellbook))
+	for i, n := range card.RelatedCards.Spellbook {
+		names[i] = n
+	}
+	b := db.NewSQLBuilder("cards").WhereIn("name", names)
+	idx := b.AddParam(card.SetCode)
 	sql, params := b.Build()
+	sql += fmt.Sprintf("\nQUALIFY ROW_NUMBER() OVER (PARTITION BY name ORDER BY setCode = $%d DESC, uuid) = 1"+
+		"\nORDER BY name", idx)
 	var cards []models.CardSet
 	if err := q.conn.ExecuteInto(ctx, &cards, sql, params...); err != nil {
 		return nil, err
 	}
+	q.normalizeKeywords(ctx, cards)
 	return cards, nil
 }
 
-// GetPrintings returns all printings of a card across all sets.
-func (q *CardQuery) GetPrintings(ctx context.Context, name string) ([]models.CardSet, error) {
-	return q.GetByName(ctx, name)
+// Planes returns the Planechase planes and phenomena, one printing per name
+// (the oversized printing where there is one), ordered by name.
+func (q *CardQuery) Planes(ctx context.Context) ([]models.CardSet, error) {
+	return q.byCasualLayout(ctx, "planar")
+}
+
+// Schemes returns the Archenemy schemes, one printing per name (the oversized
+// printing where there is one), ordered by name.
+func (q *CardQuery) Schemes(ctx context.Context) ([]models.CardSet, error) {
+	return q.byCasualLayout(ctx, "scheme")
+}
+
+func (q *CardQuery) byCasualLayout(ctx context.Context, layout string) ([]models.CardSet, error) {
+	if err := q.conn.EnsureViews(ctx, "cards"); err != nil {
+		return nil, err
+	}
+	sql := "SELECT * FROM cards WHERE layout = $1 " +
+		"QUALIFY ROW_NUMBER() OVER (PARTITION BY name " +
+		"ORDER BY COALESCE(isOversized, false) DESC, setCode, number) = 1 " +
+		"ORDER BY name"
+	var cards []models.CardSet
+	if err := q.conn.ExecuteInto(ctx, &cards, sql, layout); err != nil {
+		return nil, err
+	}
+	q.normalizeKeywords(ctx, cards)
+	return cards, nil
 }
 
 // GetAtomic returns de-duplicated oracle card data by name.
@@ -285,6 +635,19 @@ func (q *CardQuery) GetAtomic(ctx context.Context, name string) ([]models.CardAt
 		return []models.CardAtomic{}, nil
 	}
 
+	// The flat data rarely has firstPrinting; resolve it from the printings.
+	first, err := q.FirstPrinting(ctx, results[0].Name)
+	if err != nil {
+		return nil, err
+	}
+	if first != nil {
+		for i := range results {
+			if results[i].FirstPrinting == nil {
+				results[i].FirstPrinting = &first.SetCode
+			}
+		}
+	}
+
 	// De-duplicate by name+faceName
 	type key struct {
 		name     string
@@ -306,6 +669,32 @@ func (q *CardQuery) GetAtomic(ctx context.Context, name string) ([]models.CardAt
 	return unique, nil
 }
 
+// FirstPrinting returns the earliest printing of a card by name (or face
+// name), by the release date of its set: the card's original set and date.
+// Ties go to non-promo printings, then set code and collector number.
+// Returns nil if the card is not found.
+func (q *CardQuery) FirstPrinting(ctx context.Context, name string) (*models.FirstPrinting, error) {
+	if err := q.conn.EnsureViews(ctx, "cards", "sets"); err != nil {
+		return nil, err
+	}
+	var result []models.FirstPrinting
+	if err := q.conn.ExecuteInto(ctx, &result,
+		"SELECT c.name, c.uuid, c.setCode, s.name AS setName, "+
+			"  CAST(s.releaseDate AS VARCHAR) AS releaseDate, c.number "+
+			"FROM cards c JOIN sets s ON s.code = c.setCode "+
+			"WHERE c.name = $1 OR CAST(c.faceName AS VARCHAR) = $1 "+
+			"ORDER BY s.releaseDate ASC NULLS LAST, COALESCE(c.isPromo, false), c.setCode, "+
+			numberSQL+" NULLS LAST, c.number "+
+			"LIMIT 1",
+		name); err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return &result[0], nil
+}
+
 // FindByScryfallID finds cards by their Scryfall ID.
 func (q *CardQuery) FindByScryfallID(ctx context.Context, scryfallID string) ([]models.CardSet, error) {
 	if err := q.conn.EnsureViews(ctx, "cards", "card_identifiers"); err != nil {
@@ -316,6 +705,7 @@ func (q *CardQuery) FindByScryfallID(ctx context.Context, scryfallID string) ([]
 	if err := q.conn.ExecuteInto(ctx, &cards, sql, scryfallID); err != nil {
 		return nil, err
 	}
+	q.normalizeKeywords(ctx, cards)
 	return cards, nil
 }
 
@@ -329,9 +719,75 @@ func (q *CardQuery) Random(ctx context.Context, count int) ([]models.CardSet, er
 	if err := q.conn.ExecuteInto(ctx, &cards, sql); err != nil {
 		return nil, err
 	}
+	q.normalizeKeywords(ctx, cards)
 	return cards, nil
 }
 
+// relatedStopwords are common rules-text words ignored when comparing text.
+var relatedStopwords = []string{
+	"that", "this", "with", "your", "from", "until", "turn", "card", "cards",
+	"each", "target", "control", "into", "when", "whenever", "then", "have",
+	"their", "they", "only", "other", "where", "more", "than", "under", "also",
+}
+
+// Related weights: a shared keyword counts most, shared rules-text words least.
+const (
+	relatedKeywordWeight = 3.0
+	relatedSubtypeWeight = 2.0
+	relatedManaWeight    = 1.0
+	relatedTextWeight    = 0.5
+)
+
+// Related returns up to limit other cards (one printing per name) ranked by a
+// synergy score computed from shared keywords, subtypes, produced mana and
+// rules-text words. It is an offline approximation of "cards like this";
+// returns nil if the card is not found. limit defaults to 10.
+func (q *CardQuery) Related(ctx context.Context, uuid string, limit int) ([]models.CardSynergy, error) {
+	if err := q.conn.EnsureViews(ctx, "cards"); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	stop := make([]string, len(relatedStopwords))
+	for i, w := range relatedStopwords {
+		stop[i] = "'" + w + "'"
+	}
+	tokens := fmt.Sprintf(
+		"list_distinct(list_filter(regexp_split_to_array(lower(replace(COALESCE(text, ''), name, '')), '[^a-z]+'), "+
+			"w -> length(w) > 3 AND NOT list_contains([%s], w)))", strings.Join(stop, ", "))
+	features := "uuid, name, " +
+		"COALESCE(CAST(keywords AS VARCHAR[]), []) AS kw, " +
+		"COALESCE(CAST(subtypes AS VARCHAR[]), []) AS st, " +
+		"COALESCE(CAST(producedMana AS VARCHAR[]), []) AS pm, " +
+		tokens + " AS tk"
+	sql := fmt.Sprintf(`WITH target AS (SELECT %[1]s FROM cards WHERE uuid = $1),
+candidates AS (
+	SELECT %[1]s FROM cards
+	WHERE name <> (SELECT name FROM target)
+	QUALIFY ROW_NUMBER() OVER (PARTITION BY name ORDER BY uuid) = 1
+),
+shared AS (
+	SELECT c.uuid, c.name,
+		list_sort(list_intersect(c.kw, t.kw)) AS shared_keywords,
+		list_sort(list_intersect(c.st, t.st)) AS shared_subtypes,
+		list_sort(list_intersect(c.pm, t.pm)) AS shared_produced_mana,
+		list_sort(list_intersect(c.tk, t.tk)) AS shared_text_tokens
+	FROM candidates c, target t
+)
+SELECT *, len(shared_keywords) * %[2]g + len(shared_subtypes) * %[3]g
+	+ len(shared_produced_mana) * %[4]g + len(shared_text_tokens) * %[5]g AS score
+FROM shared
+WHERE score > 0
+ORDER BY score DESC, name ASC
+LIMIT $2`, features, relatedKeywordWeight, relatedSubtypeWeight, relatedManaWeight, relatedTextWeight)
+	var result []models.CardSynergy
+	if err := q.conn.ExecuteInto(ctx, &result, sql, uuid, limit); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 // Count returns the number of cards matching optional column filters.
 func (q *CardQuery) Count(ctx context.Context, filters ...Filter) (int, error) {
 	if err := q.conn.EnsureViews(ctx, "cards"); err != nil {
@@ -356,6 +812,30 @@ func (q *CardQuery) Count(ctx context.Context, filters ...Filter) (int, error) {
 	return db.ScalarToInt(val), nil
 }
 
+// CountSearch returns how many cards Search would find for p without
+// Limit and Offset, for paginating search results.
+func (q *CardQuery) CountSearch(ctx context.Context, p SearchCardsParams) (int, error) {
+	if err := q.conn.EnsureViews(ctx, q.searchViews(p)...); err != nil {
+		return 0, err
+	}
+	if err := q.ensureForeignASCII(ctx, p); err != nil {
+		return 0, err
+	}
+	if p.Keyword != "" {
+		q.textKeywords(ctx)
+	}
+	if p.FuzzyName != "" && !q.conn.Capabilities().JaroWinkler {
+		matches, err := q.fuzzyFallbackMatches(ctx, p)
+		return len(matches), err
+	}
+	sql, params := q.searchBuilder(p).Build()
+	val, err := q.conn.ExecuteScalar(ctx, "SELECT COUNT(*) FROM ("+sql+")", params...)
+	if err != nil {
+		return 0, err
+	}
+	return db.ScalarToInt(val), nil
+}
+
 // Filter is a simple column=value filter for Count methods.
 type Filter struct {
 	Column string
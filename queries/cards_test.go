@@ -1,8 +1,18 @@
 package queries
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
+
+	"github.com/mtgjson/mtgjson-sdk-go/db"
+	"github.com/mtgjson/mtgjson-sdk-go/manacost"
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+	"github.com/mtgjson/mtgjson-sdk-go/testsupport/sample"
 )
 
 func TestCardGetByUUID(t *testing.T) {
@@ -107,6 +117,32 @@ func TestCardSearchByManaValue(t *testing.T) {
 	}
 }
 
+func TestCardSearchByNumberRange(t *testing.T) {
+	q := NewCardQuery(setupNumberedDB(t))
+	ctx := context.Background()
+
+	cards, err := q.Search(ctx, SearchCardsParams{NumberGTE: "10", NumberLTE: "10a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range cards {
+		got = append(got, c.Number)
+	}
+	slices.Sort(got)
+	if strings.Join(got, ",") != "10,10a" {
+		t.Fatalf("expected 10 and 10a, got %v", got)
+	}
+
+	n, err := q.CountSearch(ctx, SearchCardsParams{NumberGTE: "11"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("expected only 100 at or above 11, got %d", n)
+	}
+}
+
 func TestCardSearchByColors(t *testing.T) {
 	conn := setupSampleDB(t)
 	q := NewCardQuery(conn)
@@ -185,6 +221,41 @@ func TestCardSearchLegalIn(t *testing.T) {
 	}
 }
 
+func TestCardSearchLegalInMultipleFormats(t *testing.T) {
+	conn := setupSampleDB(t)
+	q := NewCardQuery(conn)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		params SearchCardsParams
+		want   []string
+	}{
+		{"all", SearchCardsParams{LegalInAll: []string{"modern", "vintage"}}, []string{"Counterspell"}},
+		{"all with restricted", SearchCardsParams{LegalInAll: []string{"modern", "vintage"}, LegalStatus: []string{"Legal", "Restricted"}},
+			[]string{"Counterspell", "Lightning Bolt"}},
+		{"LegalIn joins all", SearchCardsParams{LegalIn: "modern", LegalInAll: []string{"legacy", "modern"}}, []string{"Counterspell", "Lightning Bolt"}},
+		{"any", SearchCardsParams{LegalInAny: []string{"vintage", "standard"}}, []string{"Counterspell"}},
+		{"any with status", SearchCardsParams{LegalInAny: []string{"historic"}, LegalStatus: []string{"Suspended"}}, []string{"Counterspell"}},
+		{"all and any", SearchCardsParams{LegalInAll: []string{"legacy"}, LegalInAny: []string{"standard"}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cards, err := q.Search(ctx, tt.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, c := range cards {
+				names = append(names, c.Name)
+			}
+			if !slices.Equal(names, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, names)
+			}
+		})
+	}
+}
+
 func TestCardGetPrintings(t *testing.T) {
 	conn := setupSampleDB(t)
 	q := NewCardQuery(conn)
@@ -304,6 +375,39 @@ func TestCardSearchByColorIdentity(t *testing.T) {
 	}
 }
 
+func TestCardSearchByColorIdentityOp(t *testing.T) {
+	conn := setupSampleDB(t)
+	q := NewCardQuery(conn)
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		colors []string
+		op     ColorMatch
+		want   []string
+	}{
+		{[]string{"R"}, "", []string{"Fire // Ice", "Lightning Bolt"}},
+		{[]string{"R"}, ColorAtLeast, []string{"Fire // Ice", "Lightning Bolt"}},
+		{[]string{"R"}, ColorExactly, []string{"Lightning Bolt"}},
+		{[]string{"U", "R"}, ColorExactly, []string{"Fire // Ice"}},
+		{[]string{"R"}, ColorAtMost, []string{"Lightning Bolt"}},
+		{[]string{"w", "u", "r"}, ColorAtMost, []string{"Counterspell", "Fire // Ice", "Lightning Bolt"}},
+		{nil, ColorAtMost, nil},
+	} {
+		cards, err := q.Search(ctx, SearchCardsParams{ColorIdentity: tc.colors, ColorIdentityOp: tc.op})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, c := range cards {
+			names = append(names, c.Name)
+		}
+		slices.Sort(names)
+		if !slices.Equal(names, tc.want) {
+			t.Errorf("%v %q: expected %v, got %v", tc.colors, tc.op, tc.want, names)
+		}
+	}
+}
+
 func TestCardSearchByAvailability(t *testing.T) {
 	conn := setupSampleDB(t)
 	q := NewCardQuery(conn)
@@ -318,6 +422,197 @@ func TestCardSearchByAvailability(t *testing.T) {
 	}
 }
 
+func TestCardSearchInBoosters(t *testing.T) {
+	conn := setupSampleDB(t)
+	q := NewCardQuery(conn)
+	ctx := context.Background()
+
+	yes, no := true, false
+	cards, err := q.Search(ctx, SearchCardsParams{InBoosters: &yes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 2 {
+		t.Fatalf("expected 2 booster cards, got %d", len(cards))
+	}
+	cards, err = q.Search(ctx, SearchCardsParams{InBoosters: &no})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 1 || cards[0].Name != "Fire // Ice" {
+		t.Fatalf("expected only Fire // Ice outside boosters, got %d cards", len(cards))
+	}
+}
+
+func TestCardSearchByBoosterType(t *testing.T) {
+	conn := setupSampleDB(t)
+	q := NewCardQuery(conn)
+	ctx := context.Background()
+
+	cards, err := q.Search(ctx, SearchCardsParams{BoosterType: "deck"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 1 || cards[0].Name != "Counterspell" {
+		t.Fatalf("expected only Counterspell, got %d cards", len(cards))
+	}
+}
+
+func TestCardSearchByAttractionLights(t *testing.T) {
+	conn := setupSampleDB(t)
+	q := NewCardQuery(conn)
+	ctx := context.Background()
+
+	attraction := make(map[string]any, len(sampleCards[0]))
+	for k, v := range sampleCards[0] {
+		attraction[k] = v
+	}
+	attraction["uuid"] = "card-uuid-attraction"
+	attraction["name"] = "Balloon Stand"
+	attraction["type"] = "Artifact — Attraction"
+	attraction["attractionLights"] = []any{2, 4, 6}
+	if err := conn.RegisterTableFromData(ctx, "cards", append([]map[string]any{attraction}, sampleCards...)); err != nil {
+		t.Fatal(err)
+	}
+
+	cards, err := q.Search(ctx, SearchCardsParams{AttractionLights: []int{2, 6}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 1 || cards[0].Name != "Balloon Stand" {
+		t.Fatalf("expected Balloon Stand, got %d cards", len(cards))
+	}
+	if got := cards[0].AttractionLights; len(got) != 3 || got[2] != 6 {
+		t.Fatalf("expected lights [2 4 6], got %v", got)
+	}
+	cards, err = q.Search(ctx, SearchCardsParams{AttractionLights: []int{3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 0 {
+		t.Fatalf("expected no Attractions lit on 3, got %d", len(cards))
+	}
+}
+
+// setupCasualDB adds two printings of a plane and one scheme to the sample cards.
+func setupCasualDB(t *testing.T) *db.Connection {
+	t.Helper()
+	conn := setupSampleDB(t)
+	card := func(uuid, name, layout, setCode string, oversized any) map[string]any {
+		c := make(map[string]any, len(sampleCards[0]))
+		for k, v := range sampleCards[0] {
+			c[k] = v
+		}
+		c["uuid"], c["name"], c["layout"], c["setCode"], c["isOversized"] = uuid, name, layout, setCode, oversized
+		return c
+	}
+	cards := append([]map[string]any{
+		card("plane-uuid-1", "Tazeem", "planar", "HOP", true),
+		card("plane-uuid-2", "Tazeem", "planar", "MOC", nil),
+		card("scheme-uuid-1", "Behold the Power of Destruction", "scheme", "ARC", true),
+	}, sampleCards...)
+	if err := conn.RegisterTableFromData(context.Background(), "cards", cards); err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+func TestCardPlanesAndSchemes(t *testing.T) {
+	conn := setupCasualDB(t)
+	q := NewCardQuery(conn)
+	ctx := context.Background()
+
+	planes, err := q.Planes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(planes) != 1 || planes[0].UUID != "plane-uuid-1" {
+		t.Fatalf("expected the oversized Tazeem printing, got %v", planes)
+	}
+	schemes, err := q.Schemes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schemes) != 1 || schemes[0].Name != "Behold the Power of Destruction" {
+		t.Fatalf("expected one scheme, got %v", schemes)
+	}
+}
+
+func TestCardSearchCasualLayoutsExcluded(t *testing.T) {
+	conn := setupCasualDB(t)
+	ctx := context.Background()
+
+	cards, err := NewCardQuery(conn).Search(ctx, SearchCardsParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 6 {
+		t.Fatalf("expected casual cards to be included by default, got %d", len(cards))
+	}
+	q := NewCardQuery(conn, WithCasualLayoutsExcluded(true))
+	cards, err = q.Search(ctx, SearchCardsParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 3 {
+		t.Fatalf("expected only the 3 constructed cards, got %d", len(cards))
+	}
+	cards, err = q.Search(ctx, SearchCardsParams{Layout: "planar"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 2 {
+		t.Fatalf("expected an explicit layout to return both planes, got %d", len(cards))
+	}
+}
+
+func TestCardSearchSQL(t *testing.T) {
+	conn := setupSampleDB(t)
+	q := NewCardQuery(conn)
+	ctx := context.Background()
+
+	p := SearchCardsParams{Colors: []string{"R"}, Rarity: "uncommon", Limit: 5}
+	sql, params := q.SearchSQL(p)
+	if !strings.Contains(sql, "list_contains(colors, $2)") || !strings.HasSuffix(sql, "LIMIT $3\nOFFSET $4") {
+		t.Fatalf("unexpected SQL:\n%s", sql)
+	}
+	if len(params) != 4 || params[0] != "uncommon" || params[1] != "R" || params[2] != 5 {
+		t.Fatalf("unexpected params: %v", params)
+	}
+	rows, err := conn.Execute(ctx, sql, params...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cards, err := q.Search(ctx, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != len(cards) || len(rows) != 2 {
+		t.Fatalf("expected SearchSQL to match Search, got %d rows and %d cards", len(rows), len(cards))
+	}
+}
+
+func TestCardSpellbook(t *testing.T) {
+	conn := setupSampleDB(t)
+	q := NewCardQuery(conn)
+	ctx := context.Background()
+
+	cards, err := q.Spellbook(ctx, "card-uuid-002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 2 || cards[0].Name != "Fire // Ice" || cards[1].Name != "Lightning Bolt" {
+		t.Fatalf("expected Fire // Ice and Lightning Bolt, got %v", cards)
+	}
+	cards, err = q.Spellbook(ctx, "card-uuid-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cards != nil {
+		t.Fatalf("expected nil for a card without a spellbook, got %v", cards)
+	}
+}
+
 func TestCardSearchByLanguage(t *testing.T) {
 	conn := setupSampleDB(t)
 	q := NewCardQuery(conn)
@@ -598,6 +893,27 @@ func TestCardSearchTextRegexNoMatch(t *testing.T) {
 	}
 }
 
+func TestCardSearchManaSymbols(t *testing.T) {
+	conn := setupSampleDB(t)
+	q := NewCardQuery(conn)
+	ctx := context.Background()
+
+	cards, err := q.Search(ctx, SearchCardsParams{ManaSymbols: []manacost.Predicate{manacost.OfColor("R"), manacost.Exactly("{1}")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 1 || cards[0].Name != "Fire // Ice" {
+		t.Fatalf("expected Fire // Ice, got %v", cards)
+	}
+	cards, err = q.Search(ctx, SearchCardsParams{ManaSymbols: []manacost.Predicate{manacost.IsPhyrexian}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 0 {
+		t.Fatalf("expected no Phyrexian mana costs, got %d", len(cards))
+	}
+}
+
 func TestCardSearchFuzzyName(t *testing.T) {
 	conn := setupSampleDB(t)
 	q := NewCardQuery(conn)
@@ -662,3 +978,342 @@ func TestCardSearchFuzzyNameOrderedBySimilarity(t *testing.T) {
 		t.Fatalf("expected Counterspell first, got %s", cards[0].Name)
 	}
 }
+
+func TestCardSearchFuzzyNameLevenshteinFallback(t *testing.T) {
+	conn := setupSampleDB(t)
+	conn.SetCapabilities(db.Capabilities{JSON: true, JaroWinkler: false})
+	q := NewCardQuery(conn)
+	ctx := context.Background()
+
+	for query, want := range map[string]string{"Ligtning Bolt": "Lightning Bolt", "Countrsepll": "Counterspell"} {
+		cards, err := q.Search(ctx, SearchCardsParams{FuzzyName: query})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cards) != 1 || cards[0].Name != want {
+			t.Errorf("%q: expected only %s, got %d cards", query, want, len(cards))
+		}
+	}
+	cards, err := q.Search(ctx, SearchCardsParams{FuzzyName: "Ligtning Bolt", Offset: 1})
+	if err != nil || len(cards) != 0 {
+		t.Errorf("expected an empty second page, got %d cards (%v)", len(cards), err)
+	}
+}
+
+func TestCardSearchToWriter(t *testing.T) {
+	q := NewCardQuery(setupSampleDB(t))
+	ctx := context.Background()
+
+	for _, p := range []SearchCardsParams{{}, {Colors: []string{"R"}}, {FuzzyName: "Ligtning Bolt", Limit: 1}} {
+		cards, err := q.Search(ctx, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := q.SearchToWriter(ctx, &buf, p); err != nil {
+			t.Fatal(err)
+		}
+		dec := json.NewDecoder(&buf)
+		var got []models.CardSet
+		for dec.More() {
+			var c models.CardSet
+			if err := dec.Decode(&c); err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, c)
+		}
+		if len(got) != len(cards) {
+			t.Fatalf("%+v: expected %d lines, got %d", p, len(cards), len(got))
+		}
+		for i := range got {
+			if got[i].UUID != cards[i].UUID {
+				t.Errorf("%+v: line %d is %s, Search has %s", p, i, got[i].UUID, cards[i].UUID)
+			}
+		}
+	}
+}
+
+func TestCardCountSearch(t *testing.T) {
+	conn := setupSampleDB(t)
+	q := NewCardQuery(conn)
+	ctx := context.Background()
+
+	for _, p := range []SearchCardsParams{
+		{},
+		{Colors: []string{"R"}},
+		{LegalIn: "modern", Limit: 1},
+		{LegalInAll: []string{"modern", "vintage"}, Offset: 5},
+		{LocalizedName: "%o%"},
+		{SetType: "masters", Rarity: "uncommon"},
+		{FuzzyName: "Ligtning Bolt"},
+	} {
+		want := p
+		want.Limit, want.Offset = 1000, 0
+		cards, err := q.Search(ctx, want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n, err := q.CountSearch(ctx, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != len(cards) {
+			t.Errorf("%+v: CountSearch = %d, Search found %d", p, n, len(cards))
+		}
+	}
+
+	conn.SetCapabilities(db.Capabilities{JSON: true, JaroWinkler: false})
+	if n, err := q.CountSearch(ctx, SearchCardsParams{FuzzyName: "Countrsepll", Limit: 1}); err != nil || n != 1 {
+		t.Errorf("expected one fuzzy match without jaro_winkler, got %d (%v)", n, err)
+	}
+}
+
+func TestCardSearchUniqueNames(t *testing.T) {
+	conn := setupSampleDB(t)
+	ctx := context.Background()
+	reprint := sample.Cards()[0]
+	reprint["uuid"], reprint["setCode"], reprint["number"] = "card-uuid-004", "MH2", "999"
+	if err := conn.RegisterTableFromData(ctx, "cards", append(sample.Cards(), reprint)); err != nil {
+		t.Fatal(err)
+	}
+	q := NewCardQuery(conn)
+
+	cards, err := q.Search(ctx, SearchCardsParams{UniqueNames: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 3 {
+		t.Fatalf("expected one row per name, got %d", len(cards))
+	}
+	for _, c := range cards {
+		if c.Name == "Lightning Bolt" && c.UUID != "card-uuid-004" {
+			t.Errorf("expected the MH2 reprint as the latest printing, got %s", c.SetCode)
+		}
+	}
+	cards, err = q.Search(ctx, SearchCardsParams{Name: "Lightning Bolt", SetCode: "A25", UniqueNames: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 1 || cards[0].UUID != "card-uuid-001" {
+		t.Errorf("expected filters applied before picking the latest printing, got %+v", cards)
+	}
+	if n, err := q.CountSearch(ctx, SearchCardsParams{Colors: []string{"R"}}); err != nil || n != 3 {
+		t.Errorf("expected 3 red printings, got %d (%v)", n, err)
+	}
+	if n, err := q.CountSearch(ctx, SearchCardsParams{Colors: []string{"R"}, UniqueNames: true}); err != nil || n != 2 {
+		t.Errorf("expected 2 red names, got %d (%v)", n, err)
+	}
+}
+
+func TestLevenshteinSimilarity(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want float64
+	}{
+		{"", "", 1},
+		{"bolt", "bolt", 1},
+		{"bolt", "bolts", 0.8},
+		{"kitten", "sitting", 1 - 3.0/7},
+		{"æon", "aeon", 0.5},
+	} {
+		if got := levenshteinSimilarity(tc.a, tc.b); got != tc.want {
+			t.Errorf("levenshteinSimilarity(%q, %q) = %v, want %v", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+var sampleSynergyCards = []map[string]any{
+	{
+		"uuid": "syn-001", "name": "Llanowar Elves", "keywords": []any{"Haste"},
+		"subtypes": []any{"Elf", "Druid"}, "producedMana": []any{"G"},
+		"text": "{T}: Add {G}.",
+	},
+	{
+		"uuid": "syn-002", "name": "Elvish Mystic", "keywords": []any{"Haste"},
+		"subtypes": []any{"Elf", "Druid"}, "producedMana": []any{"G"},
+		"text": "{T}: Add {G}.",
+	},
+	{
+		"uuid": "syn-003", "name": "Elvish Mystic", "keywords": []any{"Haste"},
+		"subtypes": []any{"Elf", "Druid"}, "producedMana": []any{"G"},
+		"text": "{T}: Add {G}.",
+	},
+	{
+		"uuid": "syn-004", "name": "Elvish Archdruid", "keywords": []any{},
+		"subtypes": []any{"Elf", "Druid"}, "producedMana": []any{"G"},
+		"text": "Other Elf creatures you control get +1/+1.",
+	},
+	{
+		"uuid": "syn-005", "name": "Grizzly Bears", "keywords": []any{},
+		"subtypes": []any{"Bear"}, "producedMana": []any{},
+		"text": "",
+	},
+}
+
+func TestCardRelated(t *testing.T) {
+	conn := setupSampleDB(t)
+	if err := conn.RegisterTableFromData(context.Background(), "cards", sampleSynergyCards); err != nil {
+		t.Fatal(err)
+	}
+	q := NewCardQuery(conn)
+
+	related, err := q.Related(context.Background(), "syn-001", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(related) != 2 {
+		t.Fatalf("expected 2 related cards (one per name, none unrelated), got %+v", related)
+	}
+	if related[0].Name != "Elvish Mystic" || related[1].Name != "Elvish Archdruid" {
+		t.Fatalf("unexpected ranking: %+v", related)
+	}
+	if len(related[0].SharedKeywords) != 1 || len(related[0].SharedSubtypes) != 2 || related[0].Score <= related[1].Score {
+		t.Fatalf("unexpected scores: %+v", related)
+	}
+
+	related, err = q.Related(context.Background(), "missing", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(related) != 0 {
+		t.Fatalf("expected no results for unknown uuid, got %+v", related)
+	}
+}
+
+func TestCardSetAccessorsAndDiff(t *testing.T) {
+	cq := NewCardQuery(setupSampleDB(t))
+	ctx := context.Background()
+	bolt, err := cq.GetByUUID(ctx, "card-uuid-001")
+	if err != nil || bolt == nil {
+		t.Fatalf("expected Lightning Bolt, got %v (%v)", bolt, err)
+	}
+	if bolt.GetText() != "Lightning Bolt deals 3 damage to any target." || bolt.GetPower() != "" {
+		t.Errorf("unexpected accessor values %q, %q", bolt.GetText(), bolt.GetPower())
+	}
+	if !bolt.IsReprintFlag() || bolt.IsPromoFlag() {
+		t.Error("expected a non-promo reprint")
+	}
+
+	again, err := cq.GetByUUID(ctx, "card-uuid-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bolt.Equal(again) {
+		t.Errorf("expected identical reads to be equal, got diff %v", bolt.Diff(again))
+	}
+	again.Text = nil
+	again.Printings = append(again.Printings, "2X2")
+	scryfallID := "scryfall-001"
+	again.IdentifiersData.ScryfallId = &scryfallID
+	if diff := bolt.Diff(again); fmt.Sprint(diff) != "[text printings identifiers]" {
+		t.Errorf("unexpected diff %v", diff)
+	}
+}
+
+// countingBackend is a db.Backend that counts the queries it forwards.
+type countingBackend struct {
+	*db.Connection
+	queries int
+}
+
+func (b *countingBackend) Execute(ctx context.Context, query string, params ...any) ([]map[string]any, error) {
+	b.queries++
+	return b.Connection.Execute(ctx, query, params...)
+}
+
+func (b *countingBackend) ExecuteInto(ctx context.Context, dst any, query string, params ...any) error {
+	b.queries++
+	return b.Connection.ExecuteInto(ctx, dst, query, params...)
+}
+
+func TestCardQueryCustomBackend(t *testing.T) {
+	backend := &countingBackend{Connection: setupSampleDB(t)}
+	q := NewCardQuery(backend)
+	card, err := q.GetByUUID(context.Background(), "card-uuid-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card == nil || card.Name != "Lightning Bolt" || backend.queries != 1 {
+		t.Fatalf("expected Lightning Bolt through the wrapped backend, got %v after %d queries", card, backend.queries)
+	}
+}
+
+func TestPreconAppearances(t *testing.T) {
+	conn := setupSampleDB(t)
+	q := NewCardQuery(conn)
+	ctx := context.Background()
+
+	got, err := q.PreconAppearances(ctx, "Counterspell")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 appearances, got %+v", got)
+	}
+	first, second := got[0], got[1]
+	if first.DeckCode != "A25_DECK1" || first.Board != "sideBoard" || first.Count != 1 ||
+		first.ReleaseDate == nil || *first.ReleaseDate != "2018-03-16" {
+		t.Fatalf("expected the A25 sideboard first, got %+v", first)
+	}
+	if second.DeckCode != "MH2_DECK1" || second.Board != "mainBoard" || second.Count != 4 || second.UUID != "card-uuid-002" {
+		t.Fatalf("expected the MH2 main board second, got %+v", second)
+	}
+
+	none, err := q.PreconAppearances(ctx, "No Such Card")
+	if err != nil || len(none) != 0 {
+		t.Fatalf("expected no appearances, got %v, %v", none, err)
+	}
+}
+
+func TestCardSearchByRulingText(t *testing.T) {
+	conn := setupSampleDB(t)
+	ctx := context.Background()
+	if err := conn.RegisterTableFromData(ctx, "card_rulings", []map[string]any{
+		{"uuid": "card-uuid-002", "date": "2020-01-01", "text": "Counterspell can target a spell that can't be countered."},
+		{"uuid": "card-uuid-003", "date": "2021-06-01", "text": "Fire's characteristics are applied in Layer 1."},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	q := NewCardQuery(conn)
+
+	cards, err := q.Search(ctx, SearchCardsParams{RulingText: "layer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 1 || cards[0].UUID != "card-uuid-003" {
+		t.Fatalf("expected only Fire // Ice, got %v", cards)
+	}
+	if n, err := q.CountSearch(ctx, SearchCardsParams{RulingText: "banding"}); err != nil || n != 0 {
+		t.Fatalf("expected no banding rulings, got %d (%v)", n, err)
+	}
+}
+
+func TestFirstPrinting(t *testing.T) {
+	conn := setupSampleDB(t)
+	ctx := context.Background()
+	cards := sample.Cards()
+	reprint := sample.Cards()[0]
+	reprint["uuid"], reprint["setCode"], reprint["number"] = "card-uuid-bolt-mh2", "MH2", "1"
+	if err := conn.RegisterTableFromData(ctx, "cards", append([]map[string]any{reprint}, cards...)); err != nil {
+		t.Fatal(err)
+	}
+	q := NewCardQuery(conn)
+
+	first, err := q.FirstPrinting(ctx, "Lightning Bolt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == nil || first.SetCode != "A25" || first.UUID != "card-uuid-001" || first.ReleaseDate != "2018-03-16" {
+		t.Fatalf("expected the 2018 A25 printing, got %+v", first)
+	}
+	atomic, err := q.GetAtomic(ctx, "Lightning Bolt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(atomic) == 0 || atomic[0].FirstPrinting == nil || *atomic[0].FirstPrinting != "A25" {
+		t.Fatalf("expected GetAtomic to fill firstPrinting, got %+v", atomic)
+	}
+	if first, err := q.FirstPrinting(ctx, "No Such Card"); err != nil || first != nil {
+		t.Fatalf("expected nil for an unknown card, got %+v, %v", first, err)
+	}
+}
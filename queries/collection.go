@@ -0,0 +1,593 @@
+package queries
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mtgjson/mtgjson-sdk-go/db"
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+// CSVFormat identifies a third-party collection/deck CSV schema.
+type CSVFormat string
+
+const (
+	CSVMoxfield  CSVFormat = "moxfield"
+	CSVArchidekt CSVFormat = "archidekt"
+	CSVDeckbox   CSVFormat = "deckbox"
+	CSVTCGPlayer CSVFormat = "tcgplayer"
+)
+
+// csvSchema maps a format's column headers onto collection entry fields.
+// Empty column names are not part of the format.
+type csvSchema struct {
+	header           []string
+	quantity         string
+	name             string
+	setCode          string
+	setName          string
+	number           string
+	finish           string
+	scryfallID       string
+	tcgplayerID      string
+	condition        string
+	language         string
+	purchasePrice    string
+	added            string
+	isoDates         bool // dates are "YYYY-MM-DD" whatever the locale
+	defaultCondition string
+	finishLabels     map[string]string // normalized finish -> exported value
+}
+
+var csvSchemas = map[CSVFormat]*csvSchema{
+	CSVMoxfield: {
+		header: []string{"Count", "Tradelist Count", "Name", "Edition", "Condition", "Language",
+			"Foil", "Tags", "Last Modified", "Collector Number", "Alter", "Proxy", "Purchase Price"},
+		quantity: "Count", name: "Name", setCode: "Edition", number: "Collector Number",
+		finish: "Foil", condition: "Condition", language: "Language",
+		purchasePrice: "Purchase Price", added: "Last Modified", isoDates: true,
+		defaultCondition: "Near Mint",
+		finishLabels:     map[string]string{"normal": "", "foil": "foil", "etched": "etched"},
+	},
+	CSVArchidekt: {
+		header: []string{"Quantity", "Name", "Finish", "Condition", "Date Added", "Language",
+			"Purchase Price", "Tags", "Edition Name", "Edition Code", "Multiverse Id",
+			"Scryfall ID", "MTGO ID", "Collector Number"},
+		quantity: "Quantity", name: "Name", setCode: "Edition Code", setName: "Edition Name",
+		number: "Collector Number", finish: "Finish", scryfallID: "Scryfall ID",
+		condition: "Condition", language: "Language",
+		purchasePrice: "Purchase Price", added: "Date Added",
+		defaultCondition: "NM",
+		finishLabels:     map[string]string{"normal": "Normal", "foil": "Foil", "etched": "Etched"},
+	},
+	CSVDeckbox: {
+		header: []string{"Count", "Tradelist Count", "Name", "Edition", "Card Number", "Condition",
+			"Language", "Foil", "Signed", "Artist Proof", "Altered Art", "Misprint", "Promo",
+			"Textless", "My Price"},
+		quantity: "Count", name: "Name", setName: "Edition", number: "Card Number",
+		finish: "Foil", condition: "Condition", language: "Language",
+		purchasePrice:    "My Price",
+		defaultCondition: "Near Mint",
+		finishLabels:     map[string]string{"normal": "", "foil": "foil", "etched": "foil"},
+	},
+	CSVTCGPlayer: {
+		header: []string{"Quantity", "Name", "Simple Name", "Set", "Card Number", "Set Code",
+			"Printing", "Condition", "Language", "Rarity", "Product ID", "SKU"},
+		quantity: "Quantity", name: "Name", setCode: "Set Code", setName: "Set",
+		number: "Card Number", finish: "Printing", tcgplayerID: "Product ID",
+		condition: "Condition", language: "Language",
+		defaultCondition: "Near Mint",
+		finishLabels:     map[string]string{"normal": "Normal", "foil": "Foil", "etched": "Foil"},
+	},
+}
+
+// boardColumns are the headers recognised as a deck board/section column.
+var boardColumns = []string{"Board", "Section", "Category"}
+
+// categoryBoards are the boards an Archidekt-style "Category" column can
+// name. Other categories, such as "Creature" or "Ramp", group cards within
+// the main deck.
+var categoryBoards = map[string]bool{"side": true, "maybe": true, "commander": true}
+
+// CollectionQuery reads and writes collections and decks in the CSV schemas
+// used by common collection managers, resolving rows to MTGJSON printings.
+type CollectionQuery struct {
+	conn db.Backend
+}
+
+func NewCollectionQuery(conn db.Backend) *CollectionQuery {
+	return &CollectionQuery{conn: conn}
+}
+
+// ImportCSV parses a CSV export in the given format into collection entries.
+// Rows are resolved to a printing UUID by Scryfall ID or TCGPlayer product ID
+// when the format carries one, then by set and collector number, then by set
+// and name. Rows that cannot be resolved keep their Name with an empty UUID,
+// so they still match any printing when used as a want list. Purchase prices
+// and dates are read in the WithExportLocale format the file was written
+// with. A quantity that is not a positive number, or a price or date that
+// cannot be read, is an error naming its line.
+func (q *CollectionQuery) ImportCSV(ctx context.Context, r io.Reader, format CSVFormat, opts ...ExportOption) ([]models.CollectionEntry, error) {
+	cfg := newExportConfig(opts)
+	schema, err := lookupCSVSchema(format)
+	if err != nil {
+		return nil, err
+	}
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("mtgjson: read %s csv header: %w", format, err)
+	}
+	cols := make(map[string]int, len(header))
+	for i, h := range header {
+		cols[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))] = i
+	}
+	field := func(rec []string, name string) string {
+		if name == "" {
+			return ""
+		}
+		i, ok := cols[strings.ToLower(name)]
+		if !ok || i >= len(rec) {
+			return ""
+		}
+		return strings.TrimSpace(rec[i])
+	}
+	if _, ok := cols[strings.ToLower(schema.name)]; !ok {
+		return nil, fmt.Errorf("mtgjson: %s csv is missing the %q column", format, schema.name)
+	}
+
+	var entries []models.CollectionEntry
+	var keys []csvRowKeys
+	for line := 2; ; line++ {
+		rec, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("mtgjson: read %s csv: %w", format, err)
+		}
+		name := field(rec, schema.name)
+		if name == "" {
+			continue
+		}
+		qty := 1
+		if s := field(rec, schema.quantity); s != "" {
+			qty, err = strconv.Atoi(s)
+			if err != nil {
+				return nil, fmt.Errorf("mtgjson: %s csv line %d: invalid quantity %q", format, line, s)
+			}
+			if qty <= 0 {
+				return nil, fmt.Errorf("mtgjson: %s csv line %d: quantity %d is not positive", format, line, qty)
+			}
+		}
+		e := models.CollectionEntry{
+			Name:       name,
+			Quantity:   qty,
+			ScryfallID: field(rec, schema.scryfallID),
+			Finish:     normalizeFinish(field(rec, schema.finish)),
+			Condition:  field(rec, schema.condition),
+			Language:   field(rec, schema.language),
+		}
+		if s := field(rec, schema.purchasePrice); s != "" {
+			price, err := cfg.locale.ParsePrice(s)
+			if err != nil {
+				return nil, fmt.Errorf("mtgjson: %s csv line %d: invalid purchase price %q", format, line, s)
+			}
+			e.PurchasePrice = &price
+		}
+		if s := field(rec, schema.added); s != "" {
+			e.Added, err = schema.locale(cfg.locale).ParseDate(s)
+			if err != nil {
+				return nil, fmt.Errorf("mtgjson: %s csv line %d: invalid date %q", format, line, s)
+			}
+		}
+		for _, c := range boardColumns {
+			b := field(rec, c)
+			if b == "" {
+				continue
+			}
+			if board := normalizeBoard(b); c != "Category" || categoryBoards[board] {
+				e.Board = board
+			}
+			break
+		}
+		entries = append(entries, e)
+		keys = append(keys, csvRowKeys{
+			setCode:     strings.ToUpper(field(rec, schema.setCode)),
+			setName:     field(rec, schema.setName),
+			number:      field(rec, schema.number),
+			tcgplayerID: field(rec, schema.tcgplayerID),
+		})
+	}
+	if err := q.resolveRows(ctx, entries, keys); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// csvRowKeys are the identifiers of a CSV row besides its Scryfall ID.
+type csvRowKeys struct {
+	setCode, setName, number, tcgplayerID string
+}
+
+// resolveRows fills in the UUIDs of entries from their rows' identifiers,
+// trying each kind of identifier in turn for the entries still unresolved,
+// with one query per kind.
+func (q *CollectionQuery) resolveRows(ctx context.Context, entries []models.CollectionEntry, keys []csvRowKeys) error {
+	// unresolved returns the values key gives the unresolved entries.
+	unresolved := func(key func(i int) string) []any {
+		seen := make(map[string]bool)
+		var values []any
+		for i := range entries {
+			if v := key(i); entries[i].UUID == "" && v != "" && !seen[v] {
+				seen[v] = true
+				values = append(values, v)
+			}
+		}
+		return values
+	}
+
+	byScryfall, err := uuidsByIdentifier(ctx, q.conn, "scryfallId",
+		unresolved(func(i int) string { return entries[i].ScryfallID }))
+	if err != nil {
+		return err
+	}
+	for i := range entries {
+		if id := entries[i].ScryfallID; entries[i].UUID == "" && id != "" {
+			entries[i].UUID = byScryfall[id]
+		}
+	}
+
+	tcgplayerID := func(i int) string { return keys[i].tcgplayerID }
+	byProduct, err := uuidsByIdentifier(ctx, q.conn, "tcgplayerProductId", unresolved(tcgplayerID))
+	if err != nil {
+		return err
+	}
+	for i := range entries {
+		if id := keys[i].tcgplayerID; entries[i].UUID == "" && id != "" {
+			entries[i].UUID = byProduct[id]
+		}
+	}
+	byEtched, err := uuidsByIdentifier(ctx, q.conn, "tcgplayerEtchedProductId", unresolved(tcgplayerID))
+	if err != nil {
+		return err
+	}
+	for i := range entries {
+		if uuid := byEtched[keys[i].tcgplayerID]; entries[i].UUID == "" && uuid != "" {
+			entries[i].UUID, entries[i].Finish = uuid, "etched"
+		}
+	}
+
+	// The remaining lookups are by set: rows naming their set get its code.
+	setNames := unresolved(func(i int) string {
+		if keys[i].setCode != "" {
+			return ""
+		}
+		return keys[i].setName
+	})
+	codes := make(map[string][]string) // set name -> codes
+	if len(setNames) > 0 {
+		if err := q.conn.EnsureViews(ctx, "sets"); err != nil {
+			return err
+		}
+		sql, params := db.NewSQLBuilder("sets").Select("code", "name").WhereIn("name", setNames).Build()
+		rows, err := q.conn.Execute(ctx, sql, params...)
+		if err != nil {
+			return err
+		}
+		for _, r := range rows {
+			code, _ := r["code"].(string)
+			name, _ := r["name"].(string)
+			codes[name] = append(codes[name], code)
+		}
+	}
+	setCodes := func(i int) []string {
+		if keys[i].setCode != "" {
+			return []string{keys[i].setCode}
+		}
+		return codes[keys[i].setName]
+	}
+
+	for _, byNumber := range []bool{true, false} {
+		column, value := "name", func(i int) string { return entries[i].Name }
+		if byNumber {
+			column, value = "number", func(i int) string { return keys[i].number }
+		}
+		var inSets []any
+		var values []any
+		seenSet, seenValue := make(map[string]bool), make(map[string]bool)
+		for i := range entries {
+			if entries[i].UUID != "" || (keys[i].number != "") != byNumber {
+				continue
+			}
+			for _, code := range setCodes(i) {
+				if !seenSet[code] {
+					seenSet[code] = true
+					inSets = append(inSets, code)
+				}
+			}
+			if v := value(i); !seenValue[v] {
+				seenValue[v] = true
+				values = append(values, v)
+			}
+		}
+		if len(inSets) == 0 {
+			continue
+		}
+		if err := q.conn.EnsureViews(ctx, "cards"); err != nil {
+			return err
+		}
+		sql, params := db.NewSQLBuilder("cards").
+			Select("uuid", "setCode", column+" AS value").
+			WhereIn("setCode", inSets).
+			WhereIn(column, values).
+			OrderBy("number ASC", "uuid ASC").
+			Build()
+		rows, err := q.conn.Execute(ctx, sql, params...)
+		if err != nil {
+			return err
+		}
+		found := make(map[[2]string]string) // (set code, value) -> first uuid
+		for _, r := range rows {
+			code, _ := r["setCode"].(string)
+			v, _ := r["value"].(string)
+			if k := [2]string{code, v}; found[k] == "" {
+				found[k], _ = r["uuid"].(string)
+			}
+		}
+		for i := range entries {
+			if entries[i].UUID != "" || (keys[i].number != "") != byNumber {
+				continue
+			}
+			for _, code := range setCodes(i) {
+				if uuid := found[[2]string{code, value(i)}]; uuid != "" {
+					entries[i].UUID = uuid
+					break
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// uuidsByIdentifier maps each of the given values of a card_identifiers
+// column to the UUID of a printing carrying it.
+func uuidsByIdentifier(ctx context.Context, conn db.Backend, column string, values []any) (map[string]string, error) {
+	uuids := make(map[string]string)
+	if len(values) == 0 {
+		return uuids, nil
+	}
+	if err := conn.EnsureViews(ctx, "card_identifiers"); err != nil {
+		return nil, err
+	}
+	sql, params := db.NewSQLBuilder("card_identifiers").
+		Select("uuid", fmt.Sprintf(`CAST("%s" AS VARCHAR) AS id`, column)).
+		WhereIn(fmt.Sprintf(`CAST("%s" AS VARCHAR)`, column), values).
+		OrderBy("uuid ASC").
+		Build()
+	rows, err := conn.Execute(ctx, sql, params...)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		id, _ := r["id"].(string)
+		if uuids[id] == "" {
+			uuids[id], _ = r["uuid"].(string)
+		}
+	}
+	return uuids, nil
+}
+
+// csvCard is the printing data needed to write a CSV row.
+type csvCard struct {
+	name, setCode, setName, number, rarity, scryfallID, tcgplayerID string
+}
+
+// ExportCSV writes entries as a CSV file in the given format. Entries are
+// identified by UUID or Scryfall ID; entries that cannot be resolved to a
+// printing are written with their Name only. Purchase prices and dates are
+// written in the WithExportLocale format.
+func (q *CollectionQuery) ExportCSV(ctx context.Context, w io.Writer, format CSVFormat, entries []models.CollectionEntry, opts ...ExportOption) error {
+	cfg := newExportConfig(opts)
+	schema, err := lookupCSVSchema(format)
+	if err != nil {
+		return err
+	}
+	entries, err = NewTradeQuery(q.conn).resolve(ctx, entries, false)
+	if err != nil {
+		return err
+	}
+	cards, err := q.loadCSVCards(ctx, entries)
+	if err != nil {
+		return err
+	}
+
+	header := schema.header
+	hasBoard := false
+	for _, e := range entries {
+		if e.Board != "" {
+			hasBoard = true
+			break
+		}
+	}
+	if hasBoard {
+		header = append(append([]string{}, header...), boardColumns[0])
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		c := cards[e.UUID]
+		if c == nil {
+			c = &csvCard{name: e.Name}
+		}
+		condition := e.Condition
+		if condition == "" {
+			condition = schema.defaultCondition
+		}
+		language := e.Language
+		if language == "" {
+			language = "English"
+		}
+		values := map[string]string{
+			schema.quantity:    strconv.Itoa(e.Quantity),
+			schema.name:        c.name,
+			schema.setCode:     strings.ToLower(c.setCode),
+			schema.setName:     c.setName,
+			schema.number:      c.number,
+			schema.finish:      schema.finishLabels[entryFinish(e)],
+			schema.scryfallID:  c.scryfallID,
+			schema.tcgplayerID: c.tcgplayerID,
+			schema.condition:   condition,
+			schema.language:    language,
+			schema.added:       schema.locale(cfg.locale).FormatDate(e.Added),
+			boardColumns[0]:    e.Board,
+		}
+		if e.PurchasePrice != nil {
+			values[schema.purchasePrice] = cfg.locale.FormatPrice(*e.PurchasePrice)
+		}
+		switch format {
+		case CSVTCGPlayer:
+			values[schema.setCode] = c.setCode
+			values["Simple Name"] = c.name
+			values["Rarity"] = c.rarity
+		case CSVArchidekt:
+			values[schema.setCode] = c.setCode
+		}
+		record := make([]string, len(header))
+		for i, h := range header {
+			record[i] = values[h]
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// loadCSVCards fetches printing details and identifiers for the entries.
+func (q *CollectionQuery) loadCSVCards(ctx context.Context, entries []models.CollectionEntry) (map[string]*csvCard, error) {
+	cards := make(map[string]*csvCard)
+	var uuids []any
+	for _, e := range entries {
+		if e.UUID != "" && cards[e.UUID] == nil {
+			cards[e.UUID] = &csvCard{}
+			uuids = append(uuids, e.UUID)
+		}
+	}
+	if len(uuids) == 0 {
+		return cards, nil
+	}
+	if err := q.conn.EnsureViews(ctx, "cards", "sets", "card_identifiers"); err != nil {
+		return nil, err
+	}
+	sql, params := db.NewSQLBuilder("cards c").
+		Select("c.uuid", "c.name", "c.setCode", "c.number", "c.rarity",
+			"s.name AS setName", "ci.scryfallId", "ci.tcgplayerProductId").
+		Join("LEFT JOIN sets s ON s.code = c.setCode").
+		Join("LEFT JOIN card_identifiers ci ON ci.uuid = c.uuid").
+		WhereIn("c.uuid", uuids).
+		Build()
+	rows, err := q.conn.Execute(ctx, sql, params...)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		uuid, _ := r["uuid"].(string)
+		c := cards[uuid]
+		if c == nil {
+			continue
+		}
+		c.name, _ = r["name"].(string)
+		c.setCode, _ = r["setCode"].(string)
+		c.setName, _ = r["setName"].(string)
+		c.number, _ = r["number"].(string)
+		c.rarity, _ = r["rarity"].(string)
+		c.scryfallID, _ = r["scryfallId"].(string)
+		c.tcgplayerID, _ = r["tcgplayerProductId"].(string)
+	}
+	return cards, nil
+}
+
+// DeckEntries flattens a deck into collection entries with Board set, ready
+// for ExportCSV.
+func DeckEntries(deck *models.Deck) []models.CollectionEntry {
+	var entries []models.CollectionEntry
+	add := func(board string, cards []models.CardDeck) {
+		for _, c := range cards {
+			finish := "normal"
+			if c.IsEtched != nil && *c.IsEtched {
+				finish = "etched"
+			} else if c.IsFoil != nil && *c.IsFoil {
+				finish = "foil"
+			}
+			entries = append(entries, models.CollectionEntry{
+				UUID:     c.UUID,
+				Name:     c.Name,
+				Quantity: c.Count,
+				Finish:   finish,
+				Board:    board,
+			})
+		}
+	}
+	add("commander", deck.Commander)
+	add("main", deck.MainBoard)
+	add("side", deck.SideBoard)
+	return entries
+}
+
+// locale returns the locale the format's dates are written in.
+func (s *csvSchema) locale(l models.Locale) models.Locale {
+	if s.isoDates {
+		l.DateLayout = models.LocaleISO.DateLayout
+	}
+	return l
+}
+
+func lookupCSVSchema(format CSVFormat) (*csvSchema, error) {
+	schema, ok := csvSchemas[CSVFormat(strings.ToLower(string(format)))]
+	if !ok {
+		return nil, fmt.Errorf("mtgjson: unknown csv format %q", format)
+	}
+	return schema, nil
+}
+
+// normalizeFinish maps the finish/foil/printing values used by the supported
+// formats onto MTGJSON finishes.
+func normalizeFinish(s string) string {
+	switch strings.ToLower(s) {
+	case "foil", "true", "yes", "1":
+		return "foil"
+	case "etched", "etched foil", "foil etched":
+		return "etched"
+	default:
+		return "normal"
+	}
+}
+
+func normalizeBoard(s string) string {
+	switch strings.ToLower(s) {
+	case "main", "mainboard", "maindeck", "deck":
+		return "main"
+	case "side", "sideboard":
+		return "side"
+	case "maybe", "maybeboard":
+		return "maybe"
+	case "commander", "commanders":
+		return "commander"
+	default:
+		return strings.ToLower(s)
+	}
+}
@@ -0,0 +1,220 @@
+package queries
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+func TestImportMoxfieldCSV(t *testing.T) {
+	q := NewCollectionQuery(setupSampleDB(t))
+	ctx := context.Background()
+
+	data := `"Count","Tradelist Count","Name","Edition","Condition","Language","Foil","Collector Number"
+"4","0","Lightning Bolt","a25","Near Mint","English","foil","141"
+"1","0","Counterspell","mh2","Near Mint","English","",""
+"2","0","Unknown Card","xyz","Near Mint","English","","1"
+`
+	entries, err := q.ImportCSV(ctx, strings.NewReader(data), CSVMoxfield)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	if entries[0].UUID != "card-uuid-001" || entries[0].Quantity != 4 || entries[0].Finish != "foil" {
+		t.Fatalf("unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].UUID != "card-uuid-002" || entries[1].Finish != "normal" {
+		t.Fatalf("expected Counterspell resolved by set and name, got %+v", entries[1])
+	}
+	if entries[2].UUID != "" || entries[2].Name != "Unknown Card" {
+		t.Fatalf("expected unresolved entry to keep its name, got %+v", entries[2])
+	}
+}
+
+func TestImportArchidektCSVByScryfallID(t *testing.T) {
+	q := NewCollectionQuery(setupSampleDB(t))
+	data := "Quantity,Name,Finish,Condition,Edition Name,Edition Code,Scryfall ID,Collector Number\n" +
+		"2,Counterspell,Etched,NM,Modern Horizons 2,MH2,scryfall-002,267\n"
+	entries, err := q.ImportCSV(context.Background(), strings.NewReader(data), CSVArchidekt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].UUID != "card-uuid-002" || entries[0].Finish != "etched" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestImportDeckboxCSVBySetName(t *testing.T) {
+	q := NewCollectionQuery(setupSampleDB(t))
+	data := "Count,Tradelist Count,Name,Edition,Card Number,Condition,Language,Foil\n" +
+		"3,0,Fire // Ice,Masters 25,223a,Near Mint,English,\n"
+	entries, err := q.ImportCSV(context.Background(), strings.NewReader(data), CSVDeckbox)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].UUID != "card-uuid-003" || entries[0].Quantity != 3 {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestImportTCGPlayerCSVByProductID(t *testing.T) {
+	q := NewCollectionQuery(setupSampleDB(t))
+	data := "Quantity,Name,Simple Name,Set,Card Number,Set Code,Printing,Condition,Language,Rarity,Product ID,SKU\n" +
+		"1,Lightning Bolt,Lightning Bolt,Masters 25,141,A25,Foil,Near Mint,English,U,12345,\n"
+	entries, err := q.ImportCSV(context.Background(), strings.NewReader(data), CSVTCGPlayer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].UUID != "card-uuid-001" || entries[0].Finish != "foil" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+}
+
+func TestImportCSVErrors(t *testing.T) {
+	q := NewCollectionQuery(setupSampleDB(t))
+	ctx := context.Background()
+
+	if _, err := q.ImportCSV(ctx, strings.NewReader("Name\n"), CSVFormat("unknown")); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+	if _, err := q.ImportCSV(ctx, strings.NewReader("Count,Edition\n1,a25\n"), CSVMoxfield); err == nil {
+		t.Fatal("expected error for missing Name column")
+	}
+	if _, err := q.ImportCSV(ctx, strings.NewReader("Count,Name\nfour,Lightning Bolt\n"), CSVMoxfield); err == nil {
+		t.Fatal("expected error for invalid quantity")
+	}
+	for _, qty := range []string{"0", "-2"} {
+		data := "Count,Name\n1,Counterspell\n" + qty + ",Lightning Bolt\n"
+		if _, err := q.ImportCSV(ctx, strings.NewReader(data), CSVMoxfield); err == nil || !strings.Contains(err.Error(), "line 3") {
+			t.Fatalf("expected an error naming line 3 for quantity %s, got %v", qty, err)
+		}
+	}
+}
+
+func TestImportCSVBatchesLookups(t *testing.T) {
+	conn := &countingBackend{Connection: setupSampleDB(t)}
+	q := NewCollectionQuery(conn)
+	var data strings.Builder
+	data.WriteString("Quantity,Name,Finish,Category,Edition Name,Edition Code,Scryfall ID,Collector Number\n")
+	for i := 0; i < 20; i++ {
+		data.WriteString("1,Counterspell,Normal,Ramp,Modern Horizons 2,MH2,scryfall-002,267\n")
+		data.WriteString("1,Lightning Bolt,Normal,Sideboard,Masters 25,A25,,141\n")
+		data.WriteString("1,Fire // Ice,Normal,Maybeboard,,A25,,\n")
+	}
+	entries, err := q.ImportCSV(context.Background(), strings.NewReader(data.String()), CSVArchidekt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 60 {
+		t.Fatalf("expected 60 entries, got %d", len(entries))
+	}
+	for i, want := range []struct{ uuid, board string }{
+		{"card-uuid-002", ""}, {"card-uuid-001", "side"}, {"card-uuid-003", "maybe"},
+	} {
+		if e := entries[i]; e.UUID != want.uuid || e.Board != want.board {
+			t.Errorf("entry %d: expected %s on board %q, got %+v", i, want.uuid, want.board, e)
+		}
+	}
+	// Scryfall IDs, product IDs, set and number, set and name: at most one
+	// query each, however many rows.
+	if conn.queries > 5 {
+		t.Errorf("expected lookups batched per identifier kind, ran %d queries", conn.queries)
+	}
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	q := NewCollectionQuery(setupSampleDB(t))
+	ctx := context.Background()
+
+	price := 1.5
+	entries := []models.CollectionEntry{
+		{UUID: "card-uuid-001", Quantity: 4, Finish: "foil", Board: "main", PurchasePrice: &price, Added: "2024-10-16"},
+		{ScryfallID: "scryfall-002", Quantity: 1, Board: "side"},
+	}
+	for _, format := range []CSVFormat{CSVMoxfield, CSVArchidekt, CSVDeckbox, CSVTCGPlayer} {
+		var buf bytes.Buffer
+		if err := q.ExportCSV(ctx, &buf, format, entries, WithExportLocale(models.LocaleGerman)); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		got, err := q.ImportCSV(ctx, &buf, format, WithExportLocale(models.LocaleGerman))
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if len(got) != 2 {
+			t.Fatalf("%s: expected 2 entries, got %d", format, len(got))
+		}
+		if got[0].UUID != "card-uuid-001" || got[0].Quantity != 4 || got[0].Finish != "foil" || got[0].Board != "main" {
+			t.Fatalf("%s: unexpected first entry: %+v", format, got[0])
+		}
+		schema := csvSchemas[format]
+		if schema.purchasePrice != "" && (got[0].PurchasePrice == nil || *got[0].PurchasePrice != 1.5) {
+			t.Fatalf("%s: expected the purchase price back, got %v", format, got[0].PurchasePrice)
+		}
+		if schema.added != "" && got[0].Added != "2024-10-16" {
+			t.Fatalf("%s: expected the date added back, got %q", format, got[0].Added)
+		}
+		if got[1].UUID != "card-uuid-002" || got[1].Board != "side" {
+			t.Fatalf("%s: unexpected second entry: %+v", format, got[1])
+		}
+	}
+}
+
+func TestExportCSVLocale(t *testing.T) {
+	q := NewCollectionQuery(setupSampleDB(t))
+	price := 1.5
+	entries := []models.CollectionEntry{{UUID: "card-uuid-001", Quantity: 1, PurchasePrice: &price, Added: "2024-10-16"}}
+
+	var buf bytes.Buffer
+	if err := q.ExportCSV(context.Background(), &buf, CSVArchidekt, entries, WithExportLocale(models.LocaleGerman)); err != nil {
+		t.Fatal(err)
+	}
+	if out := buf.String(); !strings.Contains(out, `,16.10.2024,`) || !strings.Contains(out, `"1,50"`) {
+		t.Errorf("expected a German date and price, got %q", out)
+	}
+	buf.Reset()
+	if err := q.ExportCSV(context.Background(), &buf, CSVArchidekt, entries); err != nil {
+		t.Fatal(err)
+	}
+	if out := buf.String(); !strings.Contains(out, `,2024-10-16,`) || !strings.Contains(out, `,1.50,`) {
+		t.Errorf("expected ISO date and price by default, got %q", out)
+	}
+	buf.Reset()
+	if err := q.ExportCSV(context.Background(), &buf, CSVMoxfield, entries, WithExportLocale(models.LocaleGerman)); err != nil {
+		t.Fatal(err)
+	}
+	if out := buf.String(); !strings.Contains(out, `,2024-10-16,`) || !strings.Contains(out, `"1,50"`) {
+		t.Errorf("expected Moxfield dates in ISO format, got %q", out)
+	}
+}
+
+func TestImportCSVBadPrice(t *testing.T) {
+	q := NewCollectionQuery(setupSampleDB(t))
+	in := "Quantity,Name,Purchase Price\n1,Lightning Bolt,cheap\n"
+	_, err := q.ImportCSV(context.Background(), strings.NewReader(in), CSVArchidekt)
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("expected an error naming line 2, got %v", err)
+	}
+}
+
+func TestDeckEntries(t *testing.T) {
+	foil := true
+	deck := &models.Deck{
+		MainBoard: []models.CardDeck{{CardSet: models.CardSet{UUID: "card-uuid-001", Name: "Lightning Bolt"}, Count: 4}},
+		SideBoard: []models.CardDeck{{CardSet: models.CardSet{UUID: "card-uuid-002"}, Count: 1, IsFoil: &foil}},
+	}
+	entries := DeckEntries(deck)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Board != "main" || entries[0].Quantity != 4 || entries[0].Finish != "normal" {
+		t.Fatalf("unexpected main entry: %+v", entries[0])
+	}
+	if entries[1].Board != "side" || entries[1].Finish != "foil" {
+		t.Fatalf("unexpected side entry: %+v", entries[1])
+	}
+}
@@ -0,0 +1,78 @@
+package queries
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mtgjson/mtgjson-sdk-go/db"
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+// playsetSize is how many copies of a card a playset holds.
+const playsetSize = 4
+
+// CompletionCost prices collecting every card in a set, by distinct name:
+// buying each card's cheapest printing from the set itself, and buying its
+// cheapest printing from any set, as a singleton (one copy) and a playset
+// (four). Prices are each printing's latest, filtered by the options
+// (TCGplayer normal retail in USD by default). Names with no price at all
+// are listed in Missing; a name priced only outside the set adds nothing
+// to the in-set totals. Returns nil if the set has no cards or no prices
+// can be loaded.
+func (q *SetQuery) CompletionCost(ctx context.Context, code string, opts ...FinancialSummaryOption) (*models.CompletionCost, error) {
+	if err := q.conn.EnsureViews(ctx, "cards"); err != nil {
+		return nil, err
+	}
+	if err := q.conn.EnsureOptionalViews(ctx, "all_prices_today"); err != nil {
+		return nil, err
+	}
+	if !q.conn.HasView("all_prices_today") {
+		return nil, nil
+	}
+	cfg := financialSummaryDefaults()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	code = strings.ToUpper(code)
+
+	var cards []models.CompletionCard
+	if err := q.conn.ExecuteInto(ctx, &cards,
+		"WITH prices AS (SELECT uuid, MIN(CAST(price AS DOUBLE)) AS price FROM "+db.LatestPricesTable+" "+
+			"  WHERE provider = $2 AND currency = $3 AND finish = $4 AND price_type = $5 GROUP BY uuid), "+
+			"names AS (SELECT DISTINCT name FROM cards WHERE setCode = $1), "+
+			"in_set AS (SELECT c.name, arg_min(c.uuid, p.price) AS uuid, MIN(p.price) AS price "+
+			"  FROM cards c JOIN prices p ON p.uuid = c.uuid WHERE c.setCode = $1 GROUP BY c.name), "+
+			"any_set AS (SELECT c.name, arg_min(c.uuid, p.price) AS uuid, arg_min(c.setCode, p.price) AS setCode, "+
+			"  MIN(p.price) AS price "+
+			"  FROM cards c JOIN prices p ON p.uuid = c.uuid WHERE c.name IN (SELECT name FROM names) GROUP BY c.name) "+
+			"SELECT n.name, i.uuid AS inSetUuid, i.price AS inSetPrice, "+
+			"  a.uuid AS cheapestUuid, a.setCode AS cheapestSetCode, a.price AS cheapestPrice "+
+			"FROM names n LEFT JOIN in_set i ON i.name = n.name LEFT JOIN any_set a ON a.name = n.name "+
+			"ORDER BY n.name",
+		code, cfg.provider, cfg.currency, cfg.finish, cfg.priceType); err != nil {
+		return nil, err
+	}
+	if len(cards) == 0 {
+		return nil, nil
+	}
+
+	result := &models.CompletionCost{SetCode: code, Currency: cfg.currency, Names: len(cards), Cards: cards}
+	for _, c := range cards {
+		if c.InSetPrice == nil && c.CheapestPrice == nil {
+			result.Missing = append(result.Missing, c.Name)
+			continue
+		}
+		result.Priced++
+		if c.InSetPrice != nil {
+			result.InSetSingleton += *c.InSetPrice
+		}
+		if c.CheapestPrice != nil {
+			result.AnySetSingleton += *c.CheapestPrice
+		}
+	}
+	result.InSetSingleton = roundCents(result.InSetSingleton)
+	result.AnySetSingleton = roundCents(result.AnySetSingleton)
+	result.InSetPlayset = roundCents(result.InSetSingleton * playsetSize)
+	result.AnySetPlayset = roundCents(result.AnySetSingleton * playsetSize)
+	return result, nil
+}
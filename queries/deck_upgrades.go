@@ -0,0 +1,206 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mtgjson/mtgjson-sdk-go/db"
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+const (
+	// upgradeRelatedPerCard is how many related cards are scored for each
+	// card of the deck.
+	upgradeRelatedPerCard = 20
+	// maxDeckUpgrades caps the number of suggestions Upgrades returns.
+	maxDeckUpgrades = 10
+)
+
+// Upgrades suggests up to 10 cards to add to a preconstructed deck, named by
+// its DeckList fileName (with or without ".json"). Candidates are the
+// related cards of the deck's main board and commanders, scored by the
+// keywords, subtypes, produced mana and rules text they share with it and
+// limited to the deck's color identity. They are taken best first while
+// their cheapest current TCGplayer price fits in what is left of budget;
+// unpriced cards are skipped.
+func (q *DeckQuery) Upgrades(ctx context.Context, deckFile string, budget float64) ([]models.DeckUpgrade, error) {
+	if q.cards == nil || q.prices == nil {
+		return nil, fmt.Errorf("mtgjson: deck upgrades need card and price data")
+	}
+	entry, err := q.byFileName(ctx, deckFile)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("mtgjson: deck file %q not found", deckFile)
+	}
+	deck, err := q.contents(ctx, entry.Code, entry.Name)
+	if err != nil {
+		return nil, err
+	}
+	if deck == nil {
+		return nil, fmt.Errorf("mtgjson: no card list for deck %q (%s)", entry.Name, entry.Code)
+	}
+	if budget <= 0 {
+		return nil, nil
+	}
+
+	var uuids []string
+	for _, c := range append(deck.MainBoard, deck.Commander...) {
+		uuids = append(uuids, c.UUID)
+	}
+	deckCards, err := q.cards.GetByUUIDs(ctx, uuids)
+	if err != nil {
+		return nil, err
+	}
+	identity := map[string]bool{}
+	inDeck := map[string]bool{}
+	for _, c := range deckCards {
+		for _, color := range c.ColorIdentity {
+			identity[color] = true
+		}
+		inDeck[c.Name] = true
+	}
+
+	candidates := map[string]*models.DeckUpgrade{}
+	seen := map[string]bool{}
+	for _, card := range deckCards {
+		if seen[card.Name] || slices.Contains(card.Supertypes, "Basic") {
+			continue
+		}
+		seen[card.Name] = true
+		related, err := q.cards.Related(ctx, card.UUID, upgradeRelatedPerCard)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range related {
+			if inDeck[r.Name] {
+				continue
+			}
+			u := candidates[r.Name]
+			if u == nil {
+				u = &models.DeckUpgrade{UUID: r.UUID, Name: r.Name}
+				candidates[r.Name] = u
+			}
+			u.Score += r.Score
+			u.Synergies = append(u.Synergies, card.Name)
+			u.SharedKeywords = mergeSorted(u.SharedKeywords, r.SharedKeywords)
+			u.SharedSubtypes = mergeSorted(u.SharedSubtypes, r.SharedSubtypes)
+		}
+	}
+	ranked, err := q.withinIdentity(ctx, candidates, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []models.DeckUpgrade
+	left := budget
+	for _, u := range ranked {
+		if len(result) == maxDeckUpgrades {
+			break
+		}
+		row, err := q.prices.CheapestPrinting(ctx, u.Name)
+		if err != nil {
+			return nil, err
+		}
+		if row == nil {
+			continue
+		}
+		price := db.ToFloat64(row["price"])
+		if price > left {
+			continue
+		}
+		left -= price
+		u.Price = price
+		u.Currency, _ = row["currency"].(string)
+		u.SetCode, _ = row["setCode"].(string)
+		u.Number, _ = row["number"].(string)
+		if uuid, _ := row["uuid"].(string); uuid != "" {
+			u.UUID = uuid
+		}
+		result = append(result, *u)
+	}
+	return result, nil
+}
+
+// byFileName returns the deck list entry whose fileName is name, ignoring
+// case and a ".json" suffix, or nil if there is none.
+func (q *DeckQuery) byFileName(ctx context.Context, name string) (*models.DeckList, error) {
+	if err := q.ensure(ctx); err != nil {
+		return nil, err
+	}
+	want := strings.TrimSuffix(strings.ToLower(name), ".json")
+	for _, d := range q.data {
+		if fileName, _ := d["fileName"].(string); strings.TrimSuffix(strings.ToLower(fileName), ".json") == want {
+			decks, err := marshalDeckLists([]map[string]any{d})
+			if err != nil {
+				return nil, err
+			}
+			return &decks[0], nil
+		}
+	}
+	return nil, nil
+}
+
+// contents reads a deck's boards from set_decks, or returns nil if the set
+// has no deck of that name.
+func (q *DeckQuery) contents(ctx context.Context, setCode, name string) (*models.DeckSet, error) {
+	conn := q.cards.conn
+	if err := conn.EnsureViews(ctx, "set_decks"); err != nil {
+		return nil, err
+	}
+	var decks []models.DeckSet
+	if err := conn.ExecuteInto(ctx, &decks,
+		"SELECT code, name, type, mainBoard, commander FROM set_decks WHERE setCode = $1 AND name = $2 ORDER BY code LIMIT 1",
+		setCode, name); err != nil {
+		return nil, err
+	}
+	if len(decks) == 0 {
+		return nil, nil
+	}
+	return &decks[0], nil
+}
+
+// withinIdentity returns the candidates whose color identity is inside
+// identity, best score first.
+func (q *DeckQuery) withinIdentity(ctx context.Context, candidates map[string]*models.DeckUpgrade, identity map[string]bool) ([]*models.DeckUpgrade, error) {
+	uuids := make([]string, 0, len(candidates))
+	for _, u := range candidates {
+		uuids = append(uuids, u.UUID)
+	}
+	cards, err := q.cards.GetByUUIDs(ctx, uuids)
+	if err != nil {
+		return nil, err
+	}
+	var ranked []*models.DeckUpgrade
+	for _, c := range cards {
+		u := candidates[c.Name]
+		if u == nil || u.UUID != c.UUID {
+			continue
+		}
+		fits := true
+		for _, color := range c.ColorIdentity {
+			fits = fits && identity[color]
+		}
+		if fits {
+			ranked = append(ranked, u)
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Name < ranked[j].Name
+	})
+	return ranked, nil
+}
+
+// mergeSorted returns the sorted union of two string lists.
+func mergeSorted(a, b []string) []string {
+	out := slices.Concat(a, b)
+	slices.Sort(out)
+	return slices.Compact(out)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"strings"
+	"sync"
 
 	"github.com/mtgjson/mtgjson-sdk-go/db"
 	"github.com/mtgjson/mtgjson-sdk-go/models"
@@ -13,47 +14,71 @@ import (
 // Decks are loaded from DeckList.json on the CDN (not parquet).
 type DeckQuery struct {
 	cache  *db.CacheManager
+	cards  *CardQuery
+	prices *PriceQuery
+	mu     sync.Mutex
 	data   []map[string]any
 	loaded bool
 }
 
-func NewDeckQuery(cache *db.CacheManager) *DeckQuery {
-	return &DeckQuery{cache: cache}
+// DeckQueryOption configures a DeckQuery.
+type DeckQueryOption func(*DeckQuery)
+
+// WithUpgradeSources sets the card and price modules Upgrades reads deck
+// contents, related cards and prices from.
+func WithUpgradeSources(cards *CardQuery, prices *PriceQuery) DeckQueryOption {
+	return func(q *DeckQuery) {
+		q.cards = cards
+		q.prices = prices
+	}
+}
+
+func NewDeckQuery(cache *db.CacheManager, opts ...DeckQueryOption) *DeckQuery {
+	q := &DeckQuery{cache: cache}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
 }
 
+// ensure loads the deck list once. If the file cannot be fetched the load is
+// retried on the next call; the error is returned in strict mode or when ctx
+// is done, otherwise the deck list is treated as empty.
 func (q *DeckQuery) ensure(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
 	if q.loaded {
 		return nil
 	}
 	raw, err := q.cache.LoadJSON(ctx, "deck_list")
 	if err != nil {
-		// If file not found, treat as empty
-		q.data = nil
-		q.loaded = true
+		if ctx.Err() != nil || q.cache.Strict {
+			return err
+		}
 		return nil
 	}
+	q.data = parseDeckList(raw)
+	q.loaded = true
+	return nil
+}
+
+// parseDeckList extracts the deck array from DeckList.json. Malformed data is
+// treated as empty.
+func parseDeckList(raw map[string]any) []map[string]any {
 	dataRaw, ok := raw["data"]
 	if !ok {
-		q.data = nil
-		q.loaded = true
 		return nil
 	}
 	// data is an array of objects
 	jsonBytes, err := json.Marshal(dataRaw)
 	if err != nil {
-		q.data = nil
-		q.loaded = true
 		return nil
 	}
 	var decks []map[string]any
 	if err := json.Unmarshal(jsonBytes, &decks); err != nil {
-		q.data = nil
-		q.loaded = true
 		return nil
 	}
-	q.data = decks
-	q.loaded = true
-	return nil
+	return decks
 }
 
 // List returns available decks with optional filters.
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
 	"github.com/mtgjson/mtgjson-sdk-go/db"
@@ -186,3 +187,129 @@ func TestDeckSearchNoResults(t *testing.T) {
 		t.Fatalf("expected nil, got %v", decks)
 	}
 }
+
+func TestDeckConcurrentFirstUse(t *testing.T) {
+	dq := setupDeckQuery(t)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	counts := make([]int, 8)
+	for i := range counts {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			n, err := dq.Count(ctx)
+			if err != nil {
+				t.Error(err)
+			}
+			counts[i] = n
+		}(i)
+	}
+	wg.Wait()
+	for _, n := range counts {
+		if n != 4 {
+			t.Fatalf("expected every goroutine to see 4 decks, got %v", counts)
+		}
+	}
+}
+
+func TestDeckMissingFileStrict(t *testing.T) {
+	cfg := db.DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.Offline = true
+	cfg.Strict = true
+	cache, err := db.NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cache.Close() })
+
+	dq := NewDeckQuery(cache)
+	if _, err := dq.Count(context.Background()); err == nil {
+		t.Fatal("expected strict mode to surface the missing deck list")
+	}
+	// The failed load is not cached: once the file appears it is picked up.
+	os.WriteFile(filepath.Join(cfg.CacheDir, "DeckList.json"), []byte(`{"data":[{"code":"MH3","name":"X"}]}`), 0o644)
+	if n, err := dq.Count(context.Background()); err != nil || n != 1 {
+		t.Fatalf("expected 1 deck after retry, got %d, %v", n, err)
+	}
+}
+
+func TestDeckUpgrades(t *testing.T) {
+	dq := setupDeckQuery(t)
+	conn := setupSampleDB(t)
+	ctx := context.Background()
+	elf := func(uuid, name string, identity ...any) map[string]any {
+		return map[string]any{
+			"uuid": uuid, "name": name, "keywords": []any{}, "supertypes": []any{},
+			"subtypes": []any{"Elf", "Druid"}, "producedMana": []any{"G"},
+			"colorIdentity": identity, "text": "{T}: Add {G}.", "setCode": "MH3", "number": uuid[3:],
+		}
+	}
+	cards := []map[string]any{
+		elf("up-001", "Llanowar Elves", "G"),
+		{
+			"uuid": "up-002", "name": "Forest", "keywords": []any{}, "supertypes": []any{"Basic"},
+			"subtypes": []any{"Forest"}, "producedMana": []any{"G"}, "colorIdentity": []any{"G"}, "text": "",
+			"setCode": "MH3", "number": "002",
+		},
+		elf("up-003", "Elvish Mystic", "G"),
+		elf("up-004", "Elvish Archdruid", "G"),
+		elf("up-005", "Priest of Titania", "G"),
+		elf("up-006", "Golgari Elf", "B", "G"),
+		elf("up-007", "Unpriced Elf", "G"),
+		{
+			"uuid": "up-008", "name": "Grizzly Bears", "keywords": []any{}, "supertypes": []any{},
+			"subtypes": []any{"Bear"}, "producedMana": []any{}, "colorIdentity": []any{"G"}, "text": "",
+			"setCode": "MH3", "number": "008",
+		},
+	}
+	var prices []map[string]any
+	for uuid, price := range map[string]float64{"up-003": 1, "up-004": 4, "up-005": 2, "up-006": 0.5, "up-008": 0.25} {
+		prices = append(prices, map[string]any{
+			"uuid": uuid, "source": "paper", "provider": "tcgplayer", "currency": "USD",
+			"price_type": "retail", "finish": "normal", "date": "2024-01-03", "price": price,
+		})
+	}
+	decks := []map[string]any{{
+		"setCode": "MH3", "code": "MH3_DECK1", "name": "Creative Energy", "type": "Commander Deck",
+		"mainBoard": []any{
+			map[string]any{"uuid": "up-001", "count": 1},
+			map[string]any{"uuid": "up-002", "count": 30},
+		},
+		"commander": []any{},
+	}}
+	for name, rows := range map[string][]map[string]any{"cards": cards, "all_prices_today": prices, "set_decks": decks} {
+		if err := conn.RegisterTableFromData(ctx, name, rows); err != nil {
+			t.Fatal(err)
+		}
+	}
+	WithUpgradeSources(NewCardQuery(conn), NewPriceQuery(conn))(dq)
+
+	upgrades, err := dq.Upgrades(ctx, "Creative_Energy_MH3", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(upgrades) != 2 || upgrades[0].Name != "Elvish Mystic" || upgrades[1].Name != "Priest of Titania" {
+		t.Fatalf("expected Elvish Mystic and Priest of Titania within budget, got %+v", upgrades)
+	}
+	if upgrades[0].Price != 1 || upgrades[0].Currency != "USD" || len(upgrades[0].Synergies) != 1 ||
+		upgrades[0].Synergies[0] != "Llanowar Elves" || len(upgrades[0].SharedSubtypes) != 2 {
+		t.Errorf("unexpected suggestion details: %+v", upgrades[0])
+	}
+
+	upgrades, err = dq.Upgrades(ctx, "creative_energy_mh3.json", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(upgrades) != 3 || upgrades[0].Name != "Elvish Archdruid" {
+		t.Errorf("expected all three in-color elves for a larger budget, got %+v", upgrades)
+	}
+
+	if _, err := dq.Upgrades(ctx, "Missing_Deck", 10); err == nil {
+		t.Error("expected an error for an unknown deck file")
+	}
+	if _, err := NewDeckQuery(dq.cache).Upgrades(ctx, "Creative_Energy_MH3", 10); err == nil {
+		t.Error("expected an error without card and price sources")
+	}
+}
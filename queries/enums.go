@@ -2,12 +2,31 @@ package queries
 
 import (
 	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"unicode"
 
 	"github.com/mtgjson/mtgjson-sdk-go/db"
 )
 
+// Replace the embedded snapshots with the CDN's files:
+//go:generate sh -c "for f in Keywords CardTypes EnumValues; do curl -sSfL https://mtgjson.com/api/v5/$f.json -o reference/$f.json; done"
+
+//go:embed reference/*.json
+var referenceFS embed.FS
+
+// referenceFiles maps JSON file names to their embedded snapshots.
+var referenceFiles = map[string]string{
+	"keywords":    "reference/Keywords.json",
+	"card_types":  "reference/CardTypes.json",
+	"enum_values": "reference/EnumValues.json",
+}
+
 // EnumQuery provides methods to query MTGJSON keywords, card types, and enum values.
-// Data is loaded from JSON files on the CDN (not parquet).
+// A snapshot of each file is embedded in the binary, so these work offline
+// without any download; the CDN copy is only fetched when it is newer.
 type EnumQuery struct {
 	cache *db.CacheManager
 }
@@ -19,38 +38,122 @@ func NewEnumQuery(cache *db.CacheManager) *EnumQuery {
 // Keywords returns all MTG keyword categories and their values.
 // Returns a map like {"abilityWords": ["Addendum", ...], "keywordActions": [...]}.
 func (q *EnumQuery) Keywords(ctx context.Context) (map[string]any, error) {
-	raw, err := q.cache.LoadJSON(ctx, "keywords")
-	if err != nil {
-		return nil, err
-	}
-	if data, ok := raw["data"].(map[string]any); ok {
-		return data, nil
-	}
-	return map[string]any{}, nil
+	return q.data(ctx, "keywords")
 }
 
 // CardTypes returns all card types with their valid sub- and supertypes.
 // Returns a map like {"creature": {"subTypes": [...], "superTypes": [...]}}.
 func (q *EnumQuery) CardTypes(ctx context.Context) (map[string]any, error) {
-	raw, err := q.cache.LoadJSON(ctx, "card_types")
+	return q.data(ctx, "card_types")
+}
+
+// EnumValues returns all enumerated values used by MTGJSON fields.
+// Returns a map like {"colors": ["B", "G", "R", "U", "W"], ...}.
+func (q *EnumQuery) EnumValues(ctx context.Context) (map[string]any, error) {
+	return q.data(ctx, "enum_values")
+}
+
+// data returns the "data" object of a reference file.
+func (q *EnumQuery) data(ctx context.Context, name string) (map[string]any, error) {
+	raw, err := q.load(ctx, name)
 	if err != nil {
 		return nil, err
 	}
-	if data, ok := raw["data"].(map[string]any); ok {
-		return data, nil
+	data, ok := raw["data"].(map[string]any)
+	if !ok {
+		data = map[string]any{}
 	}
-	return map[string]any{}, nil
+	return data, nil
 }
 
-// EnumValues returns all enumerated values used by MTGJSON fields.
-// Returns a map like {"colors": ["B", "G", "R", "U", "W"], ...}.
-func (q *EnumQuery) EnumValues(ctx context.Context) (map[string]any, error) {
-	raw, err := q.cache.LoadJSON(ctx, "enum_values")
+// load returns the newest available copy of a reference file. When the CDN
+// reports a version no newer than the embedded snapshot, no download is made.
+// A cached or downloaded copy is used unless its meta version is older than
+// the snapshot; if it cannot be loaded the snapshot is returned.
+func (q *EnumQuery) load(ctx context.Context, name string) (map[string]any, error) {
+	embedded, err := embeddedReference(name)
 	if err != nil {
 		return nil, err
 	}
-	if data, ok := raw["data"].(map[string]any); ok {
-		return data, nil
+	if !q.cache.Offline {
+		if remote := q.cache.RemoteVersion(ctx); remote != "" && compareVersions(remote, metaVersion(embedded)) <= 0 {
+			return embedded, nil
+		}
+	}
+	raw, err := q.cache.LoadJSON(ctx, name)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		q.cache.Logger().Debug("Using embedded reference data", "file", name, "error", err)
+		return embedded, nil
+	}
+	if v := metaVersion(raw); v != "" && compareVersions(v, metaVersion(embedded)) < 0 {
+		return embedded, nil
+	}
+	return raw, nil
+}
+
+func embeddedReference(name string) (map[string]any, error) {
+	path, ok := referenceFiles[name]
+	if !ok {
+		return nil, fmt.Errorf("mtgjson: no embedded reference file %q", name)
+	}
+	data, err := referenceFS.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("mtgjson: read embedded %s: %w", path, err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("mtgjson: decode embedded %s: %w", path, err)
+	}
+	return raw, nil
+}
+
+// metaVersion returns meta.version from an MTGJSON file, or "".
+func metaVersion(raw map[string]any) string {
+	meta, _ := raw["meta"].(map[string]any)
+	v, _ := meta["version"].(string)
+	return v
+}
+
+// compareVersions compares MTGJSON versions such as "5.2.2+20240101" by their
+// numeric components, returning -1, 0 or 1. Missing components count as zero.
+func compareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := 0; i < max(len(pa), len(pb)); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionParts(v string) []int {
+	var parts []int
+	start := -1
+	for i, r := range v + "." {
+		if unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			n, _ := strconv.Atoi(v[start:i])
+			parts = append(parts, n)
+			start = -1
+		}
 	}
-	return map[string]any{}, nil
+	return parts
 }
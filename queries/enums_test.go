@@ -136,3 +136,74 @@ func TestEnumValues(t *testing.T) {
 		t.Fatalf("expected 5 colors, got %d", len(colors))
 	}
 }
+
+func TestEnumsEmbeddedOffline(t *testing.T) {
+	cfg := db.DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.Offline = true
+	cache, err := db.NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eq := NewEnumQuery(cache)
+	ctx := context.Background()
+
+	kw, err := eq.Keywords(ctx)
+	if err != nil {
+		t.Fatalf("expected the embedded snapshot without an error, got %v", err)
+	}
+	abilities, _ := kw["keywordAbilities"].([]any)
+	found := false
+	for _, a := range abilities {
+		if a == "Flying" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected embedded keyword abilities to include Flying, got %d abilities", len(abilities))
+	}
+	types, err := eq.CardTypes(ctx)
+	if err != nil || types["creature"] == nil {
+		t.Fatalf("expected embedded card types, got %v (%v)", types, err)
+	}
+	vals, err := eq.EnumValues(ctx)
+	if err != nil || vals["card"] == nil {
+		t.Fatalf("expected embedded enum values, got %v (%v)", vals, err)
+	}
+	if entries, _ := os.ReadDir(cfg.CacheDir); len(entries) != 0 {
+		t.Fatalf("expected no files to be written, found %d", len(entries))
+	}
+}
+
+func TestEnumsPreferNewerCachedCopy(t *testing.T) {
+	cache := setupEnumCache(t)
+	writeJSON(t, filepath.Join(cache.CacheDir, "Keywords.json"), map[string]any{
+		"meta": map[string]any{"version": "5.2.2+20240101"},
+		"data": map[string]any{"abilityWords": []any{"Landfall"}},
+	})
+	kw, err := NewEnumQuery(cache).Keywords(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if aw, _ := kw["abilityWords"].([]any); len(aw) != 1 {
+		t.Fatalf("expected the newer cached copy, got %v", kw)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"5.2.2+20240101", "5.2.2+20240101", 0},
+		{"5.2.2+20240102", "5.2.2+20240101", 1},
+		{"5.2.1+20250101", "5.2.2+20240101", -1},
+		{"5.2.2", "5.2.2+20240101", -1},
+		{"0.0.0+snapshot", "5.2.2", -1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package queries
+
+import "github.com/mtgjson/mtgjson-sdk-go/models"
+
+// exportConfig holds the settings of one export.
+type exportConfig struct {
+	locale   models.Locale
+	setCodes []string
+}
+
+// ExportOption configures ExportCSV and ExportCardmarket.
+type ExportOption func(*exportConfig)
+
+// WithExportLocale writes prices and dates in a locale's format, e.g.
+// models.LocaleGerman for "1,50" and "16.10.2024". The default is
+// MTGJSON's own "1.50" and "2024-10-16".
+func WithExportLocale(l models.Locale) ExportOption {
+	return func(c *exportConfig) { c.locale = l }
+}
+
+// WithExportSets restricts ExportCardmarket to the given set codes.
+func WithExportSets(codes ...string) ExportOption {
+	return func(c *exportConfig) { c.setCodes = append(c.setCodes, codes...) }
+}
+
+func newExportConfig(opts []ExportOption) *exportConfig {
+	cfg := &exportConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
@@ -0,0 +1,125 @@
+package queries
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/mtgjson/mtgjson-sdk-go/db"
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+// ForeignDataQuery reports on the translated card data in card_foreign_data.
+type ForeignDataQuery struct {
+	conn db.Backend
+}
+
+func NewForeignDataQuery(conn db.Backend) *ForeignDataQuery {
+	return &ForeignDataQuery{conn: conn}
+}
+
+// Languages lists the languages of the foreign data with the number of
+// cards (UUIDs) translated into each, most cards first.
+func (q *ForeignDataQuery) Languages(ctx context.Context) ([]models.LanguageCount, error) {
+	if err := q.conn.EnsureViews(ctx, "card_foreign_data"); err != nil {
+		return nil, err
+	}
+	var langs []models.LanguageCount
+	err := q.conn.ExecuteInto(ctx, &langs,
+		"SELECT language, COUNT(DISTINCT uuid) AS cards FROM card_foreign_data "+
+			"WHERE language IS NOT NULL GROUP BY language ORDER BY cards DESC, language")
+	if err != nil {
+		return nil, err
+	}
+	return langs, nil
+}
+
+// CoverageForSet reports how many of a set's cards have a translation in
+// each language, most translated first. Returns nil if the set has no cards.
+func (q *ForeignDataQuery) CoverageForSet(ctx context.Context, code string) (*models.LanguageCoverage, error) {
+	if err := q.conn.EnsureViews(ctx, "cards", "card_foreign_data"); err != nil {
+		return nil, err
+	}
+	code = strings.ToUpper(code)
+	val, err := q.conn.ExecuteScalar(ctx, "SELECT COUNT(*) FROM cards WHERE setCode = $1", code)
+	if err != nil {
+		return nil, err
+	}
+	total := db.ScalarToInt(val)
+	if total == 0 {
+		return nil, nil
+	}
+	cov := &models.LanguageCoverage{SetCode: code, Cards: total, Languages: []models.LanguageTranslated{}}
+	err = q.conn.ExecuteInto(ctx, &cov.Languages,
+		"SELECT cfd.language, COUNT(DISTINCT cfd.uuid) AS translated FROM card_foreign_data cfd "+
+			"JOIN cards c ON c.uuid = cfd.uuid WHERE c.setCode = $1 AND cfd.language IS NOT NULL "+
+			"GROUP BY cfd.language ORDER BY translated DESC, cfd.language", code)
+	if err != nil {
+		return nil, err
+	}
+	for i := range cov.Languages {
+		cov.Languages[i].Coverage = float64(cov.Languages[i].Translated) / float64(total)
+	}
+	return cov, nil
+}
+
+// Availability reports which languages a card is printed in and in which
+// sets, for buyers hunting a localized printing. Each printing's languages
+// are its own (usually English) plus those of its foreign data, with the
+// localized names; SetLanguages are the languages its set was printed in,
+// which may include ones MTGJSON has no translation for. Printings are
+// newest first. Returns nil if no card has the name.
+func (q *ForeignDataQuery) Availability(ctx context.Context, name string) (*models.CardAvailability, error) {
+	if err := q.conn.EnsureViews(ctx, "cards", "sets", "card_foreign_data"); err != nil {
+		return nil, err
+	}
+	var printings []models.PrintingAvailability
+	err := q.conn.ExecuteInto(ctx, &printings,
+		"SELECT c.uuid, c.setCode, s.name AS setName, CAST(s.releaseDate AS VARCHAR) AS releaseDate, "+
+			"c.number, c.language, s.languages AS setLanguages "+
+			"FROM cards c LEFT JOIN sets s ON s.code = c.setCode WHERE c.name = $1 "+
+			"ORDER BY s.releaseDate DESC NULLS LAST, c.setCode, c.number", name)
+	if err != nil {
+		return nil, err
+	}
+	if len(printings) == 0 {
+		return nil, nil
+	}
+	rows, err := q.conn.Execute(ctx,
+		"SELECT cfd.uuid, cfd.language, cfd.name FROM card_foreign_data cfd "+
+			"JOIN cards c ON c.uuid = cfd.uuid WHERE c.name = $1 AND cfd.language IS NOT NULL "+
+			"ORDER BY cfd.language", name)
+	if err != nil {
+		return nil, err
+	}
+	foreign := map[string][]map[string]any{}
+	for _, row := range rows {
+		uuid, _ := row["uuid"].(string)
+		foreign[uuid] = append(foreign[uuid], row)
+	}
+
+	result := &models.CardAvailability{Name: name, Printings: printings}
+	for i := range printings {
+		p := &printings[i]
+		if p.Language != "" {
+			p.Languages = append(p.Languages, p.Language)
+		}
+		for _, row := range foreign[p.UUID] {
+			lang, _ := row["language"].(string)
+			localized, _ := row["name"].(string)
+			if !slices.Contains(p.Languages, lang) {
+				p.Languages = append(p.Languages, lang)
+			}
+			if localized != "" {
+				if p.LocalizedNames == nil {
+					p.LocalizedNames = map[string]string{}
+				}
+				p.LocalizedNames[lang] = localized
+			}
+		}
+		result.Languages = append(result.Languages, p.Languages...)
+	}
+	slices.Sort(result.Languages)
+	result.Languages = slices.Compact(result.Languages)
+	return result, nil
+}
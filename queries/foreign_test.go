@@ -0,0 +1,93 @@
+package queries
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestForeignDataLanguages(t *testing.T) {
+	conn := setupSampleDB(t)
+	q := NewForeignDataQuery(conn)
+	langs, err := q.Languages(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{"French": 2, "German": 1, "Japanese": 1}
+	if len(langs) != len(want) {
+		t.Fatalf("expected %d languages, got %+v", len(want), langs)
+	}
+	if langs[0].Language != "French" {
+		t.Errorf("expected the most translated language first, got %+v", langs)
+	}
+	for _, l := range langs {
+		if want[l.Language] != l.Cards {
+			t.Errorf("%s: expected %d cards, got %d", l.Language, want[l.Language], l.Cards)
+		}
+	}
+}
+
+func TestForeignDataCoverageForSet(t *testing.T) {
+	conn := setupSampleDB(t)
+	q := NewForeignDataQuery(conn)
+	ctx := context.Background()
+
+	cov, err := q.CoverageForSet(ctx, "a25")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cov == nil || cov.SetCode != "A25" || cov.Cards != 2 {
+		t.Fatalf("expected 2 A25 cards, got %+v", cov)
+	}
+	if len(cov.Languages) != 3 {
+		t.Fatalf("expected 3 languages, got %+v", cov.Languages)
+	}
+	for _, l := range cov.Languages {
+		if l.Translated != 1 || l.Coverage != 0.5 {
+			t.Errorf("%s: expected 1 of 2 cards translated, got %+v", l.Language, l)
+		}
+	}
+
+	cov, err = q.CoverageForSet(ctx, "ZZZ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cov != nil {
+		t.Errorf("expected nil for an unknown set, got %+v", cov)
+	}
+}
+
+func TestForeignDataAvailability(t *testing.T) {
+	conn := setupSampleDB(t)
+	q := NewForeignDataQuery(conn)
+	ctx := context.Background()
+
+	av, err := q.Availability(ctx, "Lightning Bolt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if av == nil || len(av.Printings) != 1 {
+		t.Fatalf("expected one printing, got %+v", av)
+	}
+	if got := strings.Join(av.Languages, ","); got != "English,French,German,Japanese" {
+		t.Errorf("unexpected languages: %s", got)
+	}
+	p := av.Printings[0]
+	if p.SetCode != "A25" || p.SetName != "Masters 25" || p.Number != "141" {
+		t.Errorf("unexpected printing: %+v", p)
+	}
+	if p.LocalizedNames["German"] != "Blitzschlag" || len(p.LocalizedNames) != 3 {
+		t.Errorf("unexpected localized names: %v", p.LocalizedNames)
+	}
+	if len(p.SetLanguages) != 1 || p.SetLanguages[0] != "English" {
+		t.Errorf("unexpected set languages: %v", p.SetLanguages)
+	}
+
+	av, err = q.Availability(ctx, "No Such Card")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if av != nil {
+		t.Errorf("expected nil for an unknown card, got %+v", av)
+	}
+}
@@ -0,0 +1,181 @@
+package queries
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/mtgjson/mtgjson-sdk-go/db"
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+// Standard rotation rules (three-year policy). A Standard year begins with the
+// fall set released on or after July 1. Sets stay legal for three Standard
+// years and rotate out together with the release of the first Standard set on
+// or after April 1 of the rotation year. Rotation dates computed for sets that
+// predate this policy are approximate; those sets have long since rotated.
+const (
+	standardLegalYears     = 3
+	standardYearStartMonth = time.July
+	standardRotationMonth  = time.April
+)
+
+// pioneerStartDate is the release date of Return to Ravnica, the first
+// Pioneer-legal set. Pioneer is non-rotating.
+const pioneerStartDate = "2012-10-05"
+
+// standardSetTypes are the set types that enter Standard on release.
+var standardSetTypes = []any{"expansion", "core"}
+
+// FormatQuery provides format-level helpers such as the Standard rotation calendar.
+// Results are derived from set types and release dates in the sets table.
+type FormatQuery struct {
+	conn db.Backend
+}
+
+func NewFormatQuery(conn db.Backend) *FormatQuery {
+	return &FormatQuery{conn: conn}
+}
+
+// standardSets returns all paper Standard-eligible sets ordered by release date.
+func (q *FormatQuery) standardSets(ctx context.Context) ([]models.SetList, error) {
+	if err := q.conn.EnsureViews(ctx, "sets"); err != nil {
+		return nil, err
+	}
+	b := db.NewSQLBuilder("sets").
+		WhereIn("type", standardSetTypes).
+		OrderBy("releaseDate ASC", "code ASC")
+	b.AddWhere("(isOnlineOnly IS NULL OR isOnlineOnly = false)")
+	sql, params := b.Build()
+	var sets []models.SetList
+	if err := q.conn.ExecuteInto(ctx, &sets, sql, params...); err != nil {
+		return nil, err
+	}
+	return sets, nil
+}
+
+// CurrentStandardSets returns the sets legal in Standard today.
+func (q *FormatQuery) CurrentStandardSets(ctx context.Context) ([]models.SetList, error) {
+	return q.StandardSetsAt(ctx, time.Now())
+}
+
+// StandardSetsAt returns the sets legal in Standard on the given date.
+// Sets whose rotation date is not yet known are treated as legal.
+func (q *FormatQuery) StandardSetsAt(ctx context.Context, at time.Time) ([]models.SetList, error) {
+	sets, err := q.standardSets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result []models.SetList
+	for _, s := range sets {
+		released, ok := parseDate(s.ReleaseDate)
+		if !ok || released.After(at) {
+			continue
+		}
+		if rot, ok := rotationDate(released, sets); ok && !rot.After(at) {
+			continue
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+// RotationDate returns the date a set rotates out of Standard, or nil if the
+// set is not a Standard set or its rotation date cannot be determined yet
+// (no Standard set has been announced for the rotation window).
+func (q *FormatQuery) RotationDate(ctx context.Context, setCode string) (*time.Time, error) {
+	sets, err := q.standardSets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	code := strings.ToUpper(setCode)
+	for _, s := range sets {
+		if s.Code != code {
+			continue
+		}
+		released, ok := parseDate(s.ReleaseDate)
+		if !ok {
+			return nil, nil
+		}
+		if rot, ok := rotationDate(released, sets); ok {
+			return &rot, nil
+		}
+		return nil, nil
+	}
+	return nil, nil
+}
+
+// WillRotateBy returns the sets currently legal in Standard that rotate out on
+// or before the given date.
+func (q *FormatQuery) WillRotateBy(ctx context.Context, date time.Time) ([]models.SetList, error) {
+	return q.rotatingBetween(ctx, time.Now(), date)
+}
+
+// rotatingBetween returns the sets legal in Standard at from that rotate out on
+// or before to.
+func (q *FormatQuery) rotatingBetween(ctx context.Context, from, to time.Time) ([]models.SetList, error) {
+	sets, err := q.standardSets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current, err := q.StandardSetsAt(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	var result []models.SetList
+	for _, s := range current {
+		released, _ := parseDate(s.ReleaseDate)
+		if rot, ok := rotationDate(released, sets); ok && !rot.After(to) {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+// PioneerSets returns all sets legal in Pioneer (Return to Ravnica onward).
+func (q *FormatQuery) PioneerSets(ctx context.Context) ([]models.SetList, error) {
+	sets, err := q.standardSets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	start, _ := parseDate(pioneerStartDate)
+	now := time.Now()
+	var result []models.SetList
+	for _, s := range sets {
+		released, ok := parseDate(s.ReleaseDate)
+		if !ok || released.Before(start) || released.After(now) {
+			continue
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+// rotationDate computes when a set released on the given date rotates out of
+// Standard. sets must be ordered by release date. Returns false if no Standard
+// set is known for the rotation window yet.
+func rotationDate(released time.Time, sets []models.SetList) (time.Time, bool) {
+	standardYear := released.Year()
+	if released.Month() < standardYearStartMonth {
+		standardYear--
+	}
+	threshold := time.Date(standardYear+standardLegalYears, standardRotationMonth, 1, 0, 0, 0, 0, time.UTC)
+	for _, s := range sets {
+		d, ok := parseDate(s.ReleaseDate)
+		if ok && !d.Before(threshold) {
+			return d, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func parseDate(s string) (time.Time, bool) {
+	if len(s) > 10 {
+		s = s[:10]
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -0,0 +1,126 @@
+package queries
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+var sampleStandardSets = []map[string]any{
+	{"code": "RTR", "name": "Return to Ravnica", "type": "expansion", "releaseDate": "2012-10-05", "isOnlineOnly": false},
+	{"code": "DMU", "name": "Dominaria United", "type": "expansion", "releaseDate": "2022-09-09", "isOnlineOnly": false},
+	{"code": "ONE", "name": "Phyrexia: All Will Be One", "type": "expansion", "releaseDate": "2023-02-10", "isOnlineOnly": false},
+	{"code": "WOE", "name": "Wilds of Eldraine", "type": "expansion", "releaseDate": "2023-09-08", "isOnlineOnly": false},
+	{"code": "DFT", "name": "Aetherdrift", "type": "expansion", "releaseDate": "2025-02-14", "isOnlineOnly": false},
+	{"code": "TDM", "name": "Tarkir: Dragonstorm", "type": "expansion", "releaseDate": "2025-04-11", "isOnlineOnly": false},
+	{"code": "MH3", "name": "Modern Horizons 3", "type": "draft_innovation", "releaseDate": "2024-06-14", "isOnlineOnly": false},
+	{"code": "Y24", "name": "Alchemy 2024", "type": "alchemy", "releaseDate": "2024-01-01", "isOnlineOnly": true},
+}
+
+func setupFormatQuery(t *testing.T) *FormatQuery {
+	t.Helper()
+	conn := setupSampleDB(t)
+	if err := conn.RegisterTableFromData(context.Background(), "sets", sampleStandardSets); err != nil {
+		t.Fatal(err)
+	}
+	return NewFormatQuery(conn)
+}
+
+func setCodes(t *testing.T, q *FormatQuery, at time.Time) map[string]bool {
+	t.Helper()
+	sets, err := q.StandardSetsAt(context.Background(), at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes := make(map[string]bool, len(sets))
+	for _, s := range sets {
+		codes[s.Code] = true
+	}
+	return codes
+}
+
+func TestRotationDate(t *testing.T) {
+	q := setupFormatQuery(t)
+	ctx := context.Background()
+
+	for _, code := range []string{"DMU", "one"} {
+		rot, err := q.RotationDate(ctx, code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rot == nil || rot.Format("2006-01-02") != "2025-04-11" {
+			t.Fatalf("%s: expected rotation 2025-04-11, got %v", code, rot)
+		}
+	}
+
+	// WOE rotates in 2026, which has no known set yet
+	rot, err := q.RotationDate(ctx, "WOE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rot != nil {
+		t.Fatalf("expected unknown rotation for WOE, got %v", rot)
+	}
+
+	// Non-Standard sets never rotate
+	rot, err = q.RotationDate(ctx, "MH3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rot != nil {
+		t.Fatalf("expected nil rotation for MH3, got %v", rot)
+	}
+}
+
+func TestStandardSetsAt(t *testing.T) {
+	q := setupFormatQuery(t)
+
+	before := setCodes(t, q, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+	for _, code := range []string{"DMU", "ONE", "WOE", "DFT"} {
+		if !before[code] {
+			t.Fatalf("expected %s in Standard before rotation, got %v", code, before)
+		}
+	}
+	if before["RTR"] || before["MH3"] || before["TDM"] || before["Y24"] {
+		t.Fatalf("unexpected sets in Standard: %v", before)
+	}
+
+	after := setCodes(t, q, time.Date(2025, 4, 11, 0, 0, 0, 0, time.UTC))
+	if after["DMU"] || after["ONE"] {
+		t.Fatalf("expected DMU and ONE rotated out, got %v", after)
+	}
+	for _, code := range []string{"WOE", "DFT", "TDM"} {
+		if !after[code] {
+			t.Fatalf("expected %s in Standard after rotation, got %v", code, after)
+		}
+	}
+}
+
+func TestRotatingBetween(t *testing.T) {
+	q := setupFormatQuery(t)
+	sets, err := q.rotatingBetween(context.Background(),
+		time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sets) != 2 || sets[0].Code != "DMU" || sets[1].Code != "ONE" {
+		t.Fatalf("expected DMU and ONE to rotate, got %v", sets)
+	}
+}
+
+func TestPioneerSets(t *testing.T) {
+	q := setupFormatQuery(t)
+	sets, err := q.PioneerSets(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sets) == 0 || sets[0].Code != "RTR" {
+		t.Fatalf("expected Pioneer to start with RTR, got %v", sets)
+	}
+	for _, s := range sets {
+		if s.Code == "MH3" {
+			t.Fatal("MH3 is not Pioneer legal")
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package queries
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+// levenshteinThreshold is the minimum Levenshtein similarity for a fuzzy
+// name match when DuckDB lacks jaro_winkler_similarity. It is stricter than
+// the 0.8 Jaro-Winkler threshold, which scores shared prefixes generously.
+const levenshteinThreshold = 0.7
+
+// fuzzyCandidate is a card matched by fuzzyFallbackMatches.
+type fuzzyCandidate struct {
+	uuid, number string
+	score        float64
+}
+
+// searchFuzzyFallback runs a FuzzyName search without jaro_winkler_similarity:
+// the other filters select candidate names in DuckDB, which are then ranked by
+// Levenshtein similarity in Go before the page is fetched.
+func (q *CardQuery) searchFuzzyFallback(ctx context.Context, p SearchCardsParams) ([]models.CardSet, error) {
+	matches, err := q.fuzzyFallbackMatches(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	start := min(p.Offset, len(matches))
+	matches = matches[start:min(start+searchLimit(p), len(matches))]
+	uuids := make([]string, len(matches))
+	for i, m := range matches {
+		uuids[i] = m.uuid
+	}
+	cards, err := q.GetByUUIDs(ctx, uuids)
+	if err != nil {
+		return nil, err
+	}
+	byUUID := make(map[string]models.CardSet, len(cards))
+	for _, c := range cards {
+		byUUID[c.UUID] = c
+	}
+	ranked := make([]models.CardSet, 0, len(cards))
+	for _, uuid := range uuids {
+		if c, ok := byUUID[uuid]; ok {
+			ranked = append(ranked, c)
+		}
+	}
+	return ranked, nil
+}
+
+// fuzzyFallbackMatches returns every card matching p, with FuzzyName
+// scored by Levenshtein similarity, best match first.
+func (q *CardQuery) fuzzyFallbackMatches(ctx context.Context, p SearchCardsParams) ([]fuzzyCandidate, error) {
+	target := strings.ToLower(p.FuzzyName)
+	filters := p
+	filters.FuzzyName = ""
+	b := q.searchBuilder(filters)
+	b.Select("cards.uuid", "cards.name", "cards.number")
+	sql, params := b.Build()
+	rows, err := q.conn.Execute(ctx, sql, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []fuzzyCandidate
+	for _, r := range rows {
+		name, _ := r["name"].(string)
+		score := levenshteinSimilarity(strings.ToLower(name), target)
+		if score < levenshteinThreshold {
+			continue
+		}
+		uuid, _ := r["uuid"].(string)
+		number, _ := r["number"].(string)
+		matches = append(matches, fuzzyCandidate{uuid, number, score})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		if matches[i].number != matches[j].number {
+			return matches[i].number < matches[j].number
+		}
+		return matches[i].uuid < matches[j].uuid
+	})
+	return matches, nil
+}
+
+// levenshteinSimilarity returns 1 minus the edit distance between a and b
+// divided by the length of the longer string, in runes.
+func levenshteinSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -32,11 +32,25 @@ var KnownIDColumns = map[string]bool{
 
 // IdentifierQuery provides cross-reference lookups by external IDs.
 type IdentifierQuery struct {
-	conn *db.Connection
+	conn  db.Backend
+	cards *CardQuery // for keywords; see WithIdentifierCards
 }
 
-func NewIdentifierQuery(conn *db.Connection) *IdentifierQuery {
-	return &IdentifierQuery{conn: conn}
+// IdentifierQueryOption configures an IdentifierQuery.
+type IdentifierQueryOption func(*IdentifierQuery)
+
+// WithIdentifierCards sets the card module whose Keywords.json fills in
+// missing keywords on the cards the lookups return.
+func WithIdentifierCards(cards *CardQuery) IdentifierQueryOption {
+	return func(q *IdentifierQuery) { q.cards = cards }
+}
+
+func NewIdentifierQuery(conn db.Backend, opts ...IdentifierQueryOption) *IdentifierQuery {
+	q := &IdentifierQuery{conn: conn}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
 }
 
 func (q *IdentifierQuery) ensure(ctx context.Context) error {
@@ -54,6 +68,7 @@ func (q *IdentifierQuery) findBy(ctx context.Context, idColumn, value string) ([
 	if err := q.conn.ExecuteInto(ctx, &cards, sql, value); err != nil {
 		return nil, err
 	}
+	q.cards.normalizeKeywords(ctx, cards)
 	return cards, nil
 }
 
@@ -151,6 +166,45 @@ func (q *IdentifierQuery) FindByCardsphereFoilID(ctx context.Context, id string)
 	return q.findBy(ctx, "cardsphereFoilId", id)
 }
 
+// FindByAny looks a value up in every known identifier column, for IDs of
+// unknown type such as those scraped from a marketplace page. The value is
+// trimmed and compared case-insensitively, so "  F7A21FE4-..." still matches
+// a Scryfall ID. Results are ordered by column, then card name; a card that
+// matches in several columns is returned once per column.
+func (q *IdentifierQuery) FindByAny(ctx context.Context, value string) ([]models.IdentifierMatch, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return nil, nil
+	}
+	if err := q.ensure(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := q.conn.Execute(ctx, "SELECT column_name FROM (DESCRIBE card_identifiers)")
+	if err != nil {
+		return nil, err
+	}
+	var probes []string
+	for _, r := range rows {
+		col, _ := r["column_name"].(string)
+		if KnownIDColumns[col] {
+			probes = append(probes, fmt.Sprintf(
+				"SELECT uuid, '%[1]s' AS id_column FROM card_identifiers "+
+					"WHERE lower(trim(CAST(\"%[1]s\" AS VARCHAR))) = $1", col))
+		}
+	}
+	if len(probes) == 0 {
+		return nil, nil
+	}
+	sort.Strings(probes)
+	sql := "SELECT c.*, m.id_column FROM (" + strings.Join(probes, " UNION ALL ") + ") m " +
+		"JOIN cards c ON c.uuid = m.uuid ORDER BY m.id_column, c.name, c.uuid"
+	var matches []models.IdentifierMatch
+	if err := q.conn.ExecuteInto(ctx, &matches, sql, value); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
 // GetIdentifiers returns all external identifiers for a card UUID.
 func (q *IdentifierQuery) GetIdentifiers(ctx context.Context, uuid string) (map[string]any, error) {
 	if err := q.conn.EnsureViews(ctx, "card_identifiers"); err != nil {
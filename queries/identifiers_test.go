@@ -276,3 +276,31 @@ func TestIdentFindByNotFound(t *testing.T) {
 		t.Fatalf("expected 0, got %d", len(cards))
 	}
 }
+
+func TestIdentFindByAny(t *testing.T) {
+	conn := setupSampleDB(t)
+	q := NewIdentifierQuery(conn)
+	ctx := context.Background()
+
+	matches, err := q.FindByAny(ctx, "  SCRYFALL-002 ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 || matches[0].Name != "Counterspell" || matches[0].IDColumn != "scryfallId" {
+		t.Fatalf("expected Counterspell via scryfallId, got %+v", matches)
+	}
+	matches, err = q.FindByAny(ctx, "442130")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 || matches[0].IDColumn != "multiverseId" {
+		t.Fatalf("expected a multiverseId match, got %+v", matches)
+	}
+	matches, err = q.FindByAny(ctx, "no-such-id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 0 {
+		t.Fatalf("expected no matches, got %d", len(matches))
+	}
+}
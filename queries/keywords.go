@@ -0,0 +1,156 @@
+package queries
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+// basicKeywordActions are keyword actions so common in rules text ("exile
+// target creature", "sacrifice a land") that MTGJSON does not list them in
+// a card's keywords, so they are never derived from text.
+var basicKeywordActions = map[string]bool{
+	"Activate": true, "Attach": true, "Cast": true, "Counter": true, "Create": true,
+	"Destroy": true, "Discard": true, "Double": true, "Exchange": true, "Exile": true,
+	"Play": true, "Reveal": true, "Sacrifice": true, "Shuffle": true, "Tap": true,
+	"Untap": true,
+}
+
+// reminderTextRe matches parenthesized reminder text, which explains
+// keywords the card does not have as often as ones it does.
+var reminderTextRe = regexp.MustCompile(`\([^)]*\)`)
+
+// reminderTextSQL is reminderTextRe's pattern for DuckDB's regexp_replace.
+const reminderTextSQL = `\([^)]*\)`
+
+// textKeyword is a keyword and the pattern finding it in rules text with
+// the reminder text removed.
+type textKeyword struct {
+	name    string
+	pattern string
+	re      *regexp.Regexp
+}
+
+// compileTextKeywords returns the keywords of Keywords.json data that can
+// be found in rules text: keyword abilities starting a line or following a
+// comma ("Flying, vigilance"), ability words starting a line before an em
+// dash ("Landfall — ...") and keyword actions anywhere ("scry 2"), except
+// basicKeywordActions.
+func compileTextKeywords(data map[string]any) []textKeyword {
+	var out []textKeyword
+	add := func(category, prefix, suffix string) {
+		values, _ := data[category].([]any)
+		for _, v := range values {
+			name, _ := v.(string)
+			if name == "" || basicKeywordActions[name] {
+				continue
+			}
+			pattern := prefix + regexp.QuoteMeta(name) + suffix
+			out = append(out, textKeyword{name: name, pattern: pattern, re: regexp.MustCompile(pattern)})
+		}
+	}
+	add("keywordAbilities", `(?im)(^|[,;] )`, `\b`)
+	add("abilityWords", `(?m)^`, ` —`)
+	add("keywordActions", `(?i)\b`, `\b`)
+	return out
+}
+
+// embeddedTextKeywords are the text keywords of the embedded Keywords.json.
+var embeddedTextKeywords = sync.OnceValue(func() []textKeyword {
+	raw, err := embeddedReference("keywords")
+	if err != nil {
+		return nil
+	}
+	data, _ := raw["data"].(map[string]any)
+	return compileTextKeywords(data)
+})
+
+// WithKeywords makes the card module find keywords in rules text with the
+// Keywords.json enums loads, so keywords newer than the embedded snapshot
+// are recognised. Without it, or if enums has no data, the embedded
+// snapshot is used.
+func WithKeywords(enums *EnumQuery) CardQueryOption {
+	return func(q *CardQuery) { q.enums = enums }
+}
+
+// textKeywords returns the text keywords of the Keywords.json given by
+// WithKeywords, loading it on first use, or the embedded ones. A nil q uses
+// the embedded ones.
+func (q *CardQuery) textKeywords(ctx context.Context) []textKeyword {
+	if q == nil || q.enums == nil {
+		return embeddedTextKeywords()
+	}
+	q.keywordsMu.Lock()
+	defer q.keywordsMu.Unlock()
+	if q.keywords == nil {
+		data, err := q.enums.Keywords(ctx)
+		if err != nil {
+			q.enums.cache.Logger().Debug("Using embedded keywords", "error", err)
+			return embeddedTextKeywords()
+		}
+		if q.keywords = compileTextKeywords(data); len(q.keywords) == 0 {
+			q.keywords = embeddedTextKeywords()
+		}
+	}
+	return q.keywords
+}
+
+// loadedTextKeywords returns the text keywords textKeywords has loaded, or
+// the embedded ones if it has not run.
+func (q *CardQuery) loadedTextKeywords() []textKeyword {
+	q.keywordsMu.Lock()
+	defer q.keywordsMu.Unlock()
+	if q.keywords == nil {
+		return embeddedTextKeywords()
+	}
+	return q.keywords
+}
+
+// lookupTextKeyword returns the text keyword named name, case-insensitively.
+func lookupTextKeyword(keywords []textKeyword, name string) (textKeyword, bool) {
+	for _, k := range keywords {
+		if strings.EqualFold(k.name, name) {
+			return k, true
+		}
+	}
+	return textKeyword{}, false
+}
+
+// KeywordsFromText returns the keywords rules text grants or uses, in
+// Keywords.json order, judged from the text alone. It uses the embedded
+// Keywords.json; the card module uses the live one given by WithKeywords.
+func KeywordsFromText(text string) []string {
+	return keywordsFromText(embeddedTextKeywords(), text)
+}
+
+func keywordsFromText(keywords []textKeyword, text string) []string {
+	text = reminderTextRe.ReplaceAllString(text, "")
+	var out []string
+	for _, k := range keywords {
+		if k.re.MatchString(text) {
+			out = append(out, k.name)
+		}
+	}
+	return out
+}
+
+// normalizeKeywords fills in the keywords of cards that have rules text
+// but no keywords from the text, since some printings in MTGJSON lack the
+// keywords column. Every module returning cards runs them through it, using
+// the card module it was given (e.g. WithSetCards), or the embedded
+// keywords when q is nil.
+func (q *CardQuery) normalizeKeywords(ctx context.Context, cards []models.CardSet) {
+	var keywords []textKeyword
+	for i := range cards {
+		c := &cards[i]
+		if len(c.Keywords) == 0 && c.Text != nil {
+			if keywords == nil {
+				keywords = q.textKeywords(ctx)
+			}
+			c.Keywords = keywordsFromText(keywords, *c.Text)
+		}
+	}
+}
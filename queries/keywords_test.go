@@ -0,0 +1,165 @@
+package queries
+
+import (
+	"context"
+	"maps"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+func TestKeywordsFromText(t *testing.T) {
+	for _, tc := range []struct {
+		text string
+		want []string
+	}{
+		{"Flying, vigilance", []string{"Flying", "Vigilance"}},
+		{"Flash\nFlashback {2}{U}", []string{"Flash", "Flashback"}},
+		{"Haste (This creature can attack as soon as it comes under your control. It has flying.)", []string{"Haste"}},
+		{"Landfall — Whenever a land you control enters, scry 1.", []string{"Landfall", "Scry"}},
+		{"Exile target creature. Its controller gains life equal to its power.", nil},
+		{"Creatures you control have flying.", nil},
+	} {
+		if got := KeywordsFromText(tc.text); !slices.Equal(got, tc.want) {
+			t.Errorf("KeywordsFromText(%q) = %v, want %v", tc.text, got, tc.want)
+		}
+	}
+}
+
+func TestSearchKeywordFromText(t *testing.T) {
+	conn := setupSampleDB(t)
+	ctx := context.Background()
+	flier := maps.Clone(sampleCards[1])
+	flier["uuid"], flier["name"], flier["text"] = "card-uuid-004", "Sky Sentry", "Flying\nWhen this creature enters, scry 2."
+	tagged := maps.Clone(sampleCards[1])
+	tagged["uuid"], tagged["name"], tagged["keywords"] = "card-uuid-005", "Tagged Flier", []any{"Flying"}
+	if err := conn.RegisterTableFromData(ctx, "cards", append(append([]map[string]any{}, sampleCards...), flier, tagged)); err != nil {
+		t.Fatal(err)
+	}
+	q := NewCardQuery(conn)
+
+	cards, err := q.Search(ctx, SearchCardsParams{Keyword: "flying"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 2 {
+		t.Fatalf("expected the tagged flier and Sky Sentry from its text, got %d", len(cards))
+	}
+	card, err := q.GetByUUID(ctx, "card-uuid-004")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(card.Keywords, []string{"Flying", "Scry"}) {
+		t.Errorf("expected keywords from text, got %v", card.Keywords)
+	}
+	card, err = q.GetByUUID(ctx, "card-uuid-005")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(card.Keywords, []string{"Flying"}) {
+		t.Errorf("expected stored keywords kept, got %v", card.Keywords)
+	}
+}
+
+func TestCardKeywordsFromLiveFile(t *testing.T) {
+	conn := setupSampleDB(t)
+	ctx := context.Background()
+	card := maps.Clone(sampleCards[1])
+	card["uuid"], card["name"], card["text"] = "card-uuid-004", "Sky Sentry", "Flying\nNewword 2"
+	tagged := maps.Clone(sampleCards[1])
+	tagged["uuid"], tagged["name"], tagged["keywords"] = "card-uuid-005", "Tagged Flier", []any{"Flying"}
+	if err := conn.RegisterTableFromData(ctx, "cards", append(append([]map[string]any{}, sampleCards...), card, tagged)); err != nil {
+		t.Fatal(err)
+	}
+	cache := setupEnumCache(t)
+	writeJSON(t, filepath.Join(cache.CacheDir, "Keywords.json"), map[string]any{
+		"meta": map[string]any{"version": "5.2.2+20240101"},
+		"data": map[string]any{"keywordAbilities": []any{"Flying", "Newword"}},
+	})
+
+	cards, err := NewCardQuery(conn).GetByName(ctx, "Sky Sentry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 1 || !slices.Equal(cards[0].Keywords, []string{"Flying"}) {
+		t.Fatalf("expected GetByName to fill in keywords from the embedded file, got %+v", cards)
+	}
+	q := NewCardQuery(conn, WithKeywords(NewEnumQuery(cache)))
+	cards, err = q.GetByName(ctx, "Sky Sentry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 1 || !slices.Equal(cards[0].Keywords, []string{"Flying", "Newword"}) {
+		t.Fatalf("expected keywords from the live file, got %+v", cards)
+	}
+	n, err := NewCardQuery(conn, WithKeywords(NewEnumQuery(cache))).CountSearch(ctx, SearchCardsParams{Keyword: "newword"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("expected CountSearch to load the live keywords before any Search, got %d", n)
+	}
+	found, err := q.Search(ctx, SearchCardsParams{Keyword: "newword"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 {
+		t.Fatalf("expected Search to match the live keyword in text, got %d", len(found))
+	}
+}
+
+func TestModulesFillInKeywords(t *testing.T) {
+	conn := setupSampleDB(t)
+	ctx := context.Background()
+	bolt := maps.Clone(sampleCards[0])
+	bolt["text"], bolt["subtypes"] = "Flying\nNewword 2", []any{"Bird"}
+	if err := conn.RegisterTableFromData(ctx, "cards", append([]map[string]any{bolt}, sampleCards[1:]...)); err != nil {
+		t.Fatal(err)
+	}
+	cache := setupEnumCache(t)
+	writeJSON(t, filepath.Join(cache.CacheDir, "Keywords.json"), map[string]any{
+		"meta": map[string]any{"version": "5.2.2+20240101"},
+		"data": map[string]any{"keywordAbilities": []any{"Flying", "Newword"}},
+	})
+	cards := NewCardQuery(conn, WithKeywords(NewEnumQuery(cache)))
+	tags := NewTagQuery(conn, filepath.Join(t.TempDir(), "annotations.duckdb"), WithTagCards(cards))
+	if err := tags.Add(ctx, "card-uuid-001", "burn", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	first := func(cards []models.CardSet, err error) models.CardSet {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range cards {
+			if c.UUID == "card-uuid-001" {
+				return c
+			}
+		}
+		t.Fatalf("expected card-uuid-001 among %d cards", len(cards))
+		return models.CardSet{}
+	}
+	census, err := NewSubtypeQuery(conn, WithSubtypeCards(cards)).Census(ctx, "Bird")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Flying", "Newword"}
+	for name, c := range map[string]models.CardSet{
+		"CardsInRange": first(NewSetQuery(conn, WithSetCards(cards)).CardsInRange(ctx, "A25", "141", "141")),
+		"Tagged":       first(tags.Tagged(ctx, "burn")),
+		"FindBy":       first(NewIdentifierQuery(conn, WithIdentifierCards(cards)).FindByScryfallID(ctx, "scryfall-001")),
+		"LegalIn":      first(NewLegalityQuery(conn, WithLegalityCards(cards)).LegalIn(ctx, "legacy")),
+		"Census":       first(census.Cards, nil),
+	} {
+		if !slices.Equal(c.Keywords, want) {
+			t.Errorf("%s: expected keywords from the live file, got %v", name, c.Keywords)
+		}
+	}
+	c := first(NewSetQuery(conn).CardsInRange(ctx, "A25", "141", "141"))
+	if !slices.Equal(c.Keywords, []string{"Flying"}) {
+		t.Errorf("expected the embedded keywords without a card module, got %v", c.Keywords)
+	}
+}
@@ -2,7 +2,6 @@ package queries
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/mtgjson/mtgjson-sdk-go/db"
 	"github.com/mtgjson/mtgjson-sdk-go/models"
@@ -10,11 +9,25 @@ import (
 
 // LegalityQuery provides methods to query card format legalities.
 type LegalityQuery struct {
-	conn *db.Connection
+	conn  db.Backend
+	cards *CardQuery // for keywords; see WithLegalityCards
 }
 
-func NewLegalityQuery(conn *db.Connection) *LegalityQuery {
-	return &LegalityQuery{conn: conn}
+// LegalityQueryOption configures a LegalityQuery.
+type LegalityQueryOption func(*LegalityQuery)
+
+// WithLegalityCards sets the card module whose Keywords.json fills in
+// missing keywords on the cards LegalIn returns.
+func WithLegalityCards(cards *CardQuery) LegalityQueryOption {
+	return func(q *LegalityQuery) { q.cards = cards }
+}
+
+func NewLegalityQuery(conn db.Backend, opts ...LegalityQueryOption) *LegalityQuery {
+	q := &LegalityQuery{conn: conn}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
 }
 
 func (q *LegalityQuery) ensure(ctx context.Context) error {
@@ -29,14 +42,13 @@ func (q *LegalityQuery) cardsByStatus(ctx context.Context, formatName, status st
 	if limit <= 0 {
 		limit = 100
 	}
-	sql := fmt.Sprintf(
-		"SELECT c.name, c.uuid FROM cards c "+
-			"JOIN card_legalities cl ON c.uuid = cl.uuid "+
-			"WHERE cl.format = $1 AND cl.status = $2 "+
-			"ORDER BY c.name ASC "+
-			"LIMIT %d OFFSET %d", limit, offset)
+	sql := "SELECT c.name, c.uuid FROM cards c " +
+		"JOIN card_legalities cl ON c.uuid = cl.uuid " +
+		"WHERE cl.format = $1 AND cl.status = $2 " +
+		"ORDER BY c.name ASC " +
+		"LIMIT $3 OFFSET $4"
 	var results []models.CardLegality
-	if err := q.conn.ExecuteInto(ctx, &results, sql, formatName, status); err != nil {
+	if err := q.conn.ExecuteInto(ctx, &results, sql, formatName, status, limit, offset); err != nil {
 		return nil, err
 	}
 	return results, nil
@@ -72,16 +84,16 @@ func (q *LegalityQuery) LegalIn(ctx context.Context, formatName string, limit ..
 	if len(limit) > 0 && limit[0] > 0 {
 		lim = limit[0]
 	}
-	sql := fmt.Sprintf(
-		"SELECT DISTINCT c.* FROM cards c "+
-			"JOIN card_legalities cl ON c.uuid = cl.uuid "+
-			"WHERE cl.format = $1 AND cl.status = 'Legal' "+
-			"ORDER BY c.name ASC "+
-			"LIMIT %d", lim)
+	sql := "SELECT DISTINCT c.* FROM cards c " +
+		"JOIN card_legalities cl ON c.uuid = cl.uuid " +
+		"WHERE cl.format = $1 AND cl.status = 'Legal' " +
+		"ORDER BY c.name ASC " +
+		"LIMIT $2"
 	var cards []models.CardSet
-	if err := q.conn.ExecuteInto(ctx, &cards, sql, formatName); err != nil {
+	if err := q.conn.ExecuteInto(ctx, &cards, sql, formatName, lim); err != nil {
 		return nil, err
 	}
+	q.cards.normalizeKeywords(ctx, cards)
 	return cards, nil
 }
 
@@ -99,6 +111,25 @@ func (q *LegalityQuery) IsLegal(ctx context.Context, uuid, formatName string) (b
 	return db.ScalarToInt(val) > 0, nil
 }
 
+// Wide returns the legalities of the given cards as MTGJSON publishes them:
+// a "uuid" column and one column per format holding the status, nil where
+// the card has none. It reads the card_legalities_wide view, which can also
+// be queried directly for every card.
+func (q *LegalityQuery) Wide(ctx context.Context, uuids ...string) ([]map[string]any, error) {
+	if len(uuids) == 0 {
+		return nil, nil
+	}
+	if err := q.conn.EnsureViews(ctx, "card_legalities_wide"); err != nil {
+		return nil, err
+	}
+	values := make([]any, len(uuids))
+	for i, u := range uuids {
+		values[i] = u
+	}
+	sql, params := db.NewSQLBuilder("card_legalities_wide").WhereIn("uuid", values).OrderBy("uuid").Build()
+	return q.conn.Execute(ctx, sql, params...)
+}
+
 // BannedIn returns all cards banned in a specific format.
 func (q *LegalityQuery) BannedIn(ctx context.Context, formatName string, limit ...int) ([]models.CardLegality, error) {
 	lim := 100
@@ -2,6 +2,7 @@ package queries
 
 import (
 	"context"
+	"maps"
 	"testing"
 )
 
@@ -126,3 +127,87 @@ func TestNotLegalIn(t *testing.T) {
 		t.Fatalf("expected 2, got %d", len(cards))
 	}
 }
+
+func TestLegalityWide(t *testing.T) {
+	conn := setupSampleDB(t)
+	ctx := context.Background()
+	if err := conn.RegisterTableFromData(ctx, "card_legalities_wide", []map[string]any{
+		{"uuid": "card-uuid-002", "modern": "Legal", "vintage": "Legal"},
+		{"uuid": "card-uuid-001", "modern": "Legal", "vintage": "Restricted"},
+		{"uuid": "card-uuid-003", "modern": nil, "vintage": "Legal"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	q := NewLegalityQuery(conn)
+
+	rows, err := q.Wide(ctx, "card-uuid-001", "card-uuid-003", "missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0]["uuid"] != "card-uuid-001" || rows[0]["vintage"] != "Restricted" || rows[1]["modern"] != nil {
+		t.Fatalf("unexpected wide rows %v", rows)
+	}
+	if rows, err := q.Wide(ctx); err != nil || rows != nil {
+		t.Errorf("expected nothing for no uuids, got %v (%v)", rows, err)
+	}
+}
+
+func TestLegalityHistory(t *testing.T) {
+	conn := setupSampleDB(t)
+	q := NewLegalityQuery(conn)
+	ctx := context.Background()
+
+	if _, err := q.StatusAsOf(ctx, "card-uuid-002", "modern", "2024-01-15"); err == nil {
+		t.Fatal("expected an error before any legalities are recorded")
+	}
+	if n, err := conn.RecordLegalities(ctx, "2024-01-01"); err != nil || n != len(sampleLegalities) {
+		t.Fatalf("expected every pair in the first release, got %d (%v)", n, err)
+	}
+	// The next release bans Counterspell in modern and drops historic.
+	var next []map[string]any
+	for _, row := range sampleLegalities {
+		row = maps.Clone(row)
+		if row["uuid"] == "card-uuid-002" && row["format"] == "historic" {
+			continue
+		}
+		if row["uuid"] == "card-uuid-002" && row["format"] == "modern" {
+			row["status"] = "Banned"
+		}
+		next = append(next, row)
+	}
+	if err := conn.RegisterTableFromData(ctx, "card_legalities", next); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := conn.RecordLegalities(ctx, "2024-02-01"); err != nil || n != 2 {
+		t.Fatalf("expected 2 changes, got %d (%v)", n, err)
+	}
+	if n, err := conn.RecordLegalities(ctx, "2024-02-01"); err != nil || n != 0 {
+		t.Fatalf("expected recording the same release to add nothing, got %d (%v)", n, err)
+	}
+
+	for date, want := range map[string]string{"2023-12-01": "", "2024-01-15": "Legal", "2024-02-15": "Banned"} {
+		got, err := q.StatusAsOf(ctx, "card-uuid-002", "modern", date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("status on %s: expected %q, got %q", date, want, got)
+		}
+	}
+
+	bans, err := q.BanHistory(ctx, "modern")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bans) != 1 || bans[0].Name != "Counterspell" || bans[0].Status != "Banned" ||
+		bans[0].PreviousStatus == nil || *bans[0].PreviousStatus != "Legal" || bans[0].Date != "2024-02-01" {
+		t.Fatalf("expected the Counterspell ban, got %+v", bans)
+	}
+	suspended, err := q.BanHistory(ctx, "historic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(suspended) != 2 || suspended[0].Status != "Suspended" || suspended[1].Status != "" {
+		t.Fatalf("expected the suspension and its removal, got %+v", suspended)
+	}
+}
@@ -0,0 +1,51 @@
+package queries
+
+import (
+	"context"
+
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+// StatusAsOf returns a card's status in a format (e.g. "Banned") on date
+// (YYYY-MM-DD), from the legality history that SDK.RecordLegalities and
+// WithLegalityHistory keep. It returns "" when the card had no status in
+// the format then, or when date precedes the first recorded release.
+func (q *LegalityQuery) StatusAsOf(ctx context.Context, uuid, formatName, date string) (string, error) {
+	if err := q.conn.EnsureViews(ctx, "legality_history"); err != nil {
+		return "", err
+	}
+	val, err := q.conn.ExecuteScalar(ctx,
+		"SELECT status FROM legality_history WHERE uuid = $1 AND format = $2 AND date <= $3 "+
+			"ORDER BY date DESC LIMIT 1",
+		uuid, formatName, date)
+	if err != nil {
+		return "", err
+	}
+	status, _ := val.(string)
+	return status, nil
+}
+
+// BanHistory returns a format's timeline of bans, restrictions and
+// suspensions from the legality history: every recorded change into or out
+// of Banned, Restricted or Suspended, oldest first. Changes in the first
+// recorded release have no PreviousStatus.
+func (q *LegalityQuery) BanHistory(ctx context.Context, formatName string) ([]models.LegalityChange, error) {
+	if err := q.conn.EnsureViews(ctx, "cards", "legality_history"); err != nil {
+		return nil, err
+	}
+	var result []models.LegalityChange
+	if err := q.conn.ExecuteInto(ctx, &result,
+		"WITH h AS (SELECT uuid, format, status, date, "+
+			"  LAG(status) OVER (PARTITION BY uuid, format ORDER BY date) AS previousStatus "+
+			"  FROM legality_history WHERE format = $1) "+
+			"SELECT h.uuid, n.name, h.format, COALESCE(h.status, '') AS status, "+
+			"  h.previousStatus, h.date "+
+			"FROM h LEFT JOIN (SELECT uuid, ANY_VALUE(name) AS name FROM cards GROUP BY uuid) n ON n.uuid = h.uuid "+
+			"WHERE h.status IN ('Banned', 'Restricted', 'Suspended') "+
+			"  OR h.previousStatus IN ('Banned', 'Restricted', 'Suspended') "+
+			"ORDER BY h.date, n.name, h.uuid",
+		formatName); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
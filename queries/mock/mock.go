@@ -0,0 +1,1321 @@
+// Code generated by mockgen. DO NOT EDIT.
+
+// Package mock provides stub implementations of the queries package's
+// XAPI interfaces for unit tests that should not open DuckDB. Set the
+// XFunc field for each method a test calls; unset methods return zero
+// values and a nil error.
+package mock
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/mtgjson/mtgjson-sdk-go/booster"
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+	"github.com/mtgjson/mtgjson-sdk-go/queries"
+)
+
+// CardAPI is a stub queries.CardAPI.
+type CardAPI struct {
+	GetByUUIDFunc         func(ctx context.Context, uuid string) (*models.CardSet, error)
+	GetByUUIDsFunc        func(ctx context.Context, uuids []string) ([]models.CardSet, error)
+	GetByNameFunc         func(ctx context.Context, name string, setCode ...string) ([]models.CardSet, error)
+	SearchFunc            func(ctx context.Context, p queries.SearchCardsParams) ([]models.CardSet, error)
+	SearchToWriterFunc    func(ctx context.Context, w io.Writer, p queries.SearchCardsParams) error
+	SearchSQLFunc         func(p queries.SearchCardsParams) (string, []any)
+	GetPrintingsFunc      func(ctx context.Context, name string) ([]models.CardSet, error)
+	SpellbookFunc         func(ctx context.Context, uuid string) ([]models.CardSet, error)
+	PlanesFunc            func(ctx context.Context) ([]models.CardSet, error)
+	SchemesFunc           func(ctx context.Context) ([]models.CardSet, error)
+	GetAtomicFunc         func(ctx context.Context, name string) ([]models.CardAtomic, error)
+	FirstPrintingFunc     func(ctx context.Context, name string) (*models.FirstPrinting, error)
+	FindByScryfallIDFunc  func(ctx context.Context, scryfallID string) ([]models.CardSet, error)
+	RandomFunc            func(ctx context.Context, count int) ([]models.CardSet, error)
+	RelatedFunc           func(ctx context.Context, uuid string, limit int) ([]models.CardSynergy, error)
+	PreconAppearancesFunc func(ctx context.Context, name string) ([]models.PreconAppearance, error)
+	CountFunc             func(ctx context.Context, filters ...queries.Filter) (int, error)
+	CountSearchFunc       func(ctx context.Context, p queries.SearchCardsParams) (int, error)
+}
+
+// GetByUUID calls GetByUUIDFunc if set.
+func (m *CardAPI) GetByUUID(ctx context.Context, uuid string) (r0 *models.CardSet, r1 error) {
+	if m.GetByUUIDFunc == nil {
+		return
+	}
+	return m.GetByUUIDFunc(ctx, uuid)
+}
+
+// GetByUUIDs calls GetByUUIDsFunc if set.
+func (m *CardAPI) GetByUUIDs(ctx context.Context, uuids []string) (r0 []models.CardSet, r1 error) {
+	if m.GetByUUIDsFunc == nil {
+		return
+	}
+	return m.GetByUUIDsFunc(ctx, uuids)
+}
+
+// GetByName calls GetByNameFunc if set.
+func (m *CardAPI) GetByName(ctx context.Context, name string, setCode ...string) (r0 []models.CardSet, r1 error) {
+	if m.GetByNameFunc == nil {
+		return
+	}
+	return m.GetByNameFunc(ctx, name, setCode...)
+}
+
+// Search calls SearchFunc if set.
+func (m *CardAPI) Search(ctx context.Context, p queries.SearchCardsParams) (r0 []models.CardSet, r1 error) {
+	if m.SearchFunc == nil {
+		return
+	}
+	return m.SearchFunc(ctx, p)
+}
+
+// SearchToWriter calls SearchToWriterFunc if set.
+func (m *CardAPI) SearchToWriter(ctx context.Context, w io.Writer, p queries.SearchCardsParams) (r0 error) {
+	if m.SearchToWriterFunc == nil {
+		return
+	}
+	return m.SearchToWriterFunc(ctx, w, p)
+}
+
+// SearchSQL calls SearchSQLFunc if set.
+func (m *CardAPI) SearchSQL(p queries.SearchCardsParams) (r0 string, r1 []any) {
+	if m.SearchSQLFunc == nil {
+		return
+	}
+	return m.SearchSQLFunc(p)
+}
+
+// GetPrintings calls GetPrintingsFunc if set.
+func (m *CardAPI) GetPrintings(ctx context.Context, name string) (r0 []models.CardSet, r1 error) {
+	if m.GetPrintingsFunc == nil {
+		return
+	}
+	return m.GetPrintingsFunc(ctx, name)
+}
+
+// Spellbook calls SpellbookFunc if set.
+func (m *CardAPI) Spellbook(ctx context.Context, uuid string) (r0 []models.CardSet, r1 error) {
+	if m.SpellbookFunc == nil {
+		return
+	}
+	return m.SpellbookFunc(ctx, uuid)
+}
+
+// Planes calls PlanesFunc if set.
+func (m *CardAPI) Planes(ctx context.Context) (r0 []models.CardSet, r1 error) {
+	if m.PlanesFunc == nil {
+		return
+	}
+	return m.PlanesFunc(ctx)
+}
+
+// Schemes calls SchemesFunc if set.
+func (m *CardAPI) Schemes(ctx context.Context) (r0 []models.CardSet, r1 error) {
+	if m.SchemesFunc == nil {
+		return
+	}
+	return m.SchemesFunc(ctx)
+}
+
+// GetAtomic calls GetAtomicFunc if set.
+func (m *CardAPI) GetAtomic(ctx context.Context, name string) (r0 []models.CardAtomic, r1 error) {
+	if m.GetAtomicFunc == nil {
+		return
+	}
+	return m.GetAtomicFunc(ctx, name)
+}
+
+// FirstPrinting calls FirstPrintingFunc if set.
+func (m *CardAPI) FirstPrinting(ctx context.Context, name string) (r0 *models.FirstPrinting, r1 error) {
+	if m.FirstPrintingFunc == nil {
+		return
+	}
+	return m.FirstPrintingFunc(ctx, name)
+}
+
+// FindByScryfallID calls FindByScryfallIDFunc if set.
+func (m *CardAPI) FindByScryfallID(ctx context.Context, scryfallID string) (r0 []models.CardSet, r1 error) {
+	if m.FindByScryfallIDFunc == nil {
+		return
+	}
+	return m.FindByScryfallIDFunc(ctx, scryfallID)
+}
+
+// Random calls RandomFunc if set.
+func (m *CardAPI) Random(ctx context.Context, count int) (r0 []models.CardSet, r1 error) {
+	if m.RandomFunc == nil {
+		return
+	}
+	return m.RandomFunc(ctx, count)
+}
+
+// Related calls RelatedFunc if set.
+func (m *CardAPI) Related(ctx context.Context, uuid string, limit int) (r0 []models.CardSynergy, r1 error) {
+	if m.RelatedFunc == nil {
+		return
+	}
+	return m.RelatedFunc(ctx, uuid, limit)
+}
+
+// PreconAppearances calls PreconAppearancesFunc if set.
+func (m *CardAPI) PreconAppearances(ctx context.Context, name string) (r0 []models.PreconAppearance, r1 error) {
+	if m.PreconAppearancesFunc == nil {
+		return
+	}
+	return m.PreconAppearancesFunc(ctx, name)
+}
+
+// Count calls CountFunc if set.
+func (m *CardAPI) Count(ctx context.Context, filters ...queries.Filter) (r0 int, r1 error) {
+	if m.CountFunc == nil {
+		return
+	}
+	return m.CountFunc(ctx, filters...)
+}
+
+// CountSearch calls CountSearchFunc if set.
+func (m *CardAPI) CountSearch(ctx context.Context, p queries.SearchCardsParams) (r0 int, r1 error) {
+	if m.CountSearchFunc == nil {
+		return
+	}
+	return m.CountSearchFunc(ctx, p)
+}
+
+// SetAPI is a stub queries.SetAPI.
+type SetAPI struct {
+	GetFunc                 func(ctx context.Context, code string) (*models.SetList, error)
+	TranslationsFunc        func(ctx context.Context, code string) (models.Translations, error)
+	AssetsFunc              func(ctx context.Context, code string) (*models.SetAssets, error)
+	CardsInRangeFunc        func(ctx context.Context, code string, from string, to string) ([]models.CardSet, error)
+	SubsetsFunc             func(ctx context.Context, code string) ([]models.SetSubset, error)
+	GetByLocalizedNameFunc  func(ctx context.Context, name string) (*models.SetList, error)
+	ListFunc                func(ctx context.Context, p queries.ListSetsParams) ([]models.SetList, error)
+	SearchFunc              func(ctx context.Context, p queries.SearchSetsParams) ([]models.SetList, error)
+	GetFinancialSummaryFunc func(ctx context.Context, setCode string, opts ...queries.FinancialSummaryOption) (*models.FinancialSummary, error)
+	BoxValueFunc            func(ctx context.Context, setCode string, boosterType string, opts ...queries.FinancialSummaryOption) (*models.BoxValue, error)
+	CompletionCostFunc      func(ctx context.Context, code string, opts ...queries.FinancialSummaryOption) (*models.CompletionCost, error)
+	RarityBreakdownFunc     func(ctx context.Context, code string) (*models.RarityBreakdown, error)
+	CountFunc               func(ctx context.Context) (int, error)
+}
+
+// Get calls GetFunc if set.
+func (m *SetAPI) Get(ctx context.Context, code string) (r0 *models.SetList, r1 error) {
+	if m.GetFunc == nil {
+		return
+	}
+	return m.GetFunc(ctx, code)
+}
+
+// Translations calls TranslationsFunc if set.
+func (m *SetAPI) Translations(ctx context.Context, code string) (r0 models.Translations, r1 error) {
+	if m.TranslationsFunc == nil {
+		return
+	}
+	return m.TranslationsFunc(ctx, code)
+}
+
+// Assets calls AssetsFunc if set.
+func (m *SetAPI) Assets(ctx context.Context, code string) (r0 *models.SetAssets, r1 error) {
+	if m.AssetsFunc == nil {
+		return
+	}
+	return m.AssetsFunc(ctx, code)
+}
+
+// CardsInRange calls CardsInRangeFunc if set.
+func (m *SetAPI) CardsInRange(ctx context.Context, code string, from string, to string) (r0 []models.CardSet, r1 error) {
+	if m.CardsInRangeFunc == nil {
+		return
+	}
+	return m.CardsInRangeFunc(ctx, code, from, to)
+}
+
+// Subsets calls SubsetsFunc if set.
+func (m *SetAPI) Subsets(ctx context.Context, code string) (r0 []models.SetSubset, r1 error) {
+	if m.SubsetsFunc == nil {
+		return
+	}
+	return m.SubsetsFunc(ctx, code)
+}
+
+// GetByLocalizedName calls GetByLocalizedNameFunc if set.
+func (m *SetAPI) GetByLocalizedName(ctx context.Context, name string) (r0 *models.SetList, r1 error) {
+	if m.GetByLocalizedNameFunc == nil {
+		return
+	}
+	return m.GetByLocalizedNameFunc(ctx, name)
+}
+
+// List calls ListFunc if set.
+func (m *SetAPI) List(ctx context.Context, p queries.ListSetsParams) (r0 []models.SetList, r1 error) {
+	if m.ListFunc == nil {
+		return
+	}
+	return m.ListFunc(ctx, p)
+}
+
+// Search calls SearchFunc if set.
+func (m *SetAPI) Search(ctx context.Context, p queries.SearchSetsParams) (r0 []models.SetList, r1 error) {
+	if m.SearchFunc == nil {
+		return
+	}
+	return m.SearchFunc(ctx, p)
+}
+
+// GetFinancialSummary calls GetFinancialSummaryFunc if set.
+func (m *SetAPI) GetFinancialSummary(ctx context.Context, setCode string, opts ...queries.FinancialSummaryOption) (r0 *models.FinancialSummary, r1 error) {
+	if m.GetFinancialSummaryFunc == nil {
+		return
+	}
+	return m.GetFinancialSummaryFunc(ctx, setCode, opts...)
+}
+
+// BoxValue calls BoxValueFunc if set.
+func (m *SetAPI) BoxValue(ctx context.Context, setCode string, boosterType string, opts ...queries.FinancialSummaryOption) (r0 *models.BoxValue, r1 error) {
+	if m.BoxValueFunc == nil {
+		return
+	}
+	return m.BoxValueFunc(ctx, setCode, boosterType, opts...)
+}
+
+// CompletionCost calls CompletionCostFunc if set.
+func (m *SetAPI) CompletionCost(ctx context.Context, code string, opts ...queries.FinancialSummaryOption) (r0 *models.CompletionCost, r1 error) {
+	if m.CompletionCostFunc == nil {
+		return
+	}
+	return m.CompletionCostFunc(ctx, code, opts...)
+}
+
+// RarityBreakdown calls RarityBreakdownFunc if set.
+func (m *SetAPI) RarityBreakdown(ctx context.Context, code string) (r0 *models.RarityBreakdown, r1 error) {
+	if m.RarityBreakdownFunc == nil {
+		return
+	}
+	return m.RarityBreakdownFunc(ctx, code)
+}
+
+// Count calls CountFunc if set.
+func (m *SetAPI) Count(ctx context.Context) (r0 int, r1 error) {
+	if m.CountFunc == nil {
+		return
+	}
+	return m.CountFunc(ctx)
+}
+
+// TokenAPI is a stub queries.TokenAPI.
+type TokenAPI struct {
+	GetByUUIDFunc  func(ctx context.Context, uuid string) (*models.CardToken, error)
+	GetByUUIDsFunc func(ctx context.Context, uuids []string) ([]models.CardToken, error)
+	GetByNameFunc  func(ctx context.Context, name string, setCode ...string) ([]models.CardToken, error)
+	SearchFunc     func(ctx context.Context, p queries.SearchTokensParams) ([]models.CardToken, error)
+	ForSetFunc     func(ctx context.Context, setCode string) ([]models.CardToken, error)
+	StickersFunc   func(ctx context.Context, setCode ...string) ([]models.CardToken, error)
+	EmblemsFunc    func(ctx context.Context, setCode ...string) ([]models.CardToken, error)
+	DungeonsFunc   func(ctx context.Context, setCode ...string) ([]models.CardToken, error)
+	ArtCardsFunc   func(ctx context.Context, setCode ...string) ([]models.CardToken, error)
+	CountFunc      func(ctx context.Context, filters ...queries.Filter) (int, error)
+	GeneratorsFunc func(ctx context.Context, token string) ([]models.TokenGenerator, error)
+}
+
+// GetByUUID calls GetByUUIDFunc if set.
+func (m *TokenAPI) GetByUUID(ctx context.Context, uuid string) (r0 *models.CardToken, r1 error) {
+	if m.GetByUUIDFunc == nil {
+		return
+	}
+	return m.GetByUUIDFunc(ctx, uuid)
+}
+
+// GetByUUIDs calls GetByUUIDsFunc if set.
+func (m *TokenAPI) GetByUUIDs(ctx context.Context, uuids []string) (r0 []models.CardToken, r1 error) {
+	if m.GetByUUIDsFunc == nil {
+		return
+	}
+	return m.GetByUUIDsFunc(ctx, uuids)
+}
+
+// GetByName calls GetByNameFunc if set.
+func (m *TokenAPI) GetByName(ctx context.Context, name string, setCode ...string) (r0 []models.CardToken, r1 error) {
+	if m.GetByNameFunc == nil {
+		return
+	}
+	return m.GetByNameFunc(ctx, name, setCode...)
+}
+
+// Search calls SearchFunc if set.
+func (m *TokenAPI) Search(ctx context.Context, p queries.SearchTokensParams) (r0 []models.CardToken, r1 error) {
+	if m.SearchFunc == nil {
+		return
+	}
+	return m.SearchFunc(ctx, p)
+}
+
+// ForSet calls ForSetFunc if set.
+func (m *TokenAPI) ForSet(ctx context.Context, setCode string) (r0 []models.CardToken, r1 error) {
+	if m.ForSetFunc == nil {
+		return
+	}
+	return m.ForSetFunc(ctx, setCode)
+}
+
+// Stickers calls StickersFunc if set.
+func (m *TokenAPI) Stickers(ctx context.Context, setCode ...string) (r0 []models.CardToken, r1 error) {
+	if m.StickersFunc == nil {
+		return
+	}
+	return m.StickersFunc(ctx, setCode...)
+}
+
+// Emblems calls EmblemsFunc if set.
+func (m *TokenAPI) Emblems(ctx context.Context, setCode ...string) (r0 []models.CardToken, r1 error) {
+	if m.EmblemsFunc == nil {
+		return
+	}
+	return m.EmblemsFunc(ctx, setCode...)
+}
+
+// Dungeons calls DungeonsFunc if set.
+func (m *TokenAPI) Dungeons(ctx context.Context, setCode ...string) (r0 []models.CardToken, r1 error) {
+	if m.DungeonsFunc == nil {
+		return
+	}
+	return m.DungeonsFunc(ctx, setCode...)
+}
+
+// ArtCards calls ArtCardsFunc if set.
+func (m *TokenAPI) ArtCards(ctx context.Context, setCode ...string) (r0 []models.CardToken, r1 error) {
+	if m.ArtCardsFunc == nil {
+		return
+	}
+	return m.ArtCardsFunc(ctx, setCode...)
+}
+
+// Count calls CountFunc if set.
+func (m *TokenAPI) Count(ctx context.Context, filters ...queries.Filter) (r0 int, r1 error) {
+	if m.CountFunc == nil {
+		return
+	}
+	return m.CountFunc(ctx, filters...)
+}
+
+// Generators calls GeneratorsFunc if set.
+func (m *TokenAPI) Generators(ctx context.Context, token string) (r0 []models.TokenGenerator, r1 error) {
+	if m.GeneratorsFunc == nil {
+		return
+	}
+	return m.GeneratorsFunc(ctx, token)
+}
+
+// LegalityAPI is a stub queries.LegalityAPI.
+type LegalityAPI struct {
+	FormatsForCardFunc func(ctx context.Context, uuid string) (map[string]string, error)
+	LegalInFunc        func(ctx context.Context, formatName string, limit ...int) ([]models.CardSet, error)
+	IsLegalFunc        func(ctx context.Context, uuid string, formatName string) (bool, error)
+	WideFunc           func(ctx context.Context, uuids ...string) ([]map[string]any, error)
+	BannedInFunc       func(ctx context.Context, formatName string, limit ...int) ([]models.CardLegality, error)
+	RestrictedInFunc   func(ctx context.Context, formatName string, limit ...int) ([]models.CardLegality, error)
+	SuspendedInFunc    func(ctx context.Context, formatName string, limit ...int) ([]models.CardLegality, error)
+	NotLegalInFunc     func(ctx context.Context, formatName string, limit ...int) ([]models.CardLegality, error)
+	StatusAsOfFunc     func(ctx context.Context, uuid string, formatName string, date string) (string, error)
+	BanHistoryFunc     func(ctx context.Context, formatName string) ([]models.LegalityChange, error)
+}
+
+// FormatsForCard calls FormatsForCardFunc if set.
+func (m *LegalityAPI) FormatsForCard(ctx context.Context, uuid string) (r0 map[string]string, r1 error) {
+	if m.FormatsForCardFunc == nil {
+		return
+	}
+	return m.FormatsForCardFunc(ctx, uuid)
+}
+
+// LegalIn calls LegalInFunc if set.
+func (m *LegalityAPI) LegalIn(ctx context.Context, formatName string, limit ...int) (r0 []models.CardSet, r1 error) {
+	if m.LegalInFunc == nil {
+		return
+	}
+	return m.LegalInFunc(ctx, formatName, limit...)
+}
+
+// IsLegal calls IsLegalFunc if set.
+func (m *LegalityAPI) IsLegal(ctx context.Context, uuid string, formatName string) (r0 bool, r1 error) {
+	if m.IsLegalFunc == nil {
+		return
+	}
+	return m.IsLegalFunc(ctx, uuid, formatName)
+}
+
+// Wide calls WideFunc if set.
+func (m *LegalityAPI) Wide(ctx context.Context, uuids ...string) (r0 []map[string]any, r1 error) {
+	if m.WideFunc == nil {
+		return
+	}
+	return m.WideFunc(ctx, uuids...)
+}
+
+// BannedIn calls BannedInFunc if set.
+func (m *LegalityAPI) BannedIn(ctx context.Context, formatName string, limit ...int) (r0 []models.CardLegality, r1 error) {
+	if m.BannedInFunc == nil {
+		return
+	}
+	return m.BannedInFunc(ctx, formatName, limit...)
+}
+
+// RestrictedIn calls RestrictedInFunc if set.
+func (m *LegalityAPI) RestrictedIn(ctx context.Context, formatName string, limit ...int) (r0 []models.CardLegality, r1 error) {
+	if m.RestrictedInFunc == nil {
+		return
+	}
+	return m.RestrictedInFunc(ctx, formatName, limit...)
+}
+
+// SuspendedIn calls SuspendedInFunc if set.
+func (m *LegalityAPI) SuspendedIn(ctx context.Context, formatName string, limit ...int) (r0 []models.CardLegality, r1 error) {
+	if m.SuspendedInFunc == nil {
+		return
+	}
+	return m.SuspendedInFunc(ctx, formatName, limit...)
+}
+
+// NotLegalIn calls NotLegalInFunc if set.
+func (m *LegalityAPI) NotLegalIn(ctx context.Context, formatName string, limit ...int) (r0 []models.CardLegality, r1 error) {
+	if m.NotLegalInFunc == nil {
+		return
+	}
+	return m.NotLegalInFunc(ctx, formatName, limit...)
+}
+
+// StatusAsOf calls StatusAsOfFunc if set.
+func (m *LegalityAPI) StatusAsOf(ctx context.Context, uuid string, formatName string, date string) (r0 string, r1 error) {
+	if m.StatusAsOfFunc == nil {
+		return
+	}
+	return m.StatusAsOfFunc(ctx, uuid, formatName, date)
+}
+
+// BanHistory calls BanHistoryFunc if set.
+func (m *LegalityAPI) BanHistory(ctx context.Context, formatName string) (r0 []models.LegalityChange, r1 error) {
+	if m.BanHistoryFunc == nil {
+		return
+	}
+	return m.BanHistoryFunc(ctx, formatName)
+}
+
+// IdentifierAPI is a stub queries.IdentifierAPI.
+type IdentifierAPI struct {
+	FindByFunc                       func(ctx context.Context, idType string, value string) ([]models.CardSet, error)
+	FindByScryfallIDFunc             func(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByScryfallOracleIDFunc       func(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByScryfallIllustrationIDFunc func(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByTCGPlayerIDFunc            func(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByTCGPlayerEtchedIDFunc      func(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByMTGOIDFunc                 func(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByMTGOFoilIDFunc             func(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByMTGArenaIDFunc             func(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByMultiverseIDFunc           func(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByMCMIDFunc                  func(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByMCMMetaIDFunc              func(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByCardKingdomIDFunc          func(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByCardKingdomFoilIDFunc      func(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByCardKingdomEtchedIDFunc    func(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByCardsphereIDFunc           func(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByCardsphereFoilIDFunc       func(ctx context.Context, id string) ([]models.CardSet, error)
+	FindByAnyFunc                    func(ctx context.Context, value string) ([]models.IdentifierMatch, error)
+	GetIdentifiersFunc               func(ctx context.Context, uuid string) (map[string]any, error)
+}
+
+// FindBy calls FindByFunc if set.
+func (m *IdentifierAPI) FindBy(ctx context.Context, idType string, value string) (r0 []models.CardSet, r1 error) {
+	if m.FindByFunc == nil {
+		return
+	}
+	return m.FindByFunc(ctx, idType, value)
+}
+
+// FindByScryfallID calls FindByScryfallIDFunc if set.
+func (m *IdentifierAPI) FindByScryfallID(ctx context.Context, id string) (r0 []models.CardSet, r1 error) {
+	if m.FindByScryfallIDFunc == nil {
+		return
+	}
+	return m.FindByScryfallIDFunc(ctx, id)
+}
+
+// FindByScryfallOracleID calls FindByScryfallOracleIDFunc if set.
+func (m *IdentifierAPI) FindByScryfallOracleID(ctx context.Context, id string) (r0 []models.CardSet, r1 error) {
+	if m.FindByScryfallOracleIDFunc == nil {
+		return
+	}
+	return m.FindByScryfallOracleIDFunc(ctx, id)
+}
+
+// FindByScryfallIllustrationID calls FindByScryfallIllustrationIDFunc if set.
+func (m *IdentifierAPI) FindByScryfallIllustrationID(ctx context.Context, id string) (r0 []models.CardSet, r1 error) {
+	if m.FindByScryfallIllustrationIDFunc == nil {
+		return
+	}
+	return m.FindByScryfallIllustrationIDFunc(ctx, id)
+}
+
+// FindByTCGPlayerID calls FindByTCGPlayerIDFunc if set.
+func (m *IdentifierAPI) FindByTCGPlayerID(ctx context.Context, id string) (r0 []models.CardSet, r1 error) {
+	if m.FindByTCGPlayerIDFunc == nil {
+		return
+	}
+	return m.FindByTCGPlayerIDFunc(ctx, id)
+}
+
+// FindByTCGPlayerEtchedID calls FindByTCGPlayerEtchedIDFunc if set.
+func (m *IdentifierAPI) FindByTCGPlayerEtchedID(ctx context.Context, id string) (r0 []models.CardSet, r1 error) {
+	if m.FindByTCGPlayerEtchedIDFunc == nil {
+		return
+	}
+	return m.FindByTCGPlayerEtchedIDFunc(ctx, id)
+}
+
+// FindByMTGOID calls FindByMTGOIDFunc if set.
+func (m *IdentifierAPI) FindByMTGOID(ctx context.Context, id string) (r0 []models.CardSet, r1 error) {
+	if m.FindByMTGOIDFunc == nil {
+		return
+	}
+	return m.FindByMTGOIDFunc(ctx, id)
+}
+
+// FindByMTGOFoilID calls FindByMTGOFoilIDFunc if set.
+func (m *IdentifierAPI) FindByMTGOFoilID(ctx context.Context, id string) (r0 []models.CardSet, r1 error) {
+	if m.FindByMTGOFoilIDFunc == nil {
+		return
+	}
+	return m.FindByMTGOFoilIDFunc(ctx, id)
+}
+
+// FindByMTGArenaID calls FindByMTGArenaIDFunc if set.
+func (m *IdentifierAPI) FindByMTGArenaID(ctx context.Context, id string) (r0 []models.CardSet, r1 error) {
+	if m.FindByMTGArenaIDFunc == nil {
+		return
+	}
+	return m.FindByMTGArenaIDFunc(ctx, id)
+}
+
+// FindByMultiverseID calls FindByMultiverseIDFunc if set.
+func (m *IdentifierAPI) FindByMultiverseID(ctx context.Context, id string) (r0 []models.CardSet, r1 error) {
+	if m.FindByMultiverseIDFunc == nil {
+		return
+	}
+	return m.FindByMultiverseIDFunc(ctx, id)
+}
+
+// FindByMCMID calls FindByMCMIDFunc if set.
+func (m *IdentifierAPI) FindByMCMID(ctx context.Context, id string) (r0 []models.CardSet, r1 error) {
+	if m.FindByMCMIDFunc == nil {
+		return
+	}
+	return m.FindByMCMIDFunc(ctx, id)
+}
+
+// FindByMCMMetaID calls FindByMCMMetaIDFunc if set.
+func (m *IdentifierAPI) FindByMCMMetaID(ctx context.Context, id string) (r0 []models.CardSet, r1 error) {
+	if m.FindByMCMMetaIDFunc == nil {
+		return
+	}
+	return m.FindByMCMMetaIDFunc(ctx, id)
+}
+
+// FindByCardKingdomID calls FindByCardKingdomIDFunc if set.
+func (m *IdentifierAPI) FindByCardKingdomID(ctx context.Context, id string) (r0 []models.CardSet, r1 error) {
+	if m.FindByCardKingdomIDFunc == nil {
+		return
+	}
+	return m.FindByCardKingdomIDFunc(ctx, id)
+}
+
+// FindByCardKingdomFoilID calls FindByCardKingdomFoilIDFunc if set.
+func (m *IdentifierAPI) FindByCardKingdomFoilID(ctx context.Context, id string) (r0 []models.CardSet, r1 error) {
+	if m.FindByCardKingdomFoilIDFunc == nil {
+		return
+	}
+	return m.FindByCardKingdomFoilIDFunc(ctx, id)
+}
+
+// FindByCardKingdomEtchedID calls FindByCardKingdomEtchedIDFunc if set.
+func (m *IdentifierAPI) FindByCardKingdomEtchedID(ctx context.Context, id string) (r0 []models.CardSet, r1 error) {
+	if m.FindByCardKingdomEtchedIDFunc == nil {
+		return
+	}
+	return m.FindByCardKingdomEtchedIDFunc(ctx, id)
+}
+
+// FindByCardsphereID calls FindByCardsphereIDFunc if set.
+func (m *IdentifierAPI) FindByCardsphereID(ctx context.Context, id string) (r0 []models.CardSet, r1 error) {
+	if m.FindByCardsphereIDFunc == nil {
+		return
+	}
+	return m.FindByCardsphereIDFunc(ctx, id)
+}
+
+// FindByCardsphereFoilID calls FindByCardsphereFoilIDFunc if set.
+func (m *IdentifierAPI) FindByCardsphereFoilID(ctx context.Context, id string) (r0 []models.CardSet, r1 error) {
+	if m.FindByCardsphereFoilIDFunc == nil {
+		return
+	}
+	return m.FindByCardsphereFoilIDFunc(ctx, id)
+}
+
+// FindByAny calls FindByAnyFunc if set.
+func (m *IdentifierAPI) FindByAny(ctx context.Context, value string) (r0 []models.IdentifierMatch, r1 error) {
+	if m.FindByAnyFunc == nil {
+		return
+	}
+	return m.FindByAnyFunc(ctx, value)
+}
+
+// GetIdentifiers calls GetIdentifiersFunc if set.
+func (m *IdentifierAPI) GetIdentifiers(ctx context.Context, uuid string) (r0 map[string]any, r1 error) {
+	if m.GetIdentifiersFunc == nil {
+		return
+	}
+	return m.GetIdentifiersFunc(ctx, uuid)
+}
+
+// PriceAPI is a stub queries.PriceAPI.
+type PriceAPI struct {
+	GetFunc                    func(ctx context.Context, uuid string) (map[string]any, error)
+	TodayFunc                  func(ctx context.Context, uuid string, opts ...queries.PriceFilterOption) ([]map[string]any, error)
+	HistoryFunc                func(ctx context.Context, uuid string, opts ...queries.PriceHistoryOption) ([]map[string]any, error)
+	PriceTrendFunc             func(ctx context.Context, uuid string, opts ...queries.PriceFilterOption) (*models.PriceTrend, error)
+	CheapestPrintingFunc       func(ctx context.Context, name string, opts ...queries.PriceFilterOption) (map[string]any, error)
+	CheapestPrintingsFunc      func(ctx context.Context, opts ...queries.PriceListOption) ([]models.PricePrinting, error)
+	MostExpensivePrintingsFunc func(ctx context.Context, opts ...queries.PriceListOption) ([]models.ExpensivePrinting, error)
+	SpreadFunc                 func(ctx context.Context, uuid string, opts ...queries.PriceFilterOption) ([]models.PriceSpread, error)
+	TopSpreadsFunc             func(ctx context.Context, opts ...queries.PriceListOption) ([]models.PriceSpread, error)
+	ByVendorFunc               func(ctx context.Context, uuid string) ([]models.VendorPrice, error)
+	ValueMetricsFunc           func(ctx context.Context, p queries.ValueMetricsParams) ([]models.CardValue, error)
+	ExportCardmarketFunc       func(ctx context.Context, w io.Writer, opts ...queries.ExportOption) error
+}
+
+// Get calls GetFunc if set.
+func (m *PriceAPI) Get(ctx context.Context, uuid string) (r0 map[string]any, r1 error) {
+	if m.GetFunc == nil {
+		return
+	}
+	return m.GetFunc(ctx, uuid)
+}
+
+// Today calls TodayFunc if set.
+func (m *PriceAPI) Today(ctx context.Context, uuid string, opts ...queries.PriceFilterOption) (r0 []map[string]any, r1 error) {
+	if m.TodayFunc == nil {
+		return
+	}
+	return m.TodayFunc(ctx, uuid, opts...)
+}
+
+// History calls HistoryFunc if set.
+func (m *PriceAPI) History(ctx context.Context, uuid string, opts ...queries.PriceHistoryOption) (r0 []map[string]any, r1 error) {
+	if m.HistoryFunc == nil {
+		return
+	}
+	return m.HistoryFunc(ctx, uuid, opts...)
+}
+
+// PriceTrend calls PriceTrendFunc if set.
+func (m *PriceAPI) PriceTrend(ctx context.Context, uuid string, opts ...queries.PriceFilterOption) (r0 *models.PriceTrend, r1 error) {
+	if m.PriceTrendFunc == nil {
+		return
+	}
+	return m.PriceTrendFunc(ctx, uuid, opts...)
+}
+
+// CheapestPrinting calls CheapestPrintingFunc if set.
+func (m *PriceAPI) CheapestPrinting(ctx context.Context, name string, opts ...queries.PriceFilterOption) (r0 map[string]any, r1 error) {
+	if m.CheapestPrintingFunc == nil {
+		return
+	}
+	return m.CheapestPrintingFunc(ctx, name, opts...)
+}
+
+// CheapestPrintings calls CheapestPrintingsFunc if set.
+func (m *PriceAPI) CheapestPrintings(ctx context.Context, opts ...queries.PriceListOption) (r0 []models.PricePrinting, r1 error) {
+	if m.CheapestPrintingsFunc == nil {
+		return
+	}
+	return m.CheapestPrintingsFunc(ctx, opts...)
+}
+
+// MostExpensivePrintings calls MostExpensivePrintingsFunc if set.
+func (m *PriceAPI) MostExpensivePrintings(ctx context.Context, opts ...queries.PriceListOption) (r0 []models.ExpensivePrinting, r1 error) {
+	if m.MostExpensivePrintingsFunc == nil {
+		return
+	}
+	return m.MostExpensivePrintingsFunc(ctx, opts...)
+}
+
+// Spread calls SpreadFunc if set.
+func (m *PriceAPI) Spread(ctx context.Context, uuid string, opts ...queries.PriceFilterOption) (r0 []models.PriceSpread, r1 error) {
+	if m.SpreadFunc == nil {
+		return
+	}
+	return m.SpreadFunc(ctx, uuid, opts...)
+}
+
+// TopSpreads calls TopSpreadsFunc if set.
+func (m *PriceAPI) TopSpreads(ctx context.Context, opts ...queries.PriceListOption) (r0 []models.PriceSpread, r1 error) {
+	if m.TopSpreadsFunc == nil {
+		return
+	}
+	return m.TopSpreadsFunc(ctx, opts...)
+}
+
+// ByVendor calls ByVendorFunc if set.
+func (m *PriceAPI) ByVendor(ctx context.Context, uuid string) (r0 []models.VendorPrice, r1 error) {
+	if m.ByVendorFunc == nil {
+		return
+	}
+	return m.ByVendorFunc(ctx, uuid)
+}
+
+// ValueMetrics calls ValueMetricsFunc if set.
+func (m *PriceAPI) ValueMetrics(ctx context.Context, p queries.ValueMetricsParams) (r0 []models.CardValue, r1 error) {
+	if m.ValueMetricsFunc == nil {
+		return
+	}
+	return m.ValueMetricsFunc(ctx, p)
+}
+
+// ExportCardmarket calls ExportCardmarketFunc if set.
+func (m *PriceAPI) ExportCardmarket(ctx context.Context, w io.Writer, opts ...queries.ExportOption) (r0 error) {
+	if m.ExportCardmarketFunc == nil {
+		return
+	}
+	return m.ExportCardmarketFunc(ctx, w, opts...)
+}
+
+// DeckAPI is a stub queries.DeckAPI.
+type DeckAPI struct {
+	ListFunc     func(ctx context.Context, params queries.ListDecksParams) ([]models.DeckList, error)
+	SearchFunc   func(ctx context.Context, params queries.SearchDecksParams) ([]models.DeckList, error)
+	CountFunc    func(ctx context.Context) (int, error)
+	UpgradesFunc func(ctx context.Context, deckFile string, budget float64) ([]models.DeckUpgrade, error)
+}
+
+// List calls ListFunc if set.
+func (m *DeckAPI) List(ctx context.Context, params queries.ListDecksParams) (r0 []models.DeckList, r1 error) {
+	if m.ListFunc == nil {
+		return
+	}
+	return m.ListFunc(ctx, params)
+}
+
+// Search calls SearchFunc if set.
+func (m *DeckAPI) Search(ctx context.Context, params queries.SearchDecksParams) (r0 []models.DeckList, r1 error) {
+	if m.SearchFunc == nil {
+		return
+	}
+	return m.SearchFunc(ctx, params)
+}
+
+// Count calls CountFunc if set.
+func (m *DeckAPI) Count(ctx context.Context) (r0 int, r1 error) {
+	if m.CountFunc == nil {
+		return
+	}
+	return m.CountFunc(ctx)
+}
+
+// Upgrades calls UpgradesFunc if set.
+func (m *DeckAPI) Upgrades(ctx context.Context, deckFile string, budget float64) (r0 []models.DeckUpgrade, r1 error) {
+	if m.UpgradesFunc == nil {
+		return
+	}
+	return m.UpgradesFunc(ctx, deckFile, budget)
+}
+
+// EnumAPI is a stub queries.EnumAPI.
+type EnumAPI struct {
+	KeywordsFunc   func(ctx context.Context) (map[string]any, error)
+	CardTypesFunc  func(ctx context.Context) (map[string]any, error)
+	EnumValuesFunc func(ctx context.Context) (map[string]any, error)
+}
+
+// Keywords calls KeywordsFunc if set.
+func (m *EnumAPI) Keywords(ctx context.Context) (r0 map[string]any, r1 error) {
+	if m.KeywordsFunc == nil {
+		return
+	}
+	return m.KeywordsFunc(ctx)
+}
+
+// CardTypes calls CardTypesFunc if set.
+func (m *EnumAPI) CardTypes(ctx context.Context) (r0 map[string]any, r1 error) {
+	if m.CardTypesFunc == nil {
+		return
+	}
+	return m.CardTypesFunc(ctx)
+}
+
+// EnumValues calls EnumValuesFunc if set.
+func (m *EnumAPI) EnumValues(ctx context.Context) (r0 map[string]any, r1 error) {
+	if m.EnumValuesFunc == nil {
+		return
+	}
+	return m.EnumValuesFunc(ctx)
+}
+
+// SkuAPI is a stub queries.SkuAPI.
+type SkuAPI struct {
+	GetFunc             func(ctx context.Context, uuid string, opts ...queries.SkuOption) ([]models.TcgplayerSkus, error)
+	CountFunc           func(ctx context.Context, uuid string, opts ...queries.SkuOption) (int, error)
+	FindBySkuIDFunc     func(ctx context.Context, skuID int) (map[string]any, error)
+	FindByProductIDFunc func(ctx context.Context, productID int) ([]map[string]any, error)
+}
+
+// Get calls GetFunc if set.
+func (m *SkuAPI) Get(ctx context.Context, uuid string, opts ...queries.SkuOption) (r0 []models.TcgplayerSkus, r1 error) {
+	if m.GetFunc == nil {
+		return
+	}
+	return m.GetFunc(ctx, uuid, opts...)
+}
+
+// Count calls CountFunc if set.
+func (m *SkuAPI) Count(ctx context.Context, uuid string, opts ...queries.SkuOption) (r0 int, r1 error) {
+	if m.CountFunc == nil {
+		return
+	}
+	return m.CountFunc(ctx, uuid, opts...)
+}
+
+// FindBySkuID calls FindBySkuIDFunc if set.
+func (m *SkuAPI) FindBySkuID(ctx context.Context, skuID int) (r0 map[string]any, r1 error) {
+	if m.FindBySkuIDFunc == nil {
+		return
+	}
+	return m.FindBySkuIDFunc(ctx, skuID)
+}
+
+// FindByProductID calls FindByProductIDFunc if set.
+func (m *SkuAPI) FindByProductID(ctx context.Context, productID int) (r0 []map[string]any, r1 error) {
+	if m.FindByProductIDFunc == nil {
+		return
+	}
+	return m.FindByProductIDFunc(ctx, productID)
+}
+
+// SealedAPI is a stub queries.SealedAPI.
+type SealedAPI struct {
+	ListFunc       func(ctx context.Context, params queries.ListSealedParams) ([]map[string]any, error)
+	CategoriesFunc func(ctx context.Context) ([]models.SealedCategoryCount, error)
+	GetFunc        func(ctx context.Context, uuid string) (map[string]any, error)
+}
+
+// List calls ListFunc if set.
+func (m *SealedAPI) List(ctx context.Context, params queries.ListSealedParams) (r0 []map[string]any, r1 error) {
+	if m.ListFunc == nil {
+		return
+	}
+	return m.ListFunc(ctx, params)
+}
+
+// Categories calls CategoriesFunc if set.
+func (m *SealedAPI) Categories(ctx context.Context) (r0 []models.SealedCategoryCount, r1 error) {
+	if m.CategoriesFunc == nil {
+		return
+	}
+	return m.CategoriesFunc(ctx)
+}
+
+// Get calls GetFunc if set.
+func (m *SealedAPI) Get(ctx context.Context, uuid string) (r0 map[string]any, r1 error) {
+	if m.GetFunc == nil {
+		return
+	}
+	return m.GetFunc(ctx, uuid)
+}
+
+// FormatAPI is a stub queries.FormatAPI.
+type FormatAPI struct {
+	CurrentStandardSetsFunc func(ctx context.Context) ([]models.SetList, error)
+	StandardSetsAtFunc      func(ctx context.Context, at time.Time) ([]models.SetList, error)
+	RotationDateFunc        func(ctx context.Context, setCode string) (*time.Time, error)
+	WillRotateByFunc        func(ctx context.Context, date time.Time) ([]models.SetList, error)
+	PioneerSetsFunc         func(ctx context.Context) ([]models.SetList, error)
+}
+
+// CurrentStandardSets calls CurrentStandardSetsFunc if set.
+func (m *FormatAPI) CurrentStandardSets(ctx context.Context) (r0 []models.SetList, r1 error) {
+	if m.CurrentStandardSetsFunc == nil {
+		return
+	}
+	return m.CurrentStandardSetsFunc(ctx)
+}
+
+// StandardSetsAt calls StandardSetsAtFunc if set.
+func (m *FormatAPI) StandardSetsAt(ctx context.Context, at time.Time) (r0 []models.SetList, r1 error) {
+	if m.StandardSetsAtFunc == nil {
+		return
+	}
+	return m.StandardSetsAtFunc(ctx, at)
+}
+
+// RotationDate calls RotationDateFunc if set.
+func (m *FormatAPI) RotationDate(ctx context.Context, setCode string) (r0 *time.Time, r1 error) {
+	if m.RotationDateFunc == nil {
+		return
+	}
+	return m.RotationDateFunc(ctx, setCode)
+}
+
+// WillRotateBy calls WillRotateByFunc if set.
+func (m *FormatAPI) WillRotateBy(ctx context.Context, date time.Time) (r0 []models.SetList, r1 error) {
+	if m.WillRotateByFunc == nil {
+		return
+	}
+	return m.WillRotateByFunc(ctx, date)
+}
+
+// PioneerSets calls PioneerSetsFunc if set.
+func (m *FormatAPI) PioneerSets(ctx context.Context) (r0 []models.SetList, r1 error) {
+	if m.PioneerSetsFunc == nil {
+		return
+	}
+	return m.PioneerSetsFunc(ctx)
+}
+
+// TradeAPI is a stub queries.TradeAPI.
+type TradeAPI struct {
+	MatchFunc   func(ctx context.Context, have []models.CollectionEntry, want []models.CollectionEntry, opts ...queries.PriceFilterOption) ([]models.TradeItem, error)
+	ProposeFunc func(ctx context.Context, mine models.TradeParty, theirs models.TradeParty, opts ...queries.PriceFilterOption) (*models.TradeProposal, error)
+}
+
+// Match calls MatchFunc if set.
+func (m *TradeAPI) Match(ctx context.Context, have []models.CollectionEntry, want []models.CollectionEntry, opts ...queries.PriceFilterOption) (r0 []models.TradeItem, r1 error) {
+	if m.MatchFunc == nil {
+		return
+	}
+	return m.MatchFunc(ctx, have, want, opts...)
+}
+
+// Propose calls ProposeFunc if set.
+func (m *TradeAPI) Propose(ctx context.Context, mine models.TradeParty, theirs models.TradeParty, opts ...queries.PriceFilterOption) (r0 *models.TradeProposal, r1 error) {
+	if m.ProposeFunc == nil {
+		return
+	}
+	return m.ProposeFunc(ctx, mine, theirs, opts...)
+}
+
+// CollectionAPI is a stub queries.CollectionAPI.
+type CollectionAPI struct {
+	ImportCSVFunc func(ctx context.Context, r io.Reader, format queries.CSVFormat, opts ...queries.ExportOption) ([]models.CollectionEntry, error)
+	ExportCSVFunc func(ctx context.Context, w io.Writer, format queries.CSVFormat, entries []models.CollectionEntry, opts ...queries.ExportOption) error
+}
+
+// ImportCSV calls ImportCSVFunc if set.
+func (m *CollectionAPI) ImportCSV(ctx context.Context, r io.Reader, format queries.CSVFormat, opts ...queries.ExportOption) (r0 []models.CollectionEntry, r1 error) {
+	if m.ImportCSVFunc == nil {
+		return
+	}
+	return m.ImportCSVFunc(ctx, r, format, opts...)
+}
+
+// ExportCSV calls ExportCSVFunc if set.
+func (m *CollectionAPI) ExportCSV(ctx context.Context, w io.Writer, format queries.CSVFormat, entries []models.CollectionEntry, opts ...queries.ExportOption) (r0 error) {
+	if m.ExportCSVFunc == nil {
+		return
+	}
+	return m.ExportCSVFunc(ctx, w, format, entries, opts...)
+}
+
+// TagAPI is a stub queries.TagAPI.
+type TagAPI struct {
+	AddFunc     func(ctx context.Context, uuid string, tag string, note string) error
+	RemoveFunc  func(ctx context.Context, uuid string, tag string) error
+	ForUUIDFunc func(ctx context.Context, uuid string) ([]models.Tag, error)
+	ListFunc    func(ctx context.Context) (map[string]int, error)
+	TaggedFunc  func(ctx context.Context, tag string) ([]models.CardSet, error)
+}
+
+// Add calls AddFunc if set.
+func (m *TagAPI) Add(ctx context.Context, uuid string, tag string, note string) (r0 error) {
+	if m.AddFunc == nil {
+		return
+	}
+	return m.AddFunc(ctx, uuid, tag, note)
+}
+
+// Remove calls RemoveFunc if set.
+func (m *TagAPI) Remove(ctx context.Context, uuid string, tag string) (r0 error) {
+	if m.RemoveFunc == nil {
+		return
+	}
+	return m.RemoveFunc(ctx, uuid, tag)
+}
+
+// ForUUID calls ForUUIDFunc if set.
+func (m *TagAPI) ForUUID(ctx context.Context, uuid string) (r0 []models.Tag, r1 error) {
+	if m.ForUUIDFunc == nil {
+		return
+	}
+	return m.ForUUIDFunc(ctx, uuid)
+}
+
+// List calls ListFunc if set.
+func (m *TagAPI) List(ctx context.Context) (r0 map[string]int, r1 error) {
+	if m.ListFunc == nil {
+		return
+	}
+	return m.ListFunc(ctx)
+}
+
+// Tagged calls TaggedFunc if set.
+func (m *TagAPI) Tagged(ctx context.Context, tag string) (r0 []models.CardSet, r1 error) {
+	if m.TaggedFunc == nil {
+		return
+	}
+	return m.TaggedFunc(ctx, tag)
+}
+
+// SavedSearchAPI is a stub queries.SavedSearchAPI.
+type SavedSearchAPI struct {
+	SaveFunc   func(ctx context.Context, name string, p queries.SearchCardsParams) error
+	GetFunc    func(ctx context.Context, name string) (*queries.SearchCardsParams, error)
+	ListFunc   func(ctx context.Context) ([]string, error)
+	DeleteFunc func(ctx context.Context, name string) error
+	RunFunc    func(ctx context.Context, name string, vars map[string]string) ([]models.CardSet, error)
+}
+
+// Save calls SaveFunc if set.
+func (m *SavedSearchAPI) Save(ctx context.Context, name string, p queries.SearchCardsParams) (r0 error) {
+	if m.SaveFunc == nil {
+		return
+	}
+	return m.SaveFunc(ctx, name, p)
+}
+
+// Get calls GetFunc if set.
+func (m *SavedSearchAPI) Get(ctx context.Context, name string) (r0 *queries.SearchCardsParams, r1 error) {
+	if m.GetFunc == nil {
+		return
+	}
+	return m.GetFunc(ctx, name)
+}
+
+// List calls ListFunc if set.
+func (m *SavedSearchAPI) List(ctx context.Context) (r0 []string, r1 error) {
+	if m.ListFunc == nil {
+		return
+	}
+	return m.ListFunc(ctx)
+}
+
+// Delete calls DeleteFunc if set.
+func (m *SavedSearchAPI) Delete(ctx context.Context, name string) (r0 error) {
+	if m.DeleteFunc == nil {
+		return
+	}
+	return m.DeleteFunc(ctx, name)
+}
+
+// Run calls RunFunc if set.
+func (m *SavedSearchAPI) Run(ctx context.Context, name string, vars map[string]string) (r0 []models.CardSet, r1 error) {
+	if m.RunFunc == nil {
+		return
+	}
+	return m.RunFunc(ctx, name, vars)
+}
+
+// PurchaseAPI is a stub queries.PurchaseAPI.
+type PurchaseAPI struct {
+	LinksFunc   func(ctx context.Context, uuid string) ([]models.PurchaseLink, error)
+	ResolveFunc func(ctx context.Context, vendor string, link string) (string, error)
+}
+
+// Links calls LinksFunc if set.
+func (m *PurchaseAPI) Links(ctx context.Context, uuid string) (r0 []models.PurchaseLink, r1 error) {
+	if m.LinksFunc == nil {
+		return
+	}
+	return m.LinksFunc(ctx, uuid)
+}
+
+// Resolve calls ResolveFunc if set.
+func (m *PurchaseAPI) Resolve(ctx context.Context, vendor string, link string) (r0 string, r1 error) {
+	if m.ResolveFunc == nil {
+		return
+	}
+	return m.ResolveFunc(ctx, vendor, link)
+}
+
+// SubtypeAPI is a stub queries.SubtypeAPI.
+type SubtypeAPI struct {
+	CensusFunc func(ctx context.Context, subtype string, opts ...queries.CensusOption) (*models.SubtypeCensus, error)
+}
+
+// Census calls CensusFunc if set.
+func (m *SubtypeAPI) Census(ctx context.Context, subtype string, opts ...queries.CensusOption) (r0 *models.SubtypeCensus, r1 error) {
+	if m.CensusFunc == nil {
+		return
+	}
+	return m.CensusFunc(ctx, subtype, opts...)
+}
+
+// ForeignDataAPI is a stub queries.ForeignDataAPI.
+type ForeignDataAPI struct {
+	LanguagesFunc      func(ctx context.Context) ([]models.LanguageCount, error)
+	CoverageForSetFunc func(ctx context.Context, code string) (*models.LanguageCoverage, error)
+	AvailabilityFunc   func(ctx context.Context, name string) (*models.CardAvailability, error)
+}
+
+// Languages calls LanguagesFunc if set.
+func (m *ForeignDataAPI) Languages(ctx context.Context) (r0 []models.LanguageCount, r1 error) {
+	if m.LanguagesFunc == nil {
+		return
+	}
+	return m.LanguagesFunc(ctx)
+}
+
+// CoverageForSet calls CoverageForSetFunc if set.
+func (m *ForeignDataAPI) CoverageForSet(ctx context.Context, code string) (r0 *models.LanguageCoverage, r1 error) {
+	if m.CoverageForSetFunc == nil {
+		return
+	}
+	return m.CoverageForSetFunc(ctx, code)
+}
+
+// Availability calls AvailabilityFunc if set.
+func (m *ForeignDataAPI) Availability(ctx context.Context, name string) (r0 *models.CardAvailability, r1 error) {
+	if m.AvailabilityFunc == nil {
+		return
+	}
+	return m.AvailabilityFunc(ctx, name)
+}
+
+// BoosterAPI is a stub queries.BoosterAPI.
+type BoosterAPI struct {
+	ConfigsFunc           func(ctx context.Context, setCode string) (map[string]models.BoosterConfig, error)
+	AvailableTypesFunc    func(ctx context.Context, setCode string) ([]string, error)
+	CatalogFunc           func(ctx context.Context) (map[string][]string, error)
+	ConfigFunc            func(ctx context.Context, setCode string, boosterType string) (*models.BoosterConfig, error)
+	OpenPackFunc          func(ctx context.Context, setCode string, boosterType string) ([]models.CardSet, error)
+	OpenBoxFunc           func(ctx context.Context, setCode string, boosterType string, packs int) ([][]models.CardSet, error)
+	OpenBoxSummaryFunc    func(ctx context.Context, setCode string, boosterType string, packs int, opts ...booster.BoxOption) (*models.BoosterBox, error)
+	OpenPacksParallelFunc func(ctx context.Context, setCode string, boosterType string, n int, opts ...booster.BoxOption) (*models.PackBatch, error)
+	SimulateBoxesFunc     func(ctx context.Context, setCode string, boosterType string, packs int, boxes int, opts ...booster.BoxOption) (*models.BoxDistribution, error)
+	OpenJumpstartPackFunc func(ctx context.Context, setCode string) (*models.JumpstartPack, error)
+	SheetContentsFunc     func(ctx context.Context, setCode string, boosterType string, sheetName string) (map[string]int, error)
+	ExpectedValueFunc     func(ctx context.Context, setCode string, boosterType string, provider string) (float64, error)
+}
+
+// Configs calls ConfigsFunc if set.
+func (m *BoosterAPI) Configs(ctx context.Context, setCode string) (r0 map[string]models.BoosterConfig, r1 error) {
+	if m.ConfigsFunc == nil {
+		return
+	}
+	return m.ConfigsFunc(ctx, setCode)
+}
+
+// AvailableTypes calls AvailableTypesFunc if set.
+func (m *BoosterAPI) AvailableTypes(ctx context.Context, setCode string) (r0 []string, r1 error) {
+	if m.AvailableTypesFunc == nil {
+		return
+	}
+	return m.AvailableTypesFunc(ctx, setCode)
+}
+
+// Catalog calls CatalogFunc if set.
+func (m *BoosterAPI) Catalog(ctx context.Context) (r0 map[string][]string, r1 error) {
+	if m.CatalogFunc == nil {
+		return
+	}
+	return m.CatalogFunc(ctx)
+}
+
+// Config calls ConfigFunc if set.
+func (m *BoosterAPI) Config(ctx context.Context, setCode string, boosterType string) (r0 *models.BoosterConfig, r1 error) {
+	if m.ConfigFunc == nil {
+		return
+	}
+	return m.ConfigFunc(ctx, setCode, boosterType)
+}
+
+// OpenPack calls OpenPackFunc if set.
+func (m *BoosterAPI) OpenPack(ctx context.Context, setCode string, boosterType string) (r0 []models.CardSet, r1 error) {
+	if m.OpenPackFunc == nil {
+		return
+	}
+	return m.OpenPackFunc(ctx, setCode, boosterType)
+}
+
+// OpenBox calls OpenBoxFunc if set.
+func (m *BoosterAPI) OpenBox(ctx context.Context, setCode string, boosterType string, packs int) (r0 [][]models.CardSet, r1 error) {
+	if m.OpenBoxFunc == nil {
+		return
+	}
+	return m.OpenBoxFunc(ctx, setCode, boosterType, packs)
+}
+
+// OpenBoxSummary calls OpenBoxSummaryFunc if set.
+func (m *BoosterAPI) OpenBoxSummary(ctx context.Context, setCode string, boosterType string, packs int, opts ...booster.BoxOption) (r0 *models.BoosterBox, r1 error) {
+	if m.OpenBoxSummaryFunc == nil {
+		return
+	}
+	return m.OpenBoxSummaryFunc(ctx, setCode, boosterType, packs, opts...)
+}
+
+// OpenPacksParallel calls OpenPacksParallelFunc if set.
+func (m *BoosterAPI) OpenPacksParallel(ctx context.Context, setCode string, boosterType string, n int, opts ...booster.BoxOption) (r0 *models.PackBatch, r1 error) {
+	if m.OpenPacksParallelFunc == nil {
+		return
+	}
+	return m.OpenPacksParallelFunc(ctx, setCode, boosterType, n, opts...)
+}
+
+// SimulateBoxes calls SimulateBoxesFunc if set.
+func (m *BoosterAPI) SimulateBoxes(ctx context.Context, setCode string, boosterType string, packs int, boxes int, opts ...booster.BoxOption) (r0 *models.BoxDistribution, r1 error) {
+	if m.SimulateBoxesFunc == nil {
+		return
+	}
+	return m.SimulateBoxesFunc(ctx, setCode, boosterType, packs, boxes, opts...)
+}
+
+// OpenJumpstartPack calls OpenJumpstartPackFunc if set.
+func (m *BoosterAPI) OpenJumpstartPack(ctx context.Context, setCode string) (r0 *models.JumpstartPack, r1 error) {
+	if m.OpenJumpstartPackFunc == nil {
+		return
+	}
+	return m.OpenJumpstartPackFunc(ctx, setCode)
+}
+
+// SheetContents calls SheetContentsFunc if set.
+func (m *BoosterAPI) SheetContents(ctx context.Context, setCode string, boosterType string, sheetName string) (r0 map[string]int, r1 error) {
+	if m.SheetContentsFunc == nil {
+		return
+	}
+	return m.SheetContentsFunc(ctx, setCode, boosterType, sheetName)
+}
+
+// ExpectedValue calls ExpectedValueFunc if set.
+func (m *BoosterAPI) ExpectedValue(ctx context.Context, setCode string, boosterType string, provider string) (r0 float64, r1 error) {
+	if m.ExpectedValueFunc == nil {
+		return
+	}
+	return m.ExpectedValueFunc(ctx, setCode, boosterType, provider)
+}
+
+var (
+	_ queries.CardAPI        = (*CardAPI)(nil)
+	_ queries.SetAPI         = (*SetAPI)(nil)
+	_ queries.TokenAPI       = (*TokenAPI)(nil)
+	_ queries.LegalityAPI    = (*LegalityAPI)(nil)
+	_ queries.IdentifierAPI  = (*IdentifierAPI)(nil)
+	_ queries.PriceAPI       = (*PriceAPI)(nil)
+	_ queries.DeckAPI        = (*DeckAPI)(nil)
+	_ queries.EnumAPI        = (*EnumAPI)(nil)
+	_ queries.SkuAPI         = (*SkuAPI)(nil)
+	_ queries.SealedAPI      = (*SealedAPI)(nil)
+	_ queries.FormatAPI      = (*FormatAPI)(nil)
+	_ queries.TradeAPI       = (*TradeAPI)(nil)
+	_ queries.CollectionAPI  = (*CollectionAPI)(nil)
+	_ queries.TagAPI         = (*TagAPI)(nil)
+	_ queries.SavedSearchAPI = (*SavedSearchAPI)(nil)
+	_ queries.PurchaseAPI    = (*PurchaseAPI)(nil)
+	_ queries.SubtypeAPI     = (*SubtypeAPI)(nil)
+	_ queries.ForeignDataAPI = (*ForeignDataAPI)(nil)
+	_ queries.BoosterAPI     = (*BoosterAPI)(nil)
+)
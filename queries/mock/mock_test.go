@@ -0,0 +1,69 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+	"github.com/mtgjson/mtgjson-sdk-go/queries"
+)
+
+// deckValue stands in for application code written against the interfaces.
+func deckValue(ctx context.Context, cards queries.CardAPI, prices queries.PriceAPI, name string) (float64, error) {
+	printings, err := cards.GetByName(ctx, name)
+	if err != nil || len(printings) == 0 {
+		return 0, err
+	}
+	trend, err := prices.PriceTrend(ctx, printings[0].UUID)
+	if err != nil || trend == nil {
+		return 0, err
+	}
+	return trend.AvgPrice, nil
+}
+
+func TestStubbedMethods(t *testing.T) {
+	var gotSet []string
+	cards := &CardAPI{
+		GetByNameFunc: func(ctx context.Context, name string, setCode ...string) ([]models.CardSet, error) {
+			gotSet = setCode
+			return []models.CardSet{{UUID: "bolt", Name: name}}, nil
+		},
+	}
+	prices := &PriceAPI{
+		PriceTrendFunc: func(ctx context.Context, uuid string, opts ...queries.PriceFilterOption) (*models.PriceTrend, error) {
+			if uuid != "bolt" {
+				t.Errorf("unexpected uuid %q", uuid)
+			}
+			return &models.PriceTrend{AvgPrice: 2.5}, nil
+		},
+	}
+	v, err := deckValue(context.Background(), cards, prices, "Lightning Bolt")
+	if err != nil || v != 2.5 {
+		t.Fatalf("expected 2.5, got %v (%v)", v, err)
+	}
+	if gotSet != nil {
+		t.Errorf("expected no set code, got %v", gotSet)
+	}
+
+	if _, err := cards.GetByName(context.Background(), "x", "A25"); err != nil || len(gotSet) != 1 || gotSet[0] != "A25" {
+		t.Errorf("expected variadic arguments to be forwarded, got %v (%v)", gotSet, err)
+	}
+}
+
+func TestUnstubbedMethods(t *testing.T) {
+	var cards CardAPI
+	card, err := cards.GetByUUID(context.Background(), "x")
+	if card != nil || err != nil {
+		t.Errorf("expected zero values, got %+v (%v)", card, err)
+	}
+	if n, err := (&SetAPI{}).Count(context.Background()); n != 0 || err != nil {
+		t.Errorf("expected zero values, got %d (%v)", n, err)
+	}
+
+	boom := errors.New("boom")
+	tags := &TagAPI{AddFunc: func(ctx context.Context, uuid, tag, note string) error { return boom }}
+	if err := tags.Add(context.Background(), "x", "t", ""); !errors.Is(err, boom) {
+		t.Errorf("expected the stubbed error, got %v", err)
+	}
+}
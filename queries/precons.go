@@ -0,0 +1,34 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mtgjson/mtgjson-sdk-go/db"
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+// PreconAppearances lists every preconstructed deck (from set_decks) that
+// includes a printing of the card with the exact name, oldest release
+// first, with the board and count it appears in. The lookup uses the
+// deck_cards index built when set_decks loads, which needs DuckDB's json
+// extension.
+func (q *CardQuery) PreconAppearances(ctx context.Context, name string) ([]models.PreconAppearance, error) {
+	if err := q.conn.EnsureViews(ctx, "cards", "set_decks"); err != nil {
+		return nil, err
+	}
+	if !q.conn.Capabilities().JSON {
+		return nil, fmt.Errorf("mtgjson: precon appearances need the DuckDB json extension")
+	}
+	var result []models.PreconAppearance
+	if err := q.conn.ExecuteInto(ctx, &result,
+		"SELECT d.deckCode, d.deckName, d.deckType, d.setCode, d.releaseDate, "+
+			"  d.uuid, c.setCode AS printingSetCode, c.number, d.board, d.count, d.isFoil "+
+			"FROM "+db.DeckCardsTable+" d JOIN cards c ON c.uuid = d.uuid "+
+			"WHERE c.name = $1 "+
+			"ORDER BY d.releaseDate NULLS LAST, d.deckCode, d.board",
+		name); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
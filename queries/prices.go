@@ -12,25 +12,43 @@ import (
 // PriceQuery provides methods to query card price data.
 // Prices come from AllPricesToday.parquet, registered as a DuckDB view.
 type PriceQuery struct {
-	conn *db.Connection
+	conn  db.Backend
+	cards *CardQuery
 }
 
-func NewPriceQuery(conn *db.Connection) *PriceQuery {
-	return &PriceQuery{conn: conn}
+// PriceQueryOption configures a PriceQuery.
+type PriceQueryOption func(*PriceQuery)
+
+// WithPriceCards sets the card module ValueMetrics filters card pools
+// with, so its SearchCardsParams honor that module's exclusions.
+func WithPriceCards(cards *CardQuery) PriceQueryOption {
+	return func(q *PriceQuery) { q.cards = cards }
+}
+
+func NewPriceQuery(conn db.Backend, opts ...PriceQueryOption) *PriceQuery {
+	q := &PriceQuery{conn: conn}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
 }
 
-func (q *PriceQuery) ensure(ctx context.Context) {
-	_ = q.conn.EnsureViews(ctx, "all_prices_today")
+// ensure loads today's prices. A failed download is retried on the next call
+// and only surfaces as an error in strict mode; otherwise callers see no data.
+func (q *PriceQuery) ensure(ctx context.Context) error {
+	return q.conn.EnsureOptionalViews(ctx, "all_prices_today")
 }
 
-func (q *PriceQuery) ensureHistory(ctx context.Context) {
-	_ = q.conn.EnsureViews(ctx, "all_prices")
+func (q *PriceQuery) ensureHistory(ctx context.Context) error {
+	return q.conn.EnsureOptionalViews(ctx, "all_prices")
 }
 
 // Get returns full price data for a card UUID as a nested map.
 // Returns nil if no price data exists.
 func (q *PriceQuery) Get(ctx context.Context, uuid string) (map[string]any, error) {
-	q.ensure(ctx)
+	if err := q.ensure(ctx); err != nil {
+		return nil, err
+	}
 	if !q.conn.HasView("all_prices_today") {
 		return nil, nil
 	}
@@ -54,7 +72,7 @@ func (q *PriceQuery) Get(ctx context.Context, uuid string) (map[string]any, erro
 		price := r["price"]
 		currency, _ := r["currency"].(string)
 		if currency == "" {
-			currency = "USD"
+			currency = defaultCurrency(prov)
 		}
 
 		srcMap := ensureNestedMap(result, src)
@@ -67,9 +85,12 @@ func (q *PriceQuery) Get(ctx context.Context, uuid string) (map[string]any, erro
 	return result, nil
 }
 
-// Today returns the latest prices for a card UUID.
+// Today returns the latest prices for a card UUID: each source, provider,
+// price type and finish's most recent row.
 func (q *PriceQuery) Today(ctx context.Context, uuid string, opts ...PriceFilterOption) ([]map[string]any, error) {
-	q.ensure(ctx)
+	if err := q.ensure(ctx); err != nil {
+		return nil, err
+	}
 	if !q.conn.HasView("all_prices_today") {
 		return nil, nil
 	}
@@ -79,13 +100,17 @@ func (q *PriceQuery) Today(ctx context.Context, uuid string, opts ...PriceFilter
 	}
 
 	parts := []string{
-		"SELECT * FROM all_prices_today",
+		"SELECT * FROM " + db.LatestPricesTable,
 		"WHERE uuid = $1",
-		"AND date = (SELECT MAX(p2.date) FROM all_prices_today p2 WHERE p2.uuid = $1)",
 	}
 	params := []any{uuid}
 	idx := 2
 
+	if cfg.source != "" {
+		parts = append(parts, fmt.Sprintf("AND source = $%d", idx))
+		params = append(params, cfg.source)
+		idx++
+	}
 	if cfg.provider != "" {
 		parts = append(parts, fmt.Sprintf("AND provider = $%d", idx))
 		params = append(params, cfg.provider)
@@ -106,7 +131,9 @@ func (q *PriceQuery) Today(ctx context.Context, uuid string, opts ...PriceFilter
 
 // History returns price history for a card UUID.
 func (q *PriceQuery) History(ctx context.Context, uuid string, opts ...PriceHistoryOption) ([]map[string]any, error) {
-	q.ensureHistory(ctx)
+	if err := q.ensureHistory(ctx); err != nil {
+		return nil, err
+	}
 	if !q.conn.HasView("all_prices") {
 		return nil, nil
 	}
@@ -119,6 +146,11 @@ func (q *PriceQuery) History(ctx context.Context, uuid string, opts ...PriceHist
 	params := []any{uuid}
 	idx := 2
 
+	if cfg.source != "" {
+		parts = append(parts, fmt.Sprintf("AND source = $%d", idx))
+		params = append(params, cfg.source)
+		idx++
+	}
 	if cfg.provider != "" {
 		parts = append(parts, fmt.Sprintf("AND provider = $%d", idx))
 		params = append(params, cfg.provider)
@@ -149,8 +181,14 @@ func (q *PriceQuery) History(ctx context.Context, uuid string, opts ...PriceHist
 }
 
 // PriceTrend returns price trend statistics for a card.
+// Statistics are never aggregated across currencies. Without a provider the
+// trend is of paper prices in USD, as with the other price queries, unless
+// WithPriceSource picks another source; when the filtered rows still span
+// several currencies, the currency with the most data points is used.
 func (q *PriceQuery) PriceTrend(ctx context.Context, uuid string, opts ...PriceFilterOption) (*models.PriceTrend, error) {
-	q.ensureHistory(ctx)
+	if err := q.ensureHistory(ctx); err != nil {
+		return nil, err
+	}
 	if !q.conn.HasView("all_prices") {
 		return nil, nil
 	}
@@ -161,6 +199,7 @@ func (q *PriceQuery) PriceTrend(ctx context.Context, uuid string, opts ...PriceF
 
 	parts := []string{
 		"SELECT",
+		"  currency,",
 		"  MIN(price) AS min_price,",
 		"  MAX(price) AS max_price,",
 		"  ROUND(AVG(price), 2) AS avg_price,",
@@ -173,15 +212,35 @@ func (q *PriceQuery) PriceTrend(ctx context.Context, uuid string, opts ...PriceF
 	params := []any{uuid, cfg.priceType}
 	idx := 3
 
+	currency := ""
+	if cfg.provider == "" {
+		if cfg.source == "" {
+			cfg.source = "paper"
+		}
+		if cfg.source == "paper" {
+			currency = "USD"
+		}
+	}
+	if cfg.source != "" {
+		parts = append(parts, fmt.Sprintf("AND source = $%d", idx))
+		params = append(params, cfg.source)
+		idx++
+	}
 	if cfg.provider != "" {
 		parts = append(parts, fmt.Sprintf("AND provider = $%d", idx))
 		params = append(params, cfg.provider)
 		idx++
 	}
+	if currency != "" {
+		parts = append(parts, fmt.Sprintf("AND currency = $%d", idx))
+		params = append(params, currency)
+		idx++
+	}
 	if cfg.finish != "" {
 		parts = append(parts, fmt.Sprintf("AND finish = $%d", idx))
 		params = append(params, cfg.finish)
 	}
+	parts = append(parts, "GROUP BY currency ORDER BY data_points DESC, currency ASC LIMIT 1")
 
 	rows, err := q.conn.Execute(ctx, strings.Join(parts, " "), params...)
 	if err != nil {
@@ -194,7 +253,9 @@ func (q *PriceQuery) PriceTrend(ctx context.Context, uuid string, opts ...PriceF
 	if dp == 0 {
 		return nil, nil
 	}
+	currency, _ = rows[0]["currency"].(string)
 	return &models.PriceTrend{
+		Currency:   currency,
 		MinPrice:   db.ToFloat64(rows[0]["min_price"]),
 		MaxPrice:   db.ToFloat64(rows[0]["max_price"]),
 		AvgPrice:   db.ToFloat64(rows[0]["avg_price"]),
@@ -206,29 +267,37 @@ func (q *PriceQuery) PriceTrend(ctx context.Context, uuid string, opts ...PriceF
 
 // CheapestPrinting finds the cheapest printing of a card by name.
 func (q *PriceQuery) CheapestPrinting(ctx context.Context, name string, opts ...PriceFilterOption) (map[string]any, error) {
-	q.ensure(ctx)
+	if err := q.ensure(ctx); err != nil {
+		return nil, err
+	}
 	if err := q.conn.EnsureViews(ctx, "cards"); err != nil {
 		return nil, err
 	}
 	if !q.conn.HasView("all_prices_today") {
 		return nil, nil
 	}
-	cfg := &priceFilter{provider: "tcgplayer", finish: "normal", priceType: "retail"}
+	cfg := &priceFilter{finish: "normal", priceType: "retail"}
 	for _, opt := range opts {
 		opt(cfg)
 	}
+	if cfg.provider == "" {
+		cfg.provider = defaultProvider(cfg.source)
+	}
 
-	sql := "SELECT c.uuid, c.setCode, c.number, p.price, p.date " +
+	sourceClause := ""
+	params := []any{name, cfg.provider, cfg.finish, cfg.priceType}
+	if cfg.source != "" {
+		sourceClause = "AND p.source = $5 "
+		params = append(params, cfg.source)
+	}
+	sql := "SELECT c.uuid, c.setCode, c.number, p.price, p.currency, p.date " +
 		"FROM cards c " +
-		"JOIN all_prices_today p ON c.uuid = p.uuid " +
+		"JOIN " + db.LatestPricesTable + " p ON c.uuid = p.uuid " +
 		"WHERE c.name = $1 AND p.provider = $2 " +
-		"AND p.finish = $3 AND p.price_type = $4 " +
-		"AND p.date = (SELECT MAX(p2.date) FROM all_prices_today p2 " +
-		"WHERE p2.uuid = c.uuid AND p2.provider = $2 " +
-		"AND p2.finish = $3 AND p2.price_type = $4) " +
+		"AND p.finish = $3 AND p.price_type = $4 " + sourceClause +
 		"ORDER BY p.price ASC " +
 		"LIMIT 1"
-	rows, err := q.conn.Execute(ctx, sql, name, cfg.provider, cfg.finish, cfg.priceType)
+	rows, err := q.conn.Execute(ctx, sql, params...)
 	if err != nil {
 		return nil, err
 	}
@@ -240,34 +309,41 @@ func (q *PriceQuery) CheapestPrinting(ctx context.Context, name string, opts ...
 
 // CheapestPrintings finds the cheapest available printing of each card.
 func (q *PriceQuery) CheapestPrintings(ctx context.Context, opts ...PriceListOption) ([]models.PricePrinting, error) {
-	q.ensure(ctx)
+	if err := q.ensure(ctx); err != nil {
+		return nil, err
+	}
 	if err := q.conn.EnsureViews(ctx, "cards"); err != nil {
 		return nil, err
 	}
 	if !q.conn.HasView("all_prices_today") {
 		return nil, nil
 	}
-	cfg := &priceListConfig{provider: "tcgplayer", finish: "normal", priceType: "retail", limit: 100}
+	cfg := &priceListConfig{finish: "normal", priceType: "retail", limit: 100}
 	for _, opt := range opts {
 		opt(cfg)
 	}
+	if cfg.provider == "" {
+		cfg.provider = defaultProvider(cfg.source)
+	}
+	sourceClause, params := listSourceClause(cfg)
 
 	sql := fmt.Sprintf(
 		"SELECT c.name, "+
 			"  arg_min(c.setCode, p.price) AS cheapest_set, "+
 			"  arg_min(c.number, p.price) AS cheapest_number, "+
 			"  arg_min(c.uuid, p.price) AS cheapest_uuid, "+
-			"  MIN(p.price) AS min_price "+
+			"  MIN(p.price) AS min_price, "+
+			"  p.currency "+
 			"FROM cards c "+
-			"JOIN all_prices_today p ON c.uuid = p.uuid "+
-			"WHERE p.provider = $1 AND p.finish = $2 AND p.price_type = $3 "+
-			"AND p.date = (SELECT MAX(date) FROM all_prices_today) "+
-			"GROUP BY c.name "+
+			"JOIN %s p ON c.uuid = p.uuid "+
+			"WHERE p.provider = $1 AND p.finish = $2 AND p.price_type = $3 %s"+
+			"GROUP BY c.name, p.currency "+
 			"ORDER BY min_price ASC "+
-			"LIMIT %d OFFSET %d", cfg.limit, cfg.offset)
+			"LIMIT $%d OFFSET $%d", db.LatestPricesTable, sourceClause, len(params)+1, len(params)+2)
+	params = append(params, cfg.limit, cfg.offset)
 
 	var result []models.PricePrinting
-	if err := q.conn.ExecuteInto(ctx, &result, sql, cfg.provider, cfg.finish, cfg.priceType); err != nil {
+	if err := q.conn.ExecuteInto(ctx, &result, sql, params...); err != nil {
 		return nil, err
 	}
 	return result, nil
@@ -275,42 +351,217 @@ func (q *PriceQuery) CheapestPrintings(ctx context.Context, opts ...PriceListOpt
 
 // MostExpensivePrintings finds the most expensive printing of each card.
 func (q *PriceQuery) MostExpensivePrintings(ctx context.Context, opts ...PriceListOption) ([]models.ExpensivePrinting, error) {
-	q.ensure(ctx)
+	if err := q.ensure(ctx); err != nil {
+		return nil, err
+	}
 	if err := q.conn.EnsureViews(ctx, "cards"); err != nil {
 		return nil, err
 	}
 	if !q.conn.HasView("all_prices_today") {
 		return nil, nil
 	}
-	cfg := &priceListConfig{provider: "tcgplayer", finish: "normal", priceType: "retail", limit: 100}
+	cfg := &priceListConfig{finish: "normal", priceType: "retail", limit: 100}
 	for _, opt := range opts {
 		opt(cfg)
 	}
+	if cfg.provider == "" {
+		cfg.provider = defaultProvider(cfg.source)
+	}
+	sourceClause, params := listSourceClause(cfg)
 
 	sql := fmt.Sprintf(
 		"SELECT c.name, "+
 			"  arg_max(c.setCode, p.price) AS priciest_set, "+
 			"  arg_max(c.number, p.price) AS priciest_number, "+
 			"  arg_max(c.uuid, p.price) AS priciest_uuid, "+
-			"  MAX(p.price) AS max_price "+
+			"  MAX(p.price) AS max_price, "+
+			"  p.currency "+
 			"FROM cards c "+
-			"JOIN all_prices_today p ON c.uuid = p.uuid "+
-			"WHERE p.provider = $1 AND p.finish = $2 AND p.price_type = $3 "+
-			"AND p.date = (SELECT MAX(date) FROM all_prices_today) "+
-			"GROUP BY c.name "+
+			"JOIN %s p ON c.uuid = p.uuid "+
+			"WHERE p.provider = $1 AND p.finish = $2 AND p.price_type = $3 %s"+
+			"GROUP BY c.name, p.currency "+
 			"ORDER BY max_price DESC "+
-			"LIMIT %d OFFSET %d", cfg.limit, cfg.offset)
+			"LIMIT $%d OFFSET $%d", db.LatestPricesTable, sourceClause, len(params)+1, len(params)+2)
+	params = append(params, cfg.limit, cfg.offset)
 
 	var result []models.ExpensivePrinting
-	if err := q.conn.ExecuteInto(ctx, &result, sql, cfg.provider, cfg.finish, cfg.priceType); err != nil {
+	if err := q.conn.ExecuteInto(ctx, &result, sql, params...); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Spread returns the retail minus buylist spread for a card UUID, one entry
+// per provider and finish that has both a latest retail and buylist price.
+// The price type option is ignored.
+func (q *PriceQuery) Spread(ctx context.Context, uuid string, opts ...PriceFilterOption) ([]models.PriceSpread, error) {
+	if err := q.ensure(ctx); err != nil {
+		return nil, err
+	}
+	if !q.conn.HasView("all_prices_today") {
+		return nil, nil
+	}
+	cfg := &priceFilter{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	parts := []string{
+		"SELECT uuid, provider, finish,",
+		"  MAX(price) FILTER (WHERE price_type = 'retail') AS retail_price,",
+		"  MAX(price) FILTER (WHERE price_type = 'buylist') AS buylist_price,",
+		"  ROUND(MAX(price) FILTER (WHERE price_type = 'retail') -",
+		"    MAX(price) FILTER (WHERE price_type = 'buylist'), 2) AS spread,",
+		"  MAX(date) AS date",
+		"FROM " + db.LatestPricesTable,
+		"WHERE uuid = $1",
+	}
+	params := []any{uuid}
+	idx := 2
+
+	if cfg.source != "" {
+		parts = append(parts, fmt.Sprintf("AND source = $%d", idx))
+		params = append(params, cfg.source)
+		idx++
+	}
+	if cfg.provider != "" {
+		parts = append(parts, fmt.Sprintf("AND provider = $%d", idx))
+		params = append(params, cfg.provider)
+		idx++
+	}
+	if cfg.finish != "" {
+		parts = append(parts, fmt.Sprintf("AND finish = $%d", idx))
+		params = append(params, cfg.finish)
+	}
+	parts = append(parts,
+		"GROUP BY uuid, provider, finish",
+		"HAVING retail_price IS NOT NULL AND buylist_price IS NOT NULL",
+		"ORDER BY provider, finish",
+	)
+
+	var result []models.PriceSpread
+	if err := q.conn.ExecuteInto(ctx, &result, strings.Join(parts, " "), params...); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// TopSpreads ranks printings by the spread between their latest retail and
+// buylist prices, largest spread first. The price type option is ignored.
+func (q *PriceQuery) TopSpreads(ctx context.Context, opts ...PriceListOption) ([]models.PriceSpread, error) {
+	if err := q.ensure(ctx); err != nil {
+		return nil, err
+	}
+	if err := q.conn.EnsureViews(ctx, "cards"); err != nil {
+		return nil, err
+	}
+	if !q.conn.HasView("all_prices_today") {
+		return nil, nil
+	}
+	cfg := &priceListConfig{finish: "normal", limit: 100}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.provider == "" {
+		cfg.provider = defaultProvider(cfg.source)
+	}
+	sourceClause := ""
+	params := []any{cfg.provider, cfg.finish}
+	if cfg.source != "" {
+		sourceClause = "AND p.source = $3 "
+		params = append(params, cfg.source)
+	}
+
+	sql := fmt.Sprintf(
+		"SELECT p.uuid, c.name, c.setCode, c.number, p.provider, p.finish, "+
+			"  MAX(p.price) FILTER (WHERE p.price_type = 'retail') AS retail_price, "+
+			"  MAX(p.price) FILTER (WHERE p.price_type = 'buylist') AS buylist_price, "+
+			"  ROUND(MAX(p.price) FILTER (WHERE p.price_type = 'retail') - "+
+			"    MAX(p.price) FILTER (WHERE p.price_type = 'buylist'), 2) AS spread, "+
+			"  MAX(p.date) AS date "+
+			"FROM %s p "+
+			"JOIN cards c ON c.uuid = p.uuid "+
+			"WHERE p.provider = $1 AND p.finish = $2 %s"+
+			"GROUP BY p.uuid, c.name, c.setCode, c.number, p.provider, p.finish "+
+			"HAVING retail_price IS NOT NULL AND buylist_price IS NOT NULL "+
+			"ORDER BY spread DESC, c.name ASC "+
+			"LIMIT $%d OFFSET $%d", db.LatestPricesTable, sourceClause, len(params)+1, len(params)+2)
+	params = append(params, cfg.limit, cfg.offset)
+
+	var result []models.PriceSpread
+	if err := q.conn.ExecuteInto(ctx, &result, sql, params...); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// priceVendors lists the paper vendors compared by ByVendor, in display
+// order, with the MTGJSON provider name, label and default currency.
+var priceVendors = []struct {
+	provider, name, currency string
+}{
+	{"tcgplayer", "TCGplayer", "USD"},
+	{"cardmarket", "Cardmarket", "EUR"},
+	{"cardkingdom", "Card Kingdom", "USD"},
+	{"cardsphere", "Cardsphere", "USD"},
+}
+
+// ByVendor returns a card's latest paper prices side by side for TCGplayer,
+// Cardmarket, Card Kingdom and Cardsphere, one entry per vendor and finish.
+// Vendors without a price for the card are omitted.
+func (q *PriceQuery) ByVendor(ctx context.Context, uuid string) ([]models.VendorPrice, error) {
+	if err := q.ensure(ctx); err != nil {
 		return nil, err
 	}
+	if !q.conn.HasView("all_prices_today") {
+		return nil, nil
+	}
+	providers := make([]any, len(priceVendors))
+	placeholders := make([]string, len(priceVendors))
+	for i, v := range priceVendors {
+		providers[i] = v.provider
+		placeholders[i] = fmt.Sprintf("$%d", i+2)
+	}
+	sql := "SELECT provider, finish, ANY_VALUE(currency) AS currency, " +
+		"  MAX(CAST(price AS DOUBLE)) FILTER (WHERE price_type = 'retail') AS retail_price, " +
+		"  MAX(CAST(price AS DOUBLE)) FILTER (WHERE price_type = 'buylist') AS buylist_price, " +
+		"  CAST(MAX(date) AS VARCHAR) AS date " +
+		"FROM " + db.LatestPricesTable + " " +
+		"WHERE uuid = $1 AND source = 'paper' AND provider IN (" + strings.Join(placeholders, ", ") + ") " +
+		"GROUP BY provider, finish ORDER BY finish"
+	rows, err := q.conn.Execute(ctx, sql, append([]any{uuid}, providers...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []models.VendorPrice{}
+	for _, v := range priceVendors {
+		for _, r := range rows {
+			if r["provider"] != v.provider {
+				continue
+			}
+			vp := models.VendorPrice{Vendor: v.provider, VendorName: v.name, Currency: v.currency}
+			vp.Finish, _ = r["finish"].(string)
+			vp.Date, _ = r["date"].(string)
+			if c, _ := r["currency"].(string); c != "" {
+				vp.Currency = c
+			}
+			if p, ok := r["retail_price"].(float64); ok {
+				vp.RetailPrice = &p
+			}
+			if p, ok := r["buylist_price"].(float64); ok {
+				vp.BuylistPrice = &p
+			}
+			result = append(result, vp)
+		}
+	}
 	return result, nil
 }
 
 // --- Functional option types ---
 
 type priceFilter struct {
+	source    string
 	provider  string
 	finish    string
 	priceType string
@@ -319,6 +570,11 @@ type priceFilter struct {
 // PriceFilterOption configures price query filters.
 type PriceFilterOption func(*priceFilter)
 
+// WithPriceSource filters by price source ("paper" or "mtgo").
+func WithPriceSource(source string) PriceFilterOption {
+	return func(c *priceFilter) { c.source = source }
+}
+
 // WithPriceProvider filters by price provider (e.g. "tcgplayer", "cardmarket").
 func WithPriceProvider(provider string) PriceFilterOption {
 	return func(c *priceFilter) { c.provider = provider }
@@ -335,6 +591,7 @@ func WithPriceType(priceType string) PriceFilterOption {
 }
 
 type priceHistoryConfig struct {
+	source    string
 	provider  string
 	finish    string
 	priceType string
@@ -345,6 +602,11 @@ type priceHistoryConfig struct {
 // PriceHistoryOption configures price history query filters.
 type PriceHistoryOption func(*priceHistoryConfig)
 
+// WithHistorySource filters history by price source ("paper" or "mtgo").
+func WithHistorySource(source string) PriceHistoryOption {
+	return func(c *priceHistoryConfig) { c.source = source }
+}
+
 // WithHistoryProvider filters history by provider.
 func WithHistoryProvider(provider string) PriceHistoryOption {
 	return func(c *priceHistoryConfig) { c.provider = provider }
@@ -371,6 +633,7 @@ func WithDateTo(date string) PriceHistoryOption {
 }
 
 type priceListConfig struct {
+	source    string
 	provider  string
 	finish    string
 	priceType string
@@ -381,7 +644,13 @@ type priceListConfig struct {
 // PriceListOption configures cheapest/most expensive printing queries.
 type PriceListOption func(*priceListConfig)
 
-// WithListProvider sets the provider for list queries.
+// WithListSource sets the price source ("paper" or "mtgo") for list queries.
+func WithListSource(source string) PriceListOption {
+	return func(c *priceListConfig) { c.source = source }
+}
+
+// WithListProvider sets the provider for list queries (default
+// "tcgplayer", or "cardhoarder" for the mtgo source).
 func WithListProvider(provider string) PriceListOption {
 	return func(c *priceListConfig) { c.provider = provider }
 }
@@ -408,6 +677,34 @@ func WithListOffset(offset int) PriceListOption {
 
 // --- Helper ---
 
+// listSourceClause returns the optional source condition and the full
+// parameter list for the cheapest/most expensive printing queries.
+func listSourceClause(cfg *priceListConfig) (string, []any) {
+	params := []any{cfg.provider, cfg.finish, cfg.priceType}
+	if cfg.source == "" {
+		return "", params
+	}
+	return "AND p.source = $4 ", append(params, cfg.source)
+}
+
+// defaultProvider returns the provider price queries use for source when
+// none is given: Cardhoarder for MTGO, TCGplayer otherwise.
+func defaultProvider(source string) string {
+	if source == "mtgo" {
+		return "cardhoarder"
+	}
+	return "tcgplayer"
+}
+
+// defaultCurrency returns the currency a provider quotes in when the price
+// row does not carry one. Cardhoarder prices MTGO cards in event tickets.
+func defaultCurrency(provider string) string {
+	if provider == "cardhoarder" {
+		return "TIX"
+	}
+	return "USD"
+}
+
 func ensureNestedMap(parent map[string]any, key string) map[string]any {
 	if v, ok := parent[key]; ok {
 		if m, ok := v.(map[string]any); ok {
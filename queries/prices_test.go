@@ -46,6 +46,16 @@ var samplePricesExtended = []map[string]any{
 		"currency": "USD", "price_type": "retail", "finish": "normal",
 		"date": "2024-01-03", "price": 5.00,
 	},
+	{
+		"uuid": "card-uuid-002", "source": "mtgo", "provider": "cardhoarder",
+		"currency": "TIX", "price_type": "retail", "finish": "normal",
+		"date": "2024-01-02", "price": 0.04,
+	},
+	{
+		"uuid": "card-uuid-002", "source": "mtgo", "provider": "cardhoarder",
+		"currency": "TIX", "price_type": "retail", "finish": "normal",
+		"date": "2024-01-03", "price": 0.05,
+	},
 }
 
 // dateStr extracts a date string from a DuckDB value (may be time.Time or string).
@@ -60,11 +70,12 @@ func dateStr(v any) string {
 	}
 }
 
-func setupPriceQuery(t *testing.T) *PriceQuery {
+func setupPriceQuery(t *testing.T, extra ...map[string]any) *PriceQuery {
 	t.Helper()
 	conn := setupSampleDB(t)
 	ctx := context.Background()
-	if err := conn.RegisterTableFromData(ctx, "all_prices_today", samplePricesExtended); err != nil {
+	today := append(append([]map[string]any{}, samplePricesExtended...), extra...)
+	if err := conn.RegisterTableFromData(ctx, "all_prices_today", today); err != nil {
 		t.Fatal(err)
 	}
 	if err := conn.RegisterTableFromData(ctx, "all_prices", samplePricesExtended); err != nil {
@@ -89,6 +100,31 @@ func TestTodayReturnsLatestDate(t *testing.T) {
 	}
 }
 
+func TestLatestPricesPerSeries(t *testing.T) {
+	// Card Kingdom last priced card-uuid-002 before the newest date.
+	pq := setupPriceQuery(t, map[string]any{
+		"uuid": "card-uuid-002", "source": "paper", "provider": "cardkingdom",
+		"currency": "USD", "price_type": "retail", "finish": "normal",
+		"date": "2024-01-01", "price": 4.50,
+	})
+	ctx := context.Background()
+
+	rows, err := pq.Today(ctx, "card-uuid-002", WithPriceProvider("cardkingdom"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || dateStr(rows[0]["date"]) != "2024-01-01" {
+		t.Fatalf("expected Card Kingdom's 2024-01-01 price, got %v", rows)
+	}
+	cheapest, err := pq.CheapestPrintings(ctx, WithListProvider("cardkingdom"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cheapest) != 1 || cheapest[0].MinPrice != 4.50 {
+		t.Fatalf("expected the older Card Kingdom price, got %+v", cheapest)
+	}
+}
+
 func TestTodayWithProviderFilter(t *testing.T) {
 	pq := setupPriceQuery(t)
 	ctx := context.Background()
@@ -331,3 +367,234 @@ func TestGetReturnsNestedStructure(t *testing.T) {
 		t.Fatalf("expected USD currency, got %v", tcg["currency"])
 	}
 }
+
+func TestSpread(t *testing.T) {
+	pq := setupPriceQuery(t)
+	ctx := context.Background()
+
+	spreads, err := pq.Spread(ctx, "card-uuid-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spreads) != 1 {
+		t.Fatalf("expected 1 spread (normal finish only has buylist), got %d", len(spreads))
+	}
+	s := spreads[0]
+	if s.Provider != "tcgplayer" || s.Finish != "normal" {
+		t.Fatalf("unexpected provider/finish: %s/%s", s.Provider, s.Finish)
+	}
+	if s.RetailPrice != 2.00 || s.BuylistPrice != 0.80 {
+		t.Fatalf("expected retail 2.00 buylist 0.80, got %v/%v", s.RetailPrice, s.BuylistPrice)
+	}
+	if s.Spread != 1.20 {
+		t.Fatalf("expected spread 1.20, got %v", s.Spread)
+	}
+	if s.Date != "2024-01-03" {
+		t.Fatalf("expected date 2024-01-03, got %s", s.Date)
+	}
+}
+
+func TestSpreadNoBuylist(t *testing.T) {
+	pq := setupPriceQuery(t)
+	ctx := context.Background()
+
+	spreads, err := pq.Spread(ctx, "card-uuid-002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spreads) != 0 {
+		t.Fatalf("expected no spreads, got %v", spreads)
+	}
+}
+
+func TestTopSpreads(t *testing.T) {
+	pq := setupPriceQuery(t)
+	ctx := context.Background()
+
+	spreads, err := pq.TopSpreads(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spreads) != 1 {
+		t.Fatalf("expected 1 spread, got %d", len(spreads))
+	}
+	if spreads[0].Name != "Lightning Bolt" || spreads[0].SetCode != "A25" {
+		t.Fatalf("unexpected card: %+v", spreads[0])
+	}
+	if spreads[0].Spread != 1.20 {
+		t.Fatalf("expected spread 1.20, got %v", spreads[0].Spread)
+	}
+}
+
+func TestTodayWithSourceFilter(t *testing.T) {
+	pq := setupPriceQuery(t)
+	ctx := context.Background()
+
+	rows, err := pq.Today(ctx, "card-uuid-002", WithPriceSource("mtgo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("expected 1 row, got %d", len(rows))
+	}
+	if rows[0]["provider"] != "cardhoarder" || rows[0]["currency"] != "TIX" {
+		t.Fatalf("expected cardhoarder TIX row, got %v", rows[0])
+	}
+}
+
+func TestHistoryWithSourceFilter(t *testing.T) {
+	pq := setupPriceQuery(t)
+	ctx := context.Background()
+
+	rows, err := pq.History(ctx, "card-uuid-002", WithHistorySource("paper"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("expected 1 paper row, got %d", len(rows))
+	}
+}
+
+func TestPriceTrendDoesNotMixCurrencies(t *testing.T) {
+	pq := setupPriceQuery(t)
+	ctx := context.Background()
+
+	trend, err := pq.PriceTrend(ctx, "card-uuid-002", WithPriceSource("mtgo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trend == nil {
+		t.Fatal("expected trend, got nil")
+	}
+	if trend.Currency != "TIX" {
+		t.Fatalf("expected TIX currency, got %q", trend.Currency)
+	}
+	if trend.MinPrice != 0.04 || trend.MaxPrice != 0.05 {
+		t.Fatalf("expected 0.04-0.05 TIX range, got %v-%v", trend.MinPrice, trend.MaxPrice)
+	}
+
+	trend, err = pq.PriceTrend(ctx, "card-uuid-002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trend.Currency != "USD" || trend.DataPoints != 1 {
+		t.Fatalf("expected the paper USD trend by default, not the TIX one with more points, got %+v", trend)
+	}
+}
+
+func TestCheapestPrintingsWithSource(t *testing.T) {
+	pq := setupPriceQuery(t)
+	ctx := context.Background()
+
+	rows, err := pq.CheapestPrintings(ctx,
+		WithListProvider("cardhoarder"), WithListSource("mtgo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("expected 1 row, got %d", len(rows))
+	}
+	if rows[0].Name != "Counterspell" || rows[0].Currency != "TIX" {
+		t.Fatalf("unexpected row: %+v", rows[0])
+	}
+
+	// The source alone picks its provider.
+	rows, err = pq.CheapestPrintings(ctx, WithListSource("mtgo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || rows[0].Currency != "TIX" {
+		t.Fatalf("expected the cardhoarder row for mtgo, got %+v", rows)
+	}
+	cheapest, err := pq.CheapestPrinting(ctx, "Counterspell", WithPriceSource("mtgo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cheapest == nil || cheapest["currency"] != "TIX" {
+		t.Fatalf("expected a TIX price for mtgo, got %v", cheapest)
+	}
+}
+
+func TestDefaultCurrency(t *testing.T) {
+	if got := defaultCurrency("cardhoarder"); got != "TIX" {
+		t.Fatalf("expected TIX, got %s", got)
+	}
+	if got := defaultCurrency("tcgplayer"); got != "USD" {
+		t.Fatalf("expected USD, got %s", got)
+	}
+}
+
+func TestPriceByVendor(t *testing.T) {
+	pq := setupPriceQuery(t, append(sampleCardmarketPrices, map[string]any{
+		"uuid": "card-uuid-001", "source": "paper", "provider": "cardkingdom",
+		"currency": "USD", "price_type": "buylist", "finish": "normal",
+		"date": "2024-01-03", "price": 0.9,
+	})...)
+	ctx := context.Background()
+
+	got, err := pq.ByVendor(ctx, "card-uuid-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, vp := range got {
+		keys = append(keys, vp.Vendor+"/"+vp.Finish)
+	}
+	want := "tcgplayer/foil tcgplayer/normal cardmarket/foil cardmarket/normal cardkingdom/normal"
+	if s := fmt.Sprint(keys); s != "["+want+"]" {
+		t.Fatalf("expected %s, got %s", want, s)
+	}
+	tcg := got[1]
+	if tcg.RetailPrice == nil || *tcg.RetailPrice != 2.00 || tcg.BuylistPrice == nil || *tcg.BuylistPrice != 0.80 {
+		t.Errorf("unexpected TCGplayer normal prices: %+v", tcg)
+	}
+	mcm := got[3]
+	if mcm.VendorName != "Cardmarket" || mcm.Currency != "EUR" || *mcm.RetailPrice != 1.25 || mcm.BuylistPrice != nil {
+		t.Errorf("unexpected Cardmarket entry: %+v", mcm)
+	}
+	if ck := got[4]; ck.RetailPrice != nil || *ck.BuylistPrice != 0.9 || ck.VendorName != "Card Kingdom" {
+		t.Errorf("unexpected Card Kingdom entry: %+v", ck)
+	}
+}
+
+func TestPriceByVendorSkipsMTGO(t *testing.T) {
+	pq := setupPriceQuery(t)
+	got, err := pq.ByVendor(context.Background(), "card-uuid-002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Vendor != "tcgplayer" {
+		t.Errorf("expected only the TCGplayer paper price, got %+v", got)
+	}
+}
+
+func TestValueMetrics(t *testing.T) {
+	pq := setupPriceQuery(t)
+	ctx := context.Background()
+
+	ranked, err := pq.ValueMetrics(ctx, ValueMetricsParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Lightning Bolt: $2.00 / 1; Counterspell: $5.00 / 2.
+	if len(ranked) != 2 || ranked[0].Name != "Lightning Bolt" || ranked[1].Name != "Counterspell" {
+		t.Fatalf("expected Lightning Bolt then Counterspell, got %+v", ranked)
+	}
+	if got := ranked[1].PricePerManaValue; got == nil || *got != 2.5 {
+		t.Fatalf("expected Counterspell at 2.5 per mana value, got %v", got)
+	}
+
+	desc, err := pq.ValueMetrics(ctx, ValueMetricsParams{Cards: SearchCardsParams{SetCode: "MH2"}, Descending: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(desc) != 1 || desc[0].Name != "Counterspell" {
+		t.Fatalf("expected only Counterspell in MH2, got %+v", desc)
+	}
+	if power, err := pq.ValueMetrics(ctx, ValueMetricsParams{Metric: ValuePerPower}); err != nil || len(power) != 0 {
+		t.Fatalf("expected no creatures with power, got %+v, %v", power, err)
+	}
+	if _, err := pq.ValueMetrics(ctx, ValueMetricsParams{Metric: "toughness"}); err == nil {
+		t.Fatal("expected an error for an unknown metric")
+	}
+}
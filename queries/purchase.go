@@ -0,0 +1,102 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"github.com/mtgjson/mtgjson-sdk-go/db"
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+// PurchaseQuery resolves MTGJSON purchase links, which are redirects through
+// mtgjson.com, into direct vendor URLs with affiliate codes appended.
+// Resolved redirects are remembered for the lifetime of the query.
+type PurchaseQuery struct {
+	cards      *CardQuery
+	cache      *db.CacheManager
+	affiliates map[string]url.Values
+
+	mu       sync.Mutex
+	resolved map[string]string
+}
+
+func NewPurchaseQuery(cards *CardQuery, cache *db.CacheManager, affiliates map[string]url.Values) *PurchaseQuery {
+	return &PurchaseQuery{
+		cards:      cards,
+		cache:      cache,
+		affiliates: affiliates,
+		resolved:   make(map[string]string),
+	}
+}
+
+// Links resolves every purchase URL of a card. Returns nil if the card does
+// not exist.
+func (q *PurchaseQuery) Links(ctx context.Context, uuid string) ([]models.PurchaseLink, error) {
+	card, err := q.cards.GetByUUID(ctx, uuid)
+	if err != nil || card == nil {
+		return nil, err
+	}
+	u := card.PurchaseUrlsData
+	entries := []struct {
+		vendor, kind string
+		link         *string
+	}{
+		{"cardKingdom", "cardKingdom", u.CardKingdom},
+		{"cardKingdom", "cardKingdomFoil", u.CardKingdomFoil},
+		{"cardKingdom", "cardKingdomEtched", u.CardKingdomEtched},
+		{"cardmarket", "cardmarket", u.Cardmarket},
+		{"tcgplayer", "tcgplayer", u.Tcgplayer},
+		{"tcgplayer", "tcgplayerEtched", u.TcgplayerEtched},
+	}
+	links := []models.PurchaseLink{}
+	for _, e := range entries {
+		if e.link == nil || *e.link == "" {
+			continue
+		}
+		resolved, err := q.Resolve(ctx, e.vendor, *e.link)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, models.PurchaseLink{
+			Vendor:      e.vendor,
+			Kind:        e.kind,
+			RedirectURL: *e.link,
+			URL:         resolved,
+		})
+	}
+	return links, nil
+}
+
+// Resolve follows a single MTGJSON purchase link, such as one from a sealed
+// product's purchaseUrls, and appends the affiliate codes configured for
+// vendor. Codes override parameters of the same name already on the URL.
+func (q *PurchaseQuery) Resolve(ctx context.Context, vendor, link string) (string, error) {
+	q.mu.Lock()
+	target, ok := q.resolved[link]
+	q.mu.Unlock()
+	if !ok {
+		var err error
+		if target, err = q.cache.ResolveRedirect(ctx, link); err != nil {
+			return "", err
+		}
+		q.mu.Lock()
+		q.resolved[link] = target
+		q.mu.Unlock()
+	}
+	codes := q.affiliates[vendor]
+	if len(codes) == 0 {
+		return target, nil
+	}
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return "", fmt.Errorf("mtgjson: parse %s URL %q: %w", vendor, target, err)
+	}
+	query := parsed.Query()
+	for key, values := range codes {
+		query[key] = values
+	}
+	parsed.RawQuery = query.Encode()
+	return parsed.String(), nil
+}
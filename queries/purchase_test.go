@@ -0,0 +1,104 @@
+package queries
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mtgjson/mtgjson-sdk-go/db"
+)
+
+func setupPurchaseQuery(t *testing.T, affiliates map[string]url.Values) (*PurchaseQuery, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		switch r.URL.Path {
+		case "/links/tcg":
+			http.Redirect(w, r, "https://www.tcgplayer.com/product/1234?page=1", http.StatusFound)
+		case "/links/ck":
+			http.Redirect(w, r, "https://www.cardkingdom.com/mtg/masters-25/lightning-bolt", http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	conn := setupSampleDB(t)
+	bolt := maps.Clone(sampleCards[0])
+	bolt["uuid"] = "card-uuid-links"
+	bolt["purchaseUrls"] = map[string]any{
+		"tcgplayer":   srv.URL + "/links/tcg",
+		"cardKingdom": srv.URL + "/links/ck",
+	}
+	if err := conn.RegisterTableFromData(context.Background(), "cards", append([]map[string]any{bolt}, sampleCards...)); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := db.DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cache, err := db.NewCacheManager(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(cache.Close)
+	return NewPurchaseQuery(NewCardQuery(conn), cache, affiliates), &hits
+}
+
+func TestPurchaseLinks(t *testing.T) {
+	pq, hits := setupPurchaseQuery(t, map[string]url.Values{
+		"tcgplayer": {"partner": {"ACME"}, "page": {"2"}},
+	})
+	ctx := context.Background()
+
+	links, err := pq.Links(ctx, "card-uuid-links")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(links) != 2 {
+		t.Fatalf("expected 2 links, got %+v", links)
+	}
+	byKind := map[string]string{}
+	for _, l := range links {
+		byKind[l.Kind] = l.URL
+	}
+	if got := byKind["tcgplayer"]; got != "https://www.tcgplayer.com/product/1234?page=2&partner=ACME" {
+		t.Errorf("unexpected tcgplayer URL %q", got)
+	}
+	if got := byKind["cardKingdom"]; got != "https://www.cardkingdom.com/mtg/masters-25/lightning-bolt" {
+		t.Errorf("expected the Card Kingdom URL unchanged, got %q", got)
+	}
+
+	if _, err := pq.Links(ctx, "card-uuid-links"); err != nil {
+		t.Fatal(err)
+	}
+	if n := hits.Load(); n != 2 {
+		t.Errorf("expected resolved redirects to be reused, got %d requests", n)
+	}
+}
+
+func TestPurchaseLinksNoCard(t *testing.T) {
+	pq, _ := setupPurchaseQuery(t, nil)
+	links, err := pq.Links(context.Background(), "nonexistent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if links != nil {
+		t.Errorf("expected nil for a missing card, got %+v", links)
+	}
+}
+
+func TestPurchaseResolveNotRedirect(t *testing.T) {
+	pq, _ := setupPurchaseQuery(t, nil)
+	links, err := pq.Links(context.Background(), "card-uuid-001")
+	if err != nil || len(links) != 0 {
+		t.Fatalf("expected no links for a card without purchase URLs, got %+v (%v)", links, err)
+	}
+	if _, err := pq.Resolve(context.Background(), "tcgplayer", "http://127.0.0.1:1/links/x"); err == nil {
+		t.Error("expected an error for an unreachable link")
+	}
+}
@@ -3,17 +3,20 @@ package queries
 import (
 	"context"
 	"encoding/json"
+	"sort"
+	"strings"
 
 	"github.com/mtgjson/mtgjson-sdk-go/db"
+	"github.com/mtgjson/mtgjson-sdk-go/models"
 )
 
 // SealedQuery provides methods to query sealed product data (booster boxes, bundles, etc.).
 // Sealed product data lives inside the sets table as nested structs.
 type SealedQuery struct {
-	conn *db.Connection
+	conn db.Backend
 }
 
-func NewSealedQuery(conn *db.Connection) *SealedQuery {
+func NewSealedQuery(conn db.Backend) *SealedQuery {
 	return &SealedQuery{conn: conn}
 }
 
@@ -22,12 +25,78 @@ func (q *SealedQuery) ensure(ctx context.Context) error {
 }
 
 // ListSealedParams contains optional filters for listing sealed products.
+// Category is compared after NormalizeSealedCategory, so "Booster Box" and
+// SealedBoosterBox both match "booster_box".
 type ListSealedParams struct {
 	SetCode  string
 	Category string
+	Subtype  string // e.g. "draft", "collector" or "commander" within Category
 	Limit    int
 }
 
+// SealedCategory is the normalized category of a sealed product.
+type SealedCategory string
+
+// Sealed product categories used by MTGJSON.
+const (
+	SealedBoosterPack    SealedCategory = "booster_pack"
+	SealedBoosterBox     SealedCategory = "booster_box"
+	SealedBoosterCase    SealedCategory = "booster_case"
+	SealedBundle         SealedCategory = "bundle"
+	SealedBundleCase     SealedCategory = "bundle_case"
+	SealedPreconDeck     SealedCategory = "deck"
+	SealedMultipleDecks  SealedCategory = "multiple_decks"
+	SealedDeckBox        SealedCategory = "deck_box"
+	SealedBoxSet         SealedCategory = "box_set"
+	SealedKit            SealedCategory = "kit"
+	SealedLimitedAidTool SealedCategory = "limited_aid_tool"
+	SealedLimitedAidCase SealedCategory = "limited_aid_case"
+	SealedDraftSet       SealedCategory = "draft_set"
+	SealedLandStation    SealedCategory = "land_station"
+	SealedTwoPlayerSet   SealedCategory = "two_player_starter_set"
+	SealedSubset         SealedCategory = "subset"
+	SealedCase           SealedCategory = "case"
+)
+
+var sealedCategories = map[SealedCategory]bool{
+	SealedBoosterPack: true, SealedBoosterBox: true, SealedBoosterCase: true,
+	SealedBundle: true, SealedBundleCase: true, SealedPreconDeck: true,
+	SealedMultipleDecks: true, SealedDeckBox: true, SealedBoxSet: true,
+	SealedKit: true, SealedLimitedAidTool: true, SealedLimitedAidCase: true,
+	SealedDraftSet: true, SealedLandStation: true, SealedTwoPlayerSet: true,
+	SealedSubset: true, SealedCase: true,
+}
+
+// sealedCategoryAliases maps other spellings to MTGJSON's categories.
+var sealedCategoryAliases = map[string]SealedCategory{
+	"booster":             SealedBoosterPack,
+	"pack":                SealedBoosterPack,
+	"box":                 SealedBoosterBox,
+	"fat_pack":            SealedBundle,
+	"precon":              SealedPreconDeck,
+	"precon_deck":         SealedPreconDeck,
+	"preconstructed_deck": SealedPreconDeck,
+	"limited":             SealedLimitedAidTool,
+	"two_player_starter":  SealedTwoPlayerSet,
+}
+
+// NormalizeSealedCategory maps a category string, in any case and with
+// spaces or hyphens for underscores, to its SealedCategory. Unrecognized
+// categories are returned normalized but otherwise unchanged; see Known.
+func NormalizeSealedCategory(category string) SealedCategory {
+	norm := strings.ToLower(strings.TrimSpace(category))
+	norm = strings.NewReplacer(" ", "_", "-", "_").Replace(norm)
+	if c, ok := sealedCategoryAliases[norm]; ok {
+		return c
+	}
+	return SealedCategory(norm)
+}
+
+// Known reports whether c is one of the Sealed* categories.
+func (c SealedCategory) Known() bool {
+	return sealedCategories[c]
+}
+
 // List returns sealed products from set data.
 // Note: Requires the sealedProduct column (present in AllPrintings or test data,
 // but NOT in the flat sets.parquet from CDN).
@@ -60,7 +129,12 @@ func (q *SealedQuery) List(ctx context.Context, params ListSealedParams) ([]map[
 		sealed := extractSealedProducts(row["sealedProduct"])
 		for _, sp := range sealed {
 			if params.Category != "" {
-				if cat, _ := sp["category"].(string); cat != params.Category {
+				if cat, _ := sp["category"].(string); NormalizeSealedCategory(cat) != NormalizeSealedCategory(params.Category) {
+					continue
+				}
+			}
+			if params.Subtype != "" {
+				if sub, _ := sp["subtype"].(string); !strings.EqualFold(sub, params.Subtype) {
 					continue
 				}
 			}
@@ -71,6 +145,46 @@ func (q *SealedQuery) List(ctx context.Context, params ListSealedParams) ([]map[
 	return products, nil
 }
 
+// Categories counts sealed products by normalized category and, within
+// each, by subtype ("" for none), most products first.
+// Note: like List, it needs the sealedProduct column and returns nil without it.
+func (q *SealedQuery) Categories(ctx context.Context) ([]models.SealedCategoryCount, error) {
+	if err := q.ensure(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := q.conn.Execute(ctx, "SELECT sealedProduct FROM sets WHERE sealedProduct IS NOT NULL")
+	if err != nil {
+		// sealedProduct column may not exist in flat sets.parquet
+		return nil, nil
+	}
+	byCategory := make(map[SealedCategory]*models.SealedCategoryCount)
+	for _, row := range rows {
+		for _, sp := range extractSealedProducts(row["sealedProduct"]) {
+			cat, _ := sp["category"].(string)
+			sub, _ := sp["subtype"].(string)
+			c := NormalizeSealedCategory(cat)
+			count, ok := byCategory[c]
+			if !ok {
+				count = &models.SealedCategoryCount{Category: string(c), Subtypes: make(map[string]int)}
+				byCategory[c] = count
+			}
+			count.Products++
+			count.Subtypes[strings.ToLower(sub)]++
+		}
+	}
+	counts := make([]models.SealedCategoryCount, 0, len(byCategory))
+	for _, c := range byCategory {
+		counts = append(counts, *c)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Products != counts[j].Products {
+			return counts[i].Products > counts[j].Products
+		}
+		return counts[i].Category < counts[j].Category
+	})
+	return counts, nil
+}
+
 // Get returns a sealed product by UUID.
 func (q *SealedQuery) Get(ctx context.Context, uuid string) (map[string]any, error) {
 	if err := q.ensure(ctx); err != nil {
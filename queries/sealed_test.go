@@ -22,7 +22,7 @@ var sampleSetsWithSealed = []map[string]any{
 		"sealedProduct": []any{
 			map[string]any{
 				"uuid": "sealed-uuid-001", "name": "Masters 25 Booster Box",
-				"category": "booster_box", "subtype": nil,
+				"category": "booster_box", "subtype": "draft",
 				"purchaseUrls": map[string]any{}, "releaseDate": "2018-03-16",
 			},
 			map[string]any{
@@ -46,7 +46,7 @@ var sampleSetsWithSealed = []map[string]any{
 		"sealedProduct": []any{
 			map[string]any{
 				"uuid": "sealed-uuid-003", "name": "MH2 Set Booster Box",
-				"category": "booster_box", "subtype": nil,
+				"category": "booster_box", "subtype": "set",
 				"purchaseUrls": map[string]any{}, "releaseDate": "2021-06-18",
 			},
 		},
@@ -123,6 +123,62 @@ func TestSealedListByCategory(t *testing.T) {
 	}
 }
 
+func TestSealedListBySubtype(t *testing.T) {
+	conn := setupSealedDB(t)
+	sq := NewSealedQuery(conn)
+	ctx := context.Background()
+
+	products, err := sq.List(ctx, ListSealedParams{Category: "Booster Box", Subtype: "set"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(products) != 1 || products[0]["uuid"] != "sealed-uuid-003" {
+		t.Fatalf("expected the MH2 set booster box, got %v", products)
+	}
+}
+
+func TestSealedCategories(t *testing.T) {
+	conn := setupSealedDB(t)
+	sq := NewSealedQuery(conn)
+
+	cats, err := sq.Categories(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cats) != 2 {
+		t.Fatalf("expected 2 categories, got %+v", cats)
+	}
+	box := cats[0]
+	if box.Category != string(SealedBoosterBox) || box.Products != 2 || box.Subtypes["draft"] != 1 || box.Subtypes["set"] != 1 {
+		t.Errorf("expected 2 booster boxes (draft and set) first, got %+v", box)
+	}
+	if cats[1].Category != string(SealedBoosterPack) || cats[1].Subtypes[""] != 1 {
+		t.Errorf("expected 1 booster pack without a subtype, got %+v", cats[1])
+	}
+}
+
+func TestNormalizeSealedCategory(t *testing.T) {
+	tests := []struct {
+		in   string
+		want SealedCategory
+	}{
+		{"booster_box", SealedBoosterBox},
+		{"Booster Box", SealedBoosterBox},
+		{"bundle", SealedBundle},
+		{"Precon Deck", SealedPreconDeck},
+		{"two-player-starter-set", SealedTwoPlayerSet},
+		{"Mystery Thing", "mystery_thing"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeSealedCategory(tt.in); got != tt.want {
+			t.Errorf("NormalizeSealedCategory(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+	if !SealedBundle.Known() || SealedCategory("mystery_thing").Known() {
+		t.Error("Known disagrees with the category constants")
+	}
+}
+
 func TestSealedGet(t *testing.T) {
 	conn := setupSealedDB(t)
 	sq := NewSealedQuery(conn)
@@ -0,0 +1,172 @@
+package queries
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+// searchPlaceholder matches template placeholders such as "${format}" in the
+// string fields of a saved search. The "$" keeps mana symbols like "{T}" in
+// rules text from being taken for placeholders.
+var searchPlaceholder = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// SavedSearchQuery stores named card searches in the annotations database
+// (see TagQuery) so they can be listed and re-run. String fields may contain
+// placeholders like "${format}" that are filled in when the search is run.
+type SavedSearchQuery struct {
+	cards    *CardQuery
+	path     string
+	mu       sync.Mutex
+	attached bool
+}
+
+func NewSavedSearchQuery(cards *CardQuery, path string) *SavedSearchQuery {
+	return &SavedSearchQuery{cards: cards, path: path}
+}
+
+func (q *SavedSearchQuery) ensure(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.attached {
+		return nil
+	}
+	err := attachUserData(ctx, q.cards.conn, q.path,
+		"CREATE TABLE IF NOT EXISTS user_data.saved_searches ("+
+			"name VARCHAR PRIMARY KEY, params VARCHAR NOT NULL, "+
+			"updated_at TIMESTAMP NOT NULL DEFAULT current_timestamp)")
+	if err != nil {
+		return err
+	}
+	q.attached = true
+	return nil
+}
+
+// Save stores a search under name, replacing any search saved with that name.
+func (q *SavedSearchQuery) Save(ctx context.Context, name string, p SearchCardsParams) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("mtgjson: saved search needs a name")
+	}
+	if err := q.ensure(ctx); err != nil {
+		return err
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("mtgjson: encode saved search %q: %w", name, err)
+	}
+	err = q.cards.conn.Exec(ctx,
+		"INSERT INTO user_data.saved_searches (name, params) VALUES ($1, $2) "+
+			"ON CONFLICT (name) DO UPDATE SET params = excluded.params, updated_at = now()",
+		name, string(data))
+	if err != nil {
+		return fmt.Errorf("mtgjson: save search %q: %w", name, err)
+	}
+	return nil
+}
+
+// Get returns the saved search with placeholders left in place, or nil if
+// there is no search with that name.
+func (q *SavedSearchQuery) Get(ctx context.Context, name string) (*SearchCardsParams, error) {
+	raw, err := q.load(ctx, name)
+	if err != nil || raw == "" {
+		return nil, err
+	}
+	var p SearchCardsParams
+	if err := json.Unmarshal([]byte(raw), &p); err != nil {
+		return nil, fmt.Errorf("mtgjson: decode saved search %q: %w", name, err)
+	}
+	return &p, nil
+}
+
+// List returns the names of all saved searches, sorted.
+func (q *SavedSearchQuery) List(ctx context.Context) ([]string, error) {
+	if err := q.ensure(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := q.cards.conn.Execute(ctx, "SELECT name FROM user_data.saved_searches ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(rows))
+	for _, r := range rows {
+		name, _ := r["name"].(string)
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// Delete removes a saved search. Deleting a missing search is not an error.
+func (q *SavedSearchQuery) Delete(ctx context.Context, name string) error {
+	if err := q.ensure(ctx); err != nil {
+		return err
+	}
+	err := q.cards.conn.Exec(ctx,
+		"DELETE FROM user_data.saved_searches WHERE name = $1", strings.TrimSpace(name))
+	if err != nil {
+		return fmt.Errorf("mtgjson: delete saved search %q: %w", name, err)
+	}
+	return nil
+}
+
+// Run fills the saved search's placeholders from vars (e.g. {"format":
+// "modern"} for "${format}") and runs it with Cards().Search. It is an error
+// if the search does not exist or a placeholder has no value.
+func (q *SavedSearchQuery) Run(ctx context.Context, name string, vars map[string]string) ([]models.CardSet, error) {
+	raw, err := q.load(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if raw == "" {
+		return nil, fmt.Errorf("mtgjson: no saved search %q", name)
+	}
+	p, err := fillSearchTemplate(raw, vars)
+	if err != nil {
+		return nil, fmt.Errorf("mtgjson: saved search %q: %w", name, err)
+	}
+	return q.cards.Search(ctx, *p)
+}
+
+// load returns a saved search's JSON, or "" if it does not exist.
+func (q *SavedSearchQuery) load(ctx context.Context, name string) (string, error) {
+	if err := q.ensure(ctx); err != nil {
+		return "", err
+	}
+	v, err := q.cards.conn.ExecuteScalar(ctx,
+		"SELECT params FROM user_data.saved_searches WHERE name = $1", strings.TrimSpace(name))
+	if err != nil {
+		return "", fmt.Errorf("mtgjson: load saved search %q: %w", name, err)
+	}
+	raw, _ := v.(string)
+	return raw, nil
+}
+
+// fillSearchTemplate substitutes placeholders in the string values of a
+// serialized SearchCardsParams and decodes the result.
+func fillSearchTemplate(raw string, vars map[string]string) (*SearchCardsParams, error) {
+	var missing []string
+	filled := searchPlaceholder.ReplaceAllStringFunc(raw, func(m string) string {
+		key := m[2 : len(m)-1]
+		val, ok := vars[key]
+		if !ok {
+			missing = append(missing, m)
+			return m
+		}
+		// Escape the value as it sits inside a JSON string.
+		enc, _ := json.Marshal(val)
+		return string(enc[1 : len(enc)-1])
+	})
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("no value for %s", strings.Join(missing, ", "))
+	}
+	var p SearchCardsParams
+	if err := json.Unmarshal([]byte(filled), &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
@@ -0,0 +1,110 @@
+package queries
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestSavedSearchesSaveListRun(t *testing.T) {
+	conn := setupSampleDB(t)
+	q := NewSavedSearchQuery(NewCardQuery(conn), filepath.Join(t.TempDir(), "annotations.duckdb"))
+	ctx := context.Background()
+
+	if err := q.Save(ctx, "red in set", SearchCardsParams{Colors: []string{"R"}, SetCode: "${set}"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Save(ctx, "all", SearchCardsParams{Limit: 10}); err != nil {
+		t.Fatal(err)
+	}
+	names, err := q.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != "all" || names[1] != "red in set" {
+		t.Fatalf("unexpected saved searches: %v", names)
+	}
+
+	p, err := q.Get(ctx, "red in set")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p == nil || p.SetCode != "${set}" || len(p.Colors) != 1 {
+		t.Fatalf("expected the stored template, got %+v", p)
+	}
+	cards, err := q.Run(ctx, "red in set", map[string]string{"set": "A25"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 2 {
+		t.Fatalf("expected the 2 red A25 cards, got %d", len(cards))
+	}
+	cards, err = q.Run(ctx, "red in set", map[string]string{"set": "MH2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 0 {
+		t.Fatalf("expected no red MH2 cards, got %d", len(cards))
+	}
+	if _, err := q.Run(ctx, "red in set", nil); err == nil {
+		t.Fatal("expected an error for an unfilled placeholder")
+	}
+	if _, err := q.Run(ctx, "missing", nil); err == nil {
+		t.Fatal("expected an error for an unknown search")
+	}
+}
+
+func TestSavedSearchesReplaceAndDelete(t *testing.T) {
+	conn := setupSampleDB(t)
+	q := NewSavedSearchQuery(NewCardQuery(conn), filepath.Join(t.TempDir(), "annotations.duckdb"))
+	ctx := context.Background()
+
+	if err := q.Save(ctx, "s", SearchCardsParams{Rarity: "common"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Save(ctx, "s", SearchCardsParams{Rarity: "uncommon"}); err != nil {
+		t.Fatal(err)
+	}
+	p, err := q.Get(ctx, "s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Rarity != "uncommon" {
+		t.Fatalf("expected the search to be replaced, got %+v", p)
+	}
+	if err := q.Delete(ctx, "s"); err != nil {
+		t.Fatal(err)
+	}
+	if p, err := q.Get(ctx, "s"); err != nil || p != nil {
+		t.Fatalf("expected no search after delete, got %+v, %v", p, err)
+	}
+}
+
+func TestFillSearchTemplateEscapes(t *testing.T) {
+	p, err := fillSearchTemplate(`{"Name":"${name}","TextRegex":"\\d{2}"}`, map[string]string{"name": `Say "Hi"`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != `Say "Hi"` || p.TextRegex != `\d{2}` {
+		t.Fatalf("unexpected params: %+v", p)
+	}
+}
+
+func TestRunSearchWithManaSymbols(t *testing.T) {
+	conn := setupSampleDB(t)
+	ctx := context.Background()
+	q := NewSavedSearchQuery(NewCardQuery(conn), filepath.Join(t.TempDir(), "annotations.duckdb"))
+	if err := q.Save(ctx, "mana", SearchCardsParams{Text: "{T}: Add {G}", SetCode: "${set}"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.Run(ctx, "mana", map[string]string{"set": "A25"}); err != nil {
+		t.Fatalf("expected mana symbols to be left alone, got %v", err)
+	}
+	p, err := q.Get(ctx, "mana")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Text != "{T}: Add {G}" {
+		t.Fatalf("unexpected text %q", p.Text)
+	}
+}
@@ -0,0 +1,48 @@
+package queries
+
+import (
+	"fmt"
+
+	"github.com/mtgjson/mtgjson-sdk-go/db"
+)
+
+// memorabiliaSetTypes are the set types SetExclusions.Memorabilia leaves
+// out: collector memorabilia such as World Championship decks, Un-sets and
+// other funny sets, and token sets.
+var memorabiliaSetTypes = []string{"memorabilia", "funny", "token"}
+
+// SetExclusions are default filters that keep sets most tools don't want
+// out of set listings and card searches. A query opts back in with
+// IncludeAll, and asking for a set code or set type explicitly bypasses
+// them.
+type SetExclusions struct {
+	OnlineOnly  bool // sets and cards only released on Arena or MTGO
+	Memorabilia bool // memorabilia, funny and token set types
+}
+
+// any reports whether e excludes anything.
+func (e SetExclusions) any() bool {
+	return e.OnlineOnly || e.Memorabilia
+}
+
+// applyToSets adds e's filters to a query over the sets view.
+func (e SetExclusions) applyToSets(b *db.SQLBuilder, setType string) {
+	if e.OnlineOnly {
+		b.AddWhere("isOnlineOnly IS NOT TRUE")
+	}
+	if e.Memorabilia && setType == "" {
+		b.AddWhere(fmt.Sprintf("type NOT IN (%s)", inParams(b, memorabiliaSetTypes)))
+	}
+}
+
+// applyToCards adds e's filters to a query over the cards view; the
+// memorabilia filter reads the sets view.
+func (e SetExclusions) applyToCards(b *db.SQLBuilder, setType string) {
+	if e.OnlineOnly {
+		b.AddWhere("cards.isOnlineOnly IS NOT TRUE")
+	}
+	if e.Memorabilia && setType == "" {
+		b.AddWhere(fmt.Sprintf("cards.setCode NOT IN (SELECT code FROM sets WHERE type IN (%s))",
+			inParams(b, memorabiliaSetTypes)))
+	}
+}
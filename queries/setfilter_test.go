@@ -0,0 +1,121 @@
+package queries
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	"github.com/mtgjson/mtgjson-sdk-go/db"
+	"github.com/mtgjson/mtgjson-sdk-go/testsupport/sample"
+)
+
+// setupExcludableDB adds a funny set and an online-only set, with one card
+// each, to the sample data.
+func setupExcludableDB(t *testing.T) *db.Connection {
+	t.Helper()
+	conn := setupSampleDB(t)
+	ctx := context.Background()
+
+	sets := sample.Sets()
+	for _, extra := range []struct{ code, name, typ string }{
+		{"UST", "Unstable", "funny"},
+		{"Y22", "Alchemy: Innistrad", "alchemy"},
+	} {
+		set := maps.Clone(sets[0])
+		set["code"], set["name"], set["type"] = extra.code, extra.name, extra.typ
+		set["isOnlineOnly"] = extra.code == "Y22"
+		sets = append(sets, set)
+	}
+	cards := sample.Cards()
+	for _, extra := range []struct{ uuid, name, set string }{
+		{"card-uuid-ust", "Squirrel Dealer", "UST"},
+		{"card-uuid-y22", "Alchemy Bolt", "Y22"},
+	} {
+		card := maps.Clone(cards[0])
+		card["uuid"], card["name"], card["setCode"] = extra.uuid, extra.name, extra.set
+		card["isOnlineOnly"] = extra.set == "Y22"
+		cards = append(cards, card)
+	}
+	if err := conn.RegisterTableFromData(ctx, "sets", sets); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.RegisterTableFromData(ctx, "cards", cards); err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+func TestSetListExclusions(t *testing.T) {
+	conn := setupExcludableDB(t)
+	ctx := context.Background()
+	all := SetExclusions{OnlineOnly: true, Memorabilia: true}
+	tests := []struct {
+		name string
+		q    *SetQuery
+		p    ListSetsParams
+		want int
+	}{
+		{"no exclusions", NewSetQuery(conn), ListSetsParams{}, 4},
+		{"online only", NewSetQuery(conn, WithSetExclusions(SetExclusions{OnlineOnly: true})), ListSetsParams{}, 3},
+		{"memorabilia", NewSetQuery(conn, WithSetExclusions(SetExclusions{Memorabilia: true})), ListSetsParams{}, 3},
+		{"both", NewSetQuery(conn, WithSetExclusions(all)), ListSetsParams{}, 2},
+		{"include all", NewSetQuery(conn, WithSetExclusions(all)), ListSetsParams{IncludeAll: true}, 4},
+		{"explicit type", NewSetQuery(conn, WithSetExclusions(all)), ListSetsParams{SetType: "funny"}, 1},
+	}
+	for _, tt := range tests {
+		sets, err := tt.q.List(ctx, tt.p)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(sets) != tt.want {
+			t.Errorf("%s: expected %d sets, got %d", tt.name, tt.want, len(sets))
+		}
+	}
+
+	sets, err := NewSetQuery(conn, WithSetExclusions(all)).Search(ctx, SearchSetsParams{Name: "n"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range sets {
+		if s.Code == "UST" || s.Code == "Y22" {
+			t.Errorf("expected Search to exclude %s", s.Code)
+		}
+	}
+}
+
+func TestCardSearchSetExclusions(t *testing.T) {
+	conn := setupExcludableDB(t)
+	ctx := context.Background()
+	q := NewCardQuery(conn, WithCardSetExclusions(SetExclusions{OnlineOnly: true, Memorabilia: true}))
+
+	cards, err := q.Search(ctx, SearchCardsParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range cards {
+		if c.SetCode == "UST" || c.SetCode == "Y22" {
+			t.Errorf("expected %s from %s to be excluded", c.Name, c.SetCode)
+		}
+	}
+	n, err := q.CountSearch(ctx, SearchCardsParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(cards) || n != len(sampleCards) {
+		t.Errorf("expected CountSearch to match Search (%d), got %d", len(cards), n)
+	}
+
+	for _, p := range []SearchCardsParams{{IncludeAll: true}, {SetCode: "UST"}, {SetType: "funny"}} {
+		cards, err := q.Search(ctx, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found := false
+		for _, c := range cards {
+			found = found || c.SetCode == "UST"
+		}
+		if !found {
+			t.Errorf("%+v: expected the funny set's card to be included", p)
+		}
+	}
+}
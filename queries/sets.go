@@ -3,18 +3,21 @@ package queries
 import (
 	"context"
 	"fmt"
+	"math"
 	"strings"
 
+	"github.com/mtgjson/mtgjson-sdk-go/booster"
 	"github.com/mtgjson/mtgjson-sdk-go/db"
 	"github.com/mtgjson/mtgjson-sdk-go/models"
 )
 
 // ListSetsParams contains filters for listing sets.
 type ListSetsParams struct {
-	SetType string
-	Name    string
-	Limit   int // 0 means default (1000)
-	Offset  int
+	SetType    string
+	Name       string
+	Limit      int // 0 means default (1000)
+	Offset     int
+	IncludeAll bool // ignore the query's SetExclusions
 }
 
 // SearchSetsParams contains filters for searching sets.
@@ -23,16 +26,38 @@ type SearchSetsParams struct {
 	SetType     string
 	Block       string
 	ReleaseYear *int
-	Limit       int // 0 means default (100)
+	Limit       int  // 0 means default (100)
+	IncludeAll  bool // ignore the query's SetExclusions
 }
 
 // SetQuery provides methods to search and retrieve set metadata.
 type SetQuery struct {
-	conn *db.Connection
+	conn        db.Backend
+	excludeSets SetExclusions
+	cards       *CardQuery // for keywords; see WithSetCards
 }
 
-func NewSetQuery(conn *db.Connection) *SetQuery {
-	return &SetQuery{conn: conn}
+// SetQueryOption configures a SetQuery.
+type SetQueryOption func(*SetQuery)
+
+// WithSetExclusions makes List and Search leave out the sets e excludes
+// unless their params set IncludeAll.
+func WithSetExclusions(e SetExclusions) SetQueryOption {
+	return func(q *SetQuery) { q.excludeSets = e }
+}
+
+// WithSetCards sets the card module whose Keywords.json fills in missing
+// keywords on the cards CardsInRange and Subsets return.
+func WithSetCards(cards *CardQuery) SetQueryOption {
+	return func(q *SetQuery) { q.cards = cards }
+}
+
+func NewSetQuery(conn db.Backend, opts ...SetQueryOption) *SetQuery {
+	q := &SetQuery{conn: conn}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
 }
 
 // Get returns a set by its code (case-insensitive), or nil if not found.
@@ -50,6 +75,148 @@ func (q *SetQuery) Get(ctx context.Context, code string) (*models.SetList, error
 	return &sets[0], nil
 }
 
+// Translations returns a set's name keyed by language (e.g. "French"), or nil
+// if the set has no translations.
+func (q *SetQuery) Translations(ctx context.Context, code string) (models.Translations, error) {
+	if err := q.conn.EnsureViews(ctx, "set_translations"); err != nil {
+		return nil, err
+	}
+	rows, err := q.conn.Execute(ctx,
+		"SELECT language, translation FROM set_translations WHERE setCode = $1 ORDER BY language",
+		strings.ToUpper(code))
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	translations := make(models.Translations, len(rows))
+	for _, r := range rows {
+		lang, _ := r["language"].(string)
+		if name, ok := r["translation"].(string); ok {
+			translations[lang] = &name
+		} else {
+			translations[lang] = nil
+		}
+	}
+	return translations, nil
+}
+
+// scryfallSetIconURL is the Scryfall SVG set symbol for a lowercase code.
+const scryfallSetIconURL = "https://svgs.scryfall.io/sets/%s.svg"
+
+// Assets returns a set's header metadata: name, dates and size, icon URLs
+// and translated names. MTGJSON publishes no set images, so the icon is
+// Scryfall's symbol for the keyruneCode. Returns nil if the set is not found.
+func (q *SetQuery) Assets(ctx context.Context, code string) (*models.SetAssets, error) {
+	set, err := q.Get(ctx, code)
+	if err != nil || set == nil {
+		return nil, err
+	}
+	translations, err := q.Translations(ctx, set.Code)
+	if err != nil {
+		return nil, err
+	}
+	keyrune := set.KeyruneCode
+	if keyrune == "" {
+		keyrune = set.Code
+	}
+	icon := strings.ToLower(keyrune)
+	return &models.SetAssets{
+		Code:         set.Code,
+		Name:         set.Name,
+		Type:         set.Type,
+		ReleaseDate:  set.ReleaseDate,
+		TotalSetSize: set.TotalSetSize,
+		Block:        set.Block,
+		ParentCode:   set.ParentCode,
+		KeyruneCode:  keyrune,
+		KeyruneClass: "ss ss-" + icon,
+		IconURL:      fmt.Sprintf(scryfallSetIconURL, icon),
+		Translations: translations,
+	}, nil
+}
+
+// CardsInRange returns a set's cards whose collector numbers lie between
+// from and to inclusive, ordered by number, e.g. "1" to "281" for the main
+// set or "282" to "" for the extended-art tail. Numbers compare
+// numeric-aware ("9" < "10" < "10a"); an empty bound leaves that end open.
+func (q *SetQuery) CardsInRange(ctx context.Context, code, from, to string) ([]models.CardSet, error) {
+	if err := q.conn.EnsureViews(ctx, "cards"); err != nil {
+		return nil, err
+	}
+	b := db.NewSQLBuilder("cards").WhereEq("setCode", strings.ToUpper(code))
+	if from != "" {
+		whereNumber(b, ">", from)
+	}
+	if to != "" {
+		whereNumber(b, "<", to)
+	}
+	b.OrderBy(numberSQL+" NULLS LAST", numberSuffixSQL, "number", "uuid")
+	sql, params := b.Build()
+	var cards []models.CardSet
+	if err := q.conn.ExecuteInto(ctx, &cards, sql, params...); err != nil {
+		return nil, err
+	}
+	q.cards.normalizeKeywords(ctx, cards)
+	return cards, nil
+}
+
+// Subsets lists the subsets of a set, such as bonus sheets like "Multiverse
+// Legends", in order of their first collector number, each with its cards
+// ordered by number. A card in several subsets appears in each. Returns nil
+// if the set has none.
+func (q *SetQuery) Subsets(ctx context.Context, code string) ([]models.SetSubset, error) {
+	if err := q.conn.EnsureViews(ctx, "cards"); err != nil {
+		return nil, err
+	}
+	b := db.NewSQLBuilder("cards").WhereEq("setCode", strings.ToUpper(code))
+	b.AddWhere("len(subsets) > 0")
+	b.OrderBy(numberSQL+" NULLS LAST", numberSuffixSQL, "number", "uuid")
+	sql, params := b.Build()
+	var cards []models.CardSet
+	if err := q.conn.ExecuteInto(ctx, &cards, sql, params...); err != nil {
+		return nil, err
+	}
+	q.cards.normalizeKeywords(ctx, cards)
+	var subsets []models.SetSubset
+	index := map[string]int{}
+	for _, card := range cards {
+		for _, name := range card.Subsets {
+			i, ok := index[name]
+			if !ok {
+				i = len(subsets)
+				index[name] = i
+				subsets = append(subsets, models.SetSubset{Name: name})
+			}
+			subsets[i].Cards = append(subsets[i].Cards, card)
+		}
+	}
+	return subsets, nil
+}
+
+// GetByLocalizedName returns the set whose English name or any translated
+// name matches name (case-insensitive), or nil if not found. If several sets
+// match, the most recently released one is returned.
+func (q *SetQuery) GetByLocalizedName(ctx context.Context, name string) (*models.SetList, error) {
+	if err := q.conn.EnsureViews(ctx, "sets", "set_translations"); err != nil {
+		return nil, err
+	}
+	var sets []models.SetList
+	err := q.conn.ExecuteInto(ctx, &sets,
+		"SELECT * FROM sets WHERE lower(name) = lower($1) "+
+			"OR code IN (SELECT setCode FROM set_translations WHERE lower(translation) = lower($1)) "+
+			"ORDER BY releaseDate DESC LIMIT 1",
+		name)
+	if err != nil {
+		return nil, err
+	}
+	if len(sets) == 0 {
+		return nil, nil
+	}
+	return &sets[0], nil
+}
+
 // List returns sets with optional filters, ordered by release date descending.
 func (q *SetQuery) List(ctx context.Context, p ListSetsParams) ([]models.SetList, error) {
 	if err := q.conn.EnsureViews(ctx, "sets"); err != nil {
@@ -59,6 +226,9 @@ func (q *SetQuery) List(ctx context.Context, p ListSetsParams) ([]models.SetList
 	if p.SetType != "" {
 		b.WhereEq("type", p.SetType)
 	}
+	if !p.IncludeAll {
+		q.excludeSets.applyToSets(b, p.SetType)
+	}
 	if p.Name != "" {
 		if containsWildcard(p.Name) {
 			b.WhereLike("name", p.Name)
@@ -92,6 +262,9 @@ func (q *SetQuery) Search(ctx context.Context, p SearchSetsParams) ([]models.Set
 	if p.SetType != "" {
 		b.WhereEq("type", p.SetType)
 	}
+	if !p.IncludeAll {
+		q.excludeSets.applyToSets(b, p.SetType)
+	}
 	if p.Block != "" {
 		b.WhereLike("block", "%"+p.Block+"%")
 	}
@@ -113,19 +286,39 @@ func (q *SetQuery) Search(ctx context.Context, p SearchSetsParams) ([]models.Set
 	return sets, nil
 }
 
-// GetFinancialSummary returns aggregate price statistics for a set.
-// Requires price data to be loaded (via PriceQuery). Returns nil if unavailable.
+// GetFinancialSummary returns aggregate price statistics for a set, with a
+// per-rarity breakdown. It uses each printing's latest price from today's
+// prices (the latest_prices table) when available and falls back to the
+// latest date of an already-loaded price history (all_prices). Returns nil
+// if no price data is available.
 func (q *SetQuery) GetFinancialSummary(ctx context.Context, setCode string, opts ...FinancialSummaryOption) (*models.FinancialSummary, error) {
 	if err := q.conn.EnsureViews(ctx, "cards"); err != nil {
 		return nil, err
 	}
-	if err := q.conn.EnsureViews(ctx, "all_prices_today"); err != nil {
+	table, err := q.priceTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if table == "" {
 		return nil, nil
 	}
 	cfg := financialSummaryDefaults()
 	for _, opt := range opts {
 		opt(&cfg)
 	}
+	params := []any{strings.ToUpper(setCode), cfg.provider, cfg.currency, cfg.finish, cfg.priceType}
+	latest := ""
+	if table == "all_prices" {
+		latest = "\n\t  AND p.date = (SELECT MAX(p2.date) FROM all_prices p2)"
+	}
+	from := fmt.Sprintf(`FROM cards c
+	JOIN %s p ON c.uuid = p.uuid
+	WHERE c.setCode = $1
+	  AND p.provider = $2
+	  AND p.currency = $3
+	  AND p.finish = $4
+	  AND p.price_type = $5%s`, table, latest)
+
 	sql := `SELECT
 		COUNT(DISTINCT c.uuid) AS card_count,
 		ROUND(SUM(p.price), 2) AS total_value,
@@ -133,27 +326,114 @@ func (q *SetQuery) GetFinancialSummary(ctx context.Context, setCode string, opts
 		MIN(p.price) AS min_value,
 		MAX(p.price) AS max_value,
 		MAX(p.date) AS date
-	FROM cards c
-	JOIN all_prices_today p ON c.uuid = p.uuid
-	WHERE c.setCode = $1
-	  AND p.provider = $2
-	  AND p.currency = $3
-	  AND p.finish = $4
-	  AND p.price_type = $5
-	  AND p.date = (SELECT MAX(p2.date) FROM all_prices_today p2)`
+	` + from
 
 	var results []models.FinancialSummary
-	if err := q.conn.ExecuteInto(ctx, &results, sql,
-		strings.ToUpper(setCode), cfg.provider, cfg.currency, cfg.finish, cfg.priceType,
-	); err != nil {
+	if err := q.conn.ExecuteInto(ctx, &results, sql, params...); err != nil {
 		return nil, err
 	}
 	if len(results) == 0 || results[0].CardCount == 0 {
 		return nil, nil
 	}
+
+	raritySQL := `SELECT
+		c.rarity,
+		COUNT(DISTINCT c.uuid) AS card_count,
+		ROUND(SUM(p.price), 2) AS total_value,
+		ROUND(AVG(p.price), 2) AS avg_value,
+		MIN(p.price) AS min_value,
+		MAX(p.price) AS max_value
+	` + from + `
+	GROUP BY c.rarity
+	ORDER BY total_value DESC, c.rarity ASC`
+	if err := q.conn.ExecuteInto(ctx, &results[0].ByRarity, raritySQL, params...); err != nil {
+		return nil, err
+	}
 	return &results[0], nil
 }
 
+// BoxValue compares the expected value of opening a booster box with the
+// box's sealed market price. Pack EV comes from the booster simulator's sheet
+// weights and today's card prices; the box price comes from the set's
+// booster_box sealed product, preferring one whose subtype matches boosterType.
+// BoxPrice and EVRatio are nil when no priced sealed box is found. Packs per
+// box is taken from the product size, defaulting to 36.
+func (q *SetQuery) BoxValue(ctx context.Context, setCode, boosterType string, opts ...FinancialSummaryOption) (*models.BoxValue, error) {
+	cfg := financialSummaryDefaults()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	code := strings.ToUpper(setCode)
+	packEV, err := booster.NewBoosterSimulator(q.conn).ExpectedValue(ctx, code, boosterType, cfg.provider)
+	if err != nil {
+		return nil, err
+	}
+	result := &models.BoxValue{
+		SetCode:     code,
+		BoosterType: boosterType,
+		PackEV:      math.Round(packEV*100) / 100,
+		PacksPerBox: 36,
+	}
+
+	if err := q.conn.EnsureViews(ctx, "sealed_products"); err == nil {
+		rows, err := q.conn.Execute(ctx,
+			"SELECT uuid, name, productSize FROM sealed_products "+
+				"WHERE setCode = $1 AND category = 'booster_box' "+
+				"ORDER BY (CAST(subtype AS VARCHAR) = $2) DESC NULLS LAST, name ASC LIMIT 1",
+			code, boosterType)
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) > 0 {
+			uuid, _ := rows[0]["uuid"].(string)
+			name, _ := rows[0]["name"].(string)
+			result.SealedUUID = &uuid
+			result.SealedName = &name
+			if size := db.ToInt(rows[0]["productSize"]); size > 0 {
+				result.PacksPerBox = size
+			}
+			val, err := q.conn.ExecuteScalar(ctx,
+				"SELECT price FROM "+db.LatestPricesTable+" "+
+					"WHERE uuid = $1 AND provider = $2 AND currency = $3 AND price_type = $4 "+
+					"ORDER BY (finish = 'normal') DESC LIMIT 1",
+				uuid, cfg.provider, cfg.currency, cfg.priceType)
+			if err != nil {
+				return nil, err
+			}
+			if val != nil {
+				price := db.ToFloat64(val)
+				result.BoxPrice = &price
+			}
+		}
+	}
+
+	result.BoxEV = math.Round(packEV*float64(result.PacksPerBox)*100) / 100
+	if result.BoxPrice != nil && *result.BoxPrice > 0 {
+		ratio := math.Round(result.BoxEV / *result.BoxPrice * 1000) / 1000
+		result.EVRatio = &ratio
+	}
+	return result, nil
+}
+
+// priceTable returns the name of the price table to aggregate over,
+// preferring the latest of today's prices. Returns "" if no price data can
+// be loaded.
+func (q *SetQuery) priceTable(ctx context.Context) (string, error) {
+	if q.conn.HasView("all_prices_today") {
+		return db.LatestPricesTable, nil
+	}
+	if q.conn.HasView("all_prices") {
+		return "all_prices", nil
+	}
+	if err := q.conn.EnsureOptionalViews(ctx, "all_prices_today"); err != nil {
+		return "", err
+	}
+	if q.conn.HasView("all_prices_today") {
+		return db.LatestPricesTable, nil
+	}
+	return "", nil
+}
+
 // Count returns the total number of sets.
 func (q *SetQuery) Count(ctx context.Context) (int, error) {
 	if err := q.conn.EnsureViews(ctx, "sets"); err != nil {
@@ -2,8 +2,11 @@ package queries
 
 import (
 	"context"
+	"maps"
 	"strings"
 	"testing"
+
+	"github.com/mtgjson/mtgjson-sdk-go/db"
 )
 
 func TestSetGet(t *testing.T) {
@@ -212,3 +215,407 @@ func TestSetFinancialSummaryNoDataForSet(t *testing.T) {
 		t.Fatalf("expected nil, got %v", summary)
 	}
 }
+
+func TestSetFinancialSummaryByRarity(t *testing.T) {
+	conn := setupSampleDB(t)
+	ctx := context.Background()
+
+	if err := conn.RegisterTableFromData(ctx, "all_prices_today", samplePrices); err != nil {
+		t.Fatal(err)
+	}
+
+	q := NewSetQuery(conn)
+	summary, err := q.GetFinancialSummary(ctx, "A25")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary == nil {
+		t.Fatal("expected summary, got nil")
+	}
+	if len(summary.ByRarity) != 1 {
+		t.Fatalf("expected 1 rarity bucket, got %d", len(summary.ByRarity))
+	}
+	r := summary.ByRarity[0]
+	if r.Rarity != "uncommon" || r.CardCount != 2 || r.TotalValue != 5.00 {
+		t.Fatalf("unexpected rarity breakdown: %+v", r)
+	}
+}
+
+func TestSetFinancialSummaryFromHistory(t *testing.T) {
+	conn := setupSampleDB(t)
+	ctx := context.Background()
+
+	// Only the history table is loaded
+	if err := conn.RegisterTableFromData(ctx, "all_prices", samplePrices); err != nil {
+		t.Fatal(err)
+	}
+
+	q := NewSetQuery(conn)
+	summary, err := q.GetFinancialSummary(ctx, "A25")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary == nil {
+		t.Fatal("expected summary from all_prices, got nil")
+	}
+	if summary.TotalValue != 5.00 {
+		t.Fatalf("expected total_value=5.00, got %f", summary.TotalValue)
+	}
+}
+
+func TestSetBoxValue(t *testing.T) {
+	conn := setupSampleDB(t)
+	ctx := context.Background()
+
+	sets := []map[string]any{
+		{
+			"code": "A25", "name": "Masters 25", "type": "masters",
+			"booster": map[string]any{
+				"draft": map[string]any{
+					"boosters": []any{
+						map[string]any{"contents": map[string]any{"common": 2}, "weight": 1},
+					},
+					"sheets": map[string]any{
+						"common": map[string]any{
+							"cards":       map[string]any{"card-uuid-001": 1, "card-uuid-002": 1},
+							"foil":        false,
+							"totalWeight": 2,
+						},
+					},
+				},
+			},
+		},
+	}
+	if err := conn.RegisterTableFromData(ctx, "sets", sets); err != nil {
+		t.Fatal(err)
+	}
+	prices := append([]map[string]any{
+		{
+			"uuid": "sealed-uuid-001", "source": "paper", "provider": "tcgplayer",
+			"currency": "USD", "price_type": "retail", "finish": "normal",
+			"date": "2024-01-03", "price": 120.00,
+		},
+	}, samplePrices...)
+	if err := conn.RegisterTableFromData(ctx, "all_prices_today", prices); err != nil {
+		t.Fatal(err)
+	}
+
+	q := NewSetQuery(conn)
+	bv, err := q.BoxValue(ctx, "a25", "draft")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Pack EV = 2 * (2.00 + 5.00) / 2 = 7.00; box has 24 packs
+	if bv.PackEV != 7.00 {
+		t.Fatalf("expected pack EV 7.00, got %v", bv.PackEV)
+	}
+	if bv.PacksPerBox != 24 {
+		t.Fatalf("expected 24 packs, got %d", bv.PacksPerBox)
+	}
+	if bv.BoxEV != 168.00 {
+		t.Fatalf("expected box EV 168.00, got %v", bv.BoxEV)
+	}
+	if bv.SealedUUID == nil || *bv.SealedUUID != "sealed-uuid-001" {
+		t.Fatalf("expected sealed-uuid-001, got %v", bv.SealedUUID)
+	}
+	if bv.BoxPrice == nil || *bv.BoxPrice != 120.00 {
+		t.Fatalf("expected box price 120.00, got %v", bv.BoxPrice)
+	}
+	if bv.EVRatio == nil || *bv.EVRatio != 1.4 {
+		t.Fatalf("expected EV ratio 1.4, got %v", bv.EVRatio)
+	}
+}
+
+func TestSetBoxValueUnknownBoosterType(t *testing.T) {
+	conn := setupSampleDB(t)
+	q := NewSetQuery(conn)
+	if _, err := q.BoxValue(context.Background(), "A25", "draft"); err == nil {
+		t.Fatal("expected error for set without booster config")
+	}
+}
+
+func TestSetRarityBreakdown(t *testing.T) {
+	conn := setupSampleDB(t)
+	ctx := context.Background()
+	q := NewSetQuery(conn)
+
+	rb, err := q.RarityBreakdown(ctx, "a25")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rb == nil || rb.Cards != 2 || len(rb.Rarities) != 1 || rb.Rarities[0].Rarity != "uncommon" || rb.Rarities[0].Share != 1 {
+		t.Fatalf("unexpected breakdown: %+v", rb)
+	}
+	if rb.Sheets != nil {
+		t.Fatalf("expected no sheets without booster data, got %+v", rb.Sheets)
+	}
+
+	sets := []map[string]any{
+		{
+			"code": "A25", "name": "Masters 25", "type": "masters",
+			"booster": map[string]any{
+				"draft": map[string]any{
+					"boosters": []any{
+						map[string]any{"contents": map[string]any{"uncommon": 3}, "weight": 1},
+					},
+					"sheets": map[string]any{
+						"uncommon": map[string]any{
+							"cards":       map[string]any{"card-uuid-003": 1, "card-uuid-001": 2, "card-uuid-999": 1},
+							"foil":        false,
+							"totalWeight": 4,
+						},
+					},
+				},
+			},
+		},
+	}
+	if err := conn.RegisterTableFromData(ctx, "sets", sets); err != nil {
+		t.Fatal(err)
+	}
+	rb, err = q.RarityBreakdown(ctx, "A25")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rb.Sheets) != 1 {
+		t.Fatalf("expected 1 sheet, got %+v", rb.Sheets)
+	}
+	sheet := rb.Sheets[0]
+	if sheet.BoosterType != "draft" || sheet.Sheet != "uncommon" || sheet.TotalWeight != 4 {
+		t.Fatalf("unexpected sheet: %+v", sheet)
+	}
+	if len(sheet.Rarities) != 1 || sheet.Rarities[0] != "uncommon" {
+		t.Fatalf("unexpected sheet rarities: %v", sheet.Rarities)
+	}
+	// The unknown card sorts first (no set code), then by collector number.
+	var numbers []string
+	for _, slot := range sheet.Slots {
+		numbers = append(numbers, slot.Number)
+	}
+	if strings.Join(numbers, ",") != ",141,223a" {
+		t.Fatalf("unexpected slot numbers: %q", numbers)
+	}
+	if sheet.Slots[1].Weight != 2 || sheet.Slots[1].Name != "Lightning Bolt" {
+		t.Fatalf("unexpected slot: %+v", sheet.Slots[1])
+	}
+}
+
+func TestSetRarityBreakdownUnknownSet(t *testing.T) {
+	conn := setupSampleDB(t)
+	rb, err := NewSetQuery(conn).RarityBreakdown(context.Background(), "ZZZ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rb != nil {
+		t.Fatalf("expected nil for unknown set, got %+v", rb)
+	}
+}
+
+// setupNumberedDB replaces the cards with A25 printings numbered 9, 10,
+// 10a, 100 and S1.
+func setupNumberedDB(t *testing.T) *db.Connection {
+	t.Helper()
+	conn := setupSampleDB(t)
+	var cards []map[string]any
+	for _, number := range []string{"100", "10a", "S1", "9", "10"} {
+		card := maps.Clone(sampleCards[0])
+		card["uuid"], card["number"] = "card-"+number, number
+		cards = append(cards, card)
+	}
+	if err := conn.RegisterTableFromData(context.Background(), "cards", cards); err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+func TestSetCardsInRange(t *testing.T) {
+	q := NewSetQuery(setupNumberedDB(t))
+	ctx := context.Background()
+	for _, tc := range []struct{ from, to, want string }{
+		{"", "", "9,10,10a,100,S1"},
+		{"9", "10", "9,10"},
+		{"10", "99", "10,10a"},
+		{"10a", "", "10a,100"},
+		{"S1", "S9", "S1"},
+	} {
+		cards, err := q.CardsInRange(ctx, "a25", tc.from, tc.to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, c := range cards {
+			got = append(got, c.Number)
+		}
+		if strings.Join(got, ",") != tc.want {
+			t.Errorf("CardsInRange(%q, %q) = %v, want %s", tc.from, tc.to, got, tc.want)
+		}
+	}
+}
+
+func TestSetSubsets(t *testing.T) {
+	conn := setupSampleDB(t)
+	ctx := context.Background()
+	q := NewSetQuery(conn)
+
+	subsets, err := q.Subsets(ctx, "A25")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subsets != nil {
+		t.Fatalf("expected no subsets, got %+v", subsets)
+	}
+
+	var cards []map[string]any
+	for _, c := range []struct {
+		number  string
+		subsets []any
+	}{
+		{"300", []any{"Multiverse Legends"}},
+		{"20", []any{"Multiverse Legends", "Retro Frame"}},
+		{"5", nil},
+	} {
+		card := maps.Clone(sampleCards[0])
+		card["uuid"], card["number"], card["subsets"] = "card-"+c.number, c.number, c.subsets
+		cards = append(cards, card)
+	}
+	if err := conn.RegisterTableFromData(ctx, "cards", cards); err != nil {
+		t.Fatal(err)
+	}
+	subsets, err = q.Subsets(ctx, "a25")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subsets) != 2 || subsets[0].Name != "Multiverse Legends" || subsets[1].Name != "Retro Frame" {
+		t.Fatalf("unexpected subsets: %+v", subsets)
+	}
+	legends := subsets[0].Cards
+	if len(legends) != 2 || legends[0].Number != "20" || legends[1].Number != "300" {
+		t.Fatalf("unexpected Multiverse Legends cards: %+v", legends)
+	}
+
+	found, err := NewCardQuery(conn).Search(ctx, SearchCardsParams{Subset: "Retro Frame"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].UUID != "card-20" {
+		t.Fatalf("expected card-20 in Retro Frame, got %+v", found)
+	}
+}
+
+func TestSetTranslations(t *testing.T) {
+	conn := setupSampleDB(t)
+	q := NewSetQuery(conn)
+	ctx := context.Background()
+	tr, err := q.Translations(ctx, "mh2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tr) != 3 {
+		t.Fatalf("expected 3 translations, got %d", len(tr))
+	}
+	if name := tr["German"]; name == nil || *name != "Modern-Horizonte 2" {
+		t.Fatalf("expected German translation, got %v", name)
+	}
+	tr, err = q.Translations(ctx, "XXX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr != nil {
+		t.Fatalf("expected nil for unknown set, got %v", tr)
+	}
+}
+
+func TestSetGetByLocalizedName(t *testing.T) {
+	conn := setupSampleDB(t)
+	q := NewSetQuery(conn)
+	ctx := context.Background()
+	for name, code := range map[string]string{
+		"モダンホライゾン2":            "MH2",
+		"horizons du modern 2": "MH2",
+		"Masters 25":           "A25",
+		"Modern Horizons 2":    "MH2",
+	} {
+		s, err := q.GetByLocalizedName(ctx, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s == nil || s.Code != code {
+			t.Fatalf("%q: expected %s, got %v", name, code, s)
+		}
+	}
+	s, err := q.GetByLocalizedName(ctx, "Nonexistent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != nil {
+		t.Fatalf("expected nil, got %v", s)
+	}
+}
+
+func TestSetAssets(t *testing.T) {
+	conn := setupSampleDB(t)
+	sq := NewSetQuery(conn)
+	ctx := context.Background()
+
+	a, err := sq.Assets(ctx, "mh2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == nil {
+		t.Fatal("expected assets for MH2")
+	}
+	if a.Name != "Modern Horizons 2" || a.KeyruneClass != "ss ss-mh2" || a.IconURL != "https://svgs.scryfall.io/sets/mh2.svg" {
+		t.Errorf("unexpected assets: %+v", a)
+	}
+	if fr := a.Translations["French"]; fr == nil || *fr != "Horizons du Modern 2" {
+		t.Errorf("expected the French name, got %v", a.Translations)
+	}
+
+	missing, err := sq.Assets(ctx, "NOPE")
+	if err != nil || missing != nil {
+		t.Errorf("expected nil for an unknown set, got %+v (%v)", missing, err)
+	}
+}
+
+func TestSetCompletionCost(t *testing.T) {
+	conn := setupSampleDB(t)
+	ctx := context.Background()
+	q := NewSetQuery(conn)
+
+	if cost, err := q.CompletionCost(ctx, "A25"); err != nil || cost != nil {
+		t.Fatalf("expected nil without prices, got %+v (%v)", cost, err)
+	}
+
+	reprint := maps.Clone(sampleCards[0])
+	reprint["uuid"], reprint["setCode"], reprint["number"] = "card-uuid-004", "MH2", "999"
+	if err := conn.RegisterTableFromData(ctx, "cards", append(append([]map[string]any{}, sampleCards...), reprint)); err != nil {
+		t.Fatal(err)
+	}
+	cheap := maps.Clone(samplePrices[0])
+	cheap["uuid"], cheap["price"] = "card-uuid-004", 1.25
+	if err := conn.RegisterTableFromData(ctx, "all_prices_today", append(append([]map[string]any{}, samplePrices...), cheap)); err != nil {
+		t.Fatal(err)
+	}
+
+	cost, err := q.CompletionCost(ctx, "a25")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost == nil || cost.Names != 2 || cost.Priced != 2 || len(cost.Missing) != 0 {
+		t.Fatalf("expected two priced names, got %+v", cost)
+	}
+	if cost.InSetSingleton != 5.00 || cost.InSetPlayset != 20.00 {
+		t.Errorf("expected in-set 5.00/20.00, got %.2f/%.2f", cost.InSetSingleton, cost.InSetPlayset)
+	}
+	if cost.AnySetSingleton != 4.25 || cost.AnySetPlayset != 17.00 {
+		t.Errorf("expected any-set 4.25/17.00, got %.2f/%.2f", cost.AnySetSingleton, cost.AnySetPlayset)
+	}
+	bolt := cost.Cards[1]
+	if bolt.Name != "Lightning Bolt" || *bolt.InSetUUID != "card-uuid-001" ||
+		*bolt.CheapestUUID != "card-uuid-004" || *bolt.CheapestSetCode != "MH2" {
+		t.Errorf("expected Bolt's cheapest printing from MH2, got %+v", bolt)
+	}
+
+	if cost, err := q.CompletionCost(ctx, "XXXXX"); err != nil || cost != nil {
+		t.Fatalf("expected nil for an unknown set, got %+v (%v)", cost, err)
+	}
+}
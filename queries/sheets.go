@@ -0,0 +1,167 @@
+package queries
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mtgjson/mtgjson-sdk-go/booster"
+	"github.com/mtgjson/mtgjson-sdk-go/db"
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+// RarityBreakdown counts a set's cards by rarity, from common to bonus.
+// When the set has booster data, it also reconstructs each booster type's
+// card sheets (common, uncommon, rareMythic, foil, ...) with the collector
+// numbers on them, so the print runs behind a limited format can be
+// checked. Returns nil if the set has no cards.
+func (q *SetQuery) RarityBreakdown(ctx context.Context, code string) (*models.RarityBreakdown, error) {
+	if err := q.conn.EnsureViews(ctx, "cards"); err != nil {
+		return nil, err
+	}
+	code = strings.ToUpper(code)
+	var rarities []models.RarityCount
+	if err := q.conn.ExecuteInto(ctx, &rarities,
+		"SELECT rarity, COUNT(*) AS cards FROM cards WHERE setCode = $1 "+
+			"GROUP BY rarity ORDER BY rarity",
+		code); err != nil {
+		return nil, err
+	}
+	if len(rarities) == 0 {
+		return nil, nil
+	}
+	sort.SliceStable(rarities, func(i, j int) bool {
+		return rarityRank(rarities[i].Rarity) < rarityRank(rarities[j].Rarity)
+	})
+	result := &models.RarityBreakdown{SetCode: code, Rarities: rarities}
+	for _, r := range rarities {
+		result.Cards += r.Cards
+	}
+	for i := range result.Rarities {
+		result.Rarities[i].Share = float64(result.Rarities[i].Cards) / float64(result.Cards)
+	}
+
+	configs, err := booster.NewBoosterSimulator(q.conn).Configs(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	var uuids []string
+	for _, boosterType := range slices.Sorted(maps.Keys(configs)) {
+		config := configs[boosterType]
+		names := make([]string, 0, len(config.Sheets))
+		for name, sheet := range config.Sheets {
+			if len(sheet.Cards) > 0 {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			sheet := config.Sheets[name]
+			ps := models.PrintSheet{
+				BoosterType: boosterType,
+				Sheet:       name,
+				Foil:        sheet.Foil,
+				TotalWeight: sheet.TotalWeight,
+			}
+			for uuid, weight := range sheet.Cards {
+				ps.Slots = append(ps.Slots, models.SheetSlot{UUID: uuid, Weight: weight})
+				uuids = append(uuids, uuid)
+			}
+			result.Sheets = append(result.Sheets, ps)
+		}
+	}
+	if len(result.Sheets) == 0 {
+		return result, nil
+	}
+
+	b := db.NewSQLBuilder("cards").Select("uuid", "name", "number", "rarity", "setCode")
+	b.Where("uuid IN (" + inParams(b, uuids) + ")")
+	sql, params := b.Build()
+	rows, err := q.conn.Execute(ctx, sql, params...)
+	if err != nil {
+		return nil, err
+	}
+	byUUID := make(map[string]map[string]any, len(rows))
+	for _, row := range rows {
+		uuid, _ := row["uuid"].(string)
+		byUUID[uuid] = row
+	}
+	for i := range result.Sheets {
+		ps := &result.Sheets[i]
+		seen := map[string]bool{}
+		for j := range ps.Slots {
+			slot := &ps.Slots[j]
+			if row, ok := byUUID[slot.UUID]; ok {
+				slot.Name, _ = row["name"].(string)
+				slot.Number, _ = row["number"].(string)
+				slot.Rarity, _ = row["rarity"].(string)
+				slot.SetCode, _ = row["setCode"].(string)
+			}
+			if slot.Rarity != "" && !seen[slot.Rarity] {
+				seen[slot.Rarity] = true
+				ps.Rarities = append(ps.Rarities, slot.Rarity)
+			}
+		}
+		sort.Slice(ps.Rarities, func(a, b int) bool {
+			return rarityRank(ps.Rarities[a]) < rarityRank(ps.Rarities[b])
+		})
+		sort.Slice(ps.Slots, func(a, b int) bool {
+			x, y := ps.Slots[a], ps.Slots[b]
+			if x.SetCode != y.SetCode {
+				return x.SetCode < y.SetCode
+			}
+			if x.Number != y.Number {
+				return numberLess(x.Number, y.Number)
+			}
+			return x.UUID < y.UUID
+		})
+	}
+	return result, nil
+}
+
+// rarityRank orders rarities from common to bonus, unknown ones last.
+func rarityRank(rarity string) int {
+	switch rarity {
+	case "common":
+		return 0
+	case "uncommon":
+		return 1
+	case "rare":
+		return 2
+	case "mythic":
+		return 3
+	case "special":
+		return 4
+	case "bonus":
+		return 5
+	}
+	return 6
+}
+
+// numberLess orders collector numbers by their leading digits, so "9"
+// comes before "10" and "223a" after "223".
+func numberLess(a, b string) bool {
+	na, ra := splitNumber(a)
+	nb, rb := splitNumber(b)
+	if na != nb {
+		return na < nb
+	}
+	return ra < rb
+}
+
+// splitNumber splits a collector number into its leading integer (-1 if it
+// has none, sorting it first) and the rest.
+func splitNumber(s string) (int, string) {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	n, err := strconv.Atoi(s[:i])
+	if err != nil {
+		return -1, s
+	}
+	return n, s[i:]
+}
@@ -2,6 +2,7 @@ package queries
 
 import (
 	"context"
+	"strings"
 
 	"github.com/mtgjson/mtgjson-sdk-go/db"
 	"github.com/mtgjson/mtgjson-sdk-go/models"
@@ -10,33 +11,122 @@ import (
 // SkuQuery provides methods to query TCGPlayer SKU data.
 // SKUs represent individual purchasable variants of a card.
 type SkuQuery struct {
-	conn *db.Connection
+	conn db.Backend
 }
 
-func NewSkuQuery(conn *db.Connection) *SkuQuery {
+func NewSkuQuery(conn db.Backend) *SkuQuery {
 	return &SkuQuery{conn: conn}
 }
 
-func (q *SkuQuery) ensure(ctx context.Context) {
-	_ = q.conn.EnsureViews(ctx, "tcgplayer_skus")
+func (q *SkuQuery) ensure(ctx context.Context) error {
+	return q.conn.EnsureOptionalViews(ctx, "tcgplayer_skus")
 }
 
-// Get returns all TCGPlayer SKUs for a card UUID.
-func (q *SkuQuery) Get(ctx context.Context, uuid string) ([]models.TcgplayerSkus, error) {
-	q.ensure(ctx)
+type skuFilter struct {
+	condition, language, printing, finish string
+}
+
+// SkuOption filters the SKUs returned by Get and counted by Count. Values
+// are matched case-insensitively.
+type SkuOption func(*skuFilter)
+
+// skuConditions expands the usual condition abbreviations.
+var skuConditions = map[string]string{
+	"NM":  "NEAR MINT",
+	"LP":  "LIGHTLY PLAYED",
+	"MP":  "MODERATELY PLAYED",
+	"HP":  "HEAVILY PLAYED",
+	"DMG": "DAMAGED",
+}
+
+// WithSkuCondition keeps SKUs in a condition, e.g. "Near Mint" or its
+// abbreviation "NM" (also LP, MP, HP and DMG).
+func WithSkuCondition(condition string) SkuOption {
+	return func(f *skuFilter) { f.condition = condition }
+}
+
+// WithSkuLanguage keeps SKUs in a language, e.g. "English".
+func WithSkuLanguage(language string) SkuOption {
+	return func(f *skuFilter) { f.language = language }
+}
+
+// WithSkuPrinting keeps SKUs with a printing, e.g. "Foil" or "Non Foil".
+func WithSkuPrinting(printing string) SkuOption {
+	return func(f *skuFilter) { f.printing = printing }
+}
+
+// WithSkuFinish keeps SKUs with a finish, e.g. "Etched".
+func WithSkuFinish(finish string) SkuOption {
+	return func(f *skuFilter) { f.finish = finish }
+}
+
+// skuBuilder selects the SKUs of uuid ("" for every card) that pass opts.
+func skuBuilder(uuid string, opts []SkuOption) *db.SQLBuilder {
+	f := &skuFilter{}
+	for _, opt := range opts {
+		opt(f)
+	}
+	b := db.NewSQLBuilder("tcgplayer_skus")
+	if uuid != "" {
+		b.WhereEq("uuid", uuid)
+	}
+	cond := strings.ToUpper(strings.TrimSpace(f.condition))
+	if full, ok := skuConditions[cond]; ok {
+		cond = full
+	}
+	for _, filter := range []struct{ column, value string }{
+		{"condition", cond},
+		{"language", f.language},
+		{"printing", f.printing},
+		{"finish", f.finish},
+	} {
+		if filter.value != "" {
+			b.WhereEq("UPPER("+filter.column+")", strings.ToUpper(strings.TrimSpace(filter.value)))
+		}
+	}
+	return b
+}
+
+// Get returns the TCGPlayer SKUs for a card UUID, all of them unless
+// filtered by options: Get(ctx, uuid, WithSkuCondition("NM"),
+// WithSkuLanguage("English"), WithSkuPrinting("Foil")).
+func (q *SkuQuery) Get(ctx context.Context, uuid string, opts ...SkuOption) ([]models.TcgplayerSkus, error) {
+	if err := q.ensure(ctx); err != nil {
+		return nil, err
+	}
 	if !q.conn.HasView("tcgplayer_skus") {
 		return nil, nil
 	}
+	sql, params := skuBuilder(uuid, opts).Build()
 	var skus []models.TcgplayerSkus
-	if err := q.conn.ExecuteInto(ctx, &skus, "SELECT * FROM tcgplayer_skus WHERE uuid = $1", uuid); err != nil {
+	if err := q.conn.ExecuteInto(ctx, &skus, sql, params...); err != nil {
 		return nil, err
 	}
 	return skus, nil
 }
 
+// Count returns how many SKUs of a card UUID, or of every card if uuid is
+// "", pass the options.
+func (q *SkuQuery) Count(ctx context.Context, uuid string, opts ...SkuOption) (int, error) {
+	if err := q.ensure(ctx); err != nil {
+		return 0, err
+	}
+	if !q.conn.HasView("tcgplayer_skus") {
+		return 0, nil
+	}
+	sql, params := skuBuilder(uuid, opts).Select("COUNT(*)").Build()
+	val, err := q.conn.ExecuteScalar(ctx, sql, params...)
+	if err != nil {
+		return 0, err
+	}
+	return db.ScalarToInt(val), nil
+}
+
 // FindBySkuID finds a SKU by its TCGPlayer SKU ID.
 func (q *SkuQuery) FindBySkuID(ctx context.Context, skuID int) (map[string]any, error) {
-	q.ensure(ctx)
+	if err := q.ensure(ctx); err != nil {
+		return nil, err
+	}
 	if !q.conn.HasView("tcgplayer_skus") {
 		return nil, nil
 	}
@@ -52,7 +142,9 @@ func (q *SkuQuery) FindBySkuID(ctx context.Context, skuID int) (map[string]any,
 
 // FindByProductID finds all SKUs for a TCGPlayer product ID.
 func (q *SkuQuery) FindByProductID(ctx context.Context, productID int) ([]map[string]any, error) {
-	q.ensure(ctx)
+	if err := q.ensure(ctx); err != nil {
+		return nil, err
+	}
 	if !q.conn.HasView("tcgplayer_skus") {
 		return nil, nil
 	}
@@ -46,6 +46,50 @@ func TestSkuGet(t *testing.T) {
 	}
 }
 
+func TestSkuGetFiltered(t *testing.T) {
+	sq := setupSkuQuery(t)
+	ctx := context.Background()
+
+	skus, err := sq.Get(ctx, "card-uuid-001", WithSkuCondition("NM"), WithSkuLanguage("english"), WithSkuPrinting("FOIL"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(skus) != 1 || skus[0].SkuId != 12346 {
+		t.Fatalf("expected the NM English foil SKU, got %+v", skus)
+	}
+	skus, err = sq.Get(ctx, "card-uuid-001", WithSkuCondition("LP"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(skus) != 0 {
+		t.Fatalf("expected no lightly played SKUs, got %+v", skus)
+	}
+}
+
+func TestSkuCount(t *testing.T) {
+	sq := setupSkuQuery(t)
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		uuid string
+		opts []SkuOption
+		want int
+	}{
+		{"", nil, 3},
+		{"card-uuid-001", nil, 2},
+		{"", []SkuOption{WithSkuFinish("normal")}, 2},
+		{"", []SkuOption{WithSkuCondition("Near Mint"), WithSkuPrinting("Foil")}, 1},
+	} {
+		n, err := sq.Count(ctx, tt.uuid, tt.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != tt.want {
+			t.Errorf("Count(%q, %d options) = %d, want %d", tt.uuid, len(tt.opts), n, tt.want)
+		}
+	}
+}
+
 func TestSkuGetNotFound(t *testing.T) {
 	sq := setupSkuQuery(t)
 	ctx := context.Background()
@@ -0,0 +1,98 @@
+package queries
+
+import (
+	"context"
+
+	"github.com/mtgjson/mtgjson-sdk-go/db"
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+// SubtypeQuery provides subtype-level (tribal) aggregations over the cards table.
+type SubtypeQuery struct {
+	conn  db.Backend
+	cards *CardQuery // for keywords; see WithSubtypeCards
+}
+
+// SubtypeQueryOption configures a SubtypeQuery.
+type SubtypeQueryOption func(*SubtypeQuery)
+
+// WithSubtypeCards sets the card module whose Keywords.json fills in
+// missing keywords on the cards a census lists.
+func WithSubtypeCards(cards *CardQuery) SubtypeQueryOption {
+	return func(q *SubtypeQuery) { q.cards = cards }
+}
+
+func NewSubtypeQuery(conn db.Backend, opts ...SubtypeQueryOption) *SubtypeQuery {
+	q := &SubtypeQuery{conn: conn}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+type censusConfig struct {
+	format string
+}
+
+// CensusOption configures Census.
+type CensusOption func(*censusConfig)
+
+// WithCensusFormat restricts the census to cards legal in a format (e.g. "pauper").
+func WithCensusFormat(format string) CensusOption {
+	return func(c *censusConfig) { c.format = format }
+}
+
+// Census returns every printing with the given subtype (exact, e.g. "Elf"),
+// ordered by name, along with counts by color, rarity and set.
+func (q *SubtypeQuery) Census(ctx context.Context, subtype string, opts ...CensusOption) (*models.SubtypeCensus, error) {
+	cfg := &censusConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	views := []string{"cards"}
+	if cfg.format != "" {
+		views = append(views, "card_legalities")
+	}
+	if err := q.conn.EnsureViews(ctx, views...); err != nil {
+		return nil, err
+	}
+	b := db.NewSQLBuilder("cards").
+		Where("list_contains(subtypes, $1)", subtype).
+		OrderBy("name ASC", "setCode ASC", "number ASC")
+	if cfg.format != "" {
+		b.Where("uuid IN (SELECT uuid FROM card_legalities WHERE format = $1 AND status = 'Legal')", cfg.format)
+	}
+	sql, params := b.Build()
+	var cards []models.CardSet
+	if err := q.conn.ExecuteInto(ctx, &cards, sql, params...); err != nil {
+		return nil, err
+	}
+	q.cards.normalizeKeywords(ctx, cards)
+
+	census := &models.SubtypeCensus{
+		Subtype:   subtype,
+		Format:    cfg.format,
+		Printings: len(cards),
+		ByColor:   make(map[string]int),
+		ByRarity:  make(map[string]int),
+		BySet:     make(map[string]int),
+		Cards:     cards,
+	}
+	seen := make(map[string]bool)
+	for _, c := range cards {
+		census.ByRarity[c.Rarity]++
+		census.BySet[c.SetCode]++
+		if seen[c.Name] {
+			continue
+		}
+		seen[c.Name] = true
+		census.UniqueCards++
+		if len(c.Colors) == 0 {
+			census.ByColor["C"]++
+		}
+		for _, color := range c.Colors {
+			census.ByColor[color]++
+		}
+	}
+	return census, nil
+}
@@ -0,0 +1,88 @@
+package queries
+
+import (
+	"context"
+	"testing"
+)
+
+var sampleElves = []map[string]any{
+	{
+		"uuid": "elf-001", "name": "Llanowar Elves", "subtypes": []any{"Elf", "Druid"},
+		"colors": []any{"G"}, "rarity": "common", "setCode": "DOM", "number": "168",
+	},
+	{
+		"uuid": "elf-002", "name": "Llanowar Elves", "subtypes": []any{"Elf", "Druid"},
+		"colors": []any{"G"}, "rarity": "common", "setCode": "M19", "number": "314",
+	},
+	{
+		"uuid": "elf-003", "name": "Elvish Archdruid", "subtypes": []any{"Elf", "Druid"},
+		"colors": []any{"G"}, "rarity": "rare", "setCode": "M19", "number": "176",
+	},
+	{
+		"uuid": "elf-004", "name": "Drana's Emissary", "subtypes": []any{"Vampire", "Cleric"},
+		"colors": []any{"W", "B"}, "rarity": "uncommon", "setCode": "BFZ", "number": "210",
+	},
+	{
+		"uuid": "elf-005", "name": "Elvish Visionary", "subtypes": []any{"Elf", "Shaman"},
+		"colors": []any{}, "rarity": "common", "setCode": "M19", "number": "175",
+	},
+}
+
+var sampleElfLegalities = []map[string]any{
+	{"uuid": "elf-001", "format": "pauper", "status": "Legal"},
+	{"uuid": "elf-002", "format": "pauper", "status": "Legal"},
+	{"uuid": "elf-003", "format": "pauper", "status": "Not Legal"},
+	{"uuid": "elf-005", "format": "pauper", "status": "Legal"},
+}
+
+func setupSubtypeQuery(t *testing.T) *SubtypeQuery {
+	t.Helper()
+	conn := setupSampleDB(t)
+	ctx := context.Background()
+	if err := conn.RegisterTableFromData(ctx, "cards", sampleElves); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.RegisterTableFromData(ctx, "card_legalities", sampleElfLegalities); err != nil {
+		t.Fatal(err)
+	}
+	return NewSubtypeQuery(conn)
+}
+
+func TestSubtypeCensus(t *testing.T) {
+	q := setupSubtypeQuery(t)
+
+	census, err := q.Census(context.Background(), "Elf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if census.Printings != 4 || census.UniqueCards != 3 {
+		t.Fatalf("unexpected totals: printings=%d unique=%d", census.Printings, census.UniqueCards)
+	}
+	if census.ByColor["G"] != 2 || census.ByColor["C"] != 1 {
+		t.Fatalf("unexpected color counts: %v", census.ByColor)
+	}
+	if census.ByRarity["common"] != 3 || census.ByRarity["rare"] != 1 {
+		t.Fatalf("unexpected rarity counts: %v", census.ByRarity)
+	}
+	if census.BySet["M19"] != 3 || census.BySet["DOM"] != 1 {
+		t.Fatalf("unexpected set counts: %v", census.BySet)
+	}
+	if census.Cards[0].Name != "Elvish Archdruid" {
+		t.Fatalf("expected cards ordered by name, got %s first", census.Cards[0].Name)
+	}
+}
+
+func TestSubtypeCensusFormat(t *testing.T) {
+	q := setupSubtypeQuery(t)
+
+	census, err := q.Census(context.Background(), "Elf", WithCensusFormat("pauper"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if census.Format != "pauper" || census.Printings != 3 || census.UniqueCards != 2 {
+		t.Fatalf("unexpected pauper census: %+v", census)
+	}
+	if census.ByRarity["rare"] != 0 {
+		t.Fatalf("rare Elf should not be pauper legal: %v", census.ByRarity)
+	}
+}
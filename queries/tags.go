@@ -0,0 +1,161 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mtgjson/mtgjson-sdk-go/db"
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+// TagQuery stores user tags and notes on UUIDs in a local DuckDB file,
+// attached to the connection as "user_data". Unlike the MTGJSON views it is
+// writable, and its contents survive cache refreshes.
+type TagQuery struct {
+	conn     db.Backend
+	path     string
+	cards    *CardQuery // for keywords; see WithTagCards
+	mu       sync.Mutex
+	attached bool
+}
+
+// TagQueryOption configures a TagQuery.
+type TagQueryOption func(*TagQuery)
+
+// WithTagCards sets the card module whose Keywords.json fills in missing
+// keywords on the cards Tagged returns.
+func WithTagCards(cards *CardQuery) TagQueryOption {
+	return func(q *TagQuery) { q.cards = cards }
+}
+
+func NewTagQuery(conn db.Backend, path string, opts ...TagQueryOption) *TagQuery {
+	q := &TagQuery{conn: conn, path: path}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+// ensure attaches the annotations database and creates the tags table.
+func (q *TagQuery) ensure(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.attached {
+		return nil
+	}
+	err := attachUserData(ctx, q.conn, q.path,
+		"CREATE TABLE IF NOT EXISTS user_data.tags ("+
+			"uuid VARCHAR NOT NULL, tag VARCHAR NOT NULL, note VARCHAR, "+
+			"created_at TIMESTAMP NOT NULL DEFAULT current_timestamp, "+
+			"PRIMARY KEY (uuid, tag))")
+	if err != nil {
+		return err
+	}
+	q.attached = true
+	return nil
+}
+
+// attachUserData attaches the writable annotations database at path as
+// "user_data", if it is not attached yet, and runs the given DDL statements.
+func attachUserData(ctx context.Context, conn db.Backend, path string, ddl ...string) error {
+	stmts := append([]string{fmt.Sprintf("ATTACH IF NOT EXISTS %s AS user_data", db.SQLPathLiteral(path))}, ddl...)
+	for _, stmt := range stmts {
+		if err := conn.Exec(ctx, stmt); err != nil {
+			return fmt.Errorf("mtgjson: open annotations %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Add tags a UUID, replacing the note if the tag is already set. An empty
+// note is stored as no note. Tags are trimmed and must not be empty.
+func (q *TagQuery) Add(ctx context.Context, uuid, tag, note string) error {
+	tag = strings.TrimSpace(tag)
+	if uuid == "" || tag == "" {
+		return fmt.Errorf("mtgjson: tag needs a uuid and a tag name")
+	}
+	if err := q.ensure(ctx); err != nil {
+		return err
+	}
+	var noteVal any
+	if note != "" {
+		noteVal = note
+	}
+	err := q.conn.Exec(ctx,
+		"INSERT INTO user_data.tags (uuid, tag, note) VALUES ($1, $2, $3) "+
+			"ON CONFLICT (uuid, tag) DO UPDATE SET note = excluded.note",
+		uuid, tag, noteVal)
+	if err != nil {
+		return fmt.Errorf("mtgjson: add tag %q: %w", tag, err)
+	}
+	return nil
+}
+
+// Remove deletes a tag from a UUID. Removing a tag that is not set is not an
+// error.
+func (q *TagQuery) Remove(ctx context.Context, uuid, tag string) error {
+	if err := q.ensure(ctx); err != nil {
+		return err
+	}
+	err := q.conn.Exec(ctx,
+		"DELETE FROM user_data.tags WHERE uuid = $1 AND tag = $2", uuid, strings.TrimSpace(tag))
+	if err != nil {
+		return fmt.Errorf("mtgjson: remove tag %q: %w", tag, err)
+	}
+	return nil
+}
+
+// ForUUID returns the tags on a UUID, ordered by tag.
+func (q *TagQuery) ForUUID(ctx context.Context, uuid string) ([]models.Tag, error) {
+	if err := q.ensure(ctx); err != nil {
+		return nil, err
+	}
+	var tags []models.Tag
+	err := q.conn.ExecuteInto(ctx, &tags,
+		"SELECT uuid, tag, note, strftime(created_at, '%Y-%m-%dT%H:%M:%S') AS created_at "+
+			"FROM user_data.tags WHERE uuid = $1 ORDER BY tag", uuid)
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// List returns every tag in use with the number of UUIDs carrying it.
+func (q *TagQuery) List(ctx context.Context) (map[string]int, error) {
+	if err := q.ensure(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := q.conn.Execute(ctx, "SELECT tag, COUNT(*) AS n FROM user_data.tags GROUP BY tag")
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int, len(rows))
+	for _, r := range rows {
+		tag, _ := r["tag"].(string)
+		counts[tag] = db.ScalarToInt(r["n"])
+	}
+	return counts, nil
+}
+
+// Tagged returns the cards carrying a tag, ordered by name, set and number.
+// Tagged UUIDs that are not cards (tokens, sealed products) are skipped.
+func (q *TagQuery) Tagged(ctx context.Context, tag string) ([]models.CardSet, error) {
+	if err := q.ensure(ctx); err != nil {
+		return nil, err
+	}
+	if err := q.conn.EnsureViews(ctx, "cards"); err != nil {
+		return nil, err
+	}
+	var cards []models.CardSet
+	err := q.conn.ExecuteInto(ctx, &cards,
+		"SELECT c.* FROM cards c JOIN user_data.tags t ON c.uuid = t.uuid "+
+			"WHERE t.tag = $1 ORDER BY c.name, c.setCode, c.number",
+		strings.TrimSpace(tag))
+	if err != nil {
+		return nil, err
+	}
+	q.cards.normalizeKeywords(ctx, cards)
+	return cards, nil
+}
@@ -0,0 +1,85 @@
+package queries
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestTagsAddAndTagged(t *testing.T) {
+	conn := setupSampleDB(t)
+	q := NewTagQuery(conn, filepath.Join(t.TempDir(), "annotations.duckdb"))
+	ctx := context.Background()
+
+	if err := q.Add(ctx, "card-uuid-001", "burn", "trade binder"); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Add(ctx, "card-uuid-003", " burn ", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Add(ctx, "card-uuid-001", "cube", ""); err != nil {
+		t.Fatal(err)
+	}
+	cards, err := q.Tagged(ctx, "burn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 2 || cards[0].Name != "Fire // Ice" || cards[1].Name != "Lightning Bolt" {
+		t.Fatalf("expected Fire // Ice and Lightning Bolt, got %v", cards)
+	}
+
+	tags, err := q.ForUUID(ctx, "card-uuid-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 2 || tags[0].Tag != "burn" || tags[0].Note == nil || *tags[0].Note != "trade binder" {
+		t.Fatalf("unexpected tags: %+v", tags)
+	}
+	if tags[1].Note != nil || tags[1].CreatedAt == "" {
+		t.Fatalf("expected cube without a note and with a timestamp, got %+v", tags[1])
+	}
+
+	counts, err := q.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counts["burn"] != 2 || counts["cube"] != 1 {
+		t.Fatalf("unexpected tag counts: %v", counts)
+	}
+}
+
+func TestTagsUpdateAndRemove(t *testing.T) {
+	conn := setupSampleDB(t)
+	q := NewTagQuery(conn, filepath.Join(t.TempDir(), "annotations.duckdb"))
+	ctx := context.Background()
+
+	if err := q.Add(ctx, "card-uuid-002", "cube", "old"); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Add(ctx, "card-uuid-002", "cube", "new"); err != nil {
+		t.Fatal(err)
+	}
+	tags, err := q.ForUUID(ctx, "card-uuid-002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 1 || *tags[0].Note != "new" {
+		t.Fatalf("expected the note to be replaced, got %+v", tags)
+	}
+	if err := q.Remove(ctx, "card-uuid-002", "cube"); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Remove(ctx, "card-uuid-002", "cube"); err != nil {
+		t.Fatal(err)
+	}
+	tags, err = q.ForUUID(ctx, "card-uuid-002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 0 {
+		t.Fatalf("expected no tags, got %+v", tags)
+	}
+	if err := q.Add(ctx, "card-uuid-002", "  ", ""); err == nil {
+		t.Fatal("expected an error for an empty tag")
+	}
+}
@@ -3,6 +3,8 @@ package queries
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/mtgjson/mtgjson-sdk-go/db"
 	"github.com/mtgjson/mtgjson-sdk-go/models"
@@ -21,10 +23,10 @@ type SearchTokensParams struct {
 
 // TokenQuery provides methods to search and retrieve token card data.
 type TokenQuery struct {
-	conn *db.Connection
+	conn db.Backend
 }
 
-func NewTokenQuery(conn *db.Connection) *TokenQuery {
+func NewTokenQuery(conn db.Backend) *TokenQuery {
 	return &TokenQuery{conn: conn}
 }
 
@@ -130,6 +132,50 @@ func (q *TokenQuery) ForSet(ctx context.Context, setCode string) ([]models.CardT
 	return q.Search(ctx, SearchTokensParams{SetCode: setCode, Limit: 1000})
 }
 
+// Stickers returns the Unfinity-style sticker sheets among the tokens,
+// optionally limited to one set. Returns an empty slice if the data has none.
+func (q *TokenQuery) Stickers(ctx context.Context, setCode ...string) ([]models.CardToken, error) {
+	return q.ofKind(ctx, "LOWER(type) LIKE LOWER($1)", "%Stickers%", setCode)
+}
+
+// Emblems returns the emblems among the tokens (layout "emblem" or an
+// "Emblem — ..." type), optionally limited to one set.
+func (q *TokenQuery) Emblems(ctx context.Context, setCode ...string) ([]models.CardToken, error) {
+	return q.ofKind(ctx, "(layout = 'emblem' OR type LIKE $1)", "Emblem%", setCode)
+}
+
+// Dungeons returns the dungeons among the tokens, such as Tomb of
+// Annihilation and the Undercity, optionally limited to one set.
+func (q *TokenQuery) Dungeons(ctx context.Context, setCode ...string) ([]models.CardToken, error) {
+	return q.ofKind(ctx, "type LIKE $1", "Dungeon%", setCode)
+}
+
+// ArtCards returns the art series cards among the tokens (layout
+// "art_series"), optionally limited to one set.
+func (q *TokenQuery) ArtCards(ctx context.Context, setCode ...string) ([]models.CardToken, error) {
+	return q.ofKind(ctx, "layout = $1", "art_series", setCode)
+}
+
+// ofKind returns the tokens matching cond, whose one parameter is arg,
+// optionally in setCode[0], ordered by set and collector number. The result
+// is an empty slice, not nil, when nothing matches, whatever the backend.
+func (q *TokenQuery) ofKind(ctx context.Context, cond string, arg any, setCode []string) ([]models.CardToken, error) {
+	if err := q.conn.EnsureViews(ctx, "tokens"); err != nil {
+		return nil, err
+	}
+	b := db.NewSQLBuilder("tokens").Where(cond, arg)
+	if len(setCode) > 0 && setCode[0] != "" {
+		b.WhereEq("setCode", setCode[0])
+	}
+	b.OrderBy("setCode ASC", "number ASC")
+	sql, params := b.Build()
+	tokens := []models.CardToken{}
+	if err := q.conn.ExecuteInto(ctx, &tokens, sql, params...); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
 // Count returns the number of tokens matching optional column filters.
 func (q *TokenQuery) Count(ctx context.Context, filters ...Filter) (int, error) {
 	if err := q.conn.EnsureViews(ctx, "tokens"); err != nil {
@@ -153,3 +199,87 @@ func (q *TokenQuery) Count(ctx context.Context, filters ...Filter) (int, error)
 	}
 	return db.ScalarToInt(val), nil
 }
+
+// Generators returns the cards that create a token, one printing per card
+// name, ordered by name. token may be a token UUID or name (e.g. "Treasure").
+// Cards are found through the token's reverseRelated data, falling back to
+// rules text of the form "create ... <name> ... token".
+func (q *TokenQuery) Generators(ctx context.Context, token string) ([]models.TokenGenerator, error) {
+	tokens, err := q.GetByUUIDs(ctx, []string{token})
+	if err != nil {
+		return nil, err
+	}
+	name := token
+	if len(tokens) > 0 {
+		name = tokens[0].Name
+	}
+	if tokens, err = q.GetByName(ctx, name); err != nil {
+		return nil, err
+	}
+	related := make(map[string]bool)
+	for _, t := range tokens {
+		names := t.ReverseRelated
+		if t.RelatedCards != nil {
+			names = append(names, t.RelatedCards.ReverseRelated...)
+		}
+		for _, n := range names {
+			related[n] = true
+		}
+	}
+	names := make([]any, 0, len(related))
+	for n := range related {
+		names = append(names, n)
+	}
+
+	if err := q.conn.EnsureViews(ctx, "cards"); err != nil {
+		return nil, err
+	}
+	base := strings.TrimSuffix(name, " Token")
+	pattern := `create[^.]*\b` + regexp.QuoteMeta(base) + `\b[^.]*\btokens?\b`
+	b := db.NewSQLBuilder("cards")
+	nameCond := "false"
+	if len(names) > 0 {
+		placeholders := make([]string, len(names))
+		for i, n := range names {
+			placeholders[i] = fmt.Sprintf("$%d", b.AddParam(n))
+		}
+		nameCond = "name IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+	idx := b.AddParam(pattern)
+	textCond := fmt.Sprintf("COALESCE(regexp_matches(text, $%d, 'i'), false)", idx)
+	b.Select("uuid", "name", "setCode", "number", "type", "text",
+		nameCond+" AS by_related", textCond+" AS by_text")
+	b.AddWhere("(" + nameCond + " OR " + textCond + ")")
+	b.OrderBy("name ASC", "setCode ASC", "number ASC")
+	sql, params := b.Build()
+	rows, err := q.conn.Execute(ctx, sql, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []models.TokenGenerator
+	seen := make(map[string]bool)
+	for _, r := range rows {
+		g := models.TokenGenerator{}
+		g.Name, _ = r["name"].(string)
+		if seen[g.Name] {
+			continue
+		}
+		seen[g.Name] = true
+		g.UUID, _ = r["uuid"].(string)
+		g.SetCode, _ = r["setCode"].(string)
+		g.Number, _ = r["number"].(string)
+		g.Type, _ = r["type"].(string)
+		if text, ok := r["text"].(string); ok {
+			g.Text = &text
+		}
+		if v, _ := r["by_related"].(bool); v {
+			g.MatchedBy = append(g.MatchedBy, "reverse_related")
+		}
+		if v, _ := r["by_text"].(bool); v {
+			g.MatchedBy = append(g.MatchedBy, "text")
+		}
+		result = append(result, g)
+	}
+	return result, nil
+}
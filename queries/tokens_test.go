@@ -2,7 +2,10 @@ package queries
 
 import (
 	"context"
+	"maps"
 	"testing"
+
+	"github.com/mtgjson/mtgjson-sdk-go/models"
 )
 
 func TestTokenGetByUUID(t *testing.T) {
@@ -180,3 +183,142 @@ func TestTokenGetByUUIDsEmpty(t *testing.T) {
 		t.Fatalf("expected 0, got %d", len(tokens))
 	}
 }
+
+func TestTokenGenerators(t *testing.T) {
+	conn := setupSampleDB(t)
+	ctx := context.Background()
+	tokens := []map[string]any{
+		{
+			"uuid": "tok-treasure", "name": "Treasure", "setCode": "XLN", "number": "T1",
+			"relatedCards": map[string]any{"reverseRelated": []any{"Dockside Extortionist"}},
+		},
+		{
+			"uuid": "tok-soldier", "name": "Soldier", "setCode": "A25", "number": "T2",
+			"relatedCards": map[string]any{"reverseRelated": []any{}},
+		},
+	}
+	cards := []map[string]any{
+		{
+			"uuid": "gen-001", "name": "Dockside Extortionist", "setCode": "C19", "number": "24",
+			"type": "Creature — Goblin Pirate", "text": "When this enters, create X Treasure tokens.",
+		},
+		{
+			"uuid": "gen-002", "name": "Smothering Tithe", "setCode": "RNA", "number": "22",
+			"type": "Enchantment", "text": "Whenever an opponent draws a card, that player may pay {2}. If they don't, you create a Treasure token.",
+		},
+		{
+			"uuid": "gen-003", "name": "Smothering Tithe", "setCode": "CMM", "number": "52",
+			"type": "Enchantment", "text": "Whenever an opponent draws a card, that player may pay {2}. If they don't, you create a Treasure token.",
+		},
+		{
+			"uuid": "gen-004", "name": "Raise the Alarm", "setCode": "M10", "number": "34",
+			"type": "Instant", "text": "Create two 1/1 white Soldier creature tokens.",
+		},
+		{
+			"uuid": "gen-005", "name": "Lightning Bolt", "setCode": "A25", "number": "141",
+			"type": "Instant", "text": "Lightning Bolt deals 3 damage to any target.",
+		},
+	}
+	if err := conn.RegisterTableFromData(ctx, "tokens", tokens); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.RegisterTableFromData(ctx, "cards", cards); err != nil {
+		t.Fatal(err)
+	}
+	q := NewTokenQuery(conn)
+
+	gens, err := q.Generators(ctx, "tok-treasure")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gens) != 2 {
+		t.Fatalf("expected 2 Treasure generators, got %+v", gens)
+	}
+	if gens[0].Name != "Dockside Extortionist" || len(gens[0].MatchedBy) != 2 {
+		t.Fatalf("expected Dockside matched by both sources, got %+v", gens[0])
+	}
+	if gens[1].Name != "Smothering Tithe" || gens[1].MatchedBy[0] != "text" {
+		t.Fatalf("expected Smothering Tithe matched by text, got %+v", gens[1])
+	}
+
+	gens, err = q.Generators(ctx, "Soldier")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gens) != 1 || gens[0].Name != "Raise the Alarm" {
+		t.Fatalf("expected Raise the Alarm, got %+v", gens)
+	}
+}
+
+func TestTokenStickers(t *testing.T) {
+	conn := setupSampleDB(t)
+	q := NewTokenQuery(conn)
+	ctx := context.Background()
+
+	sheet := make(map[string]any, len(sampleTokens[0]))
+	for k, v := range sampleTokens[0] {
+		sheet[k] = v
+	}
+	sheet["uuid"] = "token-uuid-sticker"
+	sheet["name"] = "Ancestral Hot Dog Minotaur"
+	sheet["type"] = "Stickers"
+	sheet["types"] = []any{"Stickers"}
+	sheet["subtypes"] = []any{}
+	sheet["setCode"] = "SUNF"
+	if err := conn.RegisterTableFromData(ctx, "tokens", append([]map[string]any{sheet}, sampleTokens...)); err != nil {
+		t.Fatal(err)
+	}
+
+	stickers, err := q.Stickers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stickers) != 1 || stickers[0].UUID != "token-uuid-sticker" {
+		t.Fatalf("expected the sticker sheet, got %d tokens", len(stickers))
+	}
+	stickers, err = q.Stickers(ctx, "A25")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stickers == nil || len(stickers) != 0 {
+		t.Fatalf("expected an empty slice for A25, got %v", stickers)
+	}
+}
+
+func TestTokenEmblemsDungeonsArtCards(t *testing.T) {
+	conn := setupSampleDB(t)
+	q := NewTokenQuery(conn)
+	ctx := context.Background()
+
+	emblem := maps.Clone(sampleTokens[0])
+	emblem["uuid"], emblem["name"], emblem["layout"] = "token-uuid-emblem", "Liliana, Dreadhorde General Emblem", "emblem"
+	emblem["type"], emblem["types"], emblem["setCode"] = "Emblem — Liliana", []any{"Emblem"}, "TWAR"
+	dungeon := maps.Clone(sampleTokens[0])
+	dungeon["uuid"], dungeon["name"], dungeon["type"], dungeon["types"] = "token-uuid-dungeon", "Tomb of Annihilation", "Dungeon", []any{"Dungeon"}
+	art := maps.Clone(sampleTokens[0])
+	art["uuid"], art["name"], art["layout"], art["type"] = "token-uuid-art", "Ragavan // Ragavan", "art_series", "Card // Card"
+	if err := conn.RegisterTableFromData(ctx, "tokens", append([]map[string]any{emblem, dungeon, art}, sampleTokens...)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name  string
+		query func(context.Context, ...string) ([]models.CardToken, error)
+		want  string
+	}{
+		{"Emblems", q.Emblems, "token-uuid-emblem"},
+		{"Dungeons", q.Dungeons, "token-uuid-dungeon"},
+		{"ArtCards", q.ArtCards, "token-uuid-art"},
+	} {
+		tokens, err := tc.query(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(tokens) != 1 || tokens[0].UUID != tc.want {
+			t.Errorf("%s: expected %s, got %d tokens", tc.name, tc.want, len(tokens))
+		}
+	}
+	if tokens, err := q.Emblems(ctx, "A25"); err != nil || len(tokens) != 0 {
+		t.Errorf("expected no emblems in A25, got %d (%v)", len(tokens), err)
+	}
+}
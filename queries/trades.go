@@ -0,0 +1,323 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/mtgjson/mtgjson-sdk-go/db"
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+// TradeQuery matches collections against want lists and suggests
+// price-balanced trades using today's retail prices.
+type TradeQuery struct {
+	conn db.Backend
+}
+
+func NewTradeQuery(conn db.Backend) *TradeQuery {
+	return &TradeQuery{conn: conn}
+}
+
+// tradeCard is the card and price data needed to match and value entries.
+type tradeCard struct {
+	name    string
+	setCode string
+	number  string
+	prices  map[string]float64 // finish -> price
+}
+
+// Match returns the cards in have that satisfy entries in want, priced with
+// the latest retail prices (tcgplayer unless overridden by WithPriceProvider).
+// Want entries with a UUID match that printing only; entries with just a Name
+// match any printing. A want entry with a Finish only matches that finish.
+// Have entries with just a Name count as the card's newest paper printing.
+// Every entry needs a positive Quantity.
+func (q *TradeQuery) Match(ctx context.Context, have, want []models.CollectionEntry, opts ...PriceFilterOption) ([]models.TradeItem, error) {
+	have, err := q.resolve(ctx, have, true)
+	if err != nil {
+		return nil, err
+	}
+	want, err = q.resolve(ctx, want, false)
+	if err != nil {
+		return nil, err
+	}
+	cards, err := q.loadCards(ctx, have, opts)
+	if err != nil {
+		return nil, err
+	}
+	return matchEntries(have, want, cards), nil
+}
+
+// Propose suggests a trade between two parties. Each party gives cards from its
+// collection that the other wants; the side with the larger total is trimmed,
+// most valuable cards first, so both sides are as close in value as possible.
+func (q *TradeQuery) Propose(ctx context.Context, mine, theirs models.TradeParty, opts ...PriceFilterOption) (*models.TradeProposal, error) {
+	give, err := q.Match(ctx, mine.Collection, theirs.Wants, opts...)
+	if err != nil {
+		return nil, err
+	}
+	receive, err := q.Match(ctx, theirs.Collection, mine.Wants, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if itemsValue(give) > itemsValue(receive) {
+		give = balanceItems(give, itemsValue(receive))
+	} else {
+		receive = balanceItems(receive, itemsValue(give))
+	}
+	p := &models.TradeProposal{
+		Give:         give,
+		Receive:      receive,
+		GiveValue:    roundCents(itemsValue(give)),
+		ReceiveValue: roundCents(itemsValue(receive)),
+	}
+	p.Balance = roundCents(p.ReceiveValue - p.GiveValue)
+	return p, nil
+}
+
+// resolve checks the entries' quantities and fills in the UUIDs of entries
+// identified only by Scryfall ID and, with byName, of entries identified
+// only by Name, using the name's newest paper printing.
+func (q *TradeQuery) resolve(ctx context.Context, entries []models.CollectionEntry, byName bool) ([]models.CollectionEntry, error) {
+	out := make([]models.CollectionEntry, len(entries))
+	var scryfallIDs, names []any
+	for i, e := range entries {
+		if e.Quantity <= 0 {
+			label := e.Name
+			if label == "" {
+				label = e.UUID + e.ScryfallID
+			}
+			return nil, fmt.Errorf("mtgjson: entry %d (%s): quantity %d is not positive", i+1, label, e.Quantity)
+		}
+		switch {
+		case e.UUID != "":
+		case e.ScryfallID != "":
+			scryfallIDs = append(scryfallIDs, e.ScryfallID)
+		case byName && e.Name != "":
+			names = append(names, e.Name)
+		}
+		out[i] = e
+	}
+	byScryfall, err := uuidsByIdentifier(ctx, q.conn, "scryfallId", scryfallIDs)
+	if err != nil {
+		return nil, err
+	}
+	printings := make(map[string]string) // name -> uuid
+	if len(names) > 0 {
+		if err := q.conn.EnsureViews(ctx, "cards", "sets"); err != nil {
+			return nil, err
+		}
+		sql, params := db.NewSQLBuilder("cards c").
+			Select("c.name", "c.uuid").
+			Join("LEFT JOIN sets s ON s.code = c.setCode").
+			WhereIn("c.name", names).
+			OrderBy("COALESCE(s.isOnlineOnly, false) ASC", "s.releaseDate DESC NULLS LAST", "c.number ASC", "c.uuid ASC").
+			Build()
+		rows, err := q.conn.Execute(ctx, sql, params...)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			name, _ := r["name"].(string)
+			if printings[name] == "" {
+				printings[name], _ = r["uuid"].(string)
+			}
+		}
+	}
+	for i := range out {
+		e := &out[i]
+		switch {
+		case e.UUID != "":
+		case e.ScryfallID != "":
+			e.UUID = byScryfall[e.ScryfallID]
+		case byName:
+			e.UUID = printings[e.Name]
+		}
+	}
+	return out, nil
+}
+
+// loadCards fetches names, printings and today's retail prices for the entries.
+func (q *TradeQuery) loadCards(ctx context.Context, entries []models.CollectionEntry, opts []PriceFilterOption) (map[string]*tradeCard, error) {
+	cards := make(map[string]*tradeCard)
+	var uuids []any
+	for _, e := range entries {
+		if e.UUID != "" && cards[e.UUID] == nil {
+			cards[e.UUID] = &tradeCard{prices: make(map[string]float64)}
+			uuids = append(uuids, e.UUID)
+		}
+	}
+	if len(uuids) == 0 {
+		return cards, nil
+	}
+	if err := q.conn.EnsureViews(ctx, "cards"); err != nil {
+		return nil, err
+	}
+	sql, params := db.NewSQLBuilder("cards").
+		Select("uuid", "name", "setCode", "number").
+		WhereIn("uuid", uuids).
+		Build()
+	rows, err := q.conn.Execute(ctx, sql, params...)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		uuid, _ := r["uuid"].(string)
+		if c := cards[uuid]; c != nil {
+			c.name, _ = r["name"].(string)
+			c.setCode, _ = r["setCode"].(string)
+			c.number, _ = r["number"].(string)
+		}
+	}
+
+	if err := q.conn.EnsureOptionalViews(ctx, "all_prices_today"); err != nil {
+		return nil, err
+	}
+	if !q.conn.HasView("all_prices_today") {
+		return cards, nil
+	}
+	cfg := &priceFilter{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.provider == "" {
+		cfg.provider = defaultProvider(cfg.source)
+	}
+	b := db.NewSQLBuilder(db.LatestPricesTable).
+		Select("uuid", "finish", "price").
+		WhereEq("provider", cfg.provider).
+		WhereEq("price_type", "retail").
+		WhereIn("uuid", uuids)
+	if cfg.source != "" {
+		b.WhereEq("source", cfg.source)
+	}
+	sql, params = b.Build()
+	rows, err = q.conn.Execute(ctx, sql, params...)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		uuid, _ := r["uuid"].(string)
+		finish, _ := r["finish"].(string)
+		if c := cards[uuid]; c != nil {
+			c.prices[finish] = db.ToFloat64(r["price"])
+		}
+	}
+	return cards, nil
+}
+
+// matchEntries pairs want entries with have entries, consuming quantities so a
+// copy is never matched twice.
+func matchEntries(have, want []models.CollectionEntry, cards map[string]*tradeCard) []models.TradeItem {
+	remaining := make([]int, len(have))
+	for i, h := range have {
+		remaining[i] = h.Quantity
+	}
+	var items []models.TradeItem
+	for _, w := range want {
+		needed := w.Quantity
+		for i, h := range have {
+			if needed == 0 {
+				break
+			}
+			card := cards[h.UUID]
+			if remaining[i] == 0 || card == nil {
+				continue
+			}
+			if w.UUID != "" && w.UUID != h.UUID {
+				continue
+			}
+			if w.UUID == "" && (w.Name == "" || w.Name != card.name) {
+				continue
+			}
+			finish := entryFinish(h)
+			if w.Finish != "" && w.Finish != finish {
+				continue
+			}
+			n := min(needed, remaining[i])
+			remaining[i] -= n
+			needed -= n
+			items = append(items, models.TradeItem{
+				UUID:      h.UUID,
+				Name:      card.name,
+				SetCode:   card.setCode,
+				Number:    card.number,
+				Finish:    finish,
+				Quantity:  n,
+				UnitPrice: card.prices[finish],
+			})
+		}
+	}
+	return items
+}
+
+// balanceItems selects copies from items, most valuable first, whose total
+// comes as close as possible to target without being needlessly exceeded.
+func balanceItems(items []models.TradeItem, target float64) []models.TradeItem {
+	type unit struct {
+		idx   int
+		price float64
+	}
+	var units []unit
+	for i, it := range items {
+		for n := 0; n < it.Quantity; n++ {
+			units = append(units, unit{i, it.UnitPrice})
+		}
+	}
+	sort.SliceStable(units, func(i, j int) bool { return units[i].price > units[j].price })
+
+	picked := make([]bool, len(units))
+	total := 0.0
+	for i, u := range units {
+		if total+u.price <= target {
+			picked[i] = true
+			total += u.price
+		}
+	}
+	// One more copy may land closer to the target than stopping short.
+	for i := len(units) - 1; i >= 0; i-- {
+		if !picked[i] {
+			if math.Abs(total+units[i].price-target) < math.Abs(total-target) {
+				picked[i] = true
+			}
+			break
+		}
+	}
+
+	counts := make([]int, len(items))
+	for i, u := range units {
+		if picked[i] {
+			counts[u.idx]++
+		}
+	}
+	var result []models.TradeItem
+	for i, it := range items {
+		if counts[i] > 0 {
+			it.Quantity = counts[i]
+			result = append(result, it)
+		}
+	}
+	return result
+}
+
+func itemsValue(items []models.TradeItem) float64 {
+	total := 0.0
+	for _, it := range items {
+		total += it.UnitPrice * float64(it.Quantity)
+	}
+	return total
+}
+
+func entryFinish(e models.CollectionEntry) string {
+	if e.Finish == "" {
+		return "normal"
+	}
+	return e.Finish
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
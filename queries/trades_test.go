@@ -0,0 +1,120 @@
+package queries
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+func setupTradeQuery(t *testing.T) *TradeQuery {
+	t.Helper()
+	pq := setupPriceQuery(t)
+	return NewTradeQuery(pq.conn)
+}
+
+func TestTradeMatchByName(t *testing.T) {
+	q := setupTradeQuery(t)
+	ctx := context.Background()
+
+	have := []models.CollectionEntry{
+		{UUID: "card-uuid-001", Quantity: 4},
+		{UUID: "card-uuid-002", Quantity: 1},
+	}
+	want := []models.CollectionEntry{{Name: "Lightning Bolt", Quantity: 2}}
+	items, err := q.Match(ctx, have, want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(items))
+	}
+	if items[0].UUID != "card-uuid-001" || items[0].Quantity != 2 || items[0].UnitPrice != 2.00 {
+		t.Fatalf("unexpected item: %+v", items[0])
+	}
+}
+
+func TestTradeMatchHaveByName(t *testing.T) {
+	q := setupTradeQuery(t)
+	ctx := context.Background()
+
+	have := []models.CollectionEntry{{Name: "Counterspell", Quantity: 2}}
+	want := []models.CollectionEntry{{Name: "Counterspell", Quantity: 1}}
+	items, err := q.Match(ctx, have, want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].UUID != "card-uuid-002" || items[0].Quantity != 1 {
+		t.Fatalf("expected a have entry given by name to match, got %+v", items)
+	}
+
+	for _, qty := range []int{0, -1} {
+		bad := []models.CollectionEntry{{UUID: "card-uuid-001", Quantity: 1}, {Name: "Counterspell", Quantity: qty}}
+		if _, err := q.Match(ctx, bad, want); err == nil || !strings.Contains(err.Error(), "entry 2") {
+			t.Fatalf("expected an error naming entry 2 for quantity %d, got %v", qty, err)
+		}
+	}
+}
+
+func TestTradeMatchFinishAndScryfallID(t *testing.T) {
+	q := setupTradeQuery(t)
+	ctx := context.Background()
+
+	have := []models.CollectionEntry{
+		{ScryfallID: "scryfall-001", Quantity: 1, Finish: "foil"},
+		{UUID: "card-uuid-001", Quantity: 1},
+	}
+	want := []models.CollectionEntry{{UUID: "card-uuid-001", Quantity: 1, Finish: "foil"}}
+	items, err := q.Match(ctx, have, want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(items))
+	}
+	if items[0].Finish != "foil" || items[0].UnitPrice != 4.00 {
+		t.Fatalf("expected foil copy at 4.00, got %+v", items[0])
+	}
+}
+
+func TestTradePropose(t *testing.T) {
+	q := setupTradeQuery(t)
+	ctx := context.Background()
+
+	mine := models.TradeParty{
+		Collection: []models.CollectionEntry{{UUID: "card-uuid-001", Quantity: 4}},
+		Wants:      []models.CollectionEntry{{Name: "Counterspell", Quantity: 1}},
+	}
+	theirs := models.TradeParty{
+		Collection: []models.CollectionEntry{{UUID: "card-uuid-002", Quantity: 1}},
+		Wants:      []models.CollectionEntry{{UUID: "card-uuid-001", Quantity: 4}},
+	}
+	p, err := q.Propose(ctx, mine, theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Give) != 1 || p.Give[0].Quantity != 2 {
+		t.Fatalf("expected to give 2 bolts, got %+v", p.Give)
+	}
+	if len(p.Receive) != 1 || p.Receive[0].Name != "Counterspell" {
+		t.Fatalf("expected to receive Counterspell, got %+v", p.Receive)
+	}
+	if p.GiveValue != 4.00 || p.ReceiveValue != 5.00 || p.Balance != 1.00 {
+		t.Fatalf("unexpected values: give=%v receive=%v balance=%v", p.GiveValue, p.ReceiveValue, p.Balance)
+	}
+}
+
+func TestBalanceItems(t *testing.T) {
+	items := []models.TradeItem{
+		{UUID: "a", Quantity: 1, UnitPrice: 10},
+		{UUID: "b", Quantity: 3, UnitPrice: 1},
+	}
+	got := balanceItems(items, 12)
+	if itemsValue(got) != 12 {
+		t.Fatalf("expected value 12, got %v (%+v)", itemsValue(got), got)
+	}
+	if len(balanceItems(items, 0)) != 0 {
+		t.Fatal("expected no items for zero target")
+	}
+}
@@ -0,0 +1,229 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mtgjson/mtgjson-sdk-go/db"
+)
+
+// Transliterator returns an ASCII spelling of a localized card name, such
+// as the romaji "Inazuma" for the Japanese "稲妻", or "" if it has none.
+// language is the foreign data language, e.g. "Japanese".
+type Transliterator func(name, language string) string
+
+// WithTransliterator lets Search match an ASCII LocalizedName against
+// transliterations of the foreign names, so "inazuma" finds Lightning Bolt
+// through its Japanese name. Names whose foreign data carries an asciiName
+// column match by it without a transliterator.
+func WithTransliterator(t Transliterator) CardQueryOption {
+	return func(q *CardQuery) { q.translit = t }
+}
+
+// foreignASCIITable maps foreign names to their folded ASCII spellings. It
+// is built from card_foreign_data on the first search that needs it.
+const foreignASCIITable = "card_foreign_ascii"
+
+// foreignASCIIBatch is how many rows one INSERT into foreignASCIITable adds.
+const foreignASCIIBatch = 500
+
+// kana maps hiragana to Hepburn romaji. Katakana are looked up by their
+// hiragana equivalent.
+var kana = map[rune]string{
+	'あ': "a", 'い': "i", 'う': "u", 'え': "e", 'お': "o",
+	'か': "ka", 'き': "ki", 'く': "ku", 'け': "ke", 'こ': "ko",
+	'が': "ga", 'ぎ': "gi", 'ぐ': "gu", 'げ': "ge", 'ご': "go",
+	'さ': "sa", 'し': "shi", 'す': "su", 'せ': "se", 'そ': "so",
+	'ざ': "za", 'じ': "ji", 'ず': "zu", 'ぜ': "ze", 'ぞ': "zo",
+	'た': "ta", 'ち': "chi", 'つ': "tsu", 'て': "te", 'と': "to",
+	'だ': "da", 'ぢ': "ji", 'づ': "zu", 'で': "de", 'ど': "do",
+	'な': "na", 'に': "ni", 'ぬ': "nu", 'ね': "ne", 'の': "no",
+	'は': "ha", 'ひ': "hi", 'ふ': "fu", 'へ': "he", 'ほ': "ho",
+	'ば': "ba", 'び': "bi", 'ぶ': "bu", 'べ': "be", 'ぼ': "bo",
+	'ぱ': "pa", 'ぴ': "pi", 'ぷ': "pu", 'ぺ': "pe", 'ぽ': "po",
+	'ま': "ma", 'み': "mi", 'む': "mu", 'め': "me", 'も': "mo",
+	'や': "ya", 'ゆ': "yu", 'よ': "yo",
+	'ら': "ra", 'り': "ri", 'る': "ru", 'れ': "re", 'ろ': "ro",
+	'わ': "wa", 'ゐ': "i", 'ゑ': "e", 'を': "o", 'ん': "n",
+	'ゔ': "vu", 'ゕ': "ka", 'ゖ': "ke",
+}
+
+// smallKana are the small kana that modify the syllable before them.
+var smallKana = map[rune]string{
+	'ぁ': "a", 'ぃ': "i", 'ぅ': "u", 'ぇ': "e", 'ぉ': "o",
+	'ゃ': "ya", 'ゅ': "yu", 'ょ': "yo", 'ゎ': "wa",
+}
+
+// Kana is a Transliterator that spells Japanese names written wholly in
+// hiragana, katakana and ASCII in Hepburn romaji: "ショック" is "shokku".
+// Names with kanji, such as "稲妻", need a dictionary; Kana returns "" for
+// them so that a custom Transliterator can fall back to it.
+func Kana(name, language string) string {
+	if language != "Japanese" {
+		return ""
+	}
+	var out []string
+	geminate := false
+	for _, r := range name {
+		if r >= 'ァ' && r <= 'ヶ' {
+			r -= 'ァ' - 'ぁ'
+		}
+		var s string
+		switch {
+		case r == 'っ':
+			geminate = true
+			continue
+		case r == 'ー':
+			if n := len(out); n > 0 && strings.ContainsRune("aeiou", rune(out[n-1][len(out[n-1])-1])) {
+				out[n-1] += out[n-1][len(out[n-1])-1:]
+			}
+			continue
+		case r == '・' || r == '　':
+			s = " "
+		case r < utf8.RuneSelf:
+			s = string(r)
+		case smallKana[r] != "" && len(out) > 0:
+			out[len(out)-1] = combineKana(out[len(out)-1], smallKana[r])
+			continue
+		case smallKana[r] != "":
+			s = smallKana[r]
+		case kana[r] != "":
+			s = kana[r]
+		default:
+			return ""
+		}
+		if geminate {
+			switch {
+			case strings.HasPrefix(s, "ch"):
+				s = "t" + s
+			case !strings.ContainsAny(s[:1], "aeiou n"):
+				s = s[:1] + s
+			}
+			geminate = false
+		}
+		out = append(out, s)
+	}
+	return strings.Join(out, "")
+}
+
+// combineKana joins a syllable and the small kana after it: "ki" and "ya"
+// are "kya", "shi" and "yu" are "shu", "fu" and "a" are "fa".
+func combineKana(prev, small string) string {
+	if prev == "u" && len(small) == 1 {
+		return "w" + small
+	}
+	base := prev[:len(prev)-1]
+	if strings.HasPrefix(small, "y") && strings.HasSuffix(prev, "i") {
+		if strings.HasSuffix(base, "sh") || strings.HasSuffix(base, "ch") || strings.HasSuffix(base, "j") {
+			return base + small[1:]
+		}
+		return base + small
+	}
+	return base + small[len(small)-1:]
+}
+
+// foldASCII normalizes an ASCII spelling for comparison: lower case, only
+// letters, digits and % wildcards, macrons dropped and long vowels written
+// once, so "Tōkyō", "toukyou" and "Tokyo" are all "tokyo".
+func foldASCII(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		switch r {
+		case 'ā', 'â':
+			r = 'a'
+		case 'ī', 'î':
+			r = 'i'
+		case 'ū', 'û':
+			r = 'u'
+		case 'ē', 'ê':
+			r = 'e'
+		case 'ō', 'ô':
+			r = 'o'
+		}
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '%' {
+			b.WriteRune(r)
+		}
+	}
+	folded := b.String()
+	for _, long := range []string{"aa", "ii", "uu", "ee", "oo", "ou"} {
+		for strings.Contains(folded, long) {
+			folded = strings.ReplaceAll(folded, long, long[:1])
+		}
+	}
+	return folded
+}
+
+// isASCII reports whether s has only ASCII characters.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// wantsForeignASCII reports whether p's LocalizedName should also match
+// ASCII spellings of foreign names.
+func wantsForeignASCII(p SearchCardsParams) bool {
+	return p.LocalizedName != "" && isASCII(p.LocalizedName) && strings.Trim(foldASCII(p.LocalizedName), "%") != ""
+}
+
+// ensureForeignASCII builds foreignASCIITable once, if p needs it and there
+// is a transliterator or an asciiName column to build it from.
+func (q *CardQuery) ensureForeignASCII(ctx context.Context, p SearchCardsParams) error {
+	if !wantsForeignASCII(p) {
+		return nil
+	}
+	q.translitMu.Lock()
+	defer q.translitMu.Unlock()
+	if q.translitChecked {
+		return nil
+	}
+	hasASCII, err := q.conn.ExecuteScalar(ctx,
+		"SELECT COUNT(*) FROM information_schema.columns WHERE table_name = 'card_foreign_data' AND column_name = 'asciiName'")
+	if err != nil {
+		return err
+	}
+	asciiCol := "NULL"
+	if db.ScalarToInt(hasASCII) > 0 {
+		asciiCol = "asciiName"
+	} else if q.translit == nil {
+		q.translitChecked = true
+		return nil
+	}
+	rows, err := q.conn.Execute(ctx, fmt.Sprintf(
+		"SELECT DISTINCT name, language, CAST(%s AS VARCHAR) AS ascii FROM card_foreign_data WHERE name IS NOT NULL", asciiCol))
+	if err != nil {
+		return err
+	}
+	var values []any
+	for _, row := range rows {
+		name, _ := row["name"].(string)
+		ascii, _ := row["ascii"].(string)
+		if ascii == "" && q.translit != nil {
+			lang, _ := row["language"].(string)
+			ascii = q.translit(name, lang)
+		}
+		if folded := foldASCII(strings.ReplaceAll(ascii, "%", "")); folded != "" {
+			values = append(values, name, folded)
+		}
+	}
+	if err := q.conn.Exec(ctx, "CREATE OR REPLACE TABLE "+foreignASCIITable+" (name VARCHAR, ascii VARCHAR)"); err != nil {
+		return fmt.Errorf("mtgjson: create %s: %w", foreignASCIITable, err)
+	}
+	for len(values) > 0 {
+		n := min(len(values), 2*foreignASCIIBatch)
+		sql := "INSERT INTO " + foreignASCIITable + " VALUES " +
+			strings.TrimSuffix(strings.Repeat("(?, ?), ", n/2), ", ")
+		if err := q.conn.Exec(ctx, sql, values[:n]...); err != nil {
+			return fmt.Errorf("mtgjson: fill %s: %w", foreignASCIITable, err)
+		}
+		values = values[n:]
+	}
+	q.translitChecked = true
+	q.hasForeignASCII = true
+	return nil
+}
@@ -0,0 +1,110 @@
+package queries
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mtgjson/mtgjson-sdk-go/testsupport/sample"
+)
+
+func TestKana(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"ショック", "shokku"},
+		{"しんかのおりひめ", "shinkanoorihime"},
+		{"チャンドラ", "chandora"},
+		{"ジュース", "juusu"},
+		{"ファイアー・ボール", "faiaa booru"},
+		{"ウィザード", "wizaado"},
+		{"マッチ", "matchi"},
+		{"稲妻", ""},
+		{"Lightning Bolt", "Lightning Bolt"},
+	}
+	for _, tt := range tests {
+		if got := Kana(tt.name, "Japanese"); got != tt.want {
+			t.Errorf("Kana(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	if got := Kana("ショック", "Chinese Simplified"); got != "" {
+		t.Errorf("expected no spelling outside Japanese, got %q", got)
+	}
+}
+
+func TestFoldASCII(t *testing.T) {
+	for _, s := range []string{"Tōkyō", "toukyou", "Tokyo", "TOOKYOO"} {
+		if got := foldASCII(s); got != "tokyo" {
+			t.Errorf("foldASCII(%q) = %q, want tokyo", s, got)
+		}
+	}
+	if got := foldASCII("Ina-zuma%"); got != "inazuma%" {
+		t.Errorf("expected wildcards kept, got %q", got)
+	}
+}
+
+func TestCardSearchTransliterated(t *testing.T) {
+	conn := setupSampleDB(t)
+	ctx := context.Background()
+	readings := map[string]string{"稲妻": "Inazuma"}
+	q := NewCardQuery(conn, WithTransliterator(func(name, language string) string {
+		if r, ok := readings[name]; ok {
+			return r
+		}
+		return Kana(name, language)
+	}))
+
+	for _, name := range []string{"inazuma", "Inazuma", "ina%"} {
+		cards, err := q.Search(ctx, SearchCardsParams{LocalizedName: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cards) != 1 || cards[0].Name != "Lightning Bolt" {
+			t.Errorf("%s: expected Lightning Bolt, got %d cards", name, len(cards))
+		}
+	}
+	cards, err := q.Search(ctx, SearchCardsParams{LocalizedName: "稲妻"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 1 {
+		t.Errorf("expected the exact Japanese name to still match, got %d", len(cards))
+	}
+	n, err := q.CountSearch(ctx, SearchCardsParams{LocalizedName: "Inazuma"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("expected CountSearch to match the romaji, got %d", n)
+	}
+
+	plain, err := NewCardQuery(conn).Search(ctx, SearchCardsParams{LocalizedName: "inazuma"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plain) != 0 {
+		t.Errorf("expected no romaji matches without a transliterator, got %d cards", len(plain))
+	}
+}
+
+func TestCardSearchForeignASCIIName(t *testing.T) {
+	conn := setupSampleDB(t)
+	ctx := context.Background()
+	rows := sample.ForeignData()
+	for _, row := range rows {
+		row["asciiName"] = nil
+		if row["name"] == "稲妻" {
+			row["asciiName"] = "Inazuma"
+		}
+	}
+	if err := conn.RegisterTableFromData(ctx, "card_foreign_data", rows); err != nil {
+		t.Fatal(err)
+	}
+
+	cards, err := NewCardQuery(conn).Search(ctx, SearchCardsParams{LocalizedName: "inazuma"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 1 || cards[0].Name != "Lightning Bolt" {
+		t.Errorf("expected the asciiName column to match, got %d cards", len(cards))
+	}
+}
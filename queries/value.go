@@ -0,0 +1,103 @@
+package queries
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mtgjson/mtgjson-sdk-go/db"
+	"github.com/mtgjson/mtgjson-sdk-go/models"
+)
+
+// Value metrics ValueMetrics can rank by.
+const (
+	ValuePerManaValue = "mana_value" // price / mana value
+	ValuePerPower     = "power"      // price / power
+)
+
+// ValueMetricsParams selects the card pool and price ValueMetrics ranks.
+type ValueMetricsParams struct {
+	Cards      SearchCardsParams // the card pool; its Limit and Offset are ignored
+	Metric     string            // ValuePerManaValue (default) or ValuePerPower
+	Descending bool              // most expensive per point first, instead of cheapest
+	Source     string            // price source, e.g. "paper"; default any
+	Provider   string            // default "tcgplayer"
+	Finish     string            // default "normal"
+	PriceType  string            // default "retail"
+	Limit      int               // 0 means 100
+	Offset     int
+}
+
+// ValueMetrics ranks the printings matching p.Cards by price per mana value
+// or price per power, using each printing's latest price, cheapest per
+// point first. Cards with no mana value (or no numeric power, such as "*",
+// for ValuePerPower) or no price are left out. Both ratios are returned
+// when defined.
+func (q *PriceQuery) ValueMetrics(ctx context.Context, p ValueMetricsParams) ([]models.CardValue, error) {
+	cards := q.cards
+	if cards == nil {
+		cards = NewCardQuery(q.conn)
+	}
+	if err := q.ensure(ctx); err != nil {
+		return nil, err
+	}
+	if err := q.conn.EnsureViews(ctx, cards.searchViews(p.Cards)...); err != nil {
+		return nil, err
+	}
+	if !q.conn.HasView("all_prices_today") {
+		return nil, nil
+	}
+	metric := "price_per_mana_value"
+	switch p.Metric {
+	case "", ValuePerManaValue:
+	case ValuePerPower:
+		metric = "price_per_power"
+	default:
+		return nil, fmt.Errorf("mtgjson: unknown value metric %q", p.Metric)
+	}
+	order := "ASC"
+	if p.Descending {
+		order = "DESC"
+	}
+	provider, finish, priceType := p.Provider, p.Finish, p.PriceType
+	if provider == "" {
+		provider = "tcgplayer"
+	}
+	if finish == "" {
+		finish = "normal"
+	}
+	if priceType == "" {
+		priceType = "retail"
+	}
+
+	pool, params := cards.searchBuilder(p.Cards).Build()
+	arg := func(v any) int {
+		params = append(params, v)
+		return len(params)
+	}
+	where := fmt.Sprintf("p.provider = $%d AND p.finish = $%d AND p.price_type = $%d",
+		arg(provider), arg(finish), arg(priceType))
+	if p.Source != "" {
+		where += fmt.Sprintf(" AND p.source = $%d", arg(p.Source))
+	}
+	limit := p.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	sql := fmt.Sprintf(
+		"WITH pool AS (%s), priced AS ("+
+			"  SELECT c.uuid, c.name, c.setCode, c.number, c.manaValue, c.power, "+
+			"    CAST(p.price AS DOUBLE) AS price, p.currency, "+
+			"    ROUND(CAST(p.price AS DOUBLE) / NULLIF(c.manaValue, 0), 4) AS price_per_mana_value, "+
+			"    ROUND(CAST(p.price AS DOUBLE) / NULLIF(TRY_CAST(c.power AS DOUBLE), 0), 4) AS price_per_power "+
+			"  FROM pool c JOIN %s p ON p.uuid = c.uuid WHERE %s) "+
+			"SELECT * FROM priced WHERE %[4]s > 0 "+
+			"ORDER BY %[4]s %[5]s, name, setCode, uuid "+
+			"LIMIT $%[6]d OFFSET $%[7]d",
+		pool, db.LatestPricesTable, where, metric, order, arg(limit), arg(p.Offset))
+
+	var result []models.CardValue
+	if err := q.conn.ExecuteInto(ctx, &result, sql, params...); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -1078,11 +1078,11 @@ func TestSmoke(t *testing.T) {
 	//  REFRESH
 	// ══════════════════════════════════════════════════════════
 	t.Run("Refresh", func(t *testing.T) {
-		stale, err := sdk.Refresh(ctx)
+		result, err := sdk.Refresh(ctx)
 		if err != nil {
 			t.Fatal(err)
 		}
-		t.Logf("refresh stale=%v", stale)
+		t.Logf("refresh stale=%v old=%s new=%s", result.Stale, result.OldVersion, result.NewVersion)
 	})
 
 	fmt.Println("\nSmoke test completed successfully!")
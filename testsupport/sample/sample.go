@@ -0,0 +1,83 @@
+// Package sample holds the small MTGJSON-shaped dataset the SDK's own tests
+// run against: three printings across the A25 and MH2 sets, with tokens,
+// identifiers, legalities, foreign data, sealed products, decks and prices.
+//
+// Rows are plain maps keyed by MTGJSON column name, ready for
+// db.Connection.RegisterTableFromData. Every function returns a fresh deep
+// copy, so callers may modify the rows freely.
+package sample
+
+// Cards returns Lightning Bolt (card-uuid-001, A25), Counterspell
+// (card-uuid-002, MH2) and the Fire half of Fire // Ice (card-uuid-003, A25).
+func Cards() []map[string]any { return cloneRows(cards) }
+
+// Sets returns the A25 and MH2 sets.
+func Sets() []map[string]any { return cloneRows(sets) }
+
+// SetTranslations returns translated names for A25 and MH2.
+func SetTranslations() []map[string]any { return cloneRows(setTranslations) }
+
+// Tokens returns a Soldier token (A25) and a Beast token (MH2).
+func Tokens() []map[string]any { return cloneRows(tokens) }
+
+// Identifiers returns external IDs for card-uuid-001 and card-uuid-002.
+func Identifiers() []map[string]any { return cloneRows(identifiers) }
+
+// Legalities returns one row per card and format.
+func Legalities() []map[string]any { return cloneRows(legalities) }
+
+// ForeignData returns German, French and Japanese printings of the sample cards.
+func ForeignData() []map[string]any { return cloneRows(foreignData) }
+
+// SealedProducts returns two A25 products and one MH2 booster box.
+func SealedProducts() []map[string]any { return cloneRows(sealedProducts) }
+
+// SetDecks returns one preconstructed deck per set.
+func SetDecks() []map[string]any { return cloneRows(setDecks) }
+
+// Prices returns one TCGplayer retail price per card, all dated 2024-01-03,
+// in the flattened all_prices_today layout.
+func Prices() []map[string]any { return cloneRows(prices) }
+
+// Tables returns the sample rows keyed by the view they are registered as.
+// Prices are not included, since the price views are optional.
+func Tables() map[string][]map[string]any {
+	return map[string][]map[string]any{
+		"cards":             Cards(),
+		"sets":              Sets(),
+		"tokens":            Tokens(),
+		"card_identifiers":  Identifiers(),
+		"card_legalities":   Legalities(),
+		"card_foreign_data": ForeignData(),
+		"sealed_products":   SealedProducts(),
+		"set_decks":         SetDecks(),
+		"set_translations":  SetTranslations(),
+	}
+}
+
+func cloneRows(rows []map[string]any) []map[string]any {
+	out := make([]map[string]any, len(rows))
+	for i, r := range rows {
+		out[i] = cloneValue(r).(map[string]any)
+	}
+	return out
+}
+
+func cloneValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			m[k] = cloneValue(e)
+		}
+		return m
+	case []any:
+		s := make([]any, len(v))
+		for i, e := range v {
+			s[i] = cloneValue(e)
+		}
+		return s
+	default:
+		return v
+	}
+}
@@ -0,0 +1,110 @@
+// Package testsupport builds SDK instances backed by small in-memory
+// datasets, for testing code that uses the SDK without downloading MTGJSON
+// data.
+//
+//	func TestDeckValue(t *testing.T) {
+//		sdk := testsupport.NewSDK(t,
+//			testsupport.WithCards(testsupport.Card(map[string]any{
+//				"uuid": "my-card", "name": "Grizzly Bears", "manaValue": 2.0,
+//			})),
+//			testsupport.WithPrices(map[string]any{
+//				"uuid": "my-card", "source": "paper", "provider": "tcgplayer",
+//				"currency": "USD", "price_type": "retail", "finish": "normal",
+//				"date": "2024-01-03", "price": 0.25,
+//			}),
+//		)
+//		...
+//	}
+//
+// The default dataset is the one in package sample.
+package testsupport
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	mtgjson "github.com/mtgjson/mtgjson-sdk-go"
+	"github.com/mtgjson/mtgjson-sdk-go/testsupport/sample"
+)
+
+// Option customizes the dataset or SDK built by NewSDK.
+type Option func(*builder)
+
+type builder struct {
+	tables  map[string][]map[string]any
+	sdkOpts []mtgjson.Option
+}
+
+// WithCards adds card rows to the sample cards. Build rows with Card so that
+// every column the queries expect is present.
+func WithCards(rows ...map[string]any) Option {
+	return func(b *builder) {
+		b.tables["cards"] = append(b.tables["cards"], rows...)
+	}
+}
+
+// WithPrices adds flattened price rows to the sample prices. They are
+// registered as both all_prices_today and all_prices.
+func WithPrices(rows ...map[string]any) Option {
+	return func(b *builder) {
+		b.tables["all_prices_today"] = append(b.tables["all_prices_today"], rows...)
+	}
+}
+
+// WithTable replaces the rows of one view, such as "sets" or "tokens". A nil
+// or empty rows leaves the view unregistered, as if its file were missing.
+func WithTable(name string, rows []map[string]any) Option {
+	return func(b *builder) {
+		b.tables[name] = rows
+	}
+}
+
+// WithSDKOptions passes options through to mtgjson.New. They are applied
+// after the defaults of a temporary cache directory and offline mode.
+func WithSDKOptions(opts ...mtgjson.Option) Option {
+	return func(b *builder) {
+		b.sdkOpts = append(b.sdkOpts, opts...)
+	}
+}
+
+// NewSDK returns an offline SDK with the sample dataset, plus any rows added
+// by opts, registered as tables. The SDK is closed when the test finishes.
+func NewSDK(t testing.TB, opts ...Option) *mtgjson.SDK {
+	t.Helper()
+	b := &builder{tables: sample.Tables()}
+	b.tables["all_prices_today"] = sample.Prices()
+	for _, opt := range opts {
+		opt(b)
+	}
+	if _, ok := b.tables["all_prices"]; !ok {
+		b.tables["all_prices"] = b.tables["all_prices_today"]
+	}
+
+	sdkOpts := append([]mtgjson.Option{
+		mtgjson.WithCacheDir(t.TempDir()),
+		mtgjson.WithOffline(true),
+	}, b.sdkOpts...)
+	sdk, err := mtgjson.New(sdkOpts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sdk.Close() })
+
+	ctx := context.Background()
+	for name, rows := range b.tables {
+		if err := sdk.Connection().RegisterTableFromData(ctx, name, rows); err != nil {
+			t.Fatalf("register %s: %v", name, err)
+		}
+	}
+	return sdk
+}
+
+// Card returns a complete card row, a copy of the sample Lightning Bolt with
+// fields overriding its columns. Set at least "uuid" so the row does not
+// collide with card-uuid-001.
+func Card(fields map[string]any) map[string]any {
+	card := sample.Cards()[0]
+	maps.Copy(card, fields)
+	return card
+}
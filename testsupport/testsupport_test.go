@@ -0,0 +1,88 @@
+package testsupport
+
+import (
+	"context"
+	"testing"
+
+	mtgjson "github.com/mtgjson/mtgjson-sdk-go"
+	"github.com/mtgjson/mtgjson-sdk-go/queries"
+	"github.com/mtgjson/mtgjson-sdk-go/testsupport/sample"
+)
+
+func TestNewSDKSampleData(t *testing.T) {
+	sdk := NewSDK(t)
+	ctx := context.Background()
+
+	card, err := sdk.Cards().GetByUUID(ctx, "card-uuid-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card == nil || card.Name != "Lightning Bolt" {
+		t.Fatalf("unexpected card %+v", card)
+	}
+	set, err := sdk.Sets().Get(ctx, "MH2")
+	if err != nil || set == nil {
+		t.Fatalf("expected the MH2 set, got %+v (%v)", set, err)
+	}
+	today, err := sdk.Prices().Today(ctx, "card-uuid-002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(today) != 1 {
+		t.Errorf("expected 1 sample price, got %+v", today)
+	}
+}
+
+func TestNewSDKWithCardsAndPrices(t *testing.T) {
+	sdk := NewSDK(t,
+		WithCards(Card(map[string]any{"uuid": "bears", "name": "Grizzly Bears", "manaValue": 2.0})),
+		WithPrices(map[string]any{
+			"uuid": "bears", "source": "paper", "provider": "tcgplayer",
+			"currency": "USD", "price_type": "retail", "finish": "normal",
+			"date": "2024-01-03", "price": 0.25,
+		}),
+	)
+	ctx := context.Background()
+
+	cards, err := sdk.Cards().Search(ctx, queries.SearchCardsParams{Name: "Grizzly Bears"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 1 || cards[0].UUID != "bears" || cards[0].SetCode != "A25" {
+		t.Fatalf("unexpected search result %+v", cards)
+	}
+	if n, err := sdk.Cards().Count(ctx); err != nil || n != len(sample.Cards())+1 {
+		t.Errorf("expected the sample cards plus one, got %d (%v)", n, err)
+	}
+	history, err := sdk.Prices().History(ctx, "bears")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 {
+		t.Errorf("expected added prices in all_prices too, got %+v", history)
+	}
+}
+
+func TestNewSDKWithTable(t *testing.T) {
+	sdk := NewSDK(t,
+		WithTable("tokens", nil),
+		WithTable("sets", sample.Sets()[:1]),
+		WithSDKOptions(mtgjson.WithStrict(true)),
+	)
+	ctx := context.Background()
+
+	if n, err := sdk.Sets().Count(ctx); err != nil || n != 1 {
+		t.Errorf("expected only A25, got %d sets (%v)", n, err)
+	}
+	if _, err := sdk.Tokens().GetByUUID(ctx, "token-uuid-001"); err == nil {
+		t.Error("expected an error for the unregistered tokens view offline")
+	}
+}
+
+func TestCardDoesNotShareSample(t *testing.T) {
+	c := Card(map[string]any{"uuid": "x"})
+	c["colors"].([]any)[0] = "G"
+	if got := sample.Cards()[0]["colors"].([]any)[0]; got != "R" {
+		t.Errorf("expected sample data to be unaffected, got %v", got)
+	}
+}
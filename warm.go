@@ -0,0 +1,47 @@
+package mtgjsonsdk
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mtgjson/mtgjson-sdk-go/db"
+)
+
+// defaultWarmDatasets are the datasets WarmCache downloads when none are named.
+var defaultWarmDatasets = []string{db.DatasetCards, db.DatasetSets}
+
+// WarmCache downloads datasets (view names such as db.DatasetCards,
+// db.DatasetSets or db.DatasetAllPricesToday, listed by db.Registry; cards
+// and sets by default) into dir and checks that DuckDB can read each one
+// and that it has rows. It returns the MTGJSON version of the cache. It is
+// meant for init containers and image builds that bake a cache for SDKs
+// later opened with WithCacheDir(dir) and WithOffline(true); the
+// mtgjson-warm command wraps it for use without Go.
+func WarmCache(ctx context.Context, dir string, datasets ...string) (string, error) {
+	if len(datasets) == 0 {
+		datasets = defaultWarmDatasets
+	}
+	sdk, err := New(WithCacheDir(dir))
+	if err != nil {
+		return "", err
+	}
+	defer sdk.Close()
+
+	for _, name := range datasets {
+		if err := sdk.EnsureViews(ctx, name); err != nil {
+			return "", fmt.Errorf("mtgjson: warm %s: %w", name, err)
+		}
+		val, err := sdk.conn.ExecuteScalar(ctx, "SELECT COUNT(*) FROM "+name)
+		if err != nil {
+			return "", fmt.Errorf("mtgjson: verify %s: %w", name, err)
+		}
+		if db.ScalarToInt(val) == 0 {
+			return "", fmt.Errorf("mtgjson: verify %s: dataset is empty", name)
+		}
+	}
+	meta, err := sdk.Meta(ctx)
+	if err != nil {
+		return "", fmt.Errorf("mtgjson: warm meta: %w", err)
+	}
+	return meta.Version, nil
+}